@@ -0,0 +1,203 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// aggregateTagName is the struct tag of the aggregate result fields (see: GetModelsAggregateInto)
+const aggregateTagName = "aggregate"
+
+// Aggregate result fields (struct tag values)
+const (
+	aggregateTagCount = "count" // IE: Total int64 `aggregate:"count"`
+	aggregateTagKey   = "key"   // IE: Day time.Time `aggregate:"key"`
+)
+
+// aggregateKeyDateLayout is the format of the date fields keys (YYYYMMDD)
+const aggregateKeyDateLayout = "20060102"
+
+// ErrInvalidAggregateResults is when the aggregate results are not a pointer to a slice of structs with
+// the aggregate key and count fields
+var ErrInvalidAggregateResults = errors.New("invalid aggregate results")
+
+// aggregateRow is the count of the records of an aggregate key (typed as returned by the engine)
+type aggregateRow struct {
+	count interface{}
+	key   interface{}
+}
+
+// GetModelsAggregateInto will scan the aggregate counts of the model matching conditions into the results
+//
+// The results are a pointer to a slice of structs (or struct pointers), the fields are mapped using the aggregate
+// struct tag and converted to the field type (IE: Amount int64 `aggregate:"key"` and Total int `aggregate:"count"`)
+// The date fields are grouped by day (YYYYMMDD), use a time.Time or a string key field
+// The order of the rows is not defined
+func (c *Client) GetModelsAggregateInto(ctx context.Context, models interface{},
+	conditions map[string]interface{}, aggregateColumn string, results interface{}, timeout time.Duration) error {
+
+	// Check the results before querying
+	resultsValue := reflect.ValueOf(results)
+	if resultsValue.Kind() != reflect.Ptr || resultsValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w: %T is not a pointer to a slice", ErrInvalidAggregateResults, results)
+	}
+	sliceValue := resultsValue.Elem()
+	elemType := sliceValue.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	keyIndex, countIndex, err := getAggregateFields(structType)
+	if err != nil {
+		return err
+	}
+
+	var rows []aggregateRow
+	if rows, err = c.getAggregateRows(ctx, models, conditions, aggregateColumn, timeout); err != nil {
+		return err
+	}
+
+	items := reflect.MakeSlice(sliceValue.Type(), 0, len(rows))
+	for _, row := range rows {
+		item := reflect.New(structType)
+		if err = setAggregateField(item.Elem().FieldByIndex(keyIndex), row.key); err != nil {
+			return err
+		} else if err = setAggregateField(item.Elem().FieldByIndex(countIndex), row.count); err != nil {
+			return err
+		}
+		if elemType.Kind() != reflect.Ptr {
+			item = item.Elem()
+		}
+		items = reflect.Append(items, item)
+	}
+	sliceValue.Set(items)
+	return nil
+}
+
+// getAggregateRows will return the count of the model matching conditions grouped by the aggregate column
+func (c *Client) getAggregateRows(ctx context.Context, models interface{},
+	conditions map[string]interface{}, aggregateColumn string, timeout time.Duration) ([]aggregateRow, error) {
+
+	// Exclude the soft-deleted records
+	conditions = c.getSoftDeleteConditions(ctx, models, conditions)
+
+	// Normalize the conditions (see: WithConditionNormalization)
+	var err error
+	if conditions, err = c.normalizeQueryConditions(conditions); err != nil {
+		return nil, err
+	}
+
+	// Switch on the datastore engines
+	if c.Engine() == MongoDB {
+		start := time.Now()
+		rows, err := c.aggregateWithMongo(ctx, models, conditions, aggregateColumn, timeout)
+		return rows, newMongoQueryError("aggregate", models, conditions, start, err)
+	} else if c.Engine() == Memory {
+		return c.aggregateWithMemory(ctx, models, conditions, aggregateColumn)
+	} else if !IsSQLEngine(c.Engine()) {
+		return nil, ErrUnsupportedEngine
+	}
+
+	return c.aggregate(ctx, models, conditions, aggregateColumn, timeout)
+}
+
+// getAggregateFields will return the index of the key and count fields of the aggregate results struct
+func getAggregateFields(structType reflect.Type) (keyIndex, countIndex []int, err error) {
+	if structType.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("%w: %s is not a struct", ErrInvalidAggregateResults, structType)
+	}
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() {
+			continue
+		}
+		switch field.Tag.Get(aggregateTagName) {
+		case aggregateTagKey:
+			keyIndex = field.Index
+		case aggregateTagCount:
+			countIndex = field.Index
+		}
+	}
+	if keyIndex == nil || countIndex == nil {
+		return nil, nil, fmt.Errorf("%w: %s needs the %s:\"%s\" and %s:\"%s\" fields", ErrInvalidAggregateResults,
+			structType, aggregateTagName, aggregateTagKey, aggregateTagName, aggregateTagCount)
+	}
+	return keyIndex, countIndex, nil
+}
+
+// getAggregateKey will return the text of an aggregate key (drivers can return []byte, numbers or times)
+func getAggregateKey(value interface{}) string {
+	switch v := getScannedValue(value).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// setAggregateField will set the field of an aggregate result (converted to the field type)
+func setAggregateField(field reflect.Value, value interface{}) error {
+	value = getScannedValue(value)
+	if value == nil {
+		return nil // Zero value
+	}
+
+	// Same (or assignable) types
+	valueOf := reflect.ValueOf(value)
+	if valueOf.Type().AssignableTo(field.Type()) {
+		field.Set(valueOf)
+		return nil
+	}
+
+	var err error
+	switch field.Kind() { //nolint:exhaustive // other types are not supported
+	case reflect.String:
+		field.SetString(getAggregateKey(value))
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var number interface{}
+		if number, err = ConvertToInt64(getAggregateNumber(value)); err == nil && !field.OverflowInt(number.(int64)) {
+			field.SetInt(number.(int64))
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var number interface{}
+		if number, err = ConvertToInt64(getAggregateNumber(value)); err == nil && number.(int64) >= 0 &&
+			!field.OverflowUint(uint64(number.(int64))) {
+			field.SetUint(uint64(number.(int64)))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		var number interface{}
+		if number, err = ConvertToFloat64(getAggregateNumber(value)); err == nil {
+			field.SetFloat(number.(float64))
+			return nil
+		}
+	case reflect.Struct:
+		if field.Type() == reflect.TypeOf(time.Time{}) {
+			var date time.Time
+			if date, err = time.Parse(aggregateKeyDateLayout, getAggregateKey(value)); err == nil {
+				field.Set(reflect.ValueOf(date))
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: can not set %T (%v) to %s: %v", ErrInvalidAggregateResults, value, value, field.Type(), err)
+}
+
+// getAggregateNumber will return the value supported by ConvertToInt64 and ConvertToFloat64
+// (other types are converted from their text)
+func getAggregateNumber(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64, int, float64, []byte, string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,124 @@
+package datastore
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAggregateRow is a typed aggregate result
+type testAggregateRow struct {
+	Amount int64 `aggregate:"key"`
+	Total  int   `aggregate:"count"`
+}
+
+// testAggregateDateRow is a typed aggregate result of a date field
+type testAggregateDateRow struct {
+	Day   time.Time `aggregate:"key"`
+	Total uint      `aggregate:"count"`
+}
+
+// TestClient_GetModelsAggregateInto will test the method GetModelsAggregateInto()
+func TestClient_GetModelsAggregateInto(t *testing.T) {
+	firstDay := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	secondDay := time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC)
+	records := []*testSQLModel{
+		{ID: "aggregate-1", Name: "alice", Amount: 10, CreatedAt: firstDay},
+		{ID: "aggregate-2", Name: "bob", Amount: 10, CreatedAt: firstDay.Add(time.Hour)},
+		{ID: "aggregate-3", Name: "carol", Amount: 20, CreatedAt: secondDay},
+	}
+
+	engines := map[string]func(ctx context.Context, t *testing.T, opts ...ClientOps) (ClientInterface, func()){
+		"sqlite": testSQLiteClient,
+		"memory": testMemoryClient,
+	}
+	for name, newClient := range engines {
+		t.Run(name+" integer column", func(t *testing.T) {
+			ctx := context.Background()
+			client, deferFunc := newClient(ctx, t)
+			defer deferFunc()
+			testSaveModels(ctx, t, client, records...)
+
+			results, err := client.GetModelsAggregate(ctx, &[]*testSQLModel{}, nil, "amount", defaultDatabaseMaxTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"10": int64(2), "20": int64(1)}, results)
+
+			var rows []testAggregateRow
+			require.NoError(t, client.GetModelsAggregateInto(ctx, &[]*testSQLModel{}, nil, "amount",
+				&rows, defaultDatabaseMaxTimeout))
+			sort.Slice(rows, func(i, j int) bool { return rows[i].Amount < rows[j].Amount })
+			assert.Equal(t, []testAggregateRow{{Amount: 10, Total: 2}, {Amount: 20, Total: 1}}, rows)
+		})
+
+		t.Run(name+" date column with conditions", func(t *testing.T) {
+			ctx := context.Background()
+			client, deferFunc := newClient(ctx, t)
+			defer deferFunc()
+			testSaveModels(ctx, t, client, records...)
+
+			conditions := map[string]interface{}{"name": map[string]interface{}{conditionNotEquals: "bob"}}
+			results, err := client.GetModelsAggregate(ctx, &[]*testSQLModel{}, conditions, dateCreatedAt,
+				defaultDatabaseMaxTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"20240102": int64(1), "20240103": int64(1)}, results)
+
+			var rows []*testAggregateDateRow
+			require.NoError(t, client.GetModelsAggregateInto(ctx, &[]*testSQLModel{}, nil, dateCreatedAt,
+				&rows, defaultDatabaseMaxTimeout))
+			sort.Slice(rows, func(i, j int) bool { return rows[i].Day.Before(rows[j].Day) })
+			require.Len(t, rows, 2)
+			assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), rows[0].Day)
+			assert.Equal(t, uint(2), rows[0].Total)
+			assert.Equal(t, uint(1), rows[1].Total)
+		})
+	}
+
+	t.Run("invalid results", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t)
+		defer deferFunc()
+
+		var rows []testAggregateRow
+		err := client.GetModelsAggregateInto(ctx, &[]*testSQLModel{}, nil, "amount", rows, 0)
+		require.ErrorIs(t, err, ErrInvalidAggregateResults)
+
+		var untagged []struct{ Amount int64 }
+		err = client.GetModelsAggregateInto(ctx, &[]*testSQLModel{}, nil, "amount", &untagged, 0)
+		require.ErrorIs(t, err, ErrInvalidAggregateResults)
+	})
+}
+
+// Test_setAggregateField will test the method setAggregateField()
+func Test_setAggregateField(t *testing.T) {
+	var row struct {
+		Float  float64
+		Int    int8
+		Text   string
+		Time   time.Time
+		Uint   uint16
+		Values interface{}
+	}
+	field := func(name string) reflect.Value { return reflect.ValueOf(&row).Elem().FieldByName(name) }
+
+	require.NoError(t, setAggregateField(field("Float"), []byte("1.5")))
+	assert.InDelta(t, 1.5, row.Float, 0.001)
+	require.NoError(t, setAggregateField(field("Int"), int32(7)))
+	assert.Equal(t, int8(7), row.Int)
+	require.NoError(t, setAggregateField(field("Text"), int64(42)))
+	assert.Equal(t, "42", row.Text)
+	require.NoError(t, setAggregateField(field("Time"), "20240102"))
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), row.Time)
+	require.NoError(t, setAggregateField(field("Uint"), "3"))
+	assert.Equal(t, uint16(3), row.Uint)
+	require.NoError(t, setAggregateField(field("Values"), int64(5)))
+	assert.Equal(t, int64(5), row.Values)
+
+	require.ErrorIs(t, setAggregateField(field("Int"), int64(300)), ErrInvalidAggregateResults)
+	require.ErrorIs(t, setAggregateField(field("Uint"), int64(-1)), ErrInvalidAggregateResults)
+	require.ErrorIs(t, setAggregateField(field("Time"), "tomorrow"), ErrInvalidAggregateResults)
+}
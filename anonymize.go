@@ -0,0 +1,218 @@
+package datastore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/gorm"
+)
+
+// defaultAnonymizeBatchSize is the number of rows anonymized per batch
+const defaultAnonymizeBatchSize = 500
+
+// ErrInvalidAnonymizeField is when no fields are given or a field is not a valid column name
+var ErrInvalidAnonymizeField = errors.New("invalid anonymize field")
+
+// Anonymizer will return the anonymized value of a column (nil sets the column to NULL)
+type Anonymizer func(value interface{}) (interface{}, error)
+
+// AnonymizeNull will set the column to NULL
+func AnonymizeNull() Anonymizer {
+	return func(interface{}) (interface{}, error) {
+		return nil, nil
+	}
+}
+
+// AnonymizeReplace will replace the column with a fixed value (IE: "redacted")
+func AnonymizeReplace(replacement interface{}) Anonymizer {
+	return func(interface{}) (interface{}, error) {
+		return replacement, nil
+	}
+}
+
+// AnonymizeHash will replace the column with a salted SHA-256 hash (hex) of the value (NULL is kept)
+//
+// The same value always has the same hash, so the column can still be joined or grouped
+func AnonymizeHash(salt string) Anonymizer {
+	return func(value interface{}) (interface{}, error) {
+		if value == nil {
+			return nil, nil
+		} else if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		hash := sha256.Sum256([]byte(salt + fmt.Sprint(value)))
+		return hex.EncodeToString(hash[:]), nil
+	}
+}
+
+// AnonymizeJSONKeys will remove the keys from a JSON object column (IE: metadata)
+//
+// Supports JSON text (SQL) and the key/value arrays of metadata (MongoDB)
+func AnonymizeJSONKeys(keys ...string) Anonymizer {
+	remove := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		remove[key] = true
+	}
+	return func(value interface{}) (interface{}, error) {
+		switch v := value.(type) {
+		case nil:
+			return nil, nil
+		case []byte:
+			return removeJSONKeys(string(v), remove)
+		case string:
+			return removeJSONKeys(v, remove)
+		case map[string]interface{}:
+			return removeMapKeys(v, remove), nil
+		case bson.M:
+			return removeMapKeys(v, remove), nil
+		case bson.A:
+			return removeMetadataKeys(v, remove), nil
+		case []interface{}:
+			return removeMetadataKeys(v, remove), nil
+		default:
+			return nil, fmt.Errorf("%w: unsupported JSON value %T", ErrInvalidAnonymizeField, value)
+		}
+	}
+}
+
+// AnonymizeModels will anonymize the fields of all the models matching the conditions (in batches)
+//
+// Used for right-to-be-forgotten (GDPR erasure) workflows, returns the number of rows anonymized
+// SQL engines require a single column primary key
+func (c *Client) AnonymizeModels(ctx context.Context, model interface{}, conditions map[string]interface{},
+	fieldRules map[string]Anonymizer,
+) (int64, error) {
+	if len(fieldRules) == 0 {
+		return 0, ErrInvalidAnonymizeField
+	}
+	fields := make([]string, 0, len(fieldRules))
+	for field, anonymizer := range fieldRules {
+		if !indexNamePattern.MatchString(field) || anonymizer == nil {
+			return 0, ErrInvalidAnonymizeField
+		}
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	// Anonymize each batch (in a transaction for SQL engines)
+	var total int64
+	err := c.forEachBatch(ctx, model, conditions, fields, defaultAnonymizeBatchSize,
+		func(tableName, keyColumn string, rows []map[string]interface{}) error {
+			if err := c.anonymizeBatch(ctx, tableName, keyColumn, rows, fields, fieldRules); err != nil {
+				return err
+			}
+			total += int64(len(rows))
+			return nil
+		},
+	)
+
+	// Invalidate the cached reads of the table (see: WithCache)
+	if total > 0 {
+		c.invalidateCache(ctx, model)
+	}
+	return total, err
+}
+
+// anonymizeBatch will update the anonymized values of the rows (by the key column)
+func (c *Client) anonymizeBatch(ctx context.Context, tableName, keyColumn string, rows []map[string]interface{},
+	fields []string, fieldRules map[string]Anonymizer,
+) error {
+	if c.Engine() == MongoDB {
+		collection := c.getMongoWriteCollection(ctx, tableName)
+		for _, row := range rows {
+			values, err := anonymizeRow(row, fields, fieldRules)
+			if err != nil {
+				return err
+			}
+			if _, err = collection.UpdateOne(
+				ctx, bson.M{keyColumn: row[keyColumn]}, bson.M{conditionSet: values},
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return c.options.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			values, err := anonymizeRow(row, fields, fieldRules)
+			if err != nil {
+				return err
+			}
+			if err = tx.Table(tableName).Where(keyColumn+" = ?", row[keyColumn]).Updates(values).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// anonymizeRow will return the anonymized values (column: value) of the row
+func anonymizeRow(row map[string]interface{}, fields []string,
+	fieldRules map[string]Anonymizer,
+) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		value, err := fieldRules[field](row[field])
+		if err != nil {
+			return nil, fmt.Errorf("anonymize %s: %w", field, err)
+		}
+		values[field] = value
+	}
+	return values, nil
+}
+
+// removeJSONKeys will remove the keys from the JSON object text
+func removeJSONKeys(text string, remove map[string]bool) (interface{}, error) {
+	if text == "" {
+		return text, nil
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &object); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(removeMapKeys(object, remove))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// removeMapKeys will remove the keys from the map
+func removeMapKeys(object map[string]interface{}, remove map[string]bool) map[string]interface{} {
+	for key := range remove {
+		delete(object, key)
+	}
+	return object
+}
+
+// removeMetadataKeys will remove the keys from the metadata key/value array (MongoDB)
+func removeMetadataKeys(items []interface{}, remove map[string]bool) bson.A {
+	kept := bson.A{}
+	for _, item := range items {
+		var key interface{}
+		switch i := item.(type) {
+		case bson.D:
+			for _, e := range i {
+				if e.Key == "k" {
+					key = e.Value
+				}
+			}
+		case bson.M:
+			key = i["k"]
+		case map[string]interface{}:
+			key = i["k"]
+		}
+		if k, ok := key.(string); ok && remove[k] {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
@@ -0,0 +1,114 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestAnonymizers will test the built-in anonymizers
+func TestAnonymizers(t *testing.T) {
+	t.Run("null and replace", func(t *testing.T) {
+		value, err := AnonymizeNull()("alice")
+		require.NoError(t, err)
+		assert.Nil(t, value)
+
+		value, err = AnonymizeReplace("redacted")("alice")
+		require.NoError(t, err)
+		assert.Equal(t, "redacted", value)
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		first, err := AnonymizeHash("salt")("alice")
+		require.NoError(t, err)
+		second, err := AnonymizeHash("salt")([]byte("alice"))
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.Len(t, first, 64)
+
+		var value interface{}
+		value, err = AnonymizeHash("salt")(nil)
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("json keys", func(t *testing.T) {
+		anonymizer := AnonymizeJSONKeys("email", "phone")
+
+		value, err := anonymizer(`{"email":"a@b.com","plan":"pro"}`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"plan":"pro"}`, value)
+
+		value, err = anonymizer(map[string]interface{}{"phone": "555", "plan": "pro"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"plan": "pro"}, value)
+
+		value, err = anonymizer(bson.A{
+			bson.D{{Key: "k", Value: "email"}, {Key: "v", Value: "a@b.com"}},
+			bson.M{"k": "plan", "v": "pro"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, bson.A{bson.M{"k": "plan", "v": "pro"}}, value)
+
+		_, err = anonymizer("not json")
+		require.Error(t, err)
+
+		_, err = anonymizer(10)
+		require.ErrorIs(t, err, ErrInvalidAnonymizeField)
+	})
+}
+
+// TestClient_AnonymizeModels will test the method AnonymizeModels()
+func TestClient_AnonymizeModels(t *testing.T) {
+	t.Run("anonymize the matching rows", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "anonymize-1", Name: "alice", Amount: 10},
+			&testSQLModel{ID: "anonymize-2", Name: "bob", Amount: 10},
+			&testSQLModel{ID: "anonymize-3", Name: "carol", Amount: 1},
+		)
+
+		rows, err := client.AnonymizeModels(ctx, &testSQLModel{}, map[string]interface{}{
+			"amount": 10,
+		}, map[string]Anonymizer{
+			"amount": AnonymizeReplace(0),
+			"name":   AnonymizeReplace("redacted"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), rows)
+
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(ctx, &models, nil, &QueryParams{
+			OrderByField: sqlIDField, SortDirection: SortAsc,
+		}, nil, defaultDatabaseMaxTimeout))
+		require.Len(t, models, 3)
+		assert.Equal(t, "redacted", models[0].Name)
+		assert.Equal(t, int64(0), models[0].Amount)
+		assert.Equal(t, "redacted", models[1].Name)
+		assert.Equal(t, "carol", models[2].Name)
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite}}
+		_, err := client.AnonymizeModels(context.Background(), &testSQLModel{}, nil, nil)
+		require.ErrorIs(t, err, ErrInvalidAnonymizeField)
+
+		_, err = client.AnonymizeModels(context.Background(), &testSQLModel{}, nil, map[string]Anonymizer{
+			"name; DROP": AnonymizeNull(),
+		})
+		require.ErrorIs(t, err, ErrInvalidAnonymizeField)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		_, err := client.AnonymizeModels(context.Background(), &testSQLModel{}, nil, map[string]Anonymizer{
+			"name": AnonymizeNull(),
+		})
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
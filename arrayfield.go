@@ -0,0 +1,156 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mrz1836/go-datastore/nrgorm"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/gorm"
+)
+
+// ErrNotArrayField is when the field is not a registered array field (see: WithCustomFields)
+var ErrNotArrayField = errors.New("field is not a registered array field")
+
+// AppendToArrayField will add the values to the array field of the model (primary key based) in a single statement
+//
+// Values already in the array are skipped (set semantics), the model itself is not modified
+// SQL: the array is rebuilt from its JSON elements (MySQL 8.0+ JSON_TABLE), MongoDB: $addToSet
+func (c *Client) AppendToArrayField(ctx context.Context, model interface{}, field string, values ...string) error {
+	return c.updateArrayField(ctx, model, field, values, true)
+}
+
+// RemoveFromArrayField will remove the values from the array field of the model (primary key based) in a
+// single statement
+//
+// All the occurrences are removed, the model itself is not modified
+// SQL: the array is rebuilt from its JSON elements (MySQL 8.0+ JSON_TABLE), MongoDB: $pull
+func (c *Client) RemoveFromArrayField(ctx context.Context, model interface{}, field string, values ...string) error {
+	return c.updateArrayField(ctx, model, field, values, false)
+}
+
+// updateArrayField will add (or remove) the values of the array field
+func (c *Client) updateArrayField(ctx context.Context, model interface{}, field string, values []string,
+	add bool,
+) (err error) {
+	if !StringInSlice(field, c.GetArrayFields()) || !indexNamePattern.MatchString(field) {
+		return ErrNotArrayField
+	} else if len(values) == 0 {
+		return ErrNoFieldsToUpdate
+	}
+
+	// Remove the duplicate values (keeping the order)
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !StringInSlice(value, unique) {
+			unique = append(unique, value)
+		}
+	}
+
+	// Run the update event (and invalidate the cached reads) after the update (see: SubscribeModelEvents)
+	defer func() {
+		if err == nil {
+			c.emitModelUpdate(ctx, model)
+		}
+	}()
+
+	if c.Engine() == MongoDB {
+		start := time.Now()
+		return newMongoQueryError("update", model, nil, start,
+			c.updateArrayFieldWithMongo(ctx, model, field, unique, add))
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	primaryKey, err := c.getModelPrimaryKey(model)
+	if err != nil {
+		return err
+	}
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(unique)
+	if err != nil {
+		return err
+	}
+
+	// Set the NewRelic txn
+	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+	return c.options.db.WithContext(ctx).Table(tableName).Where(primaryKey).
+		UpdateColumn(field, gorm.Expr(getArrayFieldExpression(c.Engine(), field, add), string(raw))).Error
+}
+
+// getArrayFieldExpression will return the SQL expression that adds (or removes) the values of the JSON array
+// (the values are a single JSON array parameter)
+func getArrayFieldExpression(engine Engine, field string, add bool) string {
+	switch engine {
+	case PostgreSQL:
+		current := "COALESCE(" + field + "::jsonb, '[]'::jsonb)"
+		if add {
+			return current + " || COALESCE((SELECT jsonb_agg(e.v ORDER BY e.i) FROM jsonb_array_elements(?::jsonb) " +
+				"WITH ORDINALITY AS e(v, i) WHERE NOT " + current + " @> jsonb_build_array(e.v)), '[]'::jsonb)"
+		}
+		return "COALESCE((SELECT jsonb_agg(e.v ORDER BY e.i) FROM jsonb_array_elements(" + current + ") " +
+			"WITH ORDINALITY AS e(v, i) WHERE NOT ?::jsonb @> jsonb_build_array(e.v)), '[]'::jsonb)"
+	case MySQL:
+		current := "COALESCE(" + field + ", JSON_ARRAY())"
+		if add {
+			return "JSON_MERGE_PRESERVE(" + current + ", COALESCE((SELECT JSON_ARRAYAGG(t.v) FROM JSON_TABLE(" +
+				"CAST(? AS JSON), '$[*]' COLUMNS(v JSON PATH '$')) AS t WHERE NOT JSON_CONTAINS(" + current +
+				", t.v)), JSON_ARRAY()))"
+		}
+		return "COALESCE((SELECT JSON_ARRAYAGG(t.v) FROM JSON_TABLE(" + current +
+			", '$[*]' COLUMNS(v JSON PATH '$')) AS t WHERE NOT JSON_CONTAINS(CAST(? AS JSON), t.v)), JSON_ARRAY())"
+	default: // SQLite
+		current := "json_each(COALESCE(" + field + ", '[]'))"
+		if add {
+			return "(SELECT json_group_array(value) FROM (SELECT value FROM " + current +
+				" UNION ALL SELECT value FROM json_each(?) WHERE value NOT IN (SELECT value FROM " + current + ")))"
+		}
+		return "(SELECT json_group_array(value) FROM " + current +
+			" WHERE value NOT IN (SELECT value FROM json_each(?)))"
+	}
+}
+
+// updateArrayFieldWithMongo will add ($addToSet) or remove ($pull) the values of the array field
+func (c *Client) updateArrayFieldWithMongo(
+	ctx context.Context,
+	model interface{},
+	field string,
+	values []string,
+	add bool,
+) (err error) {
+	collectionName := GetModelTableName(model)
+	if collectionName == nil {
+		return ErrUnknownCollection
+	}
+
+	// Set the collection
+	collection := c.getMongoWriteCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	)
+
+	var primaryKey map[string]interface{}
+	if primaryKey, err = c.getModelPrimaryKey(model); err != nil {
+		return err
+	}
+
+	update := bson.M{conditionPull: bson.M{field: bson.M{conditionIn: values}}}
+	if add {
+		update = bson.M{conditionAddToSet: bson.M{field: bson.M{conditionEach: values}}}
+	}
+
+	c.DebugLog(ctx, fmt.Sprintf(logLine, "update", *collectionName, update))
+
+	if _, err = collection.UpdateOne(ctx, primaryKey, update); err != nil {
+		c.DebugLog(ctx, fmt.Sprintf(logErrorLine, "error", *collectionName, err, update))
+	}
+
+	return
+}
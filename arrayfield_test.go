@@ -0,0 +1,94 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testArrayModel is a model with a JSON array column
+type testArrayModel struct {
+	ID         string `gorm:"primaryKey"`
+	FieldInIDs string `gorm:"column:field_in_ids"`
+}
+
+// TestClient_AppendToArrayField will test the methods AppendToArrayField() and RemoveFromArrayField()
+func TestClient_AppendToArrayField(t *testing.T) {
+	t.Run("not an array field", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite, fields: &fieldConfig{}}}
+		require.ErrorIs(t, client.AppendToArrayField(context.Background(), &testArrayModel{ID: "array"},
+			"field_in_ids", "a"), ErrNotArrayField)
+		require.ErrorIs(t, client.RemoveFromArrayField(context.Background(), &testArrayModel{ID: "array"},
+			"field_in_ids", "a"), ErrNotArrayField)
+	})
+
+	t.Run("no values", func(t *testing.T) {
+		client := &Client{options: &clientOptions{
+			engine: SQLite, fields: &fieldConfig{arrayFields: []string{"field_in_ids"}},
+		}}
+		require.ErrorIs(t, client.AppendToArrayField(context.Background(), &testArrayModel{ID: "array"},
+			"field_in_ids"), ErrNoFieldsToUpdate)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{
+			engine: Empty, fields: &fieldConfig{arrayFields: []string{"field_in_ids"}},
+		}}
+		require.ErrorIs(t, client.AppendToArrayField(context.Background(), &testArrayModel{ID: "array"},
+			"field_in_ids", "a"), ErrUnsupportedEngine)
+	})
+
+	t.Run("[sqlite] append and remove", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithAutoMigrate(&testArrayModel{}),
+			WithCustomFields([]string{"field_in_ids"}, nil))
+		defer deferFunc()
+		require.NoError(t, client.ExecuteContext(ctx, "INSERT INTO test_array_models (id) VALUES (?)", "array-1").Error)
+
+		getValues := func() []string {
+			model := &testArrayModel{}
+			require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "array-1"},
+				defaultDatabaseMaxTimeout, false))
+			var values []string
+			require.NoError(t, json.Unmarshal([]byte(model.FieldInIDs), &values))
+			return values
+		}
+
+		model := &testArrayModel{ID: "array-1"}
+		require.NoError(t, client.AppendToArrayField(ctx, model, "field_in_ids", "a", "b", "a"))
+		assert.Equal(t, []string{"a", "b"}, getValues())
+
+		// Existing values are skipped
+		require.NoError(t, client.AppendToArrayField(ctx, model, "field_in_ids", "b", "c"))
+		assert.Equal(t, []string{"a", "b", "c"}, getValues())
+
+		// The record can be found using the array condition
+		found := &testArrayModel{}
+		require.NoError(t, client.GetModel(ctx, found, map[string]interface{}{"field_in_ids": "c"},
+			defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "array-1", found.ID)
+
+		require.NoError(t, client.RemoveFromArrayField(ctx, model, "field_in_ids", "a", "c", "missing"))
+		assert.Equal(t, []string{"b"}, getValues())
+
+		require.NoError(t, client.RemoveFromArrayField(ctx, model, "field_in_ids", "b"))
+		assert.Empty(t, getValues())
+	})
+}
+
+// TestGetArrayFieldExpression will test the method getArrayFieldExpression()
+func TestGetArrayFieldExpression(t *testing.T) {
+	for _, engine := range []Engine{MySQL, PostgreSQL, SQLite} {
+		for _, add := range []bool{true, false} {
+			expression := getArrayFieldExpression(engine, "field_in_ids", add)
+			assert.Contains(t, expression, "field_in_ids")
+			assert.Equal(t, 1, strings.Count(expression, "?"), expression)
+		}
+	}
+	assert.Contains(t, getArrayFieldExpression(PostgreSQL, "field_in_ids", true), "||")
+	assert.Contains(t, getArrayFieldExpression(MySQL, "field_in_ids", true), "JSON_MERGE_PRESERVE")
+}
@@ -0,0 +1,81 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BatchOps allow functional options to be supplied to CreateInBatches
+type BatchOps func(b *batchOptions)
+
+// batchOptions holds the configuration for a batch insert
+type batchOptions struct {
+	skipDuplicates bool // Skip records that already exist (duplicate key) instead of failing
+	unordered      bool // Insert without ordering (MongoDB continues after a failed insert)
+}
+
+// WithUnorderedBatch will insert the batch without ordering (MongoDB)
+//
+// MongoDB attempts all inserts even if one fails (all errors are returned), SQL engines ignore this option
+func WithUnorderedBatch() BatchOps {
+	return func(b *batchOptions) {
+		b.unordered = true
+	}
+}
+
+// WithSkipDuplicates will skip records that already exist (duplicate key) instead of failing the batch
+//
+// Useful for idempotent re-runs of ingestion jobs
+// MongoDB: E11000 errors are ignored, SQL: ON CONFLICT DO NOTHING
+func WithSkipDuplicates() BatchOps {
+	return func(b *batchOptions) {
+		b.skipDuplicates = true
+	}
+}
+
+// getBatchOptions will return the batch options
+func getBatchOptions(opts ...BatchOps) *batchOptions {
+	b := &batchOptions{}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// bulkInsertMongo will insert the models using a bulk write
+//
+// When skipping duplicates with an ordered batch, the inserts resume after each duplicate
+func bulkInsertMongo(ctx context.Context, collection *mongo.Collection, models []mongo.WriteModel,
+	batch *batchOptions) error {
+
+	bulkOptions := options.BulkWrite().SetOrdered(!batch.unordered)
+	for len(models) > 0 {
+		_, err := collection.BulkWrite(ctx, models, bulkOptions)
+		if err == nil || !batch.skipDuplicates {
+			return err
+		}
+
+		// Only duplicate key errors can be skipped
+		var bulkErr mongo.BulkWriteException
+		if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+			return err
+		}
+		for _, writeErr := range bulkErr.WriteErrors {
+			if !mongo.IsDuplicateKeyError(writeErr) {
+				return err
+			}
+		}
+
+		// Unordered batches attempted all inserts
+		if batch.unordered {
+			return nil
+		}
+
+		// Resume after the duplicate (ordered batches stop at the first error)
+		models = models[bulkErr.WriteErrors[len(bulkErr.WriteErrors)-1].Index+1:]
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetBatchOptions will test the method getBatchOptions()
+func TestGetBatchOptions(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, &batchOptions{}, getBatchOptions())
+	assert.Equal(t, &batchOptions{unordered: true}, getBatchOptions(WithUnorderedBatch()))
+	assert.Equal(t, &batchOptions{skipDuplicates: true, unordered: true},
+		getBatchOptions(WithUnorderedBatch(), WithSkipDuplicates()))
+}
+
+// TestClient_CreateInBatches will test the method CreateInBatches()
+func TestClient_CreateInBatches(t *testing.T) {
+	records := []*testSQLModel{
+		{ID: "batch-1", Name: "a"},
+		{ID: "batch-2", Name: "b"},
+		{ID: "batch-3", Name: "c"},
+	}
+
+	t.Run("duplicates fail by default", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "batch-2", Name: "existing"})
+
+		require.Error(t, client.CreateInBatches(ctx, records, 2))
+	})
+
+	t.Run("duplicates are skipped", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "batch-2", Name: "existing"})
+
+		require.NoError(t, client.CreateInBatches(ctx, records, 2, WithSkipDuplicates()))
+
+		// Re-running is idempotent
+		require.NoError(t, client.CreateInBatches(ctx, records, 2, WithSkipDuplicates()))
+
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(ctx, &models, nil, &QueryParams{OrderByField: sqlIDField}, nil, defaultDatabaseMaxTimeout))
+		require.Len(t, models, 3)
+		assert.Equal(t, "existing", models[1].Name)
+	})
+}
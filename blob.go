@@ -0,0 +1,264 @@
+package datastore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+)
+
+// Blob related settings
+const (
+	blobBucketName       = "blobs"    // GridFS bucket name for blobs (MongoDB)
+	defaultBlobChunkSize = 255 * 1024 // Size of each stored chunk (same as the GridFS default)
+)
+
+// ErrBlobReplaced is when the blob was replaced (or deleted) while it was read
+var ErrBlobReplaced = errors.New("blob was replaced or deleted while reading")
+
+// blobVersion is the current version of a blob (SQL databases)
+//
+// A new version of the chunks is stored for each PutBlob, the readers only read the chunks of the version found
+// when the reader was opened (the chunks of an old and a new blob are never mixed)
+type blobVersion struct {
+	Name    string `gorm:"type:varchar(255);primaryKey"`
+	Version string `gorm:"type:char(32)"`
+	Chunks  int
+}
+
+// blobChunk is a single chunk of a blob version (SQL databases)
+//
+// Stored as BYTEA on PostgreSQL, LONGBLOB on MySQL (size is over 16MB) and BLOB on SQLite
+type blobChunk struct {
+	Name    string `gorm:"type:varchar(255);primaryKey"`
+	Version string `gorm:"type:char(32);primaryKey"`
+	Chunk   int    `gorm:"primaryKey;autoIncrement:false"`
+	Data    []byte `gorm:"size:16777217"`
+}
+
+// PutBlob will store a large binary payload under the given name (replacing any existing blob with the name)
+//
+// The payload is streamed in chunks so it is never fully loaded into memory: GridFS on MongoDB, and a chunk
+// table on all the SQL databases (PostgreSQL large objects and SQLite incremental blob I/O are not used)
+func (c *Client) PutBlob(ctx context.Context, name string, reader io.Reader) error {
+	if c.Engine() == MongoDB {
+		return c.putBlobWithMongo(ctx, name, reader)
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	// Create the blob tables if needed
+	db := c.options.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&blobVersion{}) || !db.Migrator().HasTable(&blobChunk{}) {
+		if err := db.AutoMigrate(&blobVersion{}, &blobChunk{}); err != nil {
+			return err
+		}
+	}
+
+	// New version of the chunks
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	version := hex.EncodeToString(id)
+
+	// Store the new version, then switch the blob to it and remove the old versions
+	return db.Transaction(func(tx *gorm.DB) error {
+		buffer := make([]byte, defaultBlobChunkSize)
+		chunks := 0
+		for {
+			n, err := io.ReadFull(reader, buffer)
+			if errors.Is(err, io.EOF) && chunks > 0 {
+				break
+			} else if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return err
+			}
+
+			// Always store the first chunk (even if empty)
+			if createErr := tx.Create(&blobChunk{
+				Name:    name,
+				Version: version,
+				Chunk:   chunks,
+				Data:    buffer[:n],
+			}).Error; createErr != nil {
+				return createErr
+			}
+			chunks++
+			if err != nil {
+				break
+			}
+		}
+
+		if err := tx.Where("name = ?", name).Delete(&blobVersion{}).Error; err != nil {
+			return err
+		} else if err = tx.Create(&blobVersion{Name: name, Version: version, Chunks: chunks}).Error; err != nil {
+			return err
+		}
+		return tx.Where("name = ? AND version <> ?", name, version).Delete(&blobChunk{}).Error
+	})
+}
+
+// GetBlobReader will return a reader for the blob stored under the given name
+//
+// Chunks are loaded as they are read, the reader must be closed when finished
+// SQL databases: the reader returns ErrBlobReplaced if the blob is replaced (or deleted) before it is fully read
+func (c *Client) GetBlobReader(ctx context.Context, name string) (io.ReadCloser, error) {
+	if c.Engine() == MongoDB {
+		return c.getBlobReaderWithMongo(ctx, name)
+	} else if !IsSQLEngine(c.Engine()) {
+		return nil, ErrUnsupportedEngine
+	}
+
+	// Get the current version of the blob
+	db := c.options.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&blobVersion{}) {
+		return nil, ErrNoResults
+	}
+	var versions []blobVersion
+	if err := db.Where("name = ?", name).Limit(1).Find(&versions).Error; err != nil {
+		return nil, err
+	} else if len(versions) == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &sqlBlobReader{db: db, version: versions[0]}, nil
+}
+
+// DeleteBlob will delete the blob stored under the given name
+func (c *Client) DeleteBlob(ctx context.Context, name string) error {
+	if c.Engine() == MongoDB {
+		bucket, err := c.getBlobBucket(ctx)
+		if err != nil {
+			return err
+		}
+		return deleteMongoBlob(ctx, bucket, name)
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	db := c.options.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&blobVersion{}) {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("name = ?", name).Delete(&blobVersion{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("name = ?", name).Delete(&blobChunk{}).Error
+	})
+}
+
+// sqlBlobReader reads a version of a blob one chunk at a time
+type sqlBlobReader struct {
+	buffer  []byte
+	chunk   int
+	db      *gorm.DB
+	done    bool
+	version blobVersion
+}
+
+// Read will read from the current chunk (loading the next chunk of the version when needed)
+func (r *sqlBlobReader) Read(p []byte) (int, error) {
+	for len(r.buffer) == 0 {
+		if r.done || r.chunk >= r.version.Chunks {
+			r.done = true
+			return 0, io.EOF
+		}
+		var chunks []blobChunk
+		if err := r.db.Where(
+			"name = ? AND version = ? AND chunk = ?", r.version.Name, r.version.Version, r.chunk,
+		).Limit(1).Find(&chunks).Error; err != nil {
+			return 0, err
+		} else if len(chunks) == 0 {
+			return 0, ErrBlobReplaced
+		}
+		r.buffer = chunks[0].Data
+		r.chunk++
+	}
+
+	n := copy(p, r.buffer)
+	r.buffer = r.buffer[n:]
+	return n, nil
+}
+
+// Close will close the reader
+func (r *sqlBlobReader) Close() error {
+	r.done = true
+	r.buffer = nil
+	return nil
+}
+
+// getBlobBucket will return the GridFS bucket for blobs (deadline is set from the context)
+func (c *Client) getBlobBucket(ctx context.Context) (*gridfs.Bucket, error) {
+	bucket, err := gridfs.NewBucket(
+		c.options.mongoDB,
+		options.GridFSBucket().SetName(setPrefix(c.options.mongoDBConfig.TablePrefix, blobBucketName)).
+			SetChunkSizeBytes(defaultBlobChunkSize),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = bucket.SetWriteDeadline(deadline); err != nil {
+			return nil, err
+		}
+		if err = bucket.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	return bucket, nil
+}
+
+// putBlobWithMongo will store the blob using GridFS
+func (c *Client) putBlobWithMongo(ctx context.Context, name string, reader io.Reader) error {
+	bucket, err := c.getBlobBucket(ctx)
+	if err != nil {
+		return err
+	}
+	if err = deleteMongoBlob(ctx, bucket, name); err != nil {
+		return err
+	}
+	_, err = bucket.UploadFromStream(name, reader)
+	return err
+}
+
+// getBlobReaderWithMongo will return a GridFS download stream for the blob
+func (c *Client) getBlobReaderWithMongo(ctx context.Context, name string) (io.ReadCloser, error) {
+	bucket, err := c.getBlobBucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := bucket.OpenDownloadStreamByName(name)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, ErrNoResults
+	} else if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// deleteMongoBlob will delete all GridFS files with the given name
+func deleteMongoBlob(ctx context.Context, bucket *gridfs.Bucket, name string) error {
+	cursor, err := bucket.FindContext(ctx, bson.M{"filename": name})
+	if err != nil {
+		return err
+	}
+	var files []struct {
+		ID interface{} `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &files); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err = bucket.DeleteContext(ctx, file.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return err
+		}
+	}
+	return nil
+}
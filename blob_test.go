@@ -0,0 +1,104 @@
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_Blob will test the methods PutBlob(), GetBlobReader() and DeleteBlob()
+func TestClient_Blob(t *testing.T) {
+	t.Run("[sqlite] multiple chunks", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		payload := bytes.Repeat([]byte("0123456789"), defaultBlobChunkSize/4)
+		require.NoError(t, client.PutBlob(ctx, "large", bytes.NewReader(payload)))
+
+		reader, err := client.GetBlobReader(ctx, "large")
+		require.NoError(t, err)
+		defer func() {
+			_ = reader.Close()
+		}()
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, payload, data)
+	})
+
+	t.Run("[sqlite] replace and delete", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		require.NoError(t, client.PutBlob(ctx, "file", bytes.NewReader(bytes.Repeat([]byte("a"), defaultBlobChunkSize*2))))
+		require.NoError(t, client.PutBlob(ctx, "file", bytes.NewReader([]byte("short"))))
+
+		reader, err := client.GetBlobReader(ctx, "file")
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("short"), data)
+
+		require.NoError(t, client.DeleteBlob(ctx, "file"))
+		_, err = client.GetBlobReader(ctx, "file")
+		require.ErrorIs(t, err, ErrNoResults)
+	})
+
+	t.Run("[sqlite] replaced while reading", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		require.NoError(t, client.PutBlob(ctx, "file", bytes.NewReader(bytes.Repeat([]byte("a"), defaultBlobChunkSize*2))))
+		reader, err := client.GetBlobReader(ctx, "file")
+		require.NoError(t, err)
+		defer func() {
+			_ = reader.Close()
+		}()
+
+		// The first chunk of the old version
+		data := make([]byte, defaultBlobChunkSize)
+		_, err = io.ReadFull(reader, data)
+		require.NoError(t, err)
+		assert.Equal(t, bytes.Repeat([]byte("a"), defaultBlobChunkSize), data)
+
+		// The chunks of the new version are not mixed in
+		require.NoError(t, client.PutBlob(ctx, "file", bytes.NewReader(bytes.Repeat([]byte("b"), defaultBlobChunkSize*2))))
+		_, err = io.ReadAll(reader)
+		require.ErrorIs(t, err, ErrBlobReplaced)
+
+		newReader, err := client.GetBlobReader(ctx, "file")
+		require.NoError(t, err)
+		data, err = io.ReadAll(newReader)
+		require.NoError(t, err)
+		assert.Equal(t, bytes.Repeat([]byte("b"), defaultBlobChunkSize*2), data)
+	})
+
+	t.Run("[sqlite] empty blob", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		require.NoError(t, client.PutBlob(ctx, "empty", bytes.NewReader(nil)))
+		reader, err := client.GetBlobReader(ctx, "empty")
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Empty(t, data)
+	})
+
+	t.Run("[sqlite] missing blob", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		_, err := client.GetBlobReader(ctx, "missing")
+		require.ErrorIs(t, err, ErrNoResults)
+		require.NoError(t, client.DeleteBlob(ctx, "missing"))
+	})
+}
@@ -0,0 +1,68 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTimeoutBudgetExhausted is when the total timeout budget has been used up (no time left for another attempt)
+var ErrTimeoutBudgetExhausted = errors.New("timeout budget exhausted")
+
+// timeoutBudgetKey is the context key for a timeout budget
+type timeoutBudgetKey struct{}
+
+// WithTimeoutBudget will return a context with a total timeout budget
+//
+// The budget is shared by all attempts (retries) of an operation rather than applied per attempt,
+// each attempt gets an even share of the remaining budget (see: newAttemptCtx)
+func WithTimeoutBudget(ctx context.Context, total time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, total)
+	return context.WithValue(ctx, timeoutBudgetKey{}, true), cancel
+}
+
+// hasTimeoutBudget will return true if the context has a timeout budget
+func hasTimeoutBudget(ctx context.Context) bool {
+	budget, ok := ctx.Value(timeoutBudgetKey{}).(bool)
+	return ok && budget
+}
+
+// getBudgetTimeout will return the timeout to use, limited by the remaining time on the context (if any)
+//
+// Returns ErrTimeoutBudgetExhausted if the budget is used up (see: WithTimeoutBudget), and
+// context.DeadlineExceeded if the deadline of a context without a budget has passed
+func getBudgetTimeout(ctx context.Context, timeout time.Duration) (time.Duration, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout, nil
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		if hasTimeoutBudget(ctx) {
+			return 0, ErrTimeoutBudgetExhausted
+		}
+		return 0, context.DeadlineExceeded
+	}
+	if timeout > 0 && timeout < remaining {
+		return timeout, nil
+	}
+	return remaining, nil
+}
+
+// newAttemptCtx will return a context for a single attempt, using an even share of the remaining budget
+//
+// attemptsLeft includes the current attempt, contexts without a budget are returned as-is
+func newAttemptCtx(ctx context.Context, attemptsLeft int) (context.Context, context.CancelFunc, error) {
+	if !hasTimeoutBudget(ctx) {
+		return ctx, func() {}, nil
+	}
+	remaining, err := getBudgetTimeout(ctx, 0)
+	if err != nil {
+		return ctx, func() {}, err
+	}
+	if attemptsLeft > 1 {
+		remaining /= time.Duration(attemptsLeft)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, remaining)
+	return attemptCtx, cancel, nil
+}
@@ -0,0 +1,147 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetBudgetTimeout will test the method getBudgetTimeout()
+func TestGetBudgetTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no deadline", func(t *testing.T) {
+		timeout, err := getBudgetTimeout(context.Background(), 5*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, timeout)
+	})
+
+	t.Run("timeout is less than the budget", func(t *testing.T) {
+		ctx, cancel := WithTimeoutBudget(context.Background(), time.Minute)
+		defer cancel()
+		timeout, err := getBudgetTimeout(ctx, 5*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, timeout)
+	})
+
+	t.Run("timeout is more than the budget", func(t *testing.T) {
+		ctx, cancel := WithTimeoutBudget(context.Background(), time.Second)
+		defer cancel()
+		timeout, err := getBudgetTimeout(ctx, time.Minute)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, timeout, time.Second)
+		assert.Greater(t, timeout, time.Duration(0))
+	})
+
+	t.Run("zero timeout uses the remaining budget", func(t *testing.T) {
+		ctx, cancel := WithTimeoutBudget(context.Background(), time.Second)
+		defer cancel()
+		timeout, err := getBudgetTimeout(ctx, 0)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, timeout, time.Second)
+	})
+
+	t.Run("budget exhausted", func(t *testing.T) {
+		ctx, cancel := WithTimeoutBudget(context.Background(), time.Nanosecond)
+		defer cancel()
+		time.Sleep(time.Millisecond)
+		timeout, err := getBudgetTimeout(ctx, time.Minute)
+		require.ErrorIs(t, err, ErrTimeoutBudgetExhausted)
+		assert.Equal(t, time.Duration(0), timeout)
+	})
+
+	t.Run("deadline passed without a budget", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+		time.Sleep(time.Millisecond)
+		_, err := getBudgetTimeout(ctx, time.Minute)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NotErrorIs(t, err, ErrTimeoutBudgetExhausted)
+	})
+}
+
+// TestNewAttemptCtx will test the method newAttemptCtx()
+func TestNewAttemptCtx(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no budget", func(t *testing.T) {
+		ctx, cancel, err := newAttemptCtx(context.Background(), 3)
+		defer cancel()
+		require.NoError(t, err)
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+	})
+
+	t.Run("deadline without a budget is unchanged", func(t *testing.T) {
+		parent, parentCancel := context.WithTimeout(context.Background(), time.Minute)
+		defer parentCancel()
+		ctx, cancel, err := newAttemptCtx(parent, 3)
+		defer cancel()
+		require.NoError(t, err)
+		assert.Equal(t, parent, ctx)
+	})
+
+	t.Run("budget is split across the remaining attempts", func(t *testing.T) {
+		budget, budgetCancel := WithTimeoutBudget(context.Background(), 3*time.Second)
+		defer budgetCancel()
+		ctx, cancel, err := newAttemptCtx(budget, 3)
+		defer cancel()
+		require.NoError(t, err)
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.LessOrEqual(t, time.Until(deadline), time.Second)
+		assert.Greater(t, time.Until(deadline), 900*time.Millisecond)
+	})
+
+	t.Run("last attempt gets the full remaining budget", func(t *testing.T) {
+		budget, budgetCancel := WithTimeoutBudget(context.Background(), 3*time.Second)
+		defer budgetCancel()
+		ctx, cancel, err := newAttemptCtx(budget, 1)
+		defer cancel()
+		require.NoError(t, err)
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.Greater(t, time.Until(deadline), 2900*time.Millisecond)
+	})
+
+	t.Run("budget exhausted", func(t *testing.T) {
+		budget, budgetCancel := WithTimeoutBudget(context.Background(), time.Nanosecond)
+		defer budgetCancel()
+		time.Sleep(time.Millisecond)
+		_, cancel, err := newAttemptCtx(budget, 2)
+		defer cancel()
+		require.ErrorIs(t, err, ErrTimeoutBudgetExhausted)
+	})
+}
+
+// TestCreateCtx_Budget will test that createCtx() respects the timeout budget
+func TestCreateCtx_Budget(t *testing.T) {
+	t.Run("query timeout is limited by the budget", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		budget, cancel := WithTimeoutBudget(ctx, time.Nanosecond)
+		defer cancel()
+		time.Sleep(time.Millisecond)
+
+		_, err := client.GetModelCount(budget, &testSQLModel{}, nil, time.Minute)
+		require.ErrorIs(t, err, ErrTimeoutBudgetExhausted)
+	})
+
+	t.Run("expired context without a budget", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		expired, cancel := context.WithTimeout(ctx, time.Nanosecond)
+		defer cancel()
+		time.Sleep(time.Millisecond)
+
+		_, err := client.GetModelCount(expired, &testSQLModel{}, nil, time.Minute)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,273 @@
+package datastore
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Read cache settings
+const (
+	cacheGenerationKey = "gen"        // Key (suffix) of the table generation
+	cacheKeyPrefix     = "datastore:" // Prefix of all the cache keys
+	cacheOpGetModel    = "model"      // Cached GetModel results
+	cacheOpModelCount  = "count"      // Cached GetModelCount results
+	defaultCacheTTL    = time.Minute  // Default TTL of the cached results
+	defaultCacheSize   = 10000        // Default max entries of the in-process cache
+)
+
+// CacheInterface is the cache of the read paths (see: WithCache), IE: Redis or an in-process LRU (NewLRUCache)
+type CacheInterface interface {
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error // Zero TTL does not expire
+}
+
+// readCache is the cache of the read paths and the TTL of the results
+type readCache struct {
+	cache CacheInterface
+	ttl   time.Duration
+}
+
+// getCacheKey will return the cache key of the read (empty if the read is not cached)
+//
+// Reads from the source database (forced, causal sessions or row locks), read snapshots (see: ReadSnapshot)
+// and masked readers are not cached, neither are the models that do not round-trip as JSON (see: isCacheableModel)
+func (c *Client) getCacheKey(ctx context.Context, op string, model interface{}, conditions map[string]interface{},
+	forceWriteDB bool,
+) string {
+	if c.options.cache == nil || forceWriteDB || isCausalSession(ctx) || IsMaskedReader(ctx) ||
+		getRowLock(ctx) != nil || getReadTx(ctx) != nil {
+		return ""
+	} else if op == cacheOpGetModel && !isCacheableModel(model) {
+		return ""
+	}
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return ""
+	}
+	raw, err := json.Marshal(conditions)
+	if err != nil {
+		return ""
+	}
+	var generation string
+	if generation, err = c.getCacheGeneration(ctx, tableName); err != nil {
+		c.DebugLog(ctx, "failed to get the cache generation: "+err.Error())
+		return ""
+	}
+
+	// The conditions and model type of the read (IE: same table using a different struct)
+	hash := sha256.Sum256(append([]byte(fmt.Sprintf("%T:", model)), raw...))
+	return cacheKeyPrefix + tableName + ":" + generation + ":" + op + ":" + hex.EncodeToString(hash[:])
+}
+
+// getCacheGeneration will return the current generation of the table (a new one if missing)
+func (c *Client) getCacheGeneration(ctx context.Context, tableName string) (string, error) {
+	key := cacheKeyPrefix + tableName + ":" + cacheGenerationKey
+	value, found, err := c.options.cache.cache.Get(ctx, key)
+	if err != nil || found {
+		return string(value), err
+	}
+	return c.newCacheGeneration(ctx, tableName)
+}
+
+// newCacheGeneration will start a new generation of the table, the cached results of the previous
+// generations are no longer used (and expire)
+func (c *Client) newCacheGeneration(ctx context.Context, tableName string) (string, error) {
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	generation := hex.EncodeToString(token)
+	return generation, c.options.cache.cache.Set(ctx, cacheKeyPrefix+tableName+":"+cacheGenerationKey,
+		[]byte(generation), 0)
+}
+
+// invalidateCache will invalidate the cached reads of the model's table (see: WithCache)
+func (c *Client) invalidateCache(ctx context.Context, model interface{}) {
+	if c.options.cache == nil {
+		return
+	}
+	tableName, err := c.getModelTableName(model)
+	if err == nil {
+		_, err = c.newCacheGeneration(ctx, tableName)
+	}
+	if err != nil {
+		c.warnLog(ctx, "failed to invalidate the cache: "+err.Error())
+	}
+}
+
+// invalidateCacheTable will invalidate the cached reads of the table (see: WithCache)
+func (c *Client) invalidateCacheTable(ctx context.Context, tableName string) {
+	if c.options.cache == nil {
+		return
+	}
+	if _, err := c.newCacheGeneration(ctx, tableName); err != nil {
+		c.warnLog(ctx, "failed to invalidate the cache: "+err.Error())
+	}
+}
+
+// queueCacheInvalidation will invalidate the cached reads of the model's table after the transaction is committed
+func (c *Client) queueCacheInvalidation(ctx context.Context, tx *Transaction, model interface{}) {
+	if c.options.cache != nil {
+		tx.OnCommit(func() {
+			c.invalidateCache(ctx, model)
+		})
+	}
+}
+
+// loadCache will decode the cached result (false if not found)
+func (c *Client) loadCache(ctx context.Context, key string, result interface{}) bool {
+	if len(key) == 0 {
+		return false
+	}
+	value, found, err := c.options.cache.cache.Get(ctx, key)
+	if err != nil {
+		c.DebugLog(ctx, "failed to get the cached result: "+err.Error())
+		return false
+	}
+	return found && json.Unmarshal(value, result) == nil
+}
+
+// storeCache will cache the result (failures are only logged)
+func (c *Client) storeCache(ctx context.Context, key string, result interface{}) {
+	if len(key) == 0 {
+		return
+	}
+	value, err := json.Marshal(result)
+	if err == nil {
+		err = c.options.cache.cache.Set(ctx, key, value, c.options.cache.ttl)
+	}
+	if err != nil {
+		c.DebugLog(ctx, "failed to cache the result: "+err.Error())
+	}
+}
+
+// cacheableModels are the model types that round-trip as JSON (by type), the number of entries is bounded by
+// the model types of the program
+var cacheableModels sync.Map
+
+// isCacheableModel will return true if the model round-trips as JSON (the cached results are stored as JSON)
+//
+// Models with unexported fields or fields excluded from JSON (json:"-") would be loaded from the cache with
+// zero values, their reads are not cached
+func isCacheableModel(model interface{}) bool {
+	modelType := reflect.TypeOf(model)
+	if modelType == nil {
+		return false
+	}
+	if cacheable, ok := cacheableModels.Load(modelType); ok {
+		return cacheable.(bool)
+	}
+	cacheable := isJSONRoundTripType(modelType, make(map[reflect.Type]bool))
+	cacheableModels.Store(modelType, cacheable)
+	return cacheable
+}
+
+// isJSONRoundTripType will return true if all the fields of the type are encoded (and decoded) as JSON
+func isJSONRoundTripType(valueType reflect.Type, visited map[reflect.Type]bool) bool {
+	for valueType.Kind() == reflect.Ptr || valueType.Kind() == reflect.Slice ||
+		valueType.Kind() == reflect.Array || valueType.Kind() == reflect.Map {
+		valueType = valueType.Elem()
+	}
+	if valueType.Kind() != reflect.Struct || visited[valueType] {
+		return true
+	}
+	visited[valueType] = true
+
+	// Custom encoding (IE: time.Time, sql.NullString)
+	pointerType := reflect.PointerTo(valueType)
+	if (valueType.Implements(jsonMarshalerType) || pointerType.Implements(jsonMarshalerType)) &&
+		pointerType.Implements(jsonUnmarshalerType) {
+		return true
+	}
+
+	for index := 0; index < valueType.NumField(); index++ {
+		field := valueType.Field(index)
+		if field.Tag.Get("json") == "-" || (!field.IsExported() && !field.Anonymous) {
+			return false
+		} else if !isJSONRoundTripType(field.Type, visited) {
+			return false
+		}
+	}
+	return true
+}
+
+// JSON encoding interfaces (see: isJSONRoundTripType)
+var (
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// lruCache is an in-process LRU cache (see: NewLRUCache)
+type lruCache struct {
+	entries    map[string]*list.Element // Entries (by key)
+	maxEntries int                      // Max entries (the least recently used are evicted)
+	mu         sync.Mutex               // Lock for the entries and order
+	order      *list.List               // Most recently used first
+}
+
+// lruEntry is an entry of the in-process LRU cache
+type lruEntry struct {
+	expires time.Time // Time the entry expires (zero does not expire)
+	key     string
+	value   []byte
+}
+
+// NewLRUCache will return an in-process LRU cache with a max number of entries (default: 10,000)
+func NewLRUCache(maxEntries int) CacheInterface {
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheSize
+	}
+	return &lruCache{
+		entries:    make(map[string]*list.Element),
+		maxEntries: maxEntries,
+		order:      list.New(),
+	}
+}
+
+// Get will return the value (false if not found or expired)
+func (l *lruCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		l.order.Remove(element)
+		delete(l.entries, key)
+		return nil, false, nil
+	}
+	l.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+// Set will store the value (evicting the least recently used entry if full)
+func (l *lruCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry := &lruEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	if element, ok := l.entries[key]; ok {
+		element.Value = entry
+		l.order.MoveToFront(element)
+		return nil
+	}
+	l.entries[key] = l.order.PushFront(entry)
+	if l.order.Len() > l.maxEntries {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
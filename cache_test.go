@@ -0,0 +1,185 @@
+package datastore
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewLRUCache will test the method NewLRUCache()
+func TestNewLRUCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("least recently used entries are evicted", func(t *testing.T) {
+		cache := NewLRUCache(2)
+		require.NoError(t, cache.Set(ctx, "a", []byte("1"), 0))
+		require.NoError(t, cache.Set(ctx, "b", []byte("2"), 0))
+		_, found, _ := cache.Get(ctx, "a")
+		assert.True(t, found)
+		require.NoError(t, cache.Set(ctx, "c", []byte("3"), 0))
+
+		_, found, _ = cache.Get(ctx, "b")
+		assert.False(t, found)
+		value, found, err := cache.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, []byte("1"), value)
+	})
+
+	t.Run("expired entries", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		require.NoError(t, cache.Set(ctx, "a", []byte("1"), time.Millisecond))
+		time.Sleep(5 * time.Millisecond)
+		_, found, _ := cache.Get(ctx, "a")
+		assert.False(t, found)
+	})
+}
+
+// TestWithCache will test the read cache (see: WithCache)
+func TestWithCache(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		options := &clientOptions{}
+		WithCache(nil, time.Hour)(options)
+		assert.Nil(t, options.cache)
+		WithCache(NewLRUCache(0), 0)(options)
+		require.NotNil(t, options.cache)
+		assert.Equal(t, defaultCacheTTL, options.cache.ttl)
+	})
+
+	t.Run("[sqlite] cached reads are invalidated by writes", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithCache(NewLRUCache(0), time.Hour))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "cache-1", Name: "a", Amount: 1})
+
+		getName := func(forceWriteDB bool) string {
+			model := &testSQLModel{}
+			require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "cache-1"},
+				defaultDatabaseMaxTimeout, forceWriteDB))
+			return model.Name
+		}
+		getCount := func() int64 {
+			count, err := client.GetModelCount(ctx, &testSQLModel{}, map[string]interface{}{"amount": 1},
+				defaultDatabaseMaxTimeout)
+			require.NoError(t, err)
+			return count
+		}
+		assert.Equal(t, "a", getName(false))
+		assert.Equal(t, int64(1), getCount())
+
+		// Not visible to the cached reads (writes outside the datastore methods)
+		require.NoError(t, client.Execute("UPDATE "+testSQLTableName+" SET name = 'b', amount = 2").Error)
+		assert.Equal(t, "a", getName(false))
+		assert.Equal(t, int64(1), getCount())
+		assert.Equal(t, "b", getName(true))
+
+		// Saving invalidates the table
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "cache-2", Name: "c", Amount: 1})
+		assert.Equal(t, "b", getName(false))
+		assert.Equal(t, int64(1), getCount())
+
+		// Incrementing invalidates the table
+		require.NoError(t, client.Execute("UPDATE "+testSQLTableName+" SET name = 'd'").Error)
+		_, err := client.IncrementModel(ctx, &testSQLModel{ID: "cache-2"}, "amount", 1)
+		require.NoError(t, err)
+		assert.Equal(t, "d", getName(false))
+		assert.Equal(t, int64(0), getCount())
+
+		// Upserting invalidates the table
+		require.NoError(t, client.Execute("UPDATE "+testSQLTableName+" SET name = 'e'").Error)
+		require.NoError(t, client.UpsertModel(ctx, &testSQLModel{ID: "cache-3", Name: "f"}, nil, nil))
+		assert.Equal(t, "e", getName(false))
+	})
+
+	t.Run("[sqlite] rolled back writes do not invalidate", func(t *testing.T) {
+		ctx := context.Background()
+		cache := &testCountingCache{CacheInterface: NewLRUCache(0)}
+		client, deferFunc := testSQLiteClient(ctx, t, WithCache(cache, time.Hour))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "cache-1"})
+
+		sets := cache.sets
+		require.Error(t, client.NewTx(ctx, func(tx *Transaction) error {
+			require.NoError(t, client.UpdateModelFields(ctx, &testSQLModel{ID: "cache-1"},
+				map[string]interface{}{"name": "b"}, tx, false))
+			return assert.AnError
+		}))
+		assert.Equal(t, sets, cache.sets)
+
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.UpdateModelFields(ctx, &testSQLModel{ID: "cache-1"},
+				map[string]interface{}{"name": "b"}, tx, false)
+		}))
+		assert.Equal(t, sets+1, cache.sets)
+	})
+}
+
+// testCountingCache counts the cache writes
+type testCountingCache struct {
+	CacheInterface
+	sets int
+}
+
+// Set will count the write
+func (c *testCountingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.sets++
+	return c.CacheInterface.Set(ctx, key, value, ttl)
+}
+
+// Test_getCacheKey will test the method getCacheKey()
+func Test_getCacheKey(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t, WithCache(NewLRUCache(0), time.Hour))
+	defer deferFunc()
+	c := client.(*Client)
+
+	key := c.getCacheKey(ctx, cacheOpGetModel, &testSQLModel{}, map[string]interface{}{"id": "1"}, false)
+	assert.Contains(t, key, cacheKeyPrefix+testSQLTableName+":")
+	assert.Equal(t, key, c.getCacheKey(ctx, cacheOpGetModel, &testSQLModel{}, map[string]interface{}{"id": "1"}, false))
+	assert.NotEqual(t, key, c.getCacheKey(ctx, cacheOpGetModel, &testSQLModel{}, map[string]interface{}{"id": "2"}, false))
+	assert.NotEqual(t, key, c.getCacheKey(ctx, cacheOpModelCount, &testSQLModel{}, map[string]interface{}{"id": "1"}, false))
+
+	// Not cached
+	assert.Empty(t, c.getCacheKey(ctx, cacheOpGetModel, &testSQLModel{}, nil, true))
+	assert.Empty(t, c.getCacheKey(WithMaskedReader(ctx), cacheOpGetModel, &testSQLModel{}, nil, false))
+	assert.Empty(t, c.getCacheKey(ctx, cacheOpGetModel, &testSQLModel{},
+		map[string]interface{}{"id": func() string { return strconv.Itoa(1) }}, false))
+	assert.Empty(t, c.getCacheKey(context.WithValue(ctx, readTxKey{}, c.options.db), cacheOpGetModel,
+		&testSQLModel{}, nil, false))
+	assert.Empty(t, c.getCacheKey(ctx, cacheOpGetModel, &testUncacheableModel{}, nil, false))
+	assert.NotEmpty(t, c.getCacheKey(ctx, cacheOpModelCount, &testUncacheableModel{}, nil, false))
+}
+
+// testUncacheableModel is a model that does not round-trip as JSON
+type testUncacheableModel struct {
+	testSQLModel
+	Secret string `json:"-"`
+}
+
+// Test_isCacheableModel will test the method isCacheableModel()
+func Test_isCacheableModel(t *testing.T) {
+	type withUnexported struct {
+		Name  string `json:"name"`
+		value string
+	}
+	type withNested struct {
+		Items []*withUnexported `json:"items"`
+	}
+	type withTime struct {
+		CreatedAt time.Time  `json:"created_at"`
+		Parent    *withTime  `json:"parent"`
+		Children  []withTime `json:"children"`
+	}
+
+	assert.True(t, isCacheableModel(&testSQLModel{}))
+	assert.True(t, isCacheableModel(&[]*testSQLModel{}))
+	assert.True(t, isCacheableModel(&withTime{}))
+	assert.False(t, isCacheableModel(&testUncacheableModel{}))
+	assert.False(t, isCacheableModel(&withUnexported{value: "a"}))
+	assert.False(t, isCacheableModel(&withNested{}))
+	assert.False(t, isCacheableModel(nil))
+}
@@ -0,0 +1,155 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// CapturedQuery is a single query (SQL statement or Mongo command) recorded by CaptureQueries()
+type CapturedQuery struct {
+	Duration time.Duration `json:"duration"` // How long the query took to execute
+	Error    error         `json:"error"`    // Error returned from the query (if any)
+	Query    string        `json:"query"`    // SQL statement or Mongo command (JSON)
+	Rows     int64         `json:"rows"`     // Rows affected or returned
+}
+
+// queryCaptureKey is the context key for the query capture
+type queryCaptureKey struct{}
+
+// queryCapture is the session-scoped recorder used by CaptureQueries() and NewQueryScope()
+type queryCapture struct {
+	client  *Client          // Client that created the capture (used for repeated query detection)
+	mu      sync.Mutex       // Lock for the recorded queries
+	pending map[int64]string // Mongo commands that have started (by request id)
+	queries []CapturedQuery  // Recorded queries (in order)
+	record  bool             // Flag for recording the queries (false only tracks query shapes)
+	shapes  map[string]int   // Count of each query shape (see: detectRepeatedQuery)
+}
+
+// CaptureQueries will record all queries executed during fn using the given context
+//
+// All datastore calls inside fn must use the context given to fn, useful for
+// test assertions (IE: "this endpoint should issue exactly 2 queries")
+// Mongo commands are only captured on connections opened by this package (not an ExistingConnection)
+func (c *Client) CaptureQueries(ctx context.Context,
+	fn func(ctx context.Context) error) ([]CapturedQuery, error) {
+
+	capture := newQueryCapture(c, true)
+	err := fn(context.WithValue(ctx, queryCaptureKey{}, capture))
+	return capture.list(), err
+}
+
+// newQueryCapture will return a new query capture
+func newQueryCapture(client *Client, record bool) *queryCapture {
+	return &queryCapture{
+		client:  client,
+		pending: make(map[int64]string),
+		record:  record,
+		shapes:  make(map[string]int),
+	}
+}
+
+// getQueryCapture will return the query capture from the context (if found)
+func getQueryCapture(ctx context.Context) *queryCapture {
+	if ctx == nil {
+		return nil
+	}
+	if capture, ok := ctx.Value(queryCaptureKey{}).(*queryCapture); ok {
+		return capture
+	}
+	return nil
+}
+
+// add will record a query
+func (q *queryCapture) add(ctx context.Context, query CapturedQuery) {
+	q.mu.Lock()
+	if q.record {
+		q.queries = append(q.queries, query)
+	}
+	q.mu.Unlock()
+	q.detectRepeatedQuery(ctx, query.Query)
+}
+
+// list will return a copy of all recorded queries
+func (q *queryCapture) list() []CapturedQuery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queries := make([]CapturedQuery, len(q.queries))
+	copy(queries, q.queries)
+	return queries
+}
+
+// start will record a Mongo command that has started
+func (q *queryCapture) start(requestID int64, command string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending[requestID] = command
+}
+
+// finish will record a Mongo command that has finished
+func (q *queryCapture) finish(ctx context.Context, requestID int64, duration time.Duration, rows int64, err error) {
+	q.mu.Lock()
+	command, ok := q.pending[requestID]
+	delete(q.pending, requestID)
+	q.mu.Unlock()
+	if !ok {
+		return
+	}
+	q.add(ctx, CapturedQuery{
+		Duration: duration,
+		Error:    err,
+		Query:    command,
+		Rows:     rows,
+	})
+}
+
+// newQueryCaptureMonitor will return a Mongo command monitor that records commands for CaptureQueries()
+//
+// Failed commands are also checked for deadlocks (see: WithDeadlockDiagnostics)
+func newQueryCaptureMonitor(diagnostics *deadlockDiagnostics) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			if capture := getQueryCapture(ctx); capture != nil {
+				capture.start(evt.RequestID, evt.Command.String())
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			if capture := getQueryCapture(ctx); capture != nil {
+				capture.finish(ctx, evt.RequestID, evt.Duration, getMongoReplyRows(evt.Reply), nil)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			diagnostics.check(errors.New(evt.Failure))
+			if capture := getQueryCapture(ctx); capture != nil {
+				capture.finish(ctx, evt.RequestID, evt.Duration, 0, errors.New(evt.Failure))
+			}
+		},
+	}
+}
+
+// getMongoReplyRows will return the number of documents returned or affected from a Mongo command reply
+func getMongoReplyRows(reply bson.Raw) int64 {
+
+	// Cursor results (find, aggregate, getMore)
+	for _, batch := range []string{"firstBatch", "nextBatch"} {
+		if value, err := reply.LookupErr("cursor", batch); err == nil {
+			if values, valErr := value.Array().Values(); valErr == nil {
+				return int64(len(values))
+			}
+		}
+	}
+
+	// Write results (insert, update, delete)
+	if value, err := reply.LookupErr("n"); err == nil {
+		if n, ok := value.AsInt64OK(); ok {
+			return n
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,97 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestClient_CaptureQueries will test the method CaptureQueries()
+func TestClient_CaptureQueries(t *testing.T) {
+	t.Run("capture sql queries", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "capture-1", Name: "first"})
+
+		queries, err := client.CaptureQueries(ctx, func(ctx context.Context) error {
+			model := &testSQLModel{}
+			if err := client.GetModel(
+				ctx, model, map[string]interface{}{"name": "first"}, defaultDatabaseMaxTimeout, false,
+			); err != nil {
+				return err
+			}
+			_, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+			return err
+		})
+		require.NoError(t, err)
+		require.Len(t, queries, 2)
+		assert.Contains(t, queries[0].Query, "SELECT")
+		assert.Equal(t, int64(1), queries[0].Rows)
+		assert.Contains(t, queries[1].Query, "count(*)")
+	})
+
+	t.Run("queries outside the capture are ignored", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		queries, err := client.CaptureQueries(ctx, func(context.Context) error {
+			_, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+			return err
+		})
+		require.NoError(t, err)
+		assert.Empty(t, queries)
+	})
+
+	t.Run("error is returned", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		queries, err := client.CaptureQueries(ctx, func(ctx context.Context) error {
+			return client.GetModel(
+				ctx, &testSQLModel{}, map[string]interface{}{"name": "missing"}, defaultDatabaseMaxTimeout, false,
+			)
+		})
+		require.ErrorIs(t, err, ErrNoResults)
+		assert.Len(t, queries, 1)
+	})
+}
+
+// TestQueryCapture_Mongo will test the Mongo command recording
+func TestQueryCapture_Mongo(t *testing.T) {
+	t.Run("started and finished", func(t *testing.T) {
+		capture := newQueryCapture(nil, true)
+		capture.start(1, `{"find": "test"}`)
+		capture.finish(context.Background(), 1, time.Millisecond, 3, nil)
+		capture.finish(context.Background(), 2, time.Millisecond, 3, nil)
+
+		queries := capture.list()
+		require.Len(t, queries, 1)
+		assert.Equal(t, `{"find": "test"}`, queries[0].Query)
+		assert.Equal(t, int64(3), queries[0].Rows)
+		assert.Empty(t, capture.pending)
+	})
+
+	t.Run("reply rows", func(t *testing.T) {
+		reply, err := bson.Marshal(bson.D{{Key: "cursor", Value: bson.D{
+			{Key: "firstBatch", Value: bson.A{bson.D{}, bson.D{}}},
+		}}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), getMongoReplyRows(reply))
+
+		reply, err = bson.Marshal(bson.D{{Key: "n", Value: int32(5)}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), getMongoReplyRows(reply))
+
+		reply, err = bson.Marshal(bson.D{{Key: "ok", Value: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), getMongoReplyRows(reply))
+	})
+}
@@ -3,6 +3,8 @@ package datastore
 import (
 	"context"
 	"strings"
+	"sync"
+	"time"
 
 	zLogger "github.com/mrz1836/go-logger"
 	"github.com/newrelic/go-agent/v3/newrelic"
@@ -20,23 +22,65 @@ type (
 
 	// clientOptions holds all the configuration for the client
 	clientOptions struct {
-		autoMigrate     bool                        // Setting for Auto Migration of SQL tables
-		db              *gorm.DB                    // Database connection for Read-Only requests (can be same as Write)
-		debug           bool                        // Setting for global debugging
-		engine          Engine                      // Datastore engine (MySQL, PostgreSQL, SQLite)
-		fields          *fieldConfig                // Configuration for custom fields
-		logger          zLogger.GormLoggerInterface // Custom logger interface (standard interface)
-		loggerDB        gLogger.Interface           // Custom logger interface (for GORM)
-		migratedModels  []string                    // List of models (types) that have been migrated
-		migrateModels   []interface{}               // Models for migrations
-		mongoDB         *mongo.Database             // Database connection for a MongoDB datastore
-		mongoDBConfig   *MongoDBConfig              // Configuration for a MongoDB datastore
-		newRelicEnabled bool                        // If NewRelic is enabled (parent application)
-		sqlConfigs      []*SQLConfig                // Configuration for a MySQL or PostgreSQL datastore
-		sqLite          *SQLiteConfig               // Configuration for a SQLite datastore
-		tablePrefix     string                      // Model table prefix
+		analyzeAfterRows       int                          // Refresh the planner statistics after bulk loads of this many rows
+		autoMigrate            bool                         // Setting for Auto Migration of SQL tables
+		cache                  *readCache                   // Cache of the GetModel and GetModelCount results (see: WithCache)
+		cockroachDB            bool                         // PostgreSQL engine is a CockroachDB cluster (see: WithCockroachDB)
+		columnConverters       map[string]ColumnConverter   // Converters for scanned map results (by column name)
+		db                     *gorm.DB                     // Database connection for Read-Only requests (can be same as Write)
+		deadlockDiagnostics    *deadlockDiagnostics         // Captures engine diagnostics on deadlocks
+		dynamoDB               *dynamoDBStore               // Connection of a DynamoDB datastore
+		dynamoDBConfig         *DynamoDBConfig              // Configuration for a DynamoDB datastore
+		debug                  bool                         // Setting for global debugging
+		engine                 Engine                       // Datastore engine (MySQL, PostgreSQL, SQLite)
+		engineOptions          []string                     // Applied engine options (IE: WithSQLite), checked for conflicts
+		fields                 *fieldConfig                 // Configuration for custom fields
+		immutableModels        *immutableModels             // Registered immutable models and their cached records
+		indexHints             map[string]*IndexHint        // Vetted index hints (by name)
+		jsonIndexChecks        *jsonIndexChecks             // JSON conditions already checked for a missing index (debug only)
+		logger                 zLogger.GormLoggerInterface  // Custom logger interface (standard interface)
+		loggerDB               gLogger.Interface            // Custom logger interface (for GORM)
+		maintenanceWindow      *MaintenanceWindow           // Daily window for maintenance operations (nil is always allowed)
+		maskSpecs              map[string]MaskSpec          // Column masking for masked readers (by model name)
+		memory                 *memoryStore                 // In-process store of a Memory datastore (see: WithMemory)
+		metrics                MetricsRecorder              // Custom metrics recorder (result sizes)
+		migratedModels         []string                     // List of models (types) that have been migrated
+		migrateModels          []interface{}                // Models for migrations
+		modelDefaults          *modelDefaults               // Registered query defaults (see: RegisterModelDefaults)
+		modelEvents            *modelEvents                 // Registered model event handlers (see: SubscribeModelEvents)
+		mongoDB                *mongo.Database              // Database connection for a MongoDB datastore
+		mongoDBConfig          *MongoDBConfig               // Configuration for a MongoDB datastore
+		newRelicEnabled        bool                         // If NewRelic is enabled (parent application)
+		normalizeConditions    bool                         // Normalize the query conditions (see: NormalizeConditions)
+		onClose                CloseHook                    // Lifecycle hook run by Close() (before disconnecting)
+		onOpen                 OpenHook                     // Lifecycle hook run by NewClient() (after connecting)
+		replicas               *replicaPool                 // Read replicas of a MySQL or PostgreSQL datastore (see: UpdateReplicas)
+		queryComments          QueryCommentExtractor        // Context values added to every query as a comment (see: WithQueryComments)
+		queryKill              *queryKillConfig             // Kills the canceled queries on the server (see: WithQueryKill)
+		repeatedQueryThreshold int                          // Warn when the same query shape repeats this many times in one scope (debug only)
+		resultMapper           ResultMapper                 // Maps GetModel(s) results into a destination (see: MapInto)
+		resultSizeWarning      int                          // Warn when a GetModels result exceeds this many rows
+		retention              *retentionPolicies           // Registered retention policies and the scheduler
+		runtimeMu              sync.RWMutex                 // Lock for the runtime settings: debug, logger, slow query threshold (see: Reconfigure) and SQL configs (see: UpdateReplicas)
+		schemaChanges          *schemaChangeConfig          // Delegates the schema changes of large MySQL tables (IE: gh-ost)
+		searchSync             *searchSync                  // Mirrors the model events to a search index (see: WithSearchSync)
+		slowQueryThreshold     time.Duration                // Custom threshold for logging slow queries (zero uses the logger default)
+		softDeletes            map[string]bool              // Models (by name) that are soft-deleted (see: DeleteModel)
+		startupRetry           *RetryPolicy                 // Retries connecting to the datastore in NewClient (see: WithStartupRetry)
+		sqlConfigs             []*SQLConfig                 // Configuration for a MySQL or PostgreSQL datastore
+		sqLite                 *SQLiteConfig                // Configuration for a SQLite datastore
+		tablePrefix            string                       // Model table prefix
+		timeSeries             map[string]*TimeSeriesConfig // Time-series storage for event/metric models (by model name)
+		tombstones             bool                         // Save a tombstone for the deleted records (see: WithTombstones)
+		txWatchdog             *txWatchdogConfig            // Rolls back leaked raw transactions (see: NewRawTx)
 	}
 
+	// CloseHook is a lifecycle hook run when the client is closed (see: WithLifecycleHooks)
+	CloseHook func(ctx context.Context) error
+
+	// OpenHook is a lifecycle hook run when the client is opened (see: WithLifecycleHooks)
+	OpenHook func(ctx context.Context, client ClientInterface) error
+
 	// fieldConfig is the configuration for custom fields
 	fieldConfig struct {
 		arrayFields                   []string                                 // Fields that are an array (string, string, string)
@@ -60,13 +104,22 @@ func NewClient(ctx context.Context, opts ...ClientOps) (ClientInterface, error)
 		opt(client.options)
 	}
 
+	// Check for impossible combinations of the options
+	if err := validateClientOptions(client.options); err != nil {
+		return nil, err
+	}
+
 	// Set logger (if not set already)
 	if client.options.logger == nil {
 		client.options.logger = zLogger.NewGormLogger(client.IsDebug(), 5)
 	}
 
 	// Create GORM logger
-	client.options.loggerDB = &DatabaseLogWrapper{client.options.logger}
+	client.options.loggerDB = &DatabaseLogWrapper{
+		GormLoggerInterface: client.options.logger,
+		diagnostics:         client.options.deadlockDiagnostics,
+		slowQueryThreshold:  client.options.slowQueryThreshold,
+	}
 
 	// EMPTY! Engine was NOT set and will use the default (file based)
 	if client.Engine().IsEmpty() {
@@ -84,6 +137,12 @@ func NewClient(ctx context.Context, opts ...ClientOps) (ClientInterface, error)
 		opt(client.options)
 	}
 
+	// Set the deadlock diagnostics engine and capture method
+	if client.options.deadlockDiagnostics != nil {
+		client.options.deadlockDiagnostics.capture = client.captureDeadlockDiagnostics
+		client.options.deadlockDiagnostics.engine = client.Engine()
+	}
+
 	// Use NewRelic if it's enabled (use existing txn if found on ctx)
 	ctx = client.options.getTxnCtx(ctx)
 
@@ -98,17 +157,31 @@ func NewClient(ctx context.Context, opts ...ClientOps) (ClientInterface, error)
 	// Use different datastore configurations
 	var err error
 	if client.Engine() == MySQL || client.Engine() == PostgreSQL {
-		if client.options.db, err = openSQLDatabase(
-			client.options.loggerDB, client.options.sqlConfigs...,
-		); err != nil {
+		sqlConfigs := client.options.sqlConfigs
+		if client.isCockroachDB() {
+			sqlConfigs = getCockroachDBConfigs(sqlConfigs)
+		}
+		if err = client.connectWithRetry(ctx, func(context.Context) (connectErr error) {
+			client.options.db, client.options.replicas, connectErr = openSQLDatabase(
+				client.options.loggerDB, sqlConfigs...,
+			)
+			return connectErr
+		}); err != nil {
 			return nil, err
 		}
 	} else if client.Engine() == MongoDB {
-		if client.options.mongoDB, err = openMongoDatabase(
-			ctx, client.options.mongoDBConfig,
-		); err != nil {
+		if err = client.connectWithRetry(ctx, func(attemptCtx context.Context) (connectErr error) {
+			client.options.mongoDB, connectErr = openMongoDatabase(
+				attemptCtx, client.options.mongoDBConfig, client.options.deadlockDiagnostics,
+			)
+			return connectErr
+		}); err != nil {
 			return nil, err
 		}
+	} else if client.Engine() == Memory {
+		client.options.memory = newMemoryStore(client.options.tablePrefix)
+	} else if client.Engine() == DynamoDB {
+		client.options.dynamoDB = openDynamoDB(client.options.dynamoDBConfig, client.options.tablePrefix)
 	} else { // SQLite
 		if client.options.db, err = openSQLiteDatabase(
 			client.options.loggerDB, client.options.sqLite,
@@ -117,6 +190,14 @@ func NewClient(ctx context.Context, opts ...ClientOps) (ClientInterface, error)
 		}
 	}
 
+	// Tag the SQL statements with the context values (IE: trace IDs) and the query IDs (see: WithQueryKill)
+	if (client.options.queryComments != nil || client.supportsQueryKill()) && client.options.db != nil {
+		client.addQueryCommentCallbacks(client.options.db)
+		if client.supportsQueryKill() {
+			client.addQueryKillCallbacks(client.options.db)
+		}
+	}
+
 	// Auto migrate
 	if client.options.autoMigrate && len(client.options.migrateModels) > 0 {
 		if err = client.AutoMigrateDatabase(ctx, client.options.migrateModels...); err != nil {
@@ -124,6 +205,26 @@ func NewClient(ctx context.Context, opts ...ClientOps) (ClientInterface, error)
 		}
 	}
 
+	// Create the tombstone table
+	if err = client.migrateTombstones(ctx); err != nil {
+		_ = client.Close(ctx)
+		return nil, err
+	}
+
+	// Mirror the model events to the search index
+	if err = client.startSearchSync(); err != nil {
+		_ = client.Close(ctx)
+		return nil, err
+	}
+
+	// Run the open lifecycle hook (close the connections if it fails)
+	if client.options.onOpen != nil {
+		if err = client.options.onOpen(ctx, client); err != nil {
+			_ = client.Close(ctx)
+			return nil, err
+		}
+	}
+
 	// Return the client
 	return client, nil
 }
@@ -134,13 +235,37 @@ func (c *Client) Close(ctx context.Context) error {
 		defer txn.StartSegment("close_datastore").End()
 	}
 
+	// Run the close lifecycle hook (the connections are closed regardless)
+	var hookErr error
+	if c.options.onClose != nil {
+		hookErr = c.options.onClose(ctx)
+	}
+
+	// Stop the retention scheduler
+	c.stopRetention()
+
+	// Flush the pending search documents (the connections are closed regardless)
+	if err := c.stopSearchSync(ctx); err != nil && hookErr == nil {
+		hookErr = err
+	}
+
 	// Close Mongo
 	if c.Engine() == MongoDB {
 		if err := c.options.mongoDB.Client().Disconnect(ctx); err != nil {
 			return err
 		}
 		c.options.mongoDB = nil
+	} else if c.Engine() == Memory {
+		c.options.memory = nil
+	} else if c.Engine() == DynamoDB { // HTTP client (no connections to close)
+		c.options.dynamoDB = nil
 	} else { // All other SQL database(s)
+		if c.options.replicas != nil {
+			if err := c.options.replicas.close(); err != nil {
+				return err
+			}
+			c.options.replicas = nil
+		}
 		if err := closeSQLDatabase(c.options.db); err != nil {
 			return err
 		}
@@ -148,21 +273,76 @@ func (c *Client) Close(ctx context.Context) error {
 	}
 
 	c.options.engine = Empty
-	return nil
+	return hookErr
 }
 
 // Debug will set the debug flag
 func (c *Client) Debug(on bool) {
+	c.options.runtimeMu.Lock()
+	defer c.options.runtimeMu.Unlock()
 	c.options.debug = on
 }
 
+// Reconfigure will apply a subset of options at runtime without rebuilding any connections
+//
+// Only the debug flag, slow query threshold and logger are applied, all other options are ignored
+// Safe to call while the client is in use (the runtime settings are guarded by a lock)
+func (c *Client) Reconfigure(opts ...ClientOps) {
+	c.options.runtimeMu.Lock()
+	defer c.options.runtimeMu.Unlock()
+
+	// Apply the options to a copy of the runtime settings
+	updated := &clientOptions{
+		debug:              c.options.debug,
+		fields:             &fieldConfig{},
+		logger:             c.options.logger,
+		slowQueryThreshold: c.options.slowQueryThreshold,
+	}
+	for _, opt := range opts {
+		opt(updated)
+	}
+
+	// Toggle the logger mode if debugging changed (and the logger was not replaced)
+	if updated.debug != c.options.debug && updated.logger == c.options.logger && updated.logger != nil {
+		if updated.debug {
+			updated.logger = updated.logger.SetMode(zLogger.Info)
+		} else {
+			updated.logger = updated.logger.SetMode(zLogger.Warn)
+		}
+	}
+
+	// Set the runtime settings
+	c.options.debug = updated.debug
+	c.options.logger = updated.logger
+	c.options.slowQueryThreshold = updated.slowQueryThreshold
+
+	// Update the GORM logger in place (shared by all existing connections)
+	if wrapper, ok := c.options.loggerDB.(*DatabaseLogWrapper); ok {
+		wrapper.setLogger(updated.logger, updated.slowQueryThreshold)
+	}
+}
+
 // DebugLog will display verbose logs
 func (c *Client) DebugLog(ctx context.Context, text string) {
-	if c.IsDebug() && c.options.logger != nil {
-		c.options.logger.Info(ctx, text)
+	if logger := c.getLogger(); logger != nil && c.IsDebug() {
+		logger.Info(ctx, text)
 	}
 }
 
+// warnLog will display a warning (IE: a failed background operation)
+func (c *Client) warnLog(ctx context.Context, text string) {
+	if logger := c.getLogger(); logger != nil {
+		logger.Warn(ctx, text)
+	}
+}
+
+// getLogger will return the logger (replaced by Reconfigure)
+func (c *Client) getLogger() zLogger.GormLoggerInterface {
+	c.options.runtimeMu.RLock()
+	defer c.options.runtimeMu.RUnlock()
+	return c.options.logger
+}
+
 // Engine will return the client's engine
 func (c *Client) Engine() Engine {
 	return c.options.engine
@@ -179,6 +359,8 @@ func (c *Client) GetTableName(modelName string) string {
 // GetDatabaseName will return the full database name for the given model name
 func (c *Client) GetDatabaseName() string {
 	if c.Engine() == MySQL || c.Engine() == PostgreSQL {
+		c.options.runtimeMu.RLock()
+		defer c.options.runtimeMu.RUnlock()
 		return c.options.sqlConfigs[0].Name
 	} else if c.Engine() == MongoDB {
 		return c.options.mongoDBConfig.DatabaseName
@@ -225,6 +407,8 @@ func (c *Client) HasMigratedModel(modelType string) bool {
 
 // IsDebug will return the debug flag (bool)
 func (c *Client) IsDebug() bool {
+	c.options.runtimeMu.RLock()
+	defer c.options.runtimeMu.RUnlock()
 	return c.options.debug
 }
 
@@ -3,7 +3,9 @@ package datastore
 import (
 	"context"
 	"database/sql"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	zLogger "github.com/mrz1836/go-logger"
 	"github.com/newrelic/go-agent/v3/newrelic"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -26,7 +28,12 @@ func defaultClientOptions() *clientOptions {
 			arrayFields:  nil,
 			objectFields: []string{metadataField},
 		},
+		immutableModels: &immutableModels{},
+		jsonIndexChecks: &jsonIndexChecks{},
+		modelDefaults:   &modelDefaults{},
+		modelEvents:     &modelEvents{},
 		newRelicEnabled: false,
+		retention:       &retentionPolicies{},
 		sqLite: &SQLiteConfig{
 			CommonConfig: CommonConfig{
 				Debug: false,
@@ -64,6 +71,54 @@ func WithAutoMigrate(migrateModels ...interface{}) ClientOps {
 	}
 }
 
+// WithCache will cache the results of GetModel and GetModelCount (IE: Redis or NewLRUCache) for the TTL
+// (default: 1 minute)
+//
+// The results are keyed by table and conditions (stored as JSON), and the table is invalidated by the
+// committed writes of the client (IE: SaveModel, UpsertModel, CreateInBatches, retention, AnonymizeModels and
+// the SQL derived columns), raw writes (IE: Execute) are only visible after the TTL
+// Reads from the source database are not cached, neither are the models with unexported fields (or json:"-")
+func WithCache(cache CacheInterface, ttl time.Duration) ClientOps {
+	return func(c *clientOptions) {
+		if cache == nil {
+			return
+		}
+		if ttl <= 0 {
+			ttl = defaultCacheTTL
+		}
+		c.cache = &readCache{cache: cache, ttl: ttl}
+	}
+}
+
+// WithCockroachDB will treat the PostgreSQL engine as a CockroachDB cluster (wire-compatible with PostgreSQL)
+//
+// NewTx retries the serialization failures (40001) as CockroachDB expects (see: NewTxWithRetry), and the index
+// checks, migrations, maintenance and diagnostics use the CockroachDB statements
+func WithCockroachDB() ClientOps {
+	return func(c *clientOptions) {
+		c.cockroachDB = true
+	}
+}
+
+// WithDeadlockDiagnostics will capture engine diagnostics when a deadlock or serialization error is detected
+//
+// MySQL: SHOW ENGINE INNODB STATUS, PostgreSQL: pg_locks snapshot, MongoDB: currentOp
+// Diagnostics are sent to the sink at most once per interval (default: 1 minute)
+func WithDeadlockDiagnostics(sink DiagnosticsSink, interval time.Duration) ClientOps {
+	return func(c *clientOptions) {
+		if sink == nil {
+			return
+		}
+		if interval <= 0 {
+			interval = defaultDiagnosticsInterval
+		}
+		c.deadlockDiagnostics = &deadlockDiagnostics{
+			interval: interval,
+			sink:     sink,
+		}
+	}
+}
+
 // WithDebugging will enable debugging mode
 func WithDebugging() ClientOps {
 	return func(c *clientOptions) {
@@ -71,6 +126,266 @@ func WithDebugging() ClientOps {
 	}
 }
 
+// WithSlowQueryThreshold will set a custom threshold for logging slow queries
+func WithSlowQueryThreshold(threshold time.Duration) ClientOps {
+	return func(c *clientOptions) {
+		if threshold > 0 {
+			c.slowQueryThreshold = threshold
+		}
+	}
+}
+
+// WithRepeatedQueryDetection will enable detecting repeated query shapes (N+1 patterns) in debug mode
+//
+// A warning (with stack) is logged when the same query shape executes threshold times within a single
+// scope (see: NewQueryScope and CaptureQueries)
+func WithRepeatedQueryDetection(threshold int) ClientOps {
+	return func(c *clientOptions) {
+		if threshold > 1 {
+			c.repeatedQueryThreshold = threshold
+		}
+	}
+}
+
+// WithQueryComments will add the context values of the extractor (IE: trace_id, request_id) to every
+// operation, as a trailing SQL comment or the MongoDB $comment
+//
+// Correlates the database server logs (IE: slow query log, currentOp) with the application traces
+func WithQueryComments(extractor QueryCommentExtractor) ClientOps {
+	return func(c *clientOptions) {
+		if extractor != nil {
+			c.queryComments = extractor
+		}
+	}
+}
+
+// WithQueryKill will kill the queries on the server when their context is canceled (or the deadline is
+// exceeded), the drivers only stop waiting for the results
+//
+// Every query is tagged with a query ID (in the query comment) used to find the running statement:
+// MySQL: KILL QUERY, PostgreSQL: pg_cancel_backend, MongoDB: killOp (other engines are not supported),
+// the timeout of the kill statements defaults to 5 seconds
+func WithQueryKill(timeout time.Duration) ClientOps {
+	return func(c *clientOptions) {
+		if timeout <= 0 {
+			timeout = defaultQueryKillTimeout
+		}
+		c.queryKill = &queryKillConfig{timeout: timeout}
+	}
+}
+
+// WithIndexHint will register a vetted index hint that can be used by name in QueryParams.IndexHint
+//
+// Only registered hints can be used, so arbitrary hint strings are never injected into queries
+func WithIndexHint(name string, hint IndexHint) ClientOps {
+	return func(c *clientOptions) {
+		if name == "" || !indexNamePattern.MatchString(hint.Index) {
+			return
+		}
+		if c.indexHints == nil {
+			c.indexHints = make(map[string]*IndexHint)
+		}
+		c.indexHints[name] = &hint
+	}
+}
+
+// WithMaskedReads will register the column masking applied when a masked reader gets the model
+//
+// Callers flagged using WithMaskedReader(ctx) receive the masked projection from GetModel and GetModels
+// See CreateMaskedView() for creating the same projection as a database view
+func WithMaskedReads(model interface{}, maskSpec MaskSpec) ClientOps {
+	return func(c *clientOptions) {
+		modelName := GetModelName(model)
+		if modelName == nil || len(maskSpec) == 0 {
+			return
+		}
+		if c.maskSpecs == nil {
+			c.maskSpecs = make(map[string]MaskSpec)
+		}
+		c.maskSpecs[*modelName] = maskSpec
+	}
+}
+
+// WithMetrics will set a custom metrics recorder (result sizes are reported for GetModel and GetModels)
+func WithMetrics(metrics MetricsRecorder) ClientOps {
+	return func(c *clientOptions) {
+		if metrics != nil {
+			c.metrics = metrics
+		}
+	}
+}
+
+// WithResultSizeWarning will log a warning when a single GetModels result exceeds the number of rows
+//
+// Helps find endpoints that should be paginated
+func WithResultSizeWarning(maxRows int) ClientOps {
+	return func(c *clientOptions) {
+		if maxRows > 0 {
+			c.resultSizeWarning = maxRows
+		}
+	}
+}
+
+// WithColumnConverter will register a converter for the column when scanning into map results
+// (field results and aggregates, IE: PostgreSQL numeric into a decimal type)
+//
+// Aggregate results use the columns: _id (group) and count
+func WithColumnConverter(column string, converter ColumnConverter) ClientOps {
+	return func(c *clientOptions) {
+		if len(column) == 0 || converter == nil {
+			return
+		}
+		if c.columnConverters == nil {
+			c.columnConverters = make(map[string]ColumnConverter)
+		}
+		c.columnConverters[column] = converter
+	}
+}
+
+// WithAnalyzeAfterBulkLoad will refresh the planner statistics (ANALYZE) after CreateInBatches inserts at least minRows
+//
+// Improves the query plans following a bulk load (stale statistics), see: AnalyzeTable
+func WithAnalyzeAfterBulkLoad(minRows int) ClientOps {
+	return func(c *clientOptions) {
+		if minRows > 0 {
+			c.analyzeAfterRows = minRows
+		}
+	}
+}
+
+// WithMaintenanceWindow will only allow maintenance operations (IE: OptimizeTable) during the daily window (UTC)
+//
+// Start and end are the time of day (IE: 2*time.Hour), the window can wrap around midnight
+func WithMaintenanceWindow(start, end time.Duration) ClientOps {
+	return func(c *clientOptions) {
+		day := 24 * time.Hour
+		if start < 0 || end < 0 || start >= day || end > day || start == end {
+			return
+		}
+		c.maintenanceWindow = &MaintenanceWindow{End: end, Start: start}
+	}
+}
+
+// WithResultMapper will set a custom mapper for results read using MapInto() (IE: protobuf messages or API DTOs)
+//
+// Default is DefaultResultMapper (reflection-based, using json tags)
+func WithResultMapper(mapper ResultMapper) ClientOps {
+	return func(c *clientOptions) {
+		if mapper != nil {
+			c.resultMapper = mapper
+		}
+	}
+}
+
+// WithLifecycleHooks will set hooks that run when the client is opened (NewClient) and closed (Close)
+//
+// IE: registering collectors, warming caches or announcing to service discovery (either hook can be nil)
+// If the open hook fails, the client is closed and NewClient returns the error
+func WithLifecycleHooks(onOpen OpenHook, onClose CloseHook) ClientOps {
+	return func(c *clientOptions) {
+		c.onOpen = onOpen
+		c.onClose = onClose
+	}
+}
+
+// WithOnlineSchemaChanges will delegate the ALTER TABLE statements of AutoMigrateDatabase to the executor (MySQL)
+//
+// IE: gh-ost or pt-online-schema-change for tables with at least minRows rows (zero delegates all tables)
+func WithOnlineSchemaChanges(executor SchemaChangeExecutor, minRows int64) ClientOps {
+	return func(c *clientOptions) {
+		if executor != nil {
+			c.schemaChanges = &schemaChangeConfig{executor: executor, minRows: minRows}
+		}
+	}
+}
+
+// WithSearchSync will mirror the committed writes of the models to a search index (IE: Elasticsearch or OpenSearch)
+//
+// Documents are sent in bulk batches (by size or interval) with a backoff on failures, see NewBulkSearchIndexer
+// The pending documents are flushed on Close()
+func WithSearchSync(indexer SearchIndexer, config *SearchSyncConfig) ClientOps {
+	return func(c *clientOptions) {
+		c.searchSync = newSearchSync(indexer, config)
+	}
+}
+
+// WithSoftDeletes will soft-delete the models (DeleteModel marks deleted_at instead of removing the record)
+//
+// Soft-deleted records are excluded from reads unless the context is flagged using IncludeDeleted()
+func WithSoftDeletes(models ...interface{}) ClientOps {
+	return func(c *clientOptions) {
+		for _, model := range models {
+			modelName := GetModelName(model)
+			if modelName == nil {
+				continue
+			}
+			if c.softDeletes == nil {
+				c.softDeletes = make(map[string]bool)
+			}
+			c.softDeletes[*modelName] = true
+		}
+	}
+}
+
+// WithTombstones will save a tombstone (model, primary key and time) for every record removed (or marked as
+// deleted) by DeleteModel, in the same transaction
+//
+// Lets the differential sync and audit consumers learn about the deletions (see: GetTombstones)
+func WithTombstones() ClientOps {
+	return func(c *clientOptions) {
+		c.tombstones = true
+	}
+}
+
+// WithTransactionWatchdog will roll back the raw transactions (NewRawTx) that are idle or open beyond the limits
+//
+// The stack trace of the caller that started the transaction is logged, zero is no limit
+func WithTransactionWatchdog(maxIdle, maxOpen time.Duration) ClientOps {
+	return func(c *clientOptions) {
+		if maxIdle <= 0 && maxOpen <= 0 {
+			return
+		}
+		c.txWatchdog = &txWatchdogConfig{maxIdle: maxIdle, maxOpen: maxOpen}
+	}
+}
+
+// WithStartupRetry will retry connecting to a MySQL, PostgreSQL or MongoDB datastore in NewClient
+// (IE: the database container is still starting)
+//
+// The attempts are delayed using an exponential backoff (with jitter), the zero fields of the policy use the
+// startup defaults (10 attempts, 500ms doubled up to 10s), invalid configurations are not retried
+func WithStartupRetry(policy RetryPolicy) ClientOps {
+	return func(c *clientOptions) {
+		c.startupRetry = &policy
+	}
+}
+
+// WithConditionNormalization will normalize the conditions of GetModel, GetModels, GetModelCount and
+// GetModelsAggregate (see: NormalizeConditions)
+//
+// Conditions that can never match return ErrEmptyResultGuaranteed without querying the datastore
+func WithConditionNormalization() ClientOps {
+	return func(c *clientOptions) {
+		c.normalizeConditions = true
+	}
+}
+
+// WithTimeSeries will register the model as a time-series (event/metric) model
+//
+// AutoMigrateDatabase creates a time-series collection (MongoDB) or a partitioned table (MySQL, PostgreSQL)
+func WithTimeSeries(model interface{}, config TimeSeriesConfig) ClientOps {
+	return func(c *clientOptions) {
+		modelName := GetModelName(model)
+		if modelName == nil || !indexNamePattern.MatchString(config.TimeField) {
+			return
+		}
+		if c.timeSeries == nil {
+			c.timeSeries = make(map[string]*TimeSeriesConfig)
+		}
+		c.timeSeries[*modelName] = &config
+	}
+}
+
 // WithNewRelic will enable the NewRelic wrapper
 func WithNewRelic() ClientOps {
 	return func(c *clientOptions) {
@@ -87,6 +402,7 @@ func WithSQLite(config *SQLiteConfig) ClientOps {
 		c.sqLite = config
 		c.sqLite.MaxIdleConnections = maxIdleConnectionsSQLite // @mrz set this for issues connecting to SQLite
 		c.engine = SQLite
+		c.engineOptions = append(c.engineOptions, "WithSQLite")
 		c.tablePrefix = config.TablePrefix
 		if c.sqLite.Debug {
 			c.debug = true
@@ -94,6 +410,62 @@ func WithSQLite(config *SQLiteConfig) ClientOps {
 	}
 }
 
+// WithMemory will set the datastore to use the in-memory engine (no database, files or cgo)
+//
+// Supports the core model methods (save, get, count, update, increment, delete), writes are applied right away
+// (transactions can not be rolled back), other methods return ErrUnsupportedEngine
+func WithMemory(config *MemoryConfig) ClientOps {
+	return func(c *clientOptions) {
+		if config == nil {
+			return
+		}
+		c.engine = Memory
+		c.engineOptions = append(c.engineOptions, "WithMemory")
+		c.tablePrefix = config.TablePrefix
+		if config.Debug {
+			c.debug = true
+		}
+	}
+}
+
+// WithDynamoDB will set the datastore to use DynamoDB
+//
+// Supports GetModel, GetModels (see: Engine.SupportsOperator), SaveModel and CreateInBatches, other methods
+// return ErrUnsupportedEngine. The tables are not migrated (the key schema must match the model's primary key)
+func WithDynamoDB(config *DynamoDBConfig) ClientOps {
+	return func(c *clientOptions) {
+		if config == nil {
+			return
+		}
+		c.engine = DynamoDB
+		c.engineOptions = append(c.engineOptions, "WithDynamoDB")
+		c.tablePrefix = config.TablePrefix
+		c.dynamoDBConfig = config
+		if config.Debug {
+			c.debug = true
+		}
+	}
+}
+
+// WithDynamoDBConnection will set the datastore to use an existing DynamoDB client
+func WithDynamoDBConnection(client *dynamodb.Client, tablePrefix string) ClientOps {
+	return func(c *clientOptions) {
+		if client == nil {
+			return
+		}
+		c.engine = DynamoDB
+		c.engineOptions = append(c.engineOptions, "WithDynamoDBConnection")
+		c.tablePrefix = tablePrefix
+		c.dynamoDBConfig = &DynamoDBConfig{
+			CommonConfig: CommonConfig{
+				Debug:       c.debug,
+				TablePrefix: tablePrefix,
+			},
+			ExistingConnection: client,
+		}
+	}
+}
+
 // WithSQL will load a datastore using either an SQL database config or existing connection
 func WithSQL(engine Engine, configs []*SQLConfig) ClientOps {
 	return func(c *clientOptions) {
@@ -127,6 +499,7 @@ func WithSQL(engine Engine, configs []*SQLConfig) ClientOps {
 		// Set the engine
 		if len(c.sqlConfigs) > 0 {
 			c.engine = engine
+			c.engineOptions = append(c.engineOptions, "WithSQL("+engine.String()+")")
 		}
 	}
 }
@@ -163,6 +536,7 @@ func WithSQLConnection(engine Engine, sqlDB *sql.DB, tablePrefix string) ClientO
 			SkipInitializeWithVersion: skipInitializeWithVersion,
 		}}
 		c.engine = engine
+		c.engineOptions = append(c.engineOptions, "WithSQLConnection("+engine.String()+")")
 		c.tablePrefix = tablePrefix
 	}
 }
@@ -174,6 +548,7 @@ func WithMongo(config *MongoDBConfig) ClientOps {
 			return
 		}
 		c.engine = MongoDB
+		c.engineOptions = append(c.engineOptions, "WithMongo")
 		c.tablePrefix = config.TablePrefix
 		c.mongoDBConfig = config
 		if config.Debug {
@@ -189,6 +564,7 @@ func WithMongoConnection(database *mongo.Database, tablePrefix string) ClientOps
 			return
 		}
 		c.engine = MongoDB
+		c.engineOptions = append(c.engineOptions, "WithMongoConnection")
 		c.tablePrefix = tablePrefix
 		c.mongoDBConfig = &MongoDBConfig{
 			CommonConfig: CommonConfig{
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	zLogger "github.com/mrz1836/go-logger"
 	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/stretchr/testify/assert"
@@ -23,6 +25,14 @@ func TestDefaultClientOptions(t *testing.T) {
 		assert.False(t, defaults.newRelicEnabled)
 		assert.NotNil(t, defaults.sqLite)
 	})
+
+	t.Run("registries are created (not lazily, safe for concurrent use)", func(t *testing.T) {
+		defaults := defaultClientOptions()
+		assert.NotNil(t, defaults.immutableModels)
+		assert.NotNil(t, defaults.modelDefaults)
+		assert.NotNil(t, defaults.modelEvents)
+		assert.NotNil(t, defaults.retention)
+	})
 }
 
 // TestClientOptions_GetTxnCtx will test the method getTxnCtx()
@@ -155,6 +165,62 @@ func TestWithSQLite(t *testing.T) {
 	})
 }
 
+// TestWithMemory will test the method WithMemory()
+func TestWithMemory(t *testing.T) {
+	t.Run("test applying nil", func(t *testing.T) {
+		options := &clientOptions{}
+		WithMemory(nil)(options)
+		assert.Equal(t, Engine(""), options.engine)
+	})
+
+	t.Run("test applying option", func(t *testing.T) {
+		options := &clientOptions{}
+		WithMemory(&MemoryConfig{CommonConfig: CommonConfig{Debug: true, TablePrefix: "test"}})(options)
+		assert.Equal(t, Memory, options.engine)
+		assert.Equal(t, "test", options.tablePrefix)
+		assert.True(t, options.debug)
+	})
+}
+
+// TestWithDynamoDB will test the method WithDynamoDB()
+func TestWithDynamoDB(t *testing.T) {
+	t.Run("test applying nil", func(t *testing.T) {
+		options := &clientOptions{}
+		WithDynamoDB(nil)(options)
+		assert.Equal(t, Engine(""), options.engine)
+		assert.Nil(t, options.dynamoDBConfig)
+	})
+
+	t.Run("test applying option", func(t *testing.T) {
+		options := &clientOptions{}
+		config := &DynamoDBConfig{CommonConfig: CommonConfig{Debug: true, TablePrefix: "test"}, Region: "us-east-1"}
+		WithDynamoDB(config)(options)
+		assert.Equal(t, DynamoDB, options.engine)
+		assert.Equal(t, "test", options.tablePrefix)
+		assert.Equal(t, config, options.dynamoDBConfig)
+		assert.True(t, options.debug)
+	})
+}
+
+// TestWithDynamoDBConnection will test the method WithDynamoDBConnection()
+func TestWithDynamoDBConnection(t *testing.T) {
+	t.Run("test applying nil", func(t *testing.T) {
+		options := &clientOptions{}
+		WithDynamoDBConnection(nil, "test")(options)
+		assert.Equal(t, Engine(""), options.engine)
+	})
+
+	t.Run("test applying option", func(t *testing.T) {
+		options := &clientOptions{}
+		client := dynamodb.New(dynamodb.Options{Region: "us-east-1"})
+		WithDynamoDBConnection(client, "test")(options)
+		assert.Equal(t, DynamoDB, options.engine)
+		assert.Equal(t, "test", options.tablePrefix)
+		require.NotNil(t, options.dynamoDBConfig)
+		assert.Equal(t, client, options.dynamoDBConfig.ExistingConnection)
+	})
+}
+
 // TestWithSQL will test the method WithSQL()
 func TestWithSQL(t *testing.T) {
 	t.Run("check type", func(t *testing.T) {
@@ -336,3 +402,370 @@ func TestWithLogger(t *testing.T) {
 		assert.Equal(t, l, options.logger)
 	})
 }
+
+// TestWithSlowQueryThreshold will test the method WithSlowQueryThreshold()
+func TestWithSlowQueryThreshold(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithSlowQueryThreshold(0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying zero", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithSlowQueryThreshold(0)
+		opt(options)
+		assert.Equal(t, time.Duration(0), options.slowQueryThreshold)
+	})
+
+	t.Run("test applying threshold", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithSlowQueryThreshold(3 * time.Second)
+		opt(options)
+		assert.Equal(t, 3*time.Second, options.slowQueryThreshold)
+	})
+}
+
+// TestWithRepeatedQueryDetection will test the method WithRepeatedQueryDetection()
+func TestWithRepeatedQueryDetection(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithRepeatedQueryDetection(0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying invalid threshold", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithRepeatedQueryDetection(1)
+		opt(options)
+		assert.Equal(t, 0, options.repeatedQueryThreshold)
+	})
+
+	t.Run("test applying threshold", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithRepeatedQueryDetection(5)
+		opt(options)
+		assert.Equal(t, 5, options.repeatedQueryThreshold)
+	})
+}
+
+// TestWithMaskedReads will test the method WithMaskedReads()
+func TestWithMaskedReads(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithMaskedReads(nil, nil)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying nil model", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithMaskedReads(nil, MaskSpec{"name": MaskHash})
+		opt(options)
+		assert.Nil(t, options.maskSpecs)
+	})
+
+	t.Run("test applying spec", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithMaskedReads(&testSQLModel{}, MaskSpec{"name": MaskHash})
+		opt(options)
+		assert.Equal(t, MaskSpec{"name": MaskHash}, options.maskSpecs[testSQLModelName])
+	})
+}
+
+// TestWithDeadlockDiagnostics will test the method WithDeadlockDiagnostics()
+func TestWithDeadlockDiagnostics(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithDeadlockDiagnostics(nil, 0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying nil sink", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithDeadlockDiagnostics(nil, time.Second)
+		opt(options)
+		assert.Nil(t, options.deadlockDiagnostics)
+	})
+
+	t.Run("test applying default interval", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithDeadlockDiagnostics(func(context.Context, Engine, error, string) {}, 0)
+		opt(options)
+		require.NotNil(t, options.deadlockDiagnostics)
+		assert.Equal(t, defaultDiagnosticsInterval, options.deadlockDiagnostics.interval)
+	})
+}
+
+// TestWithMetrics will test the method WithMetrics()
+func TestWithMetrics(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithMetrics(nil)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying nil", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithMetrics(nil)
+		opt(options)
+		assert.Nil(t, options.metrics)
+	})
+
+	t.Run("test applying recorder", func(t *testing.T) {
+		options := &clientOptions{}
+		recorder := &testMetricsRecorder{}
+		opt := WithMetrics(recorder)
+		opt(options)
+		assert.Equal(t, recorder, options.metrics)
+	})
+}
+
+// TestWithResultSizeWarning will test the method WithResultSizeWarning()
+func TestWithResultSizeWarning(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithResultSizeWarning(0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying invalid value", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithResultSizeWarning(-1)
+		opt(options)
+		assert.Equal(t, 0, options.resultSizeWarning)
+	})
+
+	t.Run("test applying value", func(t *testing.T) {
+		options := &clientOptions{}
+		opt := WithResultSizeWarning(100)
+		opt(options)
+		assert.Equal(t, 100, options.resultSizeWarning)
+	})
+}
+
+// TestWithIndexHint will test the method WithIndexHint()
+func TestWithIndexHint(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithIndexHint("", IndexHint{})
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying invalid index names", func(t *testing.T) {
+		options := &clientOptions{}
+		WithIndexHint("", IndexHint{Index: "idx_name"})(options)
+		WithIndexHint("by_name", IndexHint{Index: ""})(options)
+		WithIndexHint("by_name", IndexHint{Index: "idx_name) WHERE 1=1 --"})(options)
+		assert.Nil(t, options.indexHints)
+	})
+
+	t.Run("test applying hint", func(t *testing.T) {
+		options := &clientOptions{}
+		WithIndexHint("by_name", IndexHint{Index: "idx_name", Force: true})(options)
+		assert.Equal(t, &IndexHint{Index: "idx_name", Force: true}, options.indexHints["by_name"])
+	})
+}
+
+// TestWithTimeSeries will test the method WithTimeSeries()
+func TestWithTimeSeries(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithTimeSeries(nil, TimeSeriesConfig{})
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying invalid values", func(t *testing.T) {
+		options := &clientOptions{}
+		WithTimeSeries(nil, TimeSeriesConfig{TimeField: "created_at"})(options)
+		WithTimeSeries(&testSQLModel{}, TimeSeriesConfig{})(options)
+		WithTimeSeries(&testSQLModel{}, TimeSeriesConfig{TimeField: "created_at; DROP TABLE x"})(options)
+		assert.Nil(t, options.timeSeries)
+	})
+
+	t.Run("test applying config", func(t *testing.T) {
+		options := &clientOptions{}
+		config := TimeSeriesConfig{Granularity: TimeSeriesMinutes, MetaField: "name", TimeField: "created_at"}
+		WithTimeSeries(&testSQLModel{}, config)(options)
+		assert.Equal(t, &config, options.timeSeries[testSQLModelName])
+	})
+}
+
+// TestWithResultMapper will test the method WithResultMapper()
+func TestWithResultMapper(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithResultMapper(nil)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying nil", func(t *testing.T) {
+		options := &clientOptions{}
+		WithResultMapper(nil)(options)
+		assert.Nil(t, options.resultMapper)
+	})
+
+	t.Run("test applying mapper", func(t *testing.T) {
+		options := &clientOptions{}
+		WithResultMapper(DefaultResultMapper)(options)
+		assert.NotNil(t, options.resultMapper)
+	})
+}
+
+// TestWithLifecycleHooks will test the method WithLifecycleHooks()
+func TestWithLifecycleHooks(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithLifecycleHooks(nil, nil)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying nil", func(t *testing.T) {
+		options := &clientOptions{}
+		WithLifecycleHooks(nil, nil)(options)
+		assert.Nil(t, options.onOpen)
+		assert.Nil(t, options.onClose)
+	})
+
+	t.Run("test applying hooks", func(t *testing.T) {
+		options := &clientOptions{}
+		WithLifecycleHooks(
+			func(context.Context, ClientInterface) error { return nil },
+			func(context.Context) error { return nil },
+		)(options)
+		assert.NotNil(t, options.onOpen)
+		assert.NotNil(t, options.onClose)
+	})
+}
+
+// TestWithOnlineSchemaChanges will test the method WithOnlineSchemaChanges()
+func TestWithOnlineSchemaChanges(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithOnlineSchemaChanges(nil, 0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying nil", func(t *testing.T) {
+		options := &clientOptions{}
+		WithOnlineSchemaChanges(nil, 0)(options)
+		assert.Nil(t, options.schemaChanges)
+	})
+
+	t.Run("test applying executor", func(t *testing.T) {
+		options := &clientOptions{}
+		WithOnlineSchemaChanges(SchemaChangeExecutorFunc(func(context.Context, *SchemaChange) error {
+			return nil
+		}), 1000)(options)
+		require.NotNil(t, options.schemaChanges)
+		assert.Equal(t, int64(1000), options.schemaChanges.minRows)
+	})
+}
+
+// TestWithSoftDeletes will test the method WithSoftDeletes()
+func TestWithSoftDeletes(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithSoftDeletes()
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying nil", func(t *testing.T) {
+		options := &clientOptions{}
+		WithSoftDeletes(nil)(options)
+		assert.Nil(t, options.softDeletes)
+	})
+
+	t.Run("test applying models", func(t *testing.T) {
+		options := &clientOptions{}
+		WithSoftDeletes(&testSoftDeleteModel{})(options)
+		assert.Equal(t, map[string]bool{"test_soft_delete_model": true}, options.softDeletes)
+	})
+}
+
+// TestWithColumnConverter will test the method WithColumnConverter()
+func TestWithColumnConverter(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithColumnConverter("", nil)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying invalid values", func(t *testing.T) {
+		options := &clientOptions{}
+		WithColumnConverter("", ConvertToString)(options)
+		WithColumnConverter("amount", nil)(options)
+		assert.Nil(t, options.columnConverters)
+	})
+
+	t.Run("test applying converter", func(t *testing.T) {
+		options := &clientOptions{}
+		WithColumnConverter("amount", ConvertToInt64)(options)
+		assert.NotNil(t, options.columnConverters["amount"])
+	})
+}
+
+// TestWithMaintenanceWindow will test the method WithMaintenanceWindow()
+func TestWithMaintenanceWindow(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithMaintenanceWindow(0, 0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying invalid values", func(t *testing.T) {
+		options := &clientOptions{}
+		WithMaintenanceWindow(time.Hour, time.Hour)(options)
+		WithMaintenanceWindow(-time.Hour, time.Hour)(options)
+		WithMaintenanceWindow(24*time.Hour, time.Hour)(options)
+		WithMaintenanceWindow(time.Hour, 25*time.Hour)(options)
+		assert.Nil(t, options.maintenanceWindow)
+	})
+
+	t.Run("test applying window", func(t *testing.T) {
+		options := &clientOptions{}
+		WithMaintenanceWindow(2*time.Hour, 4*time.Hour)(options)
+		assert.Equal(t, &MaintenanceWindow{Start: 2 * time.Hour, End: 4 * time.Hour}, options.maintenanceWindow)
+	})
+}
+
+// TestWithAnalyzeAfterBulkLoad will test the method WithAnalyzeAfterBulkLoad()
+func TestWithAnalyzeAfterBulkLoad(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithAnalyzeAfterBulkLoad(0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying invalid value", func(t *testing.T) {
+		options := &clientOptions{}
+		WithAnalyzeAfterBulkLoad(-1)(options)
+		assert.Equal(t, 0, options.analyzeAfterRows)
+	})
+
+	t.Run("test applying value", func(t *testing.T) {
+		options := &clientOptions{}
+		WithAnalyzeAfterBulkLoad(1000)(options)
+		assert.Equal(t, 1000, options.analyzeAfterRows)
+	})
+}
+
+// TestWithTransactionWatchdog will test the method WithTransactionWatchdog()
+func TestWithTransactionWatchdog(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithTransactionWatchdog(0, 0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying no limits", func(t *testing.T) {
+		options := &clientOptions{}
+		WithTransactionWatchdog(0, 0)(options)
+		assert.Nil(t, options.txWatchdog)
+	})
+
+	t.Run("test applying limits", func(t *testing.T) {
+		options := &clientOptions{}
+		WithTransactionWatchdog(time.Minute, time.Hour)(options)
+		require.NotNil(t, options.txWatchdog)
+		assert.Equal(t, time.Minute, options.txWatchdog.maxIdle)
+		assert.Equal(t, time.Hour, options.txWatchdog.maxOpen)
+	})
+}
+
+// TestWithConditionNormalization will test the method WithConditionNormalization()
+func TestWithConditionNormalization(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithConditionNormalization()
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying", func(t *testing.T) {
+		options := &clientOptions{}
+		WithConditionNormalization()(options)
+		assert.True(t, options.normalizeConditions)
+	})
+}
@@ -2,9 +2,13 @@ package datastore
 
 import (
 	"context"
+	"errors"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
+	zLogger "github.com/mrz1836/go-logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -137,3 +141,129 @@ func TestClient_GetTableName(t *testing.T) {
 		_ = os.Remove("datastore.db")
 	})
 }
+
+// TestClient_Reconfigure will test the method Reconfigure()
+func TestClient_Reconfigure(t *testing.T) {
+	t.Run("toggle debug and slow query threshold", func(t *testing.T) {
+		c, deferFunc := testClient(context.Background(), t, WithSQLite(&SQLiteConfig{
+			DatabasePath: "",
+			Shared:       true,
+		}))
+		defer deferFunc()
+		assert.False(t, c.IsDebug())
+
+		c.Reconfigure(WithDebugging(), WithSlowQueryThreshold(2*time.Second))
+		assert.True(t, c.IsDebug())
+
+		client := c.(*Client)
+		assert.Equal(t, 2*time.Second, client.options.slowQueryThreshold)
+		assert.Equal(t, zLogger.Info, client.options.logger.GetMode())
+
+		wrapper, ok := client.options.loggerDB.(*DatabaseLogWrapper)
+		require.True(t, ok)
+		assert.Equal(t, 2*time.Second, wrapper.slowQueryThreshold)
+	})
+
+	t.Run("replace the logger", func(t *testing.T) {
+		c, deferFunc := testClient(context.Background(), t, WithSQLite(&SQLiteConfig{
+			DatabasePath: "",
+			Shared:       true,
+		}))
+		defer deferFunc()
+
+		l := zLogger.NewGormLogger(true, 4)
+		c.Reconfigure(WithLogger(l))
+
+		client := c.(*Client)
+		assert.Equal(t, l, client.options.logger)
+		wrapper, ok := client.options.loggerDB.(*DatabaseLogWrapper)
+		require.True(t, ok)
+		assert.Equal(t, l, wrapper.GormLoggerInterface)
+	})
+
+	t.Run("connection options are ignored", func(t *testing.T) {
+		c, deferFunc := testClient(context.Background(), t, WithSQLite(&SQLiteConfig{
+			DatabasePath: "",
+			Shared:       true,
+		}))
+		defer deferFunc()
+
+		c.Reconfigure(WithMongo(&MongoDBConfig{DatabaseName: testDatabaseName}))
+		assert.Equal(t, SQLite, c.Engine())
+	})
+
+	t.Run("concurrent with queries", func(t *testing.T) {
+		ctx := context.Background()
+		c, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for index := 0; index < 50; index++ {
+				c.Reconfigure(WithDebugging(), WithSlowQueryThreshold(time.Duration(index+1)*time.Millisecond))
+				c.Debug(false)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for index := 0; index < 50; index++ {
+				_, err := c.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+				assert.NoError(t, err)
+				c.DebugLog(ctx, "query")
+			}
+		}()
+		wg.Wait()
+		assert.Equal(t, 50*time.Millisecond, c.(*Client).EffectiveConfig().SlowQueryThreshold)
+	})
+}
+
+// TestClient_LifecycleHooks will test the hooks run by NewClient() and Close()
+func TestClient_LifecycleHooks(t *testing.T) {
+	t.Run("open and close hooks", func(t *testing.T) {
+		ctx := context.Background()
+		var opened, closed bool
+		client, err := NewClient(ctx,
+			WithSQLite(&SQLiteConfig{DatabasePath: "file:memdb_hooks?mode=memory&cache=shared"}),
+			WithLifecycleHooks(
+				func(ctx context.Context, client ClientInterface) error {
+					opened = true
+					return client.Execute("SELECT 1").Error
+				},
+				func(context.Context) error {
+					closed = true
+					return nil
+				},
+			),
+		)
+		require.NoError(t, err)
+		assert.True(t, opened)
+		assert.False(t, closed)
+
+		require.NoError(t, client.Close(ctx))
+		assert.True(t, closed)
+	})
+
+	t.Run("open hook error", func(t *testing.T) {
+		errHook := errors.New("hook failed")
+		client, err := NewClient(context.Background(),
+			WithSQLite(&SQLiteConfig{DatabasePath: "file:memdb_hooks_error?mode=memory&cache=shared"}),
+			WithLifecycleHooks(func(context.Context, ClientInterface) error { return errHook }, nil),
+		)
+		require.ErrorIs(t, err, errHook)
+		assert.Nil(t, client)
+	})
+
+	t.Run("close hook error still closes", func(t *testing.T) {
+		ctx := context.Background()
+		errHook := errors.New("hook failed")
+		client, err := NewClient(ctx,
+			WithSQLite(&SQLiteConfig{DatabasePath: "file:memdb_hooks_close?mode=memory&cache=shared"}),
+			WithLifecycleHooks(nil, func(context.Context) error { return errHook }),
+		)
+		require.NoError(t, err)
+		require.ErrorIs(t, client.Close(ctx), errHook)
+		assert.Equal(t, Empty, client.Engine())
+	})
+}
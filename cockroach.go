@@ -0,0 +1,61 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// cockroachAlterColumnTypeVariable is the CockroachDB session variable allowing the column type changes of
+// AutoMigrateDatabase (rewriting the column)
+const cockroachAlterColumnTypeVariable = "enable_experimental_alter_column_type_general"
+
+// isCockroachDB will return true if the PostgreSQL engine is a CockroachDB cluster (see: WithCockroachDB)
+func (c *Client) isCockroachDB() bool {
+	return c.Engine() == PostgreSQL && c.options.cockroachDB
+}
+
+// getCockroachDBConfigs will return copies of the configurations with the CockroachDB session variables
+// (existing values are kept)
+func getCockroachDBConfigs(configs []*SQLConfig) []*SQLConfig {
+	cockroachConfigs := make([]*SQLConfig, 0, len(configs))
+	for _, config := range configs {
+		if config == nil {
+			cockroachConfigs = append(cockroachConfigs, config)
+			continue
+		}
+		cockroachConfig := *config
+		cockroachConfig.SessionVariables = map[string]string{cockroachAlterColumnTypeVariable: "true"}
+		for name, value := range config.SessionVariables {
+			cockroachConfig.SessionVariables[name] = value
+		}
+		cockroachConfigs = append(cockroachConfigs, &cockroachConfig)
+	}
+	return cockroachConfigs
+}
+
+// indexExistsCockroachDB will check the index in the current schema (one row per indexed column)
+func (c *Client) indexExistsCockroachDB(tableName, indexName string) (bool, error) {
+	var count int
+	if err := c.options.db.Raw(
+		`SELECT COUNT(*) FROM information_schema.statistics
+			WHERE table_schema = current_schema() AND table_name = ? AND index_name = ?`,
+		tableName, indexName,
+	).Scan(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// captureCockroachDBDiagnostics will return the cluster locks snapshot (pg_locks is always empty on CockroachDB)
+func (c *Client) captureCockroachDBDiagnostics(ctx context.Context) (string, error) {
+	var rows []map[string]interface{}
+	if err := c.options.db.WithContext(ctx).Raw(
+		`SELECT table_name, index_name, lock_key_pretty, lock_strength, granted, contended, duration,
+			txn_id::STRING AS txn_id
+		FROM crdb_internal.cluster_locks`,
+	).Scan(&rows).Error; err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(rows)
+	return string(b), err
+}
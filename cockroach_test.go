@@ -0,0 +1,68 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithCockroachDB will test the method WithCockroachDB()
+func TestWithCockroachDB(t *testing.T) {
+	options := &clientOptions{engine: PostgreSQL}
+	WithCockroachDB()(options)
+	assert.True(t, options.cockroachDB)
+	assert.True(t, (&Client{options: options}).isCockroachDB())
+
+	// Only used with the PostgreSQL engine
+	options.engine = MySQL
+	assert.False(t, (&Client{options: options}).isCockroachDB())
+}
+
+// Test_getCockroachDBConfigs will test the method getCockroachDBConfigs()
+func Test_getCockroachDBConfigs(t *testing.T) {
+	config := &SQLConfig{Host: "localhost"}
+	override := &SQLConfig{SessionVariables: map[string]string{
+		cockroachAlterColumnTypeVariable: "false", "application_name": "app",
+	}}
+
+	configs := getCockroachDBConfigs([]*SQLConfig{config, override})
+	require.Len(t, configs, 2)
+	assert.Equal(t, "localhost", configs[0].Host)
+	assert.Equal(t, map[string]string{cockroachAlterColumnTypeVariable: "true"}, configs[0].SessionVariables)
+	assert.Equal(t, override.SessionVariables, configs[1].SessionVariables)
+
+	// The configurations are not modified
+	assert.Nil(t, config.SessionVariables)
+}
+
+// TestClient_NewTx_CockroachDB will test retrying the serialization failures (see: WithCockroachDB)
+func TestClient_NewTx_CockroachDB(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+
+	attempts := 0
+	fn := func(*Transaction) error {
+		if attempts++; attempts == 1 {
+			return &pgconn.PgError{Code: postgresSerializeError}
+		}
+		return nil
+	}
+
+	// Not retried by default
+	require.Error(t, client.NewTx(ctx, fn))
+	assert.Equal(t, 1, attempts)
+
+	c := client.(*Client)
+	c.options.engine, c.options.cockroachDB = PostgreSQL, true
+	defer func() {
+		c.options.engine = SQLite
+	}()
+
+	attempts = 0
+	require.NoError(t, client.NewTx(ctx, fn))
+	assert.Equal(t, 2, attempts)
+}
@@ -0,0 +1,163 @@
+package datastore
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidClientOptions is when the client options are an impossible combination (see: NewClient)
+var ErrInvalidClientOptions = errors.New("invalid client options")
+
+// redactedValue replaces the secrets of the effective configuration
+const redactedValue = "[redacted]"
+
+// ClientConfig is the effective configuration of the client, secrets are redacted (see: EffectiveConfig)
+type ClientConfig struct {
+	AutoMigrate        bool            `json:"auto_migrate"`             // Auto migrate the models (see: WithAutoMigrate)
+	CockroachDB        bool            `json:"cockroach_db"`             // PostgreSQL engine is a CockroachDB cluster
+	Debug              bool            `json:"debug"`                    // Debugging is enabled
+	Engine             Engine          `json:"engine"`                   // Datastore engine
+	MigrateModels      []string        `json:"migrate_models,omitempty"` // Models (types) to migrate
+	DynamoDB           *DynamoDBConfig `json:"dynamodb,omitempty"`       // DynamoDB configuration (credentials redacted)
+	MongoDB            *MongoDBConfig  `json:"mongodb,omitempty"`        // MongoDB configuration (URI password redacted)
+	NewRelic           bool            `json:"new_relic"`                // NewRelic is enabled
+	ResultSizeWarning  int             `json:"result_size_warning"`      // Warn when a GetModels result exceeds this many rows
+	SlowQueryThreshold time.Duration   `json:"slow_query_threshold"`     // Threshold for logging slow queries (zero is the logger default)
+	SoftDeletes        []string        `json:"soft_deletes,omitempty"`   // Soft-deleted models (by name)
+	SQL                []*SQLConfig    `json:"sql,omitempty"`            // MySQL or PostgreSQL configurations (passwords and DSNs redacted)
+	SQLite             *SQLiteConfig   `json:"sqlite,omitempty"`         // SQLite configuration
+	TablePrefix        string          `json:"table_prefix"`             // Model table prefix
+	TimeSeries         []string        `json:"time_series,omitempty"`    // Time-series models (by name)
+	Tombstones         bool            `json:"tombstones"`               // Tombstones are saved for the deleted records
+	Transactions       bool            `json:"transactions"`             // MongoDB transactions are enabled
+}
+
+// EffectiveConfig will return the effective configuration of the client (IE: to debug a misconfigured deployment)
+//
+// The SQL passwords and DSNs, the password of the MongoDB URI and the DynamoDB credentials are redacted
+func (c *Client) EffectiveConfig() *ClientConfig {
+	c.options.runtimeMu.RLock()
+	debug, slowQueryThreshold := c.options.debug, c.options.slowQueryThreshold
+	c.options.runtimeMu.RUnlock()
+
+	config := &ClientConfig{
+		AutoMigrate:        c.options.autoMigrate,
+		CockroachDB:        c.isCockroachDB(),
+		Debug:              debug,
+		Engine:             c.Engine(),
+		NewRelic:           c.options.newRelicEnabled,
+		ResultSizeWarning:  c.options.resultSizeWarning,
+		SlowQueryThreshold: slowQueryThreshold,
+		TablePrefix:        c.options.tablePrefix,
+		Tombstones:         c.options.tombstones,
+	}
+	for _, model := range c.options.migrateModels {
+		config.MigrateModels = append(config.MigrateModels, fmt.Sprintf("%T", model))
+	}
+	for name := range c.options.softDeletes {
+		config.SoftDeletes = append(config.SoftDeletes, name)
+	}
+	sort.Strings(config.SoftDeletes)
+	for name := range c.options.timeSeries {
+		config.TimeSeries = append(config.TimeSeries, name)
+	}
+	sort.Strings(config.TimeSeries)
+
+	switch {
+	case c.Engine() == DynamoDB && c.options.dynamoDBConfig != nil:
+		dynamoDBConfig := *c.options.dynamoDBConfig
+		if len(dynamoDBConfig.SecretAccessKey) > 0 {
+			dynamoDBConfig.SecretAccessKey = redactedValue
+		}
+		if len(dynamoDBConfig.SessionToken) > 0 {
+			dynamoDBConfig.SessionToken = redactedValue
+		}
+		config.DynamoDB = &dynamoDBConfig
+	case c.Engine() == MongoDB && c.options.mongoDBConfig != nil:
+		mongoConfig := *c.options.mongoDBConfig
+		mongoConfig.URI = redactURI(mongoConfig.URI)
+		config.MongoDB = &mongoConfig
+		config.Transactions = mongoConfig.Transactions
+	case c.Engine() == SQLite && c.options.sqLite != nil:
+		sqLiteConfig := *c.options.sqLite
+		config.SQLite = &sqLiteConfig
+	case IsSQLEngine(c.Engine()):
+		c.options.runtimeMu.RLock()
+		sqlConfigs := c.options.sqlConfigs
+		c.options.runtimeMu.RUnlock()
+		for _, sqlConfig := range sqlConfigs {
+			redacted := *sqlConfig
+			if len(redacted.Password) > 0 {
+				redacted.Password = redactedValue
+			}
+			if len(redacted.DSN) > 0 {
+				redacted.DSN = redactedValue
+			}
+			config.SQL = append(config.SQL, &redacted)
+		}
+	}
+	return config
+}
+
+// validateClientOptions will return an error describing the impossible combinations of the options
+// (before the default engine is set)
+func validateClientOptions(options *clientOptions) error {
+	var problems []string
+
+	// Only one engine can be used
+	engineOptions := make([]string, 0, len(options.engineOptions))
+	for _, name := range options.engineOptions {
+		if !containsString(engineOptions, name) {
+			engineOptions = append(engineOptions, name)
+		}
+	}
+	if len(engineOptions) > 1 {
+		problems = append(problems, "conflicting engine options: "+strings.Join(engineOptions, ", "))
+	}
+
+	// The SQL databases need a source (write) database
+	if options.engine == MySQL || options.engine == PostgreSQL {
+		hasSource := false
+		for _, config := range options.sqlConfigs {
+			if !config.Replica {
+				hasSource = true
+			}
+		}
+		if len(options.sqlConfigs) > 0 && !hasSource {
+			problems = append(problems, "all the SQL configurations are replicas (no source database)")
+		}
+	}
+
+	// CockroachDB is only wire-compatible with PostgreSQL
+	if options.cockroachDB && options.engine != PostgreSQL {
+		problems = append(problems, "WithCockroachDB requires the PostgreSQL engine")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidClientOptions, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// redactURI will redact the password of the connection URI (replaced by xxxxx, unparsable URIs are redacted)
+func redactURI(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return redactedValue
+	}
+	return parsed.Redacted()
+}
+
+// containsString will return true if the value is in the slice
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
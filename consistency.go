@@ -0,0 +1,174 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// ReadConcern is the MongoDB read concern level
+type ReadConcern string
+
+// ReadPreference is the MongoDB read preference mode
+type ReadPreference string
+
+// Read concern levels (MongoDB)
+const (
+	ReadConcernDefault  ReadConcern = ""         // Use the connection default
+	ReadConcernLocal    ReadConcern = "local"    // Most recent data on the node (may be rolled back)
+	ReadConcernMajority ReadConcern = "majority" // Data acknowledged by a majority of the replica set
+	ReadConcernSnapshot ReadConcern = "snapshot" // Majority-committed data from a single point in time
+)
+
+// Read preference modes (MongoDB)
+const (
+	ReadPreferenceDefault            ReadPreference = ""                   // Use the connection default
+	ReadPreferenceNearest            ReadPreference = "nearest"            // Lowest latency member
+	ReadPreferencePrimary            ReadPreference = "primary"            // Primary only
+	ReadPreferencePrimaryPreferred   ReadPreference = "primaryPreferred"   // Primary, or a secondary if unavailable
+	ReadPreferenceSecondary          ReadPreference = "secondary"          // Secondaries only
+	ReadPreferenceSecondaryPreferred ReadPreference = "secondaryPreferred" // Secondaries, or the primary if unavailable
+)
+
+// readConsistencyKey is the context key for the read consistency
+type readConsistencyKey struct{}
+
+// writeConcernKey is the context key for the write concern
+type writeConcernKey struct{}
+
+// WriteConcern is the MongoDB write concern (acknowledgment level) for writes
+type WriteConcern struct {
+	Journal  bool          // Wait for the write to be written to the on-disk journal (j:true)
+	Majority bool          // Wait for a majority of the replica set to acknowledge (w:majority)
+	W        int           // Number of members that must acknowledge (ignored if Majority is set)
+	WTimeout time.Duration // Time limit for the write concern (only for w > 1 or majority)
+}
+
+// readConsistency is the read concern and read preference for the reads using the context
+type readConsistency struct {
+	concern    ReadConcern
+	preference ReadPreference
+}
+
+// WithReadConsistency will set the read concern and read preference for all Mongo reads using the context
+//
+// IE: majority reads for consistency, or secondaryPreferred for analytics reads
+// SQL engines ignore the read consistency
+func WithReadConsistency(ctx context.Context, concern ReadConcern, preference ReadPreference) context.Context {
+	return context.WithValue(ctx, readConsistencyKey{}, &readConsistency{
+		concern:    concern,
+		preference: preference,
+	})
+}
+
+// WithWriteConcern will set the write concern for all Mongo writes using the context
+//
+// Applies to SaveModel, IncrementModel and CreateInBatches (ignored inside a Mongo transaction, which
+// uses the transaction's write concern), SQL engines ignore the write concern
+// Use majority read and write concerns inside a causal session for the full causal guarantees
+func WithWriteConcern(ctx context.Context, concern WriteConcern) context.Context {
+	return context.WithValue(ctx, writeConcernKey{}, &concern)
+}
+
+// getMongoWriteCollection will get the mongo collection (full name) using the write concern from the context
+func (c *Client) getMongoWriteCollection(ctx context.Context, collectionName string) *mongo.Collection {
+	if opts := getMongoWriteOptions(ctx); opts != nil {
+		return c.options.mongoDB.Collection(collectionName, opts)
+	}
+	return c.options.mongoDB.Collection(collectionName)
+}
+
+// getMongoWriteOptions will return the collection options for the write concern from the context (if set)
+func getMongoWriteOptions(ctx context.Context) *options.CollectionOptions {
+	concern, ok := ctx.Value(writeConcernKey{}).(*WriteConcern)
+	if !ok || concern == nil || (mongo.SessionFromContext(ctx) != nil && !isCausalSession(ctx)) {
+		return nil
+	}
+
+	wc := &writeconcern.WriteConcern{WTimeout: concern.WTimeout}
+	if concern.Majority {
+		wc.W = "majority"
+	} else if concern.W > 0 {
+		wc.W = concern.W
+	}
+	if concern.Journal {
+		journal := true
+		wc.Journal = &journal
+	}
+	if wc.W == nil && wc.Journal == nil {
+		return nil
+	}
+	return options.Collection().SetWriteConcern(wc)
+}
+
+// getMongoReadCollection will get the mongo collection (full name) using the read consistency from the context
+func (c *Client) getMongoReadCollection(ctx context.Context, collectionName string) *mongo.Collection {
+	if opts := getMongoReadOptions(ctx); opts != nil {
+		return c.options.mongoDB.Collection(collectionName, opts)
+	}
+	return c.options.mongoDB.Collection(collectionName)
+}
+
+// getMongoReadOptions will return the collection options for the read consistency from the context (if set)
+func getMongoReadOptions(ctx context.Context) *options.CollectionOptions {
+	consistency, ok := ctx.Value(readConsistencyKey{}).(*readConsistency)
+	if !ok || consistency == nil {
+		return nil
+	}
+
+	opts := options.Collection()
+	set := false
+	if concern := getReadConcern(consistency.concern); concern != nil {
+		opts.SetReadConcern(concern)
+		set = true
+	}
+	if preference := getReadPreference(consistency.preference); preference != nil {
+		opts.SetReadPreference(preference)
+		set = true
+	}
+	if !set {
+		return nil
+	}
+	return opts
+}
+
+// getReadConcern will return the Mongo read concern for the level (nil for the default)
+func getReadConcern(concern ReadConcern) *readconcern.ReadConcern {
+	switch concern {
+	case ReadConcernLocal:
+		return readconcern.Local()
+	case ReadConcernMajority:
+		return readconcern.Majority()
+	case ReadConcernSnapshot:
+		return readconcern.Snapshot()
+	case ReadConcernDefault:
+		return nil
+	default:
+		return nil
+	}
+}
+
+// getReadPreference will return the Mongo read preference for the mode (nil for the default)
+func getReadPreference(preference ReadPreference) *readpref.ReadPref {
+	switch preference {
+	case ReadPreferenceNearest:
+		return readpref.Nearest()
+	case ReadPreferencePrimary:
+		return readpref.Primary()
+	case ReadPreferencePrimaryPreferred:
+		return readpref.PrimaryPreferred()
+	case ReadPreferenceSecondary:
+		return readpref.Secondary()
+	case ReadPreferenceSecondaryPreferred:
+		return readpref.SecondaryPreferred()
+	case ReadPreferenceDefault:
+		return nil
+	default:
+		return nil
+	}
+}
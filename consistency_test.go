@@ -0,0 +1,98 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// TestGetMongoReadOptions will test the method getMongoReadOptions()
+func TestGetMongoReadOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no read consistency", func(t *testing.T) {
+		assert.Nil(t, getMongoReadOptions(context.Background()))
+	})
+
+	t.Run("default read consistency", func(t *testing.T) {
+		ctx := WithReadConsistency(context.Background(), ReadConcernDefault, ReadPreferenceDefault)
+		assert.Nil(t, getMongoReadOptions(ctx))
+	})
+
+	t.Run("majority from the primary", func(t *testing.T) {
+		ctx := WithReadConsistency(context.Background(), ReadConcernMajority, ReadPreferencePrimary)
+		opts := getMongoReadOptions(ctx)
+		require.NotNil(t, opts)
+		assert.Equal(t, readconcern.Majority(), opts.ReadConcern)
+		assert.Equal(t, readpref.PrimaryMode, opts.ReadPreference.Mode())
+	})
+
+	t.Run("secondary preferred only", func(t *testing.T) {
+		ctx := WithReadConsistency(context.Background(), ReadConcernDefault, ReadPreferenceSecondaryPreferred)
+		opts := getMongoReadOptions(ctx)
+		require.NotNil(t, opts)
+		assert.Nil(t, opts.ReadConcern)
+		assert.Equal(t, readpref.SecondaryPreferredMode, opts.ReadPreference.Mode())
+	})
+}
+
+// TestGetReadConcern will test the method getReadConcern()
+func TestGetReadConcern(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, getReadConcern(ReadConcernDefault))
+	assert.Nil(t, getReadConcern("unknown"))
+	assert.Equal(t, readconcern.Local(), getReadConcern(ReadConcernLocal))
+	assert.Equal(t, readconcern.Majority(), getReadConcern(ReadConcernMajority))
+	assert.Equal(t, readconcern.Snapshot(), getReadConcern(ReadConcernSnapshot))
+}
+
+// TestGetReadPreference will test the method getReadPreference()
+func TestGetReadPreference(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, getReadPreference(ReadPreferenceDefault))
+	assert.Nil(t, getReadPreference("unknown"))
+	assert.Equal(t, readpref.NearestMode, getReadPreference(ReadPreferenceNearest).Mode())
+	assert.Equal(t, readpref.PrimaryMode, getReadPreference(ReadPreferencePrimary).Mode())
+	assert.Equal(t, readpref.PrimaryPreferredMode, getReadPreference(ReadPreferencePrimaryPreferred).Mode())
+	assert.Equal(t, readpref.SecondaryMode, getReadPreference(ReadPreferenceSecondary).Mode())
+	assert.Equal(t, readpref.SecondaryPreferredMode, getReadPreference(ReadPreferenceSecondaryPreferred).Mode())
+}
+
+// TestGetMongoWriteOptions will test the method getMongoWriteOptions()
+func TestGetMongoWriteOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no write concern", func(t *testing.T) {
+		assert.Nil(t, getMongoWriteOptions(context.Background()))
+		assert.Nil(t, getMongoWriteOptions(WithWriteConcern(context.Background(), WriteConcern{})))
+	})
+
+	t.Run("majority and journaled", func(t *testing.T) {
+		ctx := WithWriteConcern(context.Background(), WriteConcern{
+			Journal:  true,
+			Majority: true,
+			W:        3,
+			WTimeout: 5 * time.Second,
+		})
+		opts := getMongoWriteOptions(ctx)
+		require.NotNil(t, opts)
+		assert.Equal(t, "majority", opts.WriteConcern.W)
+		require.NotNil(t, opts.WriteConcern.Journal)
+		assert.True(t, *opts.WriteConcern.Journal)
+		assert.Equal(t, 5*time.Second, opts.WriteConcern.WTimeout)
+	})
+
+	t.Run("number of members", func(t *testing.T) {
+		opts := getMongoWriteOptions(WithWriteConcern(context.Background(), WriteConcern{W: 2}))
+		require.NotNil(t, opts)
+		assert.Equal(t, 2, opts.WriteConcern.W)
+		assert.Nil(t, opts.WriteConcern.Journal)
+	})
+}
@@ -0,0 +1,122 @@
+package datastore
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ColumnConverter will convert a scanned column value (IE: PostgreSQL numeric into a decimal type)
+type ColumnConverter func(value interface{}) (interface{}, error)
+
+// ConvertToString will convert a scanned value into a string (IE: MySQL returns []byte for text columns)
+func ConvertToString(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// ConvertToInt64 will convert a scanned value into an int64 (IE: counts returned as []byte, numeric or float)
+func ConvertToInt64(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case uint64:
+		return int64(v), nil //nolint:gosec // counts do not overflow
+	case float64:
+		return int64(v), nil
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return nil, fmt.Errorf("cannot convert %T to int64", value)
+	}
+}
+
+// ConvertToFloat64 will convert a scanned value into a float64 (IE: numeric or decimal columns returned as []byte)
+func ConvertToFloat64(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case []byte:
+		return strconv.ParseFloat(string(v), 64)
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return nil, fmt.Errorf("cannot convert %T to float64", value)
+	}
+}
+
+// convertRow will apply the registered column converters to the row (map result)
+func (c *Client) convertRow(row map[string]interface{}) error {
+	for column, converter := range c.options.columnConverters {
+		value, ok := row[column]
+		if !ok {
+			continue
+		}
+		converted, err := converter(getScannedValue(value))
+		if err != nil {
+			return fmt.Errorf("failed converting column %s: %w", column, err)
+		}
+		row[column] = converted
+	}
+	return nil
+}
+
+// convertMapResults will apply the registered column converters to map results (single map or slice of maps)
+func (c *Client) convertMapResults(results interface{}) error {
+	if len(c.options.columnConverters) == 0 {
+		return nil
+	}
+
+	switch r := results.(type) {
+	case map[string]interface{}:
+		return c.convertRow(r)
+	case *map[string]interface{}:
+		return c.convertRow(*r)
+	case []map[string]interface{}:
+		return c.convertRows(r)
+	case *[]map[string]interface{}:
+		return c.convertRows(*r)
+	}
+	return nil
+}
+
+// convertRows will apply the registered column converters to each row
+func (c *Client) convertRows(rows []map[string]interface{}) error {
+	for _, row := range rows {
+		if err := c.convertRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getScannedValue will return the value scanned into a map (GORM can scan the values as *interface{})
+func getScannedValue(value interface{}) interface{} {
+	if pointer, ok := value.(*interface{}); ok && pointer != nil {
+		return *pointer
+	}
+	return value
+}
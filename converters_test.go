@@ -0,0 +1,98 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestColumnConverters will test the built-in column converters
+func TestColumnConverters(t *testing.T) {
+	t.Run("ConvertToString", func(t *testing.T) {
+		for input, expected := range map[interface{}]interface{}{
+			"value": "value", int64(10): "10", nil: nil,
+		} {
+			value, err := ConvertToString(input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, value)
+		}
+		value, err := ConvertToString([]byte("bytes"))
+		require.NoError(t, err)
+		assert.Equal(t, "bytes", value)
+	})
+
+	t.Run("ConvertToInt64", func(t *testing.T) {
+		for input, expected := range map[interface{}]interface{}{
+			"10": int64(10), 10: int64(10), int64(10): int64(10), float64(10): int64(10), nil: nil,
+		} {
+			value, err := ConvertToInt64(input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, value)
+		}
+		value, err := ConvertToInt64([]byte("42"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), value)
+
+		_, err = ConvertToInt64("invalid")
+		require.Error(t, err)
+		_, err = ConvertToInt64(true)
+		require.Error(t, err)
+	})
+
+	t.Run("ConvertToFloat64", func(t *testing.T) {
+		value, err := ConvertToFloat64([]byte("12.50"))
+		require.NoError(t, err)
+		assert.InDelta(t, 12.5, value, 0.001)
+
+		value, err = ConvertToFloat64(int64(3))
+		require.NoError(t, err)
+		assert.InDelta(t, 3.0, value, 0.001)
+
+		_, err = ConvertToFloat64(true)
+		require.Error(t, err)
+	})
+}
+
+// TestClient_convertMapResults will test the method convertMapResults()
+func TestClient_convertMapResults(t *testing.T) {
+	records := []*testSQLModel{
+		{ID: "convert-1", Name: "alice", Amount: 10},
+		{ID: "convert-2", Name: "bob", Amount: 20},
+	}
+
+	t.Run("field results are converted", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithColumnConverter("amount", ConvertToFloat64))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		var models []*testSQLModel
+		var fieldResults []map[string]interface{}
+		require.NoError(t, client.GetModels(ctx, &models, nil,
+			&QueryParams{OrderByField: sqlIDField, SortDirection: SortAsc}, &fieldResults, defaultDatabaseMaxTimeout))
+		require.Len(t, fieldResults, 2)
+		assert.IsType(t, float64(0), fieldResults[0]["amount"])
+		assert.InDelta(t, 20.0, fieldResults[1]["amount"], 0.001)
+	})
+
+	t.Run("aggregate results are converted", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithColumnConverter(accumulationCountField, ConvertToFloat64))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		results, err := client.GetModelsAggregate(ctx, &[]*testSQLModel{}, nil, "name", defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"alice": float64(1), "bob": float64(1)}, results)
+	})
+
+	t.Run("converter errors are returned", func(t *testing.T) {
+		client := &Client{options: &clientOptions{}}
+		WithColumnConverter("name", ConvertToInt64)(client.options)
+		require.Error(t, client.convertMapResults(&[]map[string]interface{}{{"name": "alice"}}))
+		require.NoError(t, client.convertMapResults(map[string]interface{}{"other": "alice"}))
+		require.NoError(t, client.convertMapResults(nil))
+	})
+}
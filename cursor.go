@@ -0,0 +1,209 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-datastore/nrgorm"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidCursor is when the cursor does not match the cursor sort fields
+var ErrInvalidCursor = errors.New("cursor values do not match the cursor fields")
+
+// Cursor is the keyset pagination cursor (the sort field values of the last record of a page)
+type Cursor []interface{}
+
+// CursorParams object to use when paginating using a keyset (cursor) over one or more sort fields
+//
+// Use a unique field as the last sort field (IE: created_at, id) so records are never skipped
+type CursorParams struct {
+	After         Cursor   `json:"after,omitempty"`          // Cursor from the previous page (empty for the first page)
+	Fields        []string `json:"fields,omitempty"`         // Sort fields (IE: created_at, id)
+	PageSize      int      `json:"page_size,omitempty"`      // Records per page
+	SortDirection string   `json:"sort_direction,omitempty"` // Sort direction for all fields (asc / desc)
+}
+
+// GetModelsByCursor will return a page of models after the given cursor (keyset pagination)
+//
+// Returns the cursor for the next page (nil if this was the last page)
+func (c *Client) GetModelsByCursor(
+	ctx context.Context,
+	models interface{},
+	conditions map[string]interface{},
+	cursorParams *CursorParams,
+	timeout time.Duration,
+) (Cursor, error) {
+
+	// Check the params
+	if cursorParams == nil || len(cursorParams.Fields) == 0 {
+		return nil, errors.New("cursor params are missing the sort fields")
+	} else if len(cursorParams.After) > 0 && len(cursorParams.After) != len(cursorParams.Fields) {
+		return nil, ErrInvalidCursor
+	}
+
+	// Use a copy (the caller's params are not modified)
+	params := *cursorParams
+	if params.PageSize < 1 {
+		params.PageSize = defaultPageSize
+	}
+	params.SortDirection = strings.ToLower(params.SortDirection)
+	cursorParams = &params
+
+	// Exclude the soft-deleted records
+	conditions = c.getSoftDeleteConditions(ctx, models, conditions)
+
+	// Switch on the datastore engines
+	var err error
+	if c.Engine() == MongoDB {
+		err = c.findByCursorWithMongo(ctx, models, conditions, cursorParams)
+	} else if !IsSQLEngine(c.Engine()) {
+		return nil, ErrUnsupportedEngine
+	} else {
+		err = c.findByCursor(ctx, models, conditions, cursorParams, timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the cursor from the last record (if the page is full)
+	slice := reflect.Indirect(reflect.ValueOf(models))
+	if slice.Len() < cursorParams.PageSize {
+		return nil, nil
+	}
+	next := make(Cursor, 0, len(cursorParams.Fields))
+	for _, field := range cursorParams.Fields {
+		value, ok := getModelColumnField(slice.Index(slice.Len()-1).Interface(), field)
+		if !ok {
+			return nil, errors.New("cursor field not found on model: " + field)
+		}
+		next = append(next, value)
+	}
+	return next, nil
+}
+
+// findByCursor will get a page of records using a tuple comparison: (a, b) > (?, ?)
+func (c *Client) findByCursor(ctx context.Context, models interface{}, conditions map[string]interface{},
+	cursorParams *CursorParams, timeout time.Duration) error {
+
+	// Find the type
+	if reflect.TypeOf(models).Elem().Kind() != reflect.Slice {
+		return errors.New("field: result is not a slice, found: " + reflect.TypeOf(models).Kind().String())
+	}
+
+	// Set the NewRelic txn
+	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+	// Create a new context, and new db tx
+	ctxDB, cancel, err := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tx := ctxDB.Model(models).Limit(cursorParams.PageSize)
+
+	// Order by all the cursor fields
+	desc := cursorParams.SortDirection == SortDesc
+	columns := make([]string, 0, len(cursorParams.Fields))
+	for _, field := range cursorParams.Fields {
+		column := quoteIdentifier(c.Engine(), field)
+		columns = append(columns, column)
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: column, Raw: true}, Desc: desc})
+	}
+
+	// After the cursor
+	if len(cursorParams.After) > 0 {
+		operator := " > "
+		if desc {
+			operator = " < "
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(cursorParams.After)), ", ")
+		tx = tx.Where(
+			"("+strings.Join(columns, ", ")+")"+operator+"("+placeholders+")",
+			cursorParams.After...,
+		)
+	}
+
+	// Add conditions
+	if len(conditions) > 0 {
+		gtx := gormWhere{tx: tx}
+		return checkResult(c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB).Find(models))
+	}
+
+	return checkResult(tx.Find(models))
+}
+
+// findByCursorWithMongo will get a page of records using an $or expansion of the tuple comparison
+//
+// (a, b) > (x, y) becomes: {$or: [{a: {$gt: x}}, {a: x, b: {$gt: y}}]}
+func (c *Client) findByCursorWithMongo(ctx context.Context, models interface{}, conditions map[string]interface{},
+	cursorParams *CursorParams) error {
+
+	collectionName := GetModelTableName(models)
+	if collectionName == nil {
+		return ErrUnknownCollection
+	}
+
+	// Use the Mongo _id field
+	fields := make([]string, 0, len(cursorParams.Fields))
+	for _, field := range cursorParams.Fields {
+		if field == sqlIDField {
+			field = mongoIDField
+		}
+		fields = append(fields, field)
+	}
+
+	// Add the cursor conditions
+	queryConditions := getMongoQueryConditions(models, conditions, c.GetMongoConditionProcessor())
+	if len(cursorParams.After) > 0 {
+		operator := conditionGreaterThan
+		if cursorParams.SortDirection == SortDesc {
+			operator = conditionLessThan
+		}
+		or := make([]map[string]interface{}, 0, len(fields))
+		for i := range fields {
+			expansion := make(map[string]interface{})
+			for j := 0; j < i; j++ {
+				expansion[fields[j]] = cursorParams.After[j]
+			}
+			expansion[fields[i]] = map[string]interface{}{operator: cursorParams.After[i]}
+			or = append(or, expansion)
+		}
+		queryConditions = map[string]interface{}{conditionAnd: []map[string]interface{}{
+			queryConditions, {conditionOr: or},
+		}}
+	}
+
+	// Sort by all the cursor fields
+	sortOrder := 1
+	if cursorParams.SortDirection == SortDesc {
+		sortOrder = -1
+	}
+	sort := bson.D{}
+	for _, field := range fields {
+		sort = append(sort, bson.E{Key: field, Value: sortOrder})
+	}
+
+	collection := c.getMongoReadCollection(ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName))
+	cursor, err := collection.Find(
+		ctx, queryConditions, options.Find().SetSort(sort).SetLimit(int64(cursorParams.PageSize)),
+	)
+	if err != nil {
+		return err
+	}
+	if err = cursor.All(ctx, models); err != nil {
+		return err
+	}
+	if reflect.Indirect(reflect.ValueOf(models)).Len() == 0 {
+		return ErrNoResults
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_GetModelsByCursor will test the method GetModelsByCursor()
+func TestClient_GetModelsByCursor(t *testing.T) {
+
+	// Non-unique timestamps (the id breaks the tie)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	records := []*testSQLModel{
+		{ID: "cursor-1", Name: "a", CreatedAt: now},
+		{ID: "cursor-2", Name: "b", CreatedAt: now},
+		{ID: "cursor-3", Name: "c", CreatedAt: now},
+		{ID: "cursor-4", Name: "d", CreatedAt: now.Add(time.Hour)},
+		{ID: "cursor-5", Name: "e", CreatedAt: now.Add(time.Hour)},
+	}
+
+	// getAllPages will page through all records
+	getAllPages := func(t *testing.T, client ClientInterface, direction string) []string {
+		var ids []string
+		params := &CursorParams{Fields: []string{dateCreatedAt, sqlIDField}, PageSize: 2, SortDirection: direction}
+		for page := 0; page < 10; page++ {
+			var models []*testSQLModel
+			next, err := client.GetModelsByCursor(context.Background(), &models, nil, params, defaultDatabaseMaxTimeout)
+			if len(ids) == len(records) {
+				require.ErrorIs(t, err, ErrNoResults)
+				break
+			}
+			require.NoError(t, err)
+			for _, m := range models {
+				ids = append(ids, m.ID)
+			}
+			if next == nil {
+				break
+			}
+			params.After = next
+		}
+		return ids
+	}
+
+	t.Run("ascending over multiple fields", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		assert.Equal(t, []string{"cursor-1", "cursor-2", "cursor-3", "cursor-4", "cursor-5"}, getAllPages(t, client, SortAsc))
+	})
+
+	t.Run("descending over multiple fields", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		assert.Equal(t, []string{"cursor-5", "cursor-4", "cursor-3", "cursor-2", "cursor-1"}, getAllPages(t, client, SortDesc))
+	})
+
+	t.Run("with conditions", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		var models []*testSQLModel
+		next, err := client.GetModelsByCursor(ctx, &models, map[string]interface{}{"name": "b"}, &CursorParams{
+			Fields: []string{dateCreatedAt, sqlIDField},
+		}, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		require.Len(t, models, 1)
+		assert.Equal(t, "cursor-2", models[0].ID)
+	})
+
+	t.Run("the params are not modified", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		params := &CursorParams{Fields: []string{dateCreatedAt, sqlIDField}, SortDirection: "DESC"}
+		var models []*testSQLModel
+		_, err := client.GetModelsByCursor(ctx, &models, nil, params, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		require.Len(t, models, len(records))
+		assert.Equal(t, "cursor-5", models[0].ID)
+		assert.Equal(t, 0, params.PageSize)
+		assert.Equal(t, "DESC", params.SortDirection)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var models []*testSQLModel
+		_, err := client.GetModelsByCursor(ctx, &models, nil, nil, defaultDatabaseMaxTimeout)
+		require.Error(t, err)
+
+		_, err = client.GetModelsByCursor(ctx, &models, nil, &CursorParams{
+			After:  Cursor{"1"},
+			Fields: []string{dateCreatedAt, sqlIDField},
+		}, defaultDatabaseMaxTimeout)
+		require.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
@@ -0,0 +1,184 @@
+package customtypes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidEnumValue is when the value is not one of the allowed enum values
+var ErrInvalidEnumValue = errors.New("invalid enum value")
+
+// EnumType is a string type that declares its allowed values
+//
+// Example:
+//
+//	type Status string
+//	func (Status) EnumValues() []string { return []string{"active", "disabled"} }
+type EnumType interface {
+	~string
+	EnumValues() []string
+}
+
+// Enum wrapper around a string type that only allows the declared values (an empty value is stored as NULL)
+//
+// The allowed values are validated on Scan, Value, BSON and JSON (un)marshalling and
+// rendered as a CHECK constraint when the table is created by AutoMigrate
+type Enum[T EnumType] struct { //nolint:recvcheck // This is intentional
+	Data T
+}
+
+// NewEnum will return a new enum (or an error if the value is not allowed)
+func NewEnum[T EnumType](value T) (Enum[T], error) {
+	e := Enum[T]{Data: value}
+	if !e.IsValid() {
+		return Enum[T]{}, newInvalidEnumError(value)
+	}
+	return e, nil
+}
+
+// IsValid will return true if the value is empty or one of the allowed values
+func (e Enum[T]) IsValid() bool {
+	if e.Data == "" {
+		return true
+	}
+	for _, value := range e.Data.EnumValues() {
+		if value == string(e.Data) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsZero method is called by bson.IsZero in Mongo for type = Enum
+func (e Enum[T]) IsZero() bool {
+	return e.Data == ""
+}
+
+// String will return the enum value as a string
+func (e Enum[T]) String() string {
+	return string(e.Data)
+}
+
+// Scan will scan the value from the database (sql.Scanner)
+func (e *Enum[T]) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		e.Data = ""
+		return nil
+	case string:
+		return e.set(v)
+	case []byte:
+		return e.set(string(v))
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidEnumValue, value)
+	}
+}
+
+// Value will return the value for the database (driver.Valuer)
+func (e Enum[T]) Value() (driver.Value, error) {
+	if e.Data == "" {
+		return nil, nil
+	} else if !e.IsValid() {
+		return nil, newInvalidEnumError(e.Data)
+	}
+	return string(e.Data), nil
+}
+
+// GormDataType will return the general data type for gorm
+func (Enum[T]) GormDataType() string {
+	return string(schema.String)
+}
+
+// GormDBDataType will return the column type with a CHECK constraint of the allowed values
+func (Enum[T]) GormDBDataType(_ *gorm.DB, field *schema.Field) string {
+	var zero T
+	values := zero.EnumValues()
+
+	size := field.Size
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		if len(value) > size {
+			size = len(value)
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(value, "'", "''")+"'")
+	}
+	if size == 0 {
+		size = 1
+	}
+
+	return "varchar(" + strconv.Itoa(size) + ") CHECK (" + field.DBName + " IN (" + strings.Join(quoted, ", ") + "))"
+}
+
+// MarshalBSONValue method is called by bson.Marshal in Mongo for type = Enum
+func (e Enum[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if e.Data == "" {
+		return bsontype.Null, nil, nil
+	} else if !e.IsValid() {
+		return 0, nil, newInvalidEnumError(e.Data)
+	}
+
+	return bson.MarshalValue(string(e.Data))
+}
+
+// UnmarshalBSONValue method is called by bson.Unmarshal in Mongo for type = Enum
+func (e *Enum[T]) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+	if raw.Value == nil || t == bsontype.Null {
+		e.Data = ""
+		return nil
+	}
+
+	var value string
+	if err := raw.Unmarshal(&value); err != nil {
+		return err
+	}
+	return e.set(value)
+}
+
+// MarshalJSON method is called by the JSON marshaller
+func (e Enum[T]) MarshalJSON() ([]byte, error) {
+	if e.Data == "" {
+		return []byte("null"), nil
+	} else if !e.IsValid() {
+		return nil, newInvalidEnumError(e.Data)
+	}
+
+	return json.Marshal(string(e.Data))
+}
+
+// UnmarshalJSON method is called by the JSON unmarshaller
+func (e *Enum[T]) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == nil {
+		e.Data = ""
+		return nil
+	}
+	return e.set(*value)
+}
+
+// set will set the value (if allowed)
+func (e *Enum[T]) set(value string) error {
+	enum := Enum[T]{Data: T(value)}
+	if !enum.IsValid() {
+		return newInvalidEnumError(value)
+	}
+	e.Data = enum.Data
+	return nil
+}
+
+// newInvalidEnumError will return an error for the value that is not allowed
+func newInvalidEnumError[T ~string](value T) error {
+	return fmt.Errorf("%w: %q", ErrInvalidEnumValue, string(value))
+}
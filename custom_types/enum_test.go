@@ -0,0 +1,162 @@
+package customtypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"gorm.io/gorm/schema"
+)
+
+// testStatus is a test enum type
+type testStatus string
+
+// EnumValues will return the allowed values
+func (testStatus) EnumValues() []string {
+	return []string{"active", "disabled"}
+}
+
+// TestNewEnum will test the method NewEnum()
+func TestNewEnum(t *testing.T) {
+	t.Run("allowed value", func(t *testing.T) {
+		e, err := NewEnum(testStatus("active"))
+		require.NoError(t, err)
+		assert.Equal(t, "active", e.String())
+		assert.True(t, e.IsValid())
+		assert.False(t, e.IsZero())
+	})
+
+	t.Run("empty value", func(t *testing.T) {
+		e, err := NewEnum(testStatus(""))
+		require.NoError(t, err)
+		assert.True(t, e.IsZero())
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := NewEnum(testStatus("deleted"))
+		require.ErrorIs(t, err, ErrInvalidEnumValue)
+	})
+}
+
+// TestEnum_Scan will test the method Scan()
+func TestEnum_Scan(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		e := Enum[testStatus]{Data: "active"}
+		require.NoError(t, e.Scan(nil))
+		assert.True(t, e.IsZero())
+	})
+
+	t.Run("string and bytes", func(t *testing.T) {
+		var e Enum[testStatus]
+		require.NoError(t, e.Scan("active"))
+		assert.Equal(t, testStatus("active"), e.Data)
+		require.NoError(t, e.Scan([]byte("disabled")))
+		assert.Equal(t, testStatus("disabled"), e.Data)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		e := Enum[testStatus]{Data: "active"}
+		require.ErrorIs(t, e.Scan("deleted"), ErrInvalidEnumValue)
+		assert.Equal(t, testStatus("active"), e.Data)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		var e Enum[testStatus]
+		require.ErrorIs(t, e.Scan(123), ErrInvalidEnumValue)
+	})
+}
+
+// TestEnum_Value will test the method Value()
+func TestEnum_Value(t *testing.T) {
+	t.Run("empty is null", func(t *testing.T) {
+		value, err := Enum[testStatus]{}.Value()
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("allowed value", func(t *testing.T) {
+		value, err := Enum[testStatus]{Data: "active"}.Value()
+		require.NoError(t, err)
+		assert.Equal(t, "active", value)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := Enum[testStatus]{Data: "deleted"}.Value()
+		require.ErrorIs(t, err, ErrInvalidEnumValue)
+	})
+}
+
+// TestEnum_GormDBDataType will test the method GormDBDataType()
+func TestEnum_GormDBDataType(t *testing.T) {
+	dataType := Enum[testStatus]{}.GormDBDataType(nil, &schema.Field{DBName: "status"})
+	assert.Equal(t, "varchar(8) CHECK (status IN ('active', 'disabled'))", dataType)
+
+	dataType = Enum[testStatus]{}.GormDBDataType(nil, &schema.Field{DBName: "status", Size: 32})
+	assert.Equal(t, "varchar(32) CHECK (status IN ('active', 'disabled'))", dataType)
+
+	assert.Equal(t, "string", Enum[testStatus]{}.GormDataType())
+}
+
+// TestEnum_BSON will test the methods MarshalBSONValue() and UnmarshalBSONValue()
+func TestEnum_BSON(t *testing.T) {
+	type testDoc struct {
+		Status Enum[testStatus] `bson:"status"`
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		b, err := bson.Marshal(testDoc{Status: Enum[testStatus]{Data: "active"}})
+		require.NoError(t, err)
+
+		var doc testDoc
+		require.NoError(t, bson.Unmarshal(b, &doc))
+		assert.Equal(t, testStatus("active"), doc.Status.Data)
+	})
+
+	t.Run("empty is null", func(t *testing.T) {
+		valueType, b, err := Enum[testStatus]{}.MarshalBSONValue()
+		require.NoError(t, err)
+		assert.Equal(t, bsontype.Null, valueType)
+		assert.Nil(t, b)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := bson.Marshal(testDoc{Status: Enum[testStatus]{Data: "deleted"}})
+		require.Error(t, err)
+
+		b, err := bson.Marshal(bson.M{"status": "deleted"})
+		require.NoError(t, err)
+		var doc testDoc
+		require.ErrorIs(t, bson.Unmarshal(b, &doc), ErrInvalidEnumValue)
+	})
+}
+
+// TestEnum_JSON will test the methods MarshalJSON() and UnmarshalJSON()
+func TestEnum_JSON(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		b, err := json.Marshal(Enum[testStatus]{Data: "disabled"})
+		require.NoError(t, err)
+		assert.Equal(t, `"disabled"`, string(b))
+
+		var e Enum[testStatus]
+		require.NoError(t, json.Unmarshal(b, &e))
+		assert.Equal(t, testStatus("disabled"), e.Data)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		b, err := json.Marshal(Enum[testStatus]{})
+		require.NoError(t, err)
+		assert.Equal(t, "null", string(b))
+
+		e := Enum[testStatus]{Data: "active"}
+		require.NoError(t, json.Unmarshal([]byte("null"), &e))
+		assert.True(t, e.IsZero())
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		var e Enum[testStatus]
+		require.ErrorIs(t, json.Unmarshal([]byte(`"deleted"`), &e), ErrInvalidEnumValue)
+	})
+}
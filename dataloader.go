@@ -0,0 +1,147 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+	"github.com/mrz1836/go-datastore/nrgorm"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"gorm.io/gorm"
+)
+
+// BatchGetByKeys will get all models matching any of the given keys (single query) and return a map keyed by the column value
+//
+// Designed for use inside gqlgen dataloaders, eliminating per-resolver GetModel calls
+// models is a pointer to a slice of models (IE: &[]*Xpub{}), the map values are the slice elements
+func (c *Client) BatchGetByKeys(
+	ctx context.Context,
+	models interface{},
+	keyColumn string,
+	keys []string,
+	timeout time.Duration,
+) (map[string]interface{}, error) {
+
+	// Make sure it's a slice
+	if !IsModelSlice(models) {
+		return nil, errors.New("field: models is not a slice, found: " + reflect.TypeOf(models).Kind().String())
+	}
+
+	// Nothing to get
+	results := make(map[string]interface{})
+	if len(keys) == 0 {
+		return results, nil
+	}
+
+	// Exclude the soft-deleted records
+	softConditions := c.getSoftDeleteConditions(ctx, models, nil)
+
+	// Switch on the datastore engines
+	var err error
+	if c.Engine() == MongoDB {
+		conditions := map[string]interface{}{keyColumn: map[string]interface{}{conditionIn: keys}}
+		for key, value := range softConditions {
+			conditions[key] = value
+		}
+		err = c.getWithMongo(ctx, models, conditions, nil, &QueryParams{})
+	} else if !IsSQLEngine(c.Engine()) {
+		return nil, ErrUnsupportedEngine
+	} else {
+
+		// Set the NewRelic txn
+		c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+		// Create a new context, and new db tx
+		ctxDB, cancel, ctxErr := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+		if ctxErr != nil {
+			return nil, ctxErr
+		}
+		defer cancel()
+
+		tx := ctxDB.Model(models).Where(quoteIdentifier(c.Engine(), keyColumn)+" IN ?", keys)
+		if len(softConditions) > 0 {
+			gtx := gormWhere{tx: tx}
+			tx = c.CustomWhere(&gtx, softConditions, c.Engine()).(*gorm.DB)
+		}
+		err = checkResult(tx.Find(models))
+	}
+	if errors.Is(err, ErrNoResults) {
+		return results, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Key the results by the column value
+	slice := reflect.Indirect(reflect.ValueOf(models))
+	for i := 0; i < slice.Len(); i++ {
+		item := slice.Index(i)
+		if item.Kind() != reflect.Ptr {
+			item = item.Addr()
+		}
+		if key := getModelColumnValue(item.Interface(), keyColumn); key != nil {
+			results[*key] = item.Interface()
+		}
+	}
+
+	return results, nil
+}
+
+// getModelColumnValue will get the value (as a string) of the model field matching the column name
+//
+// Fields are matched by the gorm column, bson, json tags or the snake case field name
+func getModelColumnValue(model interface{}, column string) *string {
+	value, ok := getModelColumnField(model, column)
+	if !ok {
+		return nil
+	}
+	str := fmt.Sprint(value)
+	return &str
+}
+
+// getModelColumnField will get the value of the model field matching the column name
+func getModelColumnField(model interface{}, column string) (interface{}, bool) {
+	modelReflect := reflect.Indirect(reflect.ValueOf(model))
+	if !modelReflect.IsValid() || modelReflect.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	for _, field := range reflect.VisibleFields(modelReflect.Type()) {
+		if field.Anonymous || !field.IsExported() || !isColumnField(field, column) {
+			continue
+		}
+		return modelReflect.FieldByIndex(field.Index).Interface(), true
+	}
+
+	return nil, false
+}
+
+// isColumnField will return true if the struct field is the given column
+func isColumnField(field reflect.StructField, column string) bool {
+	if column == mongoIDField {
+		column = sqlIDField
+	}
+
+	// Check the gorm column tag
+	for _, setting := range strings.Split(field.Tag.Get("gorm"), ";") {
+		if name, ok := strings.CutPrefix(setting, "column:"); ok {
+			return name == column
+		}
+	}
+
+	// Check the bson and json tags
+	for _, tagName := range []string{bsonTagName, "json"} {
+		name := strings.Split(field.Tag.Get(tagName), ",")[0]
+		if name == mongoIDField {
+			name = sqlIDField
+		}
+		if name == column {
+			return true
+		}
+	}
+
+	return strcase.ToSnake(field.Name) == column
+}
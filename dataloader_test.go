@@ -0,0 +1,126 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_BatchGetByKeys will test the method BatchGetByKeys()
+func TestClient_BatchGetByKeys(t *testing.T) {
+	t.Run("get by id", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "batch-1", Name: "first"},
+			&testSQLModel{ID: "batch-2", Name: "second"},
+			&testSQLModel{ID: "batch-3", Name: "third"},
+		)
+
+		var models []*testSQLModel
+		results, err := client.BatchGetByKeys(
+			ctx, &models, sqlIDField, []string{"batch-1", "batch-3", "missing"}, defaultDatabaseMaxTimeout,
+		)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "first", results["batch-1"].(*testSQLModel).Name)
+		assert.Equal(t, "third", results["batch-3"].(*testSQLModel).Name)
+	})
+
+	t.Run("get by column (slice of structs)", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "batch-1", Name: "first"},
+			&testSQLModel{ID: "batch-2", Name: "second"},
+		)
+
+		var models []testSQLModel
+		results, err := client.BatchGetByKeys(
+			ctx, &models, "name", []string{"second"}, defaultDatabaseMaxTimeout,
+		)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "batch-2", results["second"].(*testSQLModel).ID)
+	})
+
+	t.Run("soft-deleted records are excluded", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t,
+			WithAutoMigrate(&testSoftDeleteModel{}),
+			WithSoftDeletes(&testSoftDeleteModel{}),
+		)
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testSoftDeleteModel{ID: "soft-1", Name: "alice"},
+			&testSoftDeleteModel{ID: "soft-2", Name: "bob"},
+		)
+		testDeleteModel(ctx, t, client, &testSoftDeleteModel{ID: "soft-1"})
+
+		var models []*testSoftDeleteModel
+		results, err := client.BatchGetByKeys(
+			ctx, &models, sqlIDField, []string{"soft-1", "soft-2"}, defaultDatabaseMaxTimeout,
+		)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Contains(t, results, "soft-2")
+
+		models = nil
+		results, err = client.BatchGetByKeys(
+			IncludeDeleted(ctx), &models, sqlIDField, []string{"soft-1", "soft-2"}, defaultDatabaseMaxTimeout,
+		)
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+	})
+
+	t.Run("no keys or no results", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var models []*testSQLModel
+		results, err := client.BatchGetByKeys(ctx, &models, sqlIDField, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+
+		results, err = client.BatchGetByKeys(ctx, &models, sqlIDField, []string{"missing"}, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("not a slice", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		_, err := client.BatchGetByKeys(ctx, &testSQLModel{}, sqlIDField, []string{"1"}, defaultDatabaseMaxTimeout)
+		require.Error(t, err)
+	})
+}
+
+// TestGetModelColumnValue will test the method getModelColumnValue()
+func TestGetModelColumnValue(t *testing.T) {
+	t.Parallel()
+
+	type columnModel struct {
+		ID       string `json:"id" bson:"_id"`
+		Custom   string `gorm:"column:custom_name"`
+		JSONName string `json:"json_name"`
+		Snake    uint32
+	}
+	m := &columnModel{ID: "id-1", Custom: "custom", JSONName: "json", Snake: 5}
+
+	assert.Equal(t, "id-1", *getModelColumnValue(m, sqlIDField))
+	assert.Equal(t, "id-1", *getModelColumnValue(m, mongoIDField))
+	assert.Equal(t, "custom", *getModelColumnValue(m, "custom_name"))
+	assert.Equal(t, "json", *getModelColumnValue(m, "json_name"))
+	assert.Equal(t, "5", *getModelColumnValue(*m, "snake"))
+	assert.Nil(t, getModelColumnValue(m, "missing"))
+	assert.Nil(t, getModelColumnValue(nil, sqlIDField))
+}
@@ -1,5 +1,13 @@
 package datastore
 
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
 const (
 	testDatabaseHost      = "localhost"
 	testDatabaseName      = "test_db"
@@ -9,4 +17,42 @@ const (
 	testDatabaseUser      = "some_username"
 	testTablePrefix       = "test"
 	testModelName         = "test_model"
+	testSQLModelName      = "test_sql_model"
+	testSQLTableName      = "test_sql_models"
 )
+
+// testSQLModel is a model used for testing against a real (SQLite) database
+type testSQLModel struct {
+	ID        string    `json:"id" toml:"id" yaml:"id" gorm:"<-:create;type:char(64);primaryKey" bson:"_id"`
+	Name      string    `json:"name" toml:"name" yaml:"name" gorm:"type:varchar(64)" bson:"name"`
+	Amount    int64     `json:"amount" toml:"amount" yaml:"amount" bson:"amount"`
+	CreatedAt time.Time `json:"created_at" toml:"created_at" yaml:"created_at" bson:"created_at"`
+}
+
+// GetModelName will return a model name
+func (m *testSQLModel) GetModelName() string {
+	return testSQLModelName
+}
+
+// GetModelTableName will return a table name
+func (m *testSQLModel) GetModelTableName() string {
+	return testSQLTableName
+}
+
+// testSQLiteClient will generate a test client using a unique in-memory SQLite database (with test models migrated)
+func testSQLiteClient(ctx context.Context, t *testing.T, opts ...ClientOps) (ClientInterface, func()) {
+	opts = append([]ClientOps{
+		WithSQLite(NewEphemeralSQLiteConfig(t, t.Name())),
+		WithAutoMigrate(&testSQLModel{}),
+	}, opts...)
+	return testClient(ctx, t, opts...)
+}
+
+// testSaveModels will save the given models (new records) using the client
+func testSaveModels[T any](ctx context.Context, t *testing.T, client ClientInterface, models ...T) {
+	for _, model := range models {
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.SaveModel(ctx, model, tx, true, true)
+		}))
+	}
+}
@@ -0,0 +1,245 @@
+// Package datastoretest is the conformance test suite of the datastore engines
+//
+// New engines (and forks) run the suite to verify they behave like the other engines:
+//
+//	func TestConformance(t *testing.T) {
+//		datastoretest.RunConformance(t, func(t *testing.T) datastore.ClientInterface {
+//			client, err := datastore.NewClient(context.Background(), datastore.WithMemory(&datastore.MemoryConfig{}),
+//				datastore.WithAutoMigrate(&datastoretest.Record{}))
+//			require.NoError(t, err)
+//			t.Cleanup(func() { _ = client.Close(context.Background()) })
+//			return client
+//		})
+//	}
+package datastoretest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory will return a new client using an empty datastore (the factory registers the cleanup, IE: t.Cleanup)
+//
+// The client migrates the Record model (IE: WithAutoMigrate(&Record{}))
+type Factory func(t *testing.T) datastore.ClientInterface
+
+// Record is the model used by the conformance suite
+type Record struct {
+	ID        string    `json:"id" toml:"id" yaml:"id" gorm:"<-:create;type:char(64);primaryKey" bson:"_id"`
+	Name      string    `json:"name" toml:"name" yaml:"name" gorm:"type:varchar(64)" bson:"name"`
+	Amount    int64     `json:"amount" toml:"amount" yaml:"amount" bson:"amount"`
+	CreatedAt time.Time `json:"created_at" toml:"created_at" yaml:"created_at" bson:"created_at"`
+}
+
+// GetModelName will return the model name
+func (r *Record) GetModelName() string {
+	return "conformance_record"
+}
+
+// GetModelTableName will return the table name
+func (r *Record) GetModelTableName() string {
+	return "records"
+}
+
+// aggregateRow is the typed aggregate result (see: GetModelsAggregateInto)
+type aggregateRow struct {
+	Amount int64 `aggregate:"key"`
+	Total  int64 `aggregate:"count"`
+}
+
+// queryTimeout is the timeout of the queries
+const queryTimeout = 10 * time.Second
+
+// errRollback is returned to roll back a transaction
+var errRollback = errors.New("rollback")
+
+// RunConformance will run the conformance suite (conditions, pagination, transactions, aggregates) against the
+// clients of the factory, each test uses a new client
+func RunConformance(t *testing.T, factory Factory) {
+	t.Run("save and get", func(t *testing.T) {
+		ctx, client := newConformanceClient(t, factory)
+
+		saveRecords(ctx, t, client, &Record{ID: "record-1", Name: "alice", Amount: 10})
+
+		record := &Record{}
+		require.NoError(t, client.GetModel(ctx, record, map[string]interface{}{"id": "record-1"}, queryTimeout, false))
+		assert.Equal(t, "alice", record.Name)
+		assert.Equal(t, int64(10), record.Amount)
+
+		err := client.GetModel(ctx, &Record{}, map[string]interface{}{"id": "record-2"}, queryTimeout, false)
+		require.ErrorIs(t, err, datastore.ErrNoResults)
+
+		// Creating an existing record fails
+		require.Error(t, client.SaveModelAuto(ctx, &Record{ID: "record-1", Name: "other"}, true))
+	})
+
+	t.Run("conditions", func(t *testing.T) {
+		ctx, client := newConformanceClient(t, factory)
+		saveTestRecords(ctx, t, client)
+
+		tests := []struct {
+			name       string
+			conditions map[string]interface{}
+			expected   []string
+		}{
+			{"equals", map[string]interface{}{"name": "bob"}, []string{"record-2"}},
+			{"not equals", map[string]interface{}{"name": map[string]interface{}{"$ne": "bob"}},
+				[]string{"record-1", "record-3", "record-4"}},
+			{"greater than", map[string]interface{}{"amount": map[string]interface{}{"$gt": 20}},
+				[]string{"record-3", "record-4"}},
+			{"range", map[string]interface{}{"amount": map[string]interface{}{"$gte": 20, "$lte": 30}},
+				[]string{"record-2", "record-3"}},
+			{"or", map[string]interface{}{"$or": []map[string]interface{}{{"name": "alice"}, {"amount": 40}}},
+				[]string{"record-1", "record-4"}},
+			{"and", map[string]interface{}{"$and": []map[string]interface{}{
+				{"amount": map[string]interface{}{"$gt": 10}},
+				{"amount": map[string]interface{}{"$lt": 40}},
+			}}, []string{"record-2", "record-3"}},
+			{"no match", map[string]interface{}{"name": "nobody"}, []string{}},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				count, err := client.GetModelCount(ctx, &Record{}, test.conditions, queryTimeout)
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(test.expected)), count)
+
+				exists, err := client.ModelExists(ctx, &Record{}, test.conditions, queryTimeout)
+				require.NoError(t, err)
+				assert.Equal(t, len(test.expected) > 0, exists)
+
+				if len(test.expected) == 0 {
+					return
+				}
+				var records []*Record
+				require.NoError(t, client.GetModels(ctx, &records, test.conditions, nil, nil, queryTimeout))
+				assert.Equal(t, test.expected, getRecordIDs(records, true))
+			})
+		}
+	})
+
+	t.Run("pagination and ordering", func(t *testing.T) {
+		ctx, client := newConformanceClient(t, factory)
+		saveTestRecords(ctx, t, client)
+
+		var records []*Record
+		require.NoError(t, client.GetModels(ctx, &records, nil, &datastore.QueryParams{
+			OrderByField: "amount", SortDirection: datastore.SortDesc, Page: 2, PageSize: 2,
+		}, nil, queryTimeout))
+		assert.Equal(t, []string{"record-2", "record-1"}, getRecordIDs(records, false))
+
+		records = nil
+		require.NoError(t, client.GetModels(ctx, &records, nil, &datastore.QueryParams{
+			OrderByField: "name", SortDirection: datastore.SortAsc, Page: 1, PageSize: 3,
+		}, nil, queryTimeout))
+		assert.Equal(t, []string{"record-1", "record-2", "record-3"}, getRecordIDs(records, false))
+	})
+
+	t.Run("update, increment and delete", func(t *testing.T) {
+		ctx, client := newConformanceClient(t, factory)
+		record := &Record{ID: "record-1", Name: "alice", Amount: 10}
+		saveRecords(ctx, t, client, record)
+
+		require.NoError(t, client.NewTx(ctx, func(tx *datastore.Transaction) error {
+			return client.UpdateModelFields(ctx, record, map[string]interface{}{"name": "alicia"}, tx, true)
+		}))
+
+		newValue, err := client.IncrementModel(ctx, record, "amount", 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(15), newValue)
+
+		found := &Record{}
+		require.NoError(t, client.GetModel(ctx, found, map[string]interface{}{"id": "record-1"}, queryTimeout, false))
+		assert.Equal(t, "alicia", found.Name)
+		assert.Equal(t, int64(15), found.Amount)
+
+		require.NoError(t, client.NewTx(ctx, func(tx *datastore.Transaction) error {
+			return client.DeleteModel(ctx, found, tx, true)
+		}))
+		err = client.GetModel(ctx, &Record{}, map[string]interface{}{"id": "record-1"}, queryTimeout, false)
+		require.ErrorIs(t, err, datastore.ErrNoResults)
+	})
+
+	t.Run("transactions", func(t *testing.T) {
+		ctx, client := newConformanceClient(t, factory)
+
+		saveRecords(ctx, t, client, &Record{ID: "record-1", Name: "alice"})
+		require.NoError(t, client.GetModel(ctx, &Record{}, map[string]interface{}{"id": "record-1"}, queryTimeout, false))
+
+		if !datastore.IsSQLEngine(client.Engine()) && !client.EffectiveConfig().Transactions {
+			t.Skip("the engine does not roll back transactions")
+		}
+		err := client.NewTx(ctx, func(tx *datastore.Transaction) error {
+			if err := client.SaveModel(ctx, &Record{ID: "record-2", Name: "bob"}, tx, true, false); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+		err = client.GetModel(ctx, &Record{}, map[string]interface{}{"id": "record-2"}, queryTimeout, false)
+		require.ErrorIs(t, err, datastore.ErrNoResults)
+	})
+
+	t.Run("aggregates", func(t *testing.T) {
+		ctx, client := newConformanceClient(t, factory)
+		saveTestRecords(ctx, t, client, &Record{ID: "record-5", Name: "eve", Amount: 40})
+
+		results, err := client.GetModelsAggregate(ctx, &[]*Record{}, nil, "amount", queryTimeout)
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		assert.EqualValues(t, 1, results["10"])
+		assert.EqualValues(t, 2, results["40"])
+
+		var rows []aggregateRow
+		require.NoError(t, client.GetModelsAggregateInto(ctx, &[]*Record{}, map[string]interface{}{
+			"amount": map[string]interface{}{"$gte": 30},
+		}, "amount", &rows, queryTimeout))
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Amount < rows[j].Amount })
+		assert.Equal(t, []aggregateRow{{Amount: 30, Total: 1}, {Amount: 40, Total: 2}}, rows)
+	})
+}
+
+// newConformanceClient will return a new client of the factory
+func newConformanceClient(t *testing.T, factory Factory) (context.Context, datastore.ClientInterface) {
+	client := factory(t)
+	require.NotNil(t, client)
+	return context.Background(), client
+}
+
+// saveRecords will create the records (one transaction per record)
+func saveRecords(ctx context.Context, t *testing.T, client datastore.ClientInterface, records ...*Record) {
+	for _, record := range records {
+		if record.CreatedAt.IsZero() {
+			record.CreatedAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		}
+		require.NoError(t, client.SaveModelAuto(ctx, record, true))
+	}
+}
+
+// saveTestRecords will create the test records (alice 10, bob 20, carol 30 and dave 40) and the extra records
+func saveTestRecords(ctx context.Context, t *testing.T, client datastore.ClientInterface, extra ...*Record) {
+	saveRecords(ctx, t, client, append([]*Record{
+		{ID: "record-1", Name: "alice", Amount: 10},
+		{ID: "record-2", Name: "bob", Amount: 20},
+		{ID: "record-3", Name: "carol", Amount: 30},
+		{ID: "record-4", Name: "dave", Amount: 40},
+	}, extra...)...)
+}
+
+// getRecordIDs will return the IDs of the records (sorted, or in the order of the records)
+func getRecordIDs(records []*Record, sorted bool) []string {
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	if sorted {
+		sort.Strings(ids)
+	}
+	return ids
+}
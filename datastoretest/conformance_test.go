@@ -0,0 +1,34 @@
+package datastoretest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mrz1836/go-datastore"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient will return a new client with the Record model migrated (closed by the cleanup)
+func newTestClient(t *testing.T, opts ...datastore.ClientOps) datastore.ClientInterface {
+	client, err := datastore.NewClient(context.Background(), append(opts, datastore.WithAutoMigrate(&Record{}))...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = client.Close(context.Background())
+	})
+	return client
+}
+
+// TestRunConformance will run the conformance suite against the engines available without a server
+func TestRunConformance(t *testing.T) {
+	t.Run("sqlite", func(t *testing.T) {
+		RunConformance(t, func(t *testing.T) datastore.ClientInterface {
+			return newTestClient(t, datastore.WithSQLite(datastore.NewEphemeralSQLiteConfig(t, t.Name())))
+		})
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		RunConformance(t, func(t *testing.T) datastore.ClientInterface {
+			return newTestClient(t, datastore.WithMemory(&datastore.MemoryConfig{}))
+		})
+	})
+}
@@ -0,0 +1,85 @@
+package datastore
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidModelDefaults is when the model defaults have an invalid order field, sort direction, page size or timeout
+var ErrInvalidModelDefaults = errors.New("invalid model defaults")
+
+// Defaults are the query defaults for a model, applied when the QueryParams fields (or timeout) are zero
+type Defaults struct {
+	OrderByField  string        // Default order field (ignored if the query has OrderByField or OrderBy)
+	PageSize      int           // Default page size (used when a page is requested)
+	SortDirection string        // Default sort direction (asc / desc)
+	Timeout       time.Duration // Default query timeout
+}
+
+// modelDefaults are the registered query defaults (by model name)
+type modelDefaults struct {
+	defaults map[string]*Defaults // Registered defaults (by model name)
+	mu       sync.RWMutex         // Lock for the defaults
+}
+
+// RegisterModelDefaults will register the query defaults for the model (replacing any existing defaults)
+//
+// The defaults are applied by GetModels and GetModelsPaged when the QueryParams fields (or timeout) are zero
+func (c *Client) RegisterModelDefaults(model interface{}, defaults Defaults) error {
+	defaults.SortDirection = strings.ToLower(defaults.SortDirection)
+	if (defaults.OrderByField != "" && !indexNamePattern.MatchString(defaults.OrderByField)) ||
+		(defaults.SortDirection != "" && defaults.SortDirection != SortAsc && defaults.SortDirection != SortDesc) ||
+		defaults.PageSize < 0 || defaults.Timeout < 0 {
+		return ErrInvalidModelDefaults
+	}
+
+	modelName := GetModelName(model)
+	if modelName == nil {
+		return ErrUnknownCollection
+	}
+
+	registered := c.options.modelDefaults
+	registered.mu.Lock()
+	defer registered.mu.Unlock()
+	if registered.defaults == nil {
+		registered.defaults = make(map[string]*Defaults)
+	}
+	registered.defaults[*modelName] = &defaults
+	return nil
+}
+
+// applyModelDefaults will set the zero QueryParams fields using the registered defaults and return the timeout
+func (c *Client) applyModelDefaults(models interface{}, queryParams *QueryParams,
+	timeout time.Duration,
+) time.Duration {
+	if c.options.modelDefaults == nil {
+		return timeout
+	}
+	modelName := GetModelName(models)
+	if modelName == nil {
+		return timeout
+	}
+
+	c.options.modelDefaults.mu.RLock()
+	defaults := c.options.modelDefaults.defaults[*modelName]
+	c.options.modelDefaults.mu.RUnlock()
+	if defaults == nil {
+		return timeout
+	}
+
+	if queryParams.OrderByField == "" && len(queryParams.OrderBy) == 0 {
+		queryParams.OrderByField = defaults.OrderByField
+	}
+	if queryParams.SortDirection == "" {
+		queryParams.SortDirection = defaults.SortDirection
+	}
+	if queryParams.PageSize == 0 {
+		queryParams.PageSize = defaults.PageSize
+	}
+	if timeout == 0 {
+		timeout = defaults.Timeout
+	}
+	return timeout
+}
@@ -0,0 +1,80 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_RegisterModelDefaults will test the method RegisterModelDefaults()
+func TestClient_RegisterModelDefaults(t *testing.T) {
+	t.Run("invalid defaults", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite, modelDefaults: &modelDefaults{}}}
+		for _, defaults := range []Defaults{
+			{OrderByField: "name;"},
+			{SortDirection: "sideways"},
+			{PageSize: -1},
+			{Timeout: -time.Second},
+		} {
+			require.ErrorIs(t, client.RegisterModelDefaults(&testSQLModel{}, defaults), ErrInvalidModelDefaults)
+		}
+		assert.Empty(t, client.options.modelDefaults.defaults)
+	})
+
+	t.Run("applied when zero", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "defaults-1", Name: "a"},
+			&testSQLModel{ID: "defaults-2", Name: "b"},
+			&testSQLModel{ID: "defaults-3", Name: "c"},
+		)
+
+		require.NoError(t, client.RegisterModelDefaults(&testSQLModel{}, Defaults{
+			OrderByField: "name", PageSize: 2, SortDirection: "DESC", Timeout: defaultDatabaseMaxTimeout,
+		}))
+
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(ctx, &models, nil, &QueryParams{Page: 1}, nil, 0))
+		require.Len(t, models, 2)
+		assert.Equal(t, "c", models[0].Name)
+		assert.Equal(t, "b", models[1].Name)
+
+		// The query params take precedence
+		models = nil
+		require.NoError(t, client.GetModels(ctx, &models, nil, &QueryParams{
+			Page: 1, PageSize: 3, OrderByField: "name", SortDirection: SortAsc,
+		}, nil, 0))
+		require.Len(t, models, 3)
+		assert.Equal(t, "a", models[0].Name)
+
+		// Paged results use the default page size
+		models = nil
+		result, err := client.GetModelsPaged(ctx, &models, nil, nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.PageSize)
+		assert.Equal(t, 2, result.TotalPages)
+	})
+}
+
+// TestClient_applyModelDefaults will test the method applyModelDefaults()
+func TestClient_applyModelDefaults(t *testing.T) {
+	client := &Client{options: &clientOptions{engine: SQLite, modelDefaults: &modelDefaults{}}}
+
+	t.Run("no defaults", func(t *testing.T) {
+		params := &QueryParams{}
+		assert.Equal(t, time.Second, client.applyModelDefaults(&testSQLModel{}, params, time.Second))
+		assert.Equal(t, QueryParams{}, *params)
+	})
+
+	t.Run("multi-column ordering is kept", func(t *testing.T) {
+		require.NoError(t, client.RegisterModelDefaults(&testSQLModel{}, Defaults{OrderByField: "name"}))
+		params := &QueryParams{OrderBy: []OrderSpec{{Field: "amount"}}}
+		client.applyModelDefaults(&[]*testSQLModel{}, params, 0)
+		assert.Empty(t, params.OrderByField)
+	})
+}
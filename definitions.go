@@ -1,15 +1,18 @@
 package datastore
 
 import (
+	"crypto/tls"
 	"database/sql"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"go.mongodb.org/mongo-driver/mongo"
 	"gorm.io/gorm"
 )
 
 // Defaults for library functionality
 const (
+	defaultCollationLocale            = "en"              // Default locale for collations (MongoDB)
 	defaultDatabaseCreateIndexTimeout = 20 * time.Second  // Default timeout for creating indexes
 	defaultDatabaseMaxIdleTime        = 360 * time.Second // Default max idle open connection time
 	defaultDatabaseMaxTimeout         = 60 * time.Second  // Default max timeout on a query
@@ -20,6 +23,7 @@ const (
 	defaultPostgreSQLHost             = "localhost"       // Default host for PostgreSQL
 	defaultPostgreSQLPort             = "5432"            // Default port for PostgreSQL
 	defaultPostgreSQLSslMode          = "disable"         // Default sslmode for PostgreSQL
+	defaultSaveModelsBatchSize        = 100               // Default rows per batched INSERT (see: SaveModels)
 	defaultSQLiteFileName             = "datastore.db"    // Default database filename
 	defaultSQLiteSharing              = true              // Default value for "sharing" in loading a SQLite database
 	defaultTablePrefix                = "x"               // Default database prefix for table names (x_model)
@@ -43,19 +47,35 @@ const (
 	nullTimeFieldType   = "NullTime"   // Field type name for Null Time
 
 	// Conditions
+	conditionAddToSet           = "$addToSet"     // Condition for adding values to an array (if missing)
 	conditionAnd                = "$and"          // Condition for an AND statement
+	conditionBetween            = "$between"      // Condition for a BETWEEN statement (inclusive range)
+	conditionBitsAllSet         = "$bitsAllSet"   // Condition for all the bits set ( (col & mask) = mask )
+	conditionBitsAnySet         = "$bitsAnySet"   // Condition for any of the bits set ( (col & mask) <> 0 )
 	conditionDateToString       = "$dateToString" // Condition for a Date to String command
+	conditionEach               = "$each"         // Modifier for adding multiple values to an array
+	conditionEndsWith           = "$endsWith"     // Condition for a suffix match (LIKE '%abc')
+	conditionEqOrNull           = "$eqOrNull"     // Condition for equals or is null ( = OR IS NULL )
+	conditionEquals             = "$eq"           // Condition for equals ( = )
 	conditionExists             = "$exists"       // Condition for an EXISTS statement
 	conditionGreaterThan        = "$gt"           // Condition for greater than ( > )
 	conditionGreaterThanOrEqual = "$gte"          // Condition for greater than or equal ( >= )
 	conditionGroup              = "$group"        // Condition for a GROUP command
+	conditionIn                 = "$in"           // Condition for an IN statement
+	conditionILike              = "$ilike"        // Condition for a case-insensitive LIKE statement
 	conditionIncrement          = "$inc"          // Condition for an INCREMENT command
 	conditionLessThan           = "$lt"           // Condition for less than ( < )
 	conditionLessThanOrEqual    = "$lte"          // Condition for less than or equal ( <= )
+	conditionLike               = "$like"         // Condition for a LIKE statement (pattern matching)
 	conditionMatch              = "$match"        // Condition for a MATCH command
 	conditionNotEquals          = "$ne"           // Condition for not equal ( != )
 	conditionOr                 = "$or"           // Condition for an OR statement
+	conditionPull               = "$pull"         // Condition for removing values from an array
+	conditionRegex              = "$regex"        // Condition for a regular expression (REGEXP)
+	conditionRegexOptions       = "$options"      // Options for a regular expression (IE: "i" for case-insensitive)
 	conditionSet                = "$set"          // Condition for a SET command
+	conditionSetOnInsert        = "$setOnInsert"  // Condition for a SET command (only when inserted)
+	conditionStartsWith         = "$startsWith"   // Condition for a prefix match (LIKE 'abc%', index friendly)
 	conditionSum                = "$sum"          // Condition for a SUM command
 	conditionUnSet              = "$unset"        // Condition for an UNSET command
 
@@ -74,6 +94,7 @@ var (
 // CommonConfig is the common configuration fields between engines
 type CommonConfig struct {
 	Debug                 bool          `json:"debug" mapstructure:"debug"`                                       // flag for debugging sql queries in logs
+	EnableForeignKeys     bool          `json:"enable_foreign_keys" mapstructure:"enable_foreign_keys"`           // create the foreign key constraints of relationships when migrating
 	MaxConnectionIdleTime time.Duration `json:"max_connection_idle_time" mapstructure:"max_connection_idle_time"` // 360
 	MaxConnectionTime     time.Duration `json:"max_connection_time" mapstructure:"max_connection_time"`           // 60
 	MaxIdleConnections    int           `json:"max_idle_connections" mapstructure:"max_idle_connections"`         // 5
@@ -85,13 +106,23 @@ type CommonConfig struct {
 type SQLConfig struct {
 	CommonConfig              `json:",inline" mapstructure:",squash"` // Common configuration
 	Driver                    string                                  `json:"driver" mapstructure:"driver"`                                             // mysql or postgresql
+	DSN                       string                                  `json:"dsn" mapstructure:"dsn" encrypted:"true"`                                  // Raw data source name, used verbatim (the connection fields and session variables are NOT used)
 	ExistingConnection        *sql.DB                                 `json:"-" mapstructure:"-"`                                                       // Used for existing database connection
+	ExtendedProtocol          bool                                    `json:"extended_protocol" mapstructure:"extended_protocol"`                       // PostgreSQL: use the pgx extended protocol (binary parameters, cached prepared statements), NOT with PgBouncer transaction pooling
 	Host                      string                                  `json:"host" mapstructure:"host"`                                                 // database host IE: localhost
 	Name                      string                                  `json:"name" mapstructure:"name"`                                                 // database-name
 	Password                  string                                  `json:"password" mapstructure:"password" encrypted:"true"`                        // user-password
 	Port                      string                                  `json:"port" mapstructure:"port"`                                                 // 3306
 	Replica                   bool                                    `json:"replica" mapstructure:"replica"`                                           // True if it's a replica (Read-Only)
+	ServerVersion             string                                  `json:"server_version" mapstructure:"server_version"`                             // MySQL or MariaDB version IE: 8.0.36 or 10.11.6-MariaDB (detected on connect if NOT set)
+	SessionVariables          map[string]string                       `json:"session_variables" mapstructure:"session_variables"`                       // Set on each new connection (IE: sql_mode, search_path), not used with ExistingConnection
+	Socket                    string                                  `json:"socket" mapstructure:"socket"`                                             // Unix socket used instead of the host and port (MySQL: socket file, PostgreSQL: socket directory IE: /cloudsql/project:region:instance)
 	SkipInitializeWithVersion bool                                    `json:"skip_initialize_with_version" mapstructure:"skip_initialize_with_version"` // Skip using MySQL in test mode
+	TLSCAFile                 string                                  `json:"tls_ca_file" mapstructure:"tls_ca_file"`                                   // MySQL: CA bundle (PEM) used to verify the server (instead of the system roots)
+	TLSCertFile               string                                  `json:"tls_cert_file" mapstructure:"tls_cert_file"`                               // MySQL: client certificate (PEM), requires TLSKeyFile
+	TLSConfig                 *tls.Config                             `json:"-" mapstructure:"-"`                                                       // MySQL: custom TLS configuration (copied, the TLS files and server name are added)
+	TLSKeyFile                string                                  `json:"tls_key_file" mapstructure:"tls_key_file"`                                 // MySQL: client certificate key (PEM), requires TLSCertFile
+	TLSServerName             string                                  `json:"tls_server_name" mapstructure:"tls_server_name"`                           // MySQL: server name verified in the certificate (default is the host)
 	TimeZone                  string                                  `json:"time_zone" mapstructure:"time_zone"`                                       // timezone (IE: Asia/Shanghai)
 	TxTimeout                 time.Duration                           `json:"tx_timeout" mapstructure:"tx_timeout"`                                     // 5*time.Second
 	User                      string                                  `json:"user" mapstructure:"user"`                                                 // database username
@@ -106,6 +137,22 @@ type SQLiteConfig struct {
 	Shared             bool                                    `json:"shared" mapstructure:"shared"`               // Adds a shared param to the connection string
 }
 
+// DynamoDBConfig is the configuration for a DynamoDB connection
+type DynamoDBConfig struct {
+	CommonConfig       `json:",inline" mapstructure:",squash"` // Common configuration
+	AccessKeyID        string                                  `json:"access_key_id" mapstructure:"access_key_id"`                          // Static credentials (if NOT set, use an ExistingConnection for the default AWS credentials)
+	Endpoint           string                                  `json:"endpoint" mapstructure:"endpoint"`                                    // Custom endpoint IE: http://localhost:8000 (DynamoDB Local)
+	ExistingConnection *dynamodb.Client                        `json:"-" mapstructure:"-"`                                                  // Used for existing database connection
+	Region             string                                  `json:"region" mapstructure:"region"`                                        // AWS region IE: us-east-1
+	SecretAccessKey    string                                  `json:"secret_access_key" mapstructure:"secret_access_key" encrypted:"true"` // Static credentials secret
+	SessionToken       string                                  `json:"session_token" mapstructure:"session_token" encrypted:"true"`         // Static credentials session token (optional)
+}
+
+// MemoryConfig is the configuration for the in-memory datastore (unit tests without a database)
+type MemoryConfig struct {
+	CommonConfig `json:",inline" mapstructure:",squash"` // Common configuration
+}
+
 // MongoDBConfig is the configuration for each MongoDB connection
 type MongoDBConfig struct {
 	CommonConfig       `json:",inline" mapstructure:",squash"` // Common configuration
@@ -0,0 +1,266 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DerivedAggregate is how a derived column is computed from the source records (see: RegisterDerivedColumn)
+type DerivedAggregate string
+
+// Derived column aggregates
+const (
+	DerivedCount DerivedAggregate = "count" // Number of source records
+	DerivedSum   DerivedAggregate = "sum"   // Sum of the source field (zero without records)
+)
+
+// ErrInvalidDerivedColumn is when the derived column declaration is incomplete (or uses invalid names)
+var ErrInvalidDerivedColumn = errors.New("invalid derived column")
+
+// DerivedColumn is a column of the model maintained from the records of the source model
+// (IE: orders.item_count from order_items.order_id)
+type DerivedColumn struct {
+	Aggregate   DerivedAggregate // Count or sum
+	Column      string           // Derived column of the model (IE: item_count)
+	ForeignKey  string           // Column of the source referencing the model primary key (IE: order_id)
+	Model       interface{}      // Model with the derived column (IE: order)
+	Source      interface{}      // Source model (IE: order item)
+	SourceField string           // Summed column of the source (sum only, IE: quantity)
+}
+
+// RegisterDerivedColumn will maintain the derived column on every write of the source records, and
+// recompute it for all the existing records
+//
+// SQL: triggers on the source table (insert, update and delete) recompute the column of the referenced records
+// MongoDB: the column is recomputed (aggregation) after the committed writes of the source model (see:
+// SubscribeModelEvents), changing the foreign key of a source record only updates the new referenced record
+// Soft-deleted source records are not counted (see: WithSoftDeletes)
+func (c *Client) RegisterDerivedColumn(ctx context.Context, derived *DerivedColumn) error {
+	if !isValidDerivedColumn(derived) {
+		return ErrInvalidDerivedColumn
+	}
+
+	if c.Engine() == MongoDB {
+		return c.registerDerivedColumnWithMongo(ctx, derived)
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	target, err := c.getModelTableName(derived.Model)
+	if err != nil {
+		return err
+	}
+	var source, primaryKey string
+	if source, err = c.getModelTableName(derived.Source); err != nil {
+		return err
+	}
+	if primaryKey, err = c.getSinglePrimaryKeyColumn(derived.Model); err != nil {
+		return err
+	}
+
+	db := c.options.db.WithContext(ctx)
+	for _, statement := range getDerivedColumnStatements(c.Engine(), derived, target, source, primaryKey,
+		c.isSoftDeleteModel(derived.Source)) {
+		c.DebugLog(ctx, "derived column: "+statement)
+		if err = db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	// The triggers update the model's table, invalidate its cached reads after the writes of the source model
+	c.invalidateCache(ctx, derived.Model)
+	if c.options.cache == nil {
+		return nil
+	}
+	handler := func(ctx context.Context, _ ModelEvent, _ interface{}) {
+		c.invalidateCache(ctx, derived.Model)
+	}
+	for _, event := range []ModelEvent{EventCreated, EventDeleted, EventUpdated} {
+		if err = c.SubscribeModelEvents(derived.Source, event, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDerivedColumnStatements will return the statements creating the triggers (replacing existing ones) and
+// recomputing the derived column for all the records
+func getDerivedColumnStatements(engine Engine, derived *DerivedColumn, target, source, primaryKey string,
+	softDelete bool,
+) []string {
+	trigger := "trg_" + target + "_" + derived.Column
+	update := func(key string) string {
+		return "UPDATE " + target + " SET " + derived.Column + " = " +
+			getDerivedColumnExpression(derived, source, key, softDelete) + " WHERE " + primaryKey + " = " + key
+	}
+	backfill := "UPDATE " + target + " SET " + derived.Column + " = " +
+		getDerivedColumnExpression(derived, source, target+"."+primaryKey, softDelete)
+
+	switch engine {
+	case PostgreSQL:
+		function := trigger + "_fn"
+		return []string{
+			"CREATE OR REPLACE FUNCTION " + function + "() RETURNS trigger AS $$ BEGIN " +
+				"IF TG_OP IN ('INSERT', 'UPDATE') THEN " + update("NEW."+derived.ForeignKey) + "; END IF; " +
+				"IF TG_OP IN ('UPDATE', 'DELETE') THEN " + update("OLD."+derived.ForeignKey) + "; END IF; " +
+				"RETURN NULL; END; $$ LANGUAGE plpgsql",
+			"DROP TRIGGER IF EXISTS " + trigger + " ON " + source,
+			"CREATE TRIGGER " + trigger + " AFTER INSERT OR UPDATE OR DELETE ON " + source +
+				" FOR EACH ROW EXECUTE FUNCTION " + function + "()",
+			backfill,
+		}
+	case MySQL:
+		return []string{
+			"DROP TRIGGER IF EXISTS " + trigger + "_ai",
+			"DROP TRIGGER IF EXISTS " + trigger + "_au",
+			"DROP TRIGGER IF EXISTS " + trigger + "_ad",
+			"CREATE TRIGGER " + trigger + "_ai AFTER INSERT ON " + source + " FOR EACH ROW " +
+				update("NEW."+derived.ForeignKey),
+			"CREATE TRIGGER " + trigger + "_au AFTER UPDATE ON " + source + " FOR EACH ROW BEGIN " +
+				update("NEW."+derived.ForeignKey) + "; " + update("OLD."+derived.ForeignKey) + "; END",
+			"CREATE TRIGGER " + trigger + "_ad AFTER DELETE ON " + source + " FOR EACH ROW " +
+				update("OLD."+derived.ForeignKey),
+			backfill,
+		}
+	default: // SQLite
+		return []string{
+			"DROP TRIGGER IF EXISTS " + trigger + "_ai",
+			"DROP TRIGGER IF EXISTS " + trigger + "_au",
+			"DROP TRIGGER IF EXISTS " + trigger + "_ad",
+			"CREATE TRIGGER " + trigger + "_ai AFTER INSERT ON " + source + " BEGIN " +
+				update("NEW."+derived.ForeignKey) + "; END",
+			"CREATE TRIGGER " + trigger + "_au AFTER UPDATE ON " + source + " BEGIN " +
+				update("NEW."+derived.ForeignKey) + "; " + update("OLD."+derived.ForeignKey) + "; END",
+			"CREATE TRIGGER " + trigger + "_ad AFTER DELETE ON " + source + " BEGIN " +
+				update("OLD."+derived.ForeignKey) + "; END",
+			backfill,
+		}
+	}
+}
+
+// getDerivedColumnExpression will return the sub-query computing the derived column for the key
+func getDerivedColumnExpression(derived *DerivedColumn, source, key string, softDelete bool) string {
+	aggregate := "COUNT(*)"
+	if derived.Aggregate == DerivedSum {
+		aggregate = "COALESCE(SUM(" + source + "." + derived.SourceField + "), 0)"
+	}
+	where := source + "." + derived.ForeignKey + " = " + key
+	if softDelete {
+		where += " AND " + source + "." + softDeleteField + " IS NULL"
+	}
+	return "(SELECT " + aggregate + " FROM " + source + " WHERE " + where + ")"
+}
+
+// registerDerivedColumnWithMongo will recompute the derived column after the writes of the source model,
+// and for all the existing documents
+func (c *Client) registerDerivedColumnWithMongo(ctx context.Context, derived *DerivedColumn) error {
+	handler := func(ctx context.Context, _ ModelEvent, model interface{}) {
+		key, found := getModelColumnField(model, derived.ForeignKey)
+		if !found || isZeroValue(key) {
+			return
+		}
+		start := time.Now()
+		if err := newMongoQueryError("aggregate", derived.Source, nil, start,
+			c.refreshDerivedColumnWithMongo(ctx, derived, key)); err != nil {
+			c.warnLog(ctx, "failed to update the derived column "+derived.Column+": "+err.Error())
+		}
+	}
+	for _, event := range []ModelEvent{EventCreated, EventDeleted, EventUpdated} {
+		if err := c.SubscribeModelEvents(derived.Source, event, handler); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	return newMongoQueryError("aggregate", derived.Source, nil, start,
+		c.refreshDerivedColumnWithMongo(ctx, derived, nil))
+}
+
+// refreshDerivedColumnWithMongo will recompute the derived column of the document with the key
+// (nil is all the documents)
+func (c *Client) refreshDerivedColumnWithMongo(ctx context.Context, derived *DerivedColumn,
+	key interface{},
+) error {
+	targetName := GetModelTableName(derived.Model)
+	sourceName := GetModelTableName(derived.Source)
+	if targetName == nil || sourceName == nil {
+		return ErrUnknownCollection
+	}
+	target := c.getMongoWriteCollection(ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *targetName))
+	source := c.getMongoReadCollection(ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *sourceName))
+
+	match := bson.M{}
+	if key != nil {
+		match[derived.ForeignKey] = key
+	}
+	if c.isSoftDeleteModel(derived.Source) {
+		match[softDeleteField] = nil
+	}
+	var value interface{} = 1
+	if derived.Aggregate == DerivedSum {
+		value = "$" + derived.SourceField
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: conditionMatch, Value: match}},
+		{{Key: conditionGroup, Value: bson.D{
+			{Key: mongoIDField, Value: "$" + derived.ForeignKey},
+			{Key: derived.Column, Value: bson.D{{Key: conditionSum, Value: value}}},
+		}}},
+	}
+
+	c.DebugLog(ctx, fmt.Sprintf(logLine, "aggregate", *sourceName, pipeline))
+
+	cursor, err := source.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		return err
+	}
+
+	// Reset the documents without source records
+	if key != nil {
+		_, err = target.UpdateOne(ctx, bson.M{mongoIDField: key}, bson.M{conditionSet: bson.M{derived.Column: 0}})
+	} else {
+		_, err = target.UpdateMany(ctx, bson.M{}, bson.M{conditionSet: bson.M{derived.Column: 0}})
+	}
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if _, err = target.UpdateOne(ctx, bson.M{mongoIDField: result[mongoIDField]},
+			bson.M{conditionSet: bson.M{derived.Column: result[derived.Column]}},
+		); err != nil {
+			return err
+		}
+	}
+	c.invalidateCache(ctx, derived.Model)
+	return nil
+}
+
+// isValidDerivedColumn will return true if the derived column declaration is complete (and uses valid names)
+func isValidDerivedColumn(derived *DerivedColumn) bool {
+	if derived == nil || derived.Model == nil || derived.Source == nil {
+		return false
+	}
+	names := []string{derived.Column, derived.ForeignKey}
+	switch derived.Aggregate {
+	case DerivedCount:
+	case DerivedSum:
+		names = append(names, derived.SourceField)
+	default:
+		return false
+	}
+	for _, name := range names {
+		if !indexNamePattern.MatchString(name) {
+			return false
+		}
+	}
+	return true
+}
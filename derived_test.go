@@ -0,0 +1,125 @@
+package datastore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOrderModel is a model with derived columns (see: testOrderItemModel)
+type testOrderModel struct {
+	ID        string `gorm:"primaryKey"`
+	ItemCount int64  `gorm:"column:item_count"`
+	Quantity  int64  `gorm:"column:quantity"`
+}
+
+// testOrderItemModel is the source of the derived columns of testOrderModel
+type testOrderItemModel struct {
+	ID       string `gorm:"primaryKey"`
+	OrderID  string `gorm:"column:order_id"`
+	Quantity int64  `gorm:"column:quantity"`
+}
+
+// TestClient_RegisterDerivedColumn will test the method RegisterDerivedColumn()
+func TestClient_RegisterDerivedColumn(t *testing.T) {
+	itemCount := &DerivedColumn{
+		Aggregate: DerivedCount, Column: "item_count", ForeignKey: "order_id",
+		Model: &testOrderModel{}, Source: &testOrderItemModel{},
+	}
+	quantity := &DerivedColumn{
+		Aggregate: DerivedSum, Column: "quantity", ForeignKey: "order_id",
+		Model: &testOrderModel{}, Source: &testOrderItemModel{}, SourceField: "quantity",
+	}
+
+	t.Run("invalid derived column", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite}}
+		ctx := context.Background()
+		require.ErrorIs(t, client.RegisterDerivedColumn(ctx, nil), ErrInvalidDerivedColumn)
+		require.ErrorIs(t, client.RegisterDerivedColumn(ctx, &DerivedColumn{
+			Aggregate: "avg", Column: "item_count", ForeignKey: "order_id",
+			Model: &testOrderModel{}, Source: &testOrderItemModel{},
+		}), ErrInvalidDerivedColumn)
+		require.ErrorIs(t, client.RegisterDerivedColumn(ctx, &DerivedColumn{
+			Aggregate: DerivedSum, Column: "quantity", ForeignKey: "order_id",
+			Model: &testOrderModel{}, Source: &testOrderItemModel{}, SourceField: "quantity;",
+		}), ErrInvalidDerivedColumn)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		require.ErrorIs(t, client.RegisterDerivedColumn(context.Background(), itemCount), ErrUnsupportedEngine)
+	})
+
+	t.Run("[sqlite] maintained on writes", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithAutoMigrate(&testOrderModel{}, &testOrderItemModel{}))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testOrderModel{ID: "order-1"}, &testOrderModel{ID: "order-2"})
+		testSaveModels(ctx, t, client, &testOrderItemModel{ID: "item-1", OrderID: "order-1", Quantity: 2})
+
+		getOrder := func(id string) *testOrderModel {
+			order := &testOrderModel{}
+			require.NoError(t, client.GetModel(ctx, order, map[string]interface{}{sqlIDField: id},
+				defaultDatabaseMaxTimeout, false))
+			return order
+		}
+
+		// Existing records are recomputed
+		require.NoError(t, client.RegisterDerivedColumn(ctx, itemCount))
+		require.NoError(t, client.RegisterDerivedColumn(ctx, quantity))
+		assert.Equal(t, &testOrderModel{ID: "order-1", ItemCount: 1, Quantity: 2}, getOrder("order-1"))
+
+		testSaveModels(ctx, t, client,
+			&testOrderItemModel{ID: "item-2", OrderID: "order-1", Quantity: 3},
+			&testOrderItemModel{ID: "item-3", OrderID: "order-2", Quantity: 4},
+		)
+		assert.Equal(t, &testOrderModel{ID: "order-1", ItemCount: 2, Quantity: 5}, getOrder("order-1"))
+		assert.Equal(t, &testOrderModel{ID: "order-2", ItemCount: 1, Quantity: 4}, getOrder("order-2"))
+
+		// Moving an item updates both orders
+		require.NoError(t, client.SaveModelAuto(ctx, &testOrderItemModel{ID: "item-2", OrderID: "order-2", Quantity: 3}, false))
+		assert.Equal(t, &testOrderModel{ID: "order-1", ItemCount: 1, Quantity: 2}, getOrder("order-1"))
+		assert.Equal(t, &testOrderModel{ID: "order-2", ItemCount: 2, Quantity: 7}, getOrder("order-2"))
+
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.DeleteModel(ctx, &testOrderItemModel{ID: "item-1"}, tx, false)
+		}))
+		assert.Equal(t, &testOrderModel{ID: "order-1"}, getOrder("order-1"))
+
+		// Registering again replaces the triggers
+		require.NoError(t, client.RegisterDerivedColumn(ctx, itemCount))
+		testSaveModels(ctx, t, client, &testOrderItemModel{ID: "item-4", OrderID: "order-1"})
+		assert.Equal(t, int64(1), getOrder("order-1").ItemCount)
+	})
+}
+
+// Test_getDerivedColumnStatements will test the method getDerivedColumnStatements()
+func Test_getDerivedColumnStatements(t *testing.T) {
+	derived := &DerivedColumn{
+		Aggregate: DerivedSum, Column: "quantity", ForeignKey: "order_id", SourceField: "quantity",
+	}
+
+	t.Run("postgresql", func(t *testing.T) {
+		statements := getDerivedColumnStatements(PostgreSQL, derived, "orders", "order_items", "id", true)
+		require.Len(t, statements, 4)
+		assert.Contains(t, statements[0], "CREATE OR REPLACE FUNCTION trg_orders_quantity_fn()")
+		assert.Contains(t, statements[0], "UPDATE orders SET quantity = (SELECT COALESCE(SUM(order_items.quantity), 0) "+
+			"FROM order_items WHERE order_items.order_id = OLD.order_id AND order_items.deleted_at IS NULL) "+
+			"WHERE id = OLD.order_id")
+		assert.Equal(t, "CREATE TRIGGER trg_orders_quantity AFTER INSERT OR UPDATE OR DELETE ON order_items "+
+			"FOR EACH ROW EXECUTE FUNCTION trg_orders_quantity_fn()", statements[2])
+		assert.True(t, strings.HasSuffix(statements[3], "WHERE order_items.order_id = orders.id "+
+			"AND order_items.deleted_at IS NULL)"))
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		statements := getDerivedColumnStatements(MySQL, derived, "orders", "order_items", "id", false)
+		require.Len(t, statements, 7)
+		assert.Equal(t, "DROP TRIGGER IF EXISTS trg_orders_quantity_ai", statements[0])
+		assert.True(t, strings.HasPrefix(statements[4], "CREATE TRIGGER trg_orders_quantity_au AFTER UPDATE "+
+			"ON order_items FOR EACH ROW BEGIN UPDATE orders"))
+	})
+}
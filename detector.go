@@ -0,0 +1,66 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"runtime/debug"
+)
+
+// Patterns for normalizing a query into a query shape
+var (
+	shapeInListPattern       = regexp.MustCompile(`(?i)\bIN\s*\((\s*\?\s*,?)+\)`)
+	shapeJSONNumberPattern   = regexp.MustCompile(`:\s*-?\d+(\.\d+)?`)
+	shapeJSONStringPattern   = regexp.MustCompile(`:\s*"(?:[^"\\]|\\.)*"`)
+	shapeSQLNumberPattern    = regexp.MustCompile(`\b\d+(\.\d+)?\b`)
+	shapeSQLStringPattern    = regexp.MustCompile(`'(?:[^']|'')*'`)
+	shapeWhitespacePattern   = regexp.MustCompile(`\s+`)
+	shapeMongoCommandPattern = regexp.MustCompile(`^\s*\{`)
+)
+
+// NewQueryScope will return a context that tracks the queries executed with it
+//
+// Used for detecting repeated queries (N+1 patterns) within a single unit of work (IE: a request)
+// See: WithRepeatedQueryDetection()
+func (c *Client) NewQueryScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryCaptureKey{}, newQueryCapture(c, false))
+}
+
+// detectRepeatedQuery will count the query shape and log a warning once it repeats past the threshold
+//
+// Only runs when debugging is enabled and a threshold is set
+func (q *queryCapture) detectRepeatedQuery(ctx context.Context, query string) {
+	if q.client == nil || !q.client.IsDebug() ||
+		q.client.options.repeatedQueryThreshold <= 0 {
+		return
+	}
+
+	// Count the shape
+	shape := getQueryShape(query)
+	q.mu.Lock()
+	q.shapes[shape]++
+	count := q.shapes[shape]
+	q.mu.Unlock()
+
+	// Only warn once (per shape, per scope)
+	if count != q.client.options.repeatedQueryThreshold {
+		return
+	}
+	q.client.warnLog(ctx, fmt.Sprintf(
+		"possible N+1 query detected: query shape executed %d times in the same context: %s\n%s",
+		count, shape, debug.Stack(),
+	))
+}
+
+// getQueryShape will normalize a query (SQL statement or Mongo command) by removing all values
+func getQueryShape(query string) string {
+	if shapeMongoCommandPattern.MatchString(query) {
+		query = shapeJSONStringPattern.ReplaceAllString(query, ": ?")
+		query = shapeJSONNumberPattern.ReplaceAllString(query, ": ?")
+	} else {
+		query = shapeSQLStringPattern.ReplaceAllString(query, "?")
+		query = shapeSQLNumberPattern.ReplaceAllString(query, "?")
+		query = shapeInListPattern.ReplaceAllString(query, "IN (?)")
+	}
+	return shapeWhitespacePattern.ReplaceAllString(query, " ")
+}
@@ -0,0 +1,100 @@
+package datastore
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	zLogger "github.com/mrz1836/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWarnLogger is a logger that records all warnings
+type testWarnLogger struct {
+	zLogger.GormLoggerInterface
+	mu       sync.Mutex
+	warnings []string
+}
+
+// Warn will record the warning
+func (l *testWarnLogger) Warn(_ context.Context, message string, _ ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warnings = append(l.warnings, message)
+}
+
+// SetMode will return the same logger (keeps the recorded warnings)
+func (l *testWarnLogger) SetMode(_ zLogger.GormLogLevel) zLogger.GormLoggerInterface {
+	return l
+}
+
+// TestGetQueryShape will test the method getQueryShape()
+func TestGetQueryShape(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sql values are removed", func(t *testing.T) {
+		assert.Equal(t,
+			"SELECT * FROM `x_users` WHERE name = ? AND amount > ? LIMIT ?",
+			getQueryShape("SELECT * FROM `x_users` WHERE name = 'it''s me'  AND amount > 10.5 LIMIT 1"),
+		)
+	})
+
+	t.Run("sql in lists are collapsed", func(t *testing.T) {
+		assert.Equal(t,
+			getQueryShape("SELECT * FROM users WHERE id IN ('a','b','c')"),
+			getQueryShape("SELECT * FROM users WHERE id IN ('d')"),
+		)
+	})
+
+	t.Run("identifiers with numbers are kept", func(t *testing.T) {
+		assert.Equal(t, "SELECT * FROM table2 WHERE id = ?", getQueryShape("SELECT * FROM table2 WHERE id = 2"))
+	})
+
+	t.Run("mongo values are removed", func(t *testing.T) {
+		assert.Equal(t,
+			getQueryShape(`{"find": "users","filter": {"_id": "abc","count": 5}}`),
+			getQueryShape(`{"find": "users","filter": {"_id": "xyz","count": 12}}`),
+		)
+		assert.NotEqual(t,
+			getQueryShape(`{"find": "users","filter": {"_id": "abc"}}`),
+			getQueryShape(`{"find": "users","filter": {"name": "abc"}}`),
+		)
+	})
+}
+
+// TestClient_NewQueryScope will test the method NewQueryScope() and repeated query detection
+func TestClient_NewQueryScope(t *testing.T) {
+	t.Run("repeated queries are detected", func(t *testing.T) {
+		ctx := context.Background()
+		l := &testWarnLogger{GormLoggerInterface: zLogger.NewGormLogger(false, 4)}
+		client, deferFunc := testSQLiteClient(ctx, t, WithLogger(l), WithDebugging(), WithRepeatedQueryDetection(3))
+		defer deferFunc()
+
+		scope := client.NewQueryScope(ctx)
+		for _, id := range []string{"1", "2", "3", "4"} {
+			_ = client.GetModel(scope, &testSQLModel{}, map[string]interface{}{"id": id}, defaultDatabaseMaxTimeout, false)
+		}
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		require.Len(t, l.warnings, 1)
+		assert.Contains(t, l.warnings[0], "possible N+1 query detected")
+	})
+
+	t.Run("no detection without debugging", func(t *testing.T) {
+		ctx := context.Background()
+		l := &testWarnLogger{GormLoggerInterface: zLogger.NewGormLogger(false, 4)}
+		client, deferFunc := testSQLiteClient(ctx, t, WithLogger(l), WithRepeatedQueryDetection(2))
+		defer deferFunc()
+
+		scope := client.NewQueryScope(ctx)
+		for _, id := range []string{"1", "2", "3"} {
+			_ = client.GetModel(scope, &testSQLModel{}, map[string]interface{}{"id": id}, defaultDatabaseMaxTimeout, false)
+		}
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		assert.Empty(t, l.warnings)
+	})
+}
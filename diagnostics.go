@@ -0,0 +1,124 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Deadlock diagnostics settings
+const (
+	defaultDiagnosticsInterval = time.Minute      // Default minimum time between diagnostic dumps
+	diagnosticsTimeout         = 10 * time.Second // Max time to capture the diagnostics
+)
+
+// deadlockErrorPatterns are (lower case) fragments of deadlock and serialization errors for all engines
+var deadlockErrorPatterns = []string{
+	"deadlock",                   // MySQL (Error 1213) and PostgreSQL (40P01)
+	"40p01",                      // PostgreSQL: deadlock_detected
+	"40001",                      // PostgreSQL: serialization_failure
+	"could not serialize access", // PostgreSQL: serialization_failure
+	"writeconflict",              // MongoDB: WriteConflict (112)
+	"write conflict",             // MongoDB: write conflict during a transaction
+}
+
+// DiagnosticsSink receives the engine diagnostics captured after a deadlock or serialization error
+type DiagnosticsSink func(ctx context.Context, engine Engine, err error, diagnostics string)
+
+// deadlockDiagnostics captures engine diagnostics (rate limited) when a deadlock is detected
+type deadlockDiagnostics struct {
+	capture  func(ctx context.Context) (string, error) // Captures the engine diagnostics
+	engine   Engine                                    // Datastore engine
+	interval time.Duration                             // Minimum time between dumps
+	last     time.Time                                 // Last time diagnostics were captured
+	mu       sync.Mutex                                // Lock for the rate limit
+	sink     DiagnosticsSink                           // Destination for the diagnostics
+}
+
+// IsDeadlockError will return true if the error is a deadlock or serialization error (any engine)
+func IsDeadlockError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	for _, pattern := range deadlockErrorPatterns {
+		if strings.Contains(message, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// check will capture and send the diagnostics if the error is a deadlock (at most once per interval)
+//
+// Diagnostics are captured in the background (the failed query's connection and context are not used)
+func (d *deadlockDiagnostics) check(err error) {
+	if d == nil || d.sink == nil || d.capture == nil || !IsDeadlockError(err) {
+		return
+	}
+
+	// Rate limit the dumps
+	d.mu.Lock()
+	if !d.last.IsZero() && time.Since(d.last) < d.interval {
+		d.mu.Unlock()
+		return
+	}
+	d.last = time.Now()
+	d.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), diagnosticsTimeout)
+		defer cancel()
+		diagnostics, captureErr := d.capture(ctx)
+		if captureErr != nil {
+			diagnostics = "failed to capture diagnostics: " + captureErr.Error()
+		}
+		d.sink(ctx, d.engine, err, diagnostics)
+	}()
+}
+
+// captureDeadlockDiagnostics will return the engine diagnostics
+//
+// MySQL: SHOW ENGINE INNODB STATUS, PostgreSQL: pg_locks snapshot, CockroachDB: cluster locks snapshot,
+// MongoDB: currentOp
+func (c *Client) captureDeadlockDiagnostics(ctx context.Context) (string, error) {
+	if c.Engine() == MongoDB {
+		var result bson.M
+		if err := c.options.mongoDB.Client().Database("admin").RunCommand(
+			ctx, bson.D{{Key: "currentOp", Value: 1}},
+		).Decode(&result); err != nil {
+			return "", err
+		}
+		b, err := bson.MarshalExtJSON(result, false, false)
+		return string(b), err
+	} else if c.Engine() == MySQL {
+		var rows []map[string]interface{}
+		if err := c.options.db.WithContext(ctx).Raw("SHOW ENGINE INNODB STATUS").Scan(&rows).Error; err != nil {
+			return "", err
+		}
+		var status []string
+		for _, row := range rows {
+			status = append(status, fmt.Sprint(row["Status"]))
+		}
+		return strings.Join(status, "\n"), nil
+	} else if c.isCockroachDB() {
+		return c.captureCockroachDBDiagnostics(ctx)
+	} else if c.Engine() == PostgreSQL {
+		var rows []map[string]interface{}
+		if err := c.options.db.WithContext(ctx).Raw(
+			`SELECT l.locktype, l.relation::regclass::text AS relation, l.mode, l.granted, l.pid,
+				a.state, a.wait_event_type, a.query
+			FROM pg_locks l LEFT JOIN pg_stat_activity a ON a.pid = l.pid`,
+		).Scan(&rows).Error; err != nil {
+			return "", err
+		}
+		b, err := json.Marshal(rows)
+		return string(b), err
+	}
+	return "", ErrNotImplemented
+}
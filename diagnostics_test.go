@@ -0,0 +1,102 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIsDeadlockError will test the method IsDeadlockError()
+func TestIsDeadlockError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{errors.New("record not found"), false},
+		{errors.New("Error 1213 (40001): Deadlock found when trying to get lock; try restarting transaction"), true},
+		{errors.New("ERROR: deadlock detected (SQLSTATE 40P01)"), true},
+		{errors.New("ERROR: could not serialize access due to concurrent update (SQLSTATE 40001)"), true},
+		{errors.New("(WriteConflict) Write conflict during plan execution and yielding is disabled."), true},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, IsDeadlockError(test.err), test.err)
+	}
+}
+
+// TestDeadlockDiagnostics_check will test the method check()
+func TestDeadlockDiagnostics_check(t *testing.T) {
+	t.Run("nil diagnostics", func(t *testing.T) {
+		var d *deadlockDiagnostics
+		assert.NotPanics(t, func() {
+			d.check(errors.New("deadlock detected"))
+		})
+	})
+
+	t.Run("captures once per interval", func(t *testing.T) {
+		dumps := make(chan string, 5)
+		d := &deadlockDiagnostics{
+			capture: func(context.Context) (string, error) {
+				return "engine status", nil
+			},
+			engine:   MySQL,
+			interval: time.Hour,
+			sink: func(_ context.Context, engine Engine, err error, diagnostics string) {
+				assert.Equal(t, MySQL, engine)
+				require.Error(t, err)
+				dumps <- diagnostics
+			},
+		}
+
+		d.check(errors.New("some other error"))
+		d.check(errors.New("Deadlock found when trying to get lock"))
+		d.check(errors.New("Deadlock found when trying to get lock"))
+
+		select {
+		case dump := <-dumps:
+			assert.Equal(t, "engine status", dump)
+		case <-time.After(5 * time.Second):
+			t.Fatal("diagnostics were not captured")
+		}
+		select {
+		case <-dumps:
+			t.Fatal("diagnostics were not rate limited")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("capture error is sent to the sink", func(t *testing.T) {
+		dumps := make(chan string, 1)
+		d := &deadlockDiagnostics{
+			capture: func(context.Context) (string, error) {
+				return "", ErrNotImplemented
+			},
+			interval: time.Hour,
+			sink: func(_ context.Context, _ Engine, _ error, diagnostics string) {
+				dumps <- diagnostics
+			},
+		}
+		d.check(errors.New("deadlock detected"))
+
+		select {
+		case dump := <-dumps:
+			assert.Contains(t, dump, ErrNotImplemented.Error())
+		case <-time.After(5 * time.Second):
+			t.Fatal("diagnostics were not captured")
+		}
+	})
+}
+
+// TestClient_captureDeadlockDiagnostics will test the method captureDeadlockDiagnostics()
+func TestClient_captureDeadlockDiagnostics(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+
+	_, err := client.(*Client).captureDeadlockDiagnostics(ctx)
+	require.ErrorIs(t, err, ErrNotImplemented)
+}
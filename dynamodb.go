@@ -0,0 +1,605 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"gorm.io/gorm/schema"
+)
+
+// dynamoDBLogLine is the debug log line of the DynamoDB engine
+const dynamoDBLogLine = "DYNAMODB %s %s: %+v\n"
+
+// dynamoDBTimeLayout is the layout of the stored times (UTC, fixed width so the strings sort as times)
+const dynamoDBTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// dynamoDBMaxBatchSize is the maximum number of items of a BatchWriteItem request
+const dynamoDBMaxBatchSize = 25
+
+// dynamoDBMaxBatchAttempts is the number of BatchWriteItem requests sent for a batch (unprocessed items)
+const dynamoDBMaxBatchAttempts = 5
+
+// ErrUnprocessedItems is when DynamoDB did not write all the items of a batch (IE: throughput exceeded)
+var ErrUnprocessedItems = errors.New("unprocessed items")
+
+type (
+
+	// dynamoDBAPI is the subset of the DynamoDB client used by the datastore (see: dynamodb.Client)
+	dynamoDBAPI interface {
+		BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput,
+			optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+		GetItem(ctx context.Context, params *dynamodb.GetItemInput,
+			optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+		PutItem(ctx context.Context, params *dynamodb.PutItemInput,
+			optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+		Scan(ctx context.Context, params *dynamodb.ScanInput,
+			optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	}
+
+	// dynamoDBStore is the connection of a DynamoDB datastore (see: WithDynamoDB)
+	//
+	// The models are parsed using the GORM naming strategy (same table and column names as the SQL engines),
+	// the table's key schema must match the model's primary key
+	dynamoDBStore struct {
+		client  dynamoDBAPI           // DynamoDB client
+		namer   schema.NamingStrategy // Same table and column names as the SQL engines
+		schemas *sync.Map             // Parsed model schemas
+	}
+
+	// dynamoDBExpression is a condition expression with its attribute names and values
+	dynamoDBExpression struct {
+		client *Client
+		names  map[string]string
+		values map[string]types.AttributeValue
+	}
+)
+
+// openDynamoDB will return the store using the existing connection, or a new client using the configuration
+func openDynamoDB(config *DynamoDBConfig, tablePrefix string) *dynamoDBStore {
+	if len(tablePrefix) > 0 {
+		tablePrefix += "_"
+	}
+	store := &dynamoDBStore{
+		namer:   schema.NamingStrategy{TablePrefix: tablePrefix},
+		schemas: &sync.Map{},
+	}
+	if config.ExistingConnection != nil {
+		store.client = config.ExistingConnection
+		return store
+	}
+	options := dynamodb.Options{Region: config.Region}
+	if len(config.Endpoint) > 0 {
+		options.BaseEndpoint = aws.String(config.Endpoint)
+	}
+	if len(config.AccessKeyID) > 0 {
+		credentials := aws.Credentials{
+			AccessKeyID:     config.AccessKeyID,
+			SecretAccessKey: config.SecretAccessKey,
+			SessionToken:    config.SessionToken,
+			Source:          "DynamoDBConfig",
+		}
+		options.Credentials = aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return credentials, nil
+		})
+	}
+	store.client = dynamodb.New(options)
+	return store
+}
+
+// parse will parse the model (or slice of models) using the GORM naming strategy
+func (d *dynamoDBStore) parse(model interface{}) (*schema.Schema, error) {
+	return parseModelType(model, d.schemas, d.namer)
+}
+
+// saveWithDynamoDB will create (ErrDuplicateKey if the primary key exists) or replace the item of the model
+//
+// The auto create/update times are set on the model (as GORM does)
+func (c *Client) saveWithDynamoDB(ctx context.Context, model interface{}, newRecord bool) (int64, error) {
+	store := c.options.dynamoDB
+	sch, err := store.parse(model)
+	if err != nil {
+		return 0, err
+	}
+	item, err := getDynamoDBModelItem(ctx, sch, model, newRecord)
+	if err != nil {
+		return 0, err
+	}
+
+	c.DebugLog(ctx, fmt.Sprintf(dynamoDBLogLine, "putItem", sch.Table, model))
+	input := &dynamodb.PutItemInput{
+		Item:      item,
+		TableName: aws.String(sch.Table),
+	}
+	if newRecord {
+		input.ConditionExpression = aws.String("attribute_not_exists(#pk)")
+		input.ExpressionAttributeNames = map[string]string{"#pk": sch.PrimaryFieldDBNames[0]}
+	}
+	if _, err = store.client.PutItem(ctx, input); err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return 0, ErrDuplicateKey
+		}
+		return 0, err
+	}
+	return 1, nil
+}
+
+// getDynamoDBModelItem will return the item of the model (sets the auto create/update times)
+func getDynamoDBModelItem(ctx context.Context, sch *schema.Schema, model interface{},
+	newRecord bool,
+) (map[string]types.AttributeValue, error) {
+	value := reflect.Indirect(reflect.ValueOf(model))
+	now := time.Now()
+	for _, field := range sch.Fields {
+		_, isZero := field.ValueOf(ctx, value)
+		if (field.AutoCreateTime > 0 && newRecord && isZero) || (field.AutoUpdateTime > 0 && (!newRecord || isZero)) {
+			if err := field.Set(ctx, value, now); err != nil {
+				return nil, err
+			}
+		}
+	}
+	row, err := getMemoryRow(ctx, sch, value)
+	if err != nil {
+		return nil, err
+	} else if _, err = getMemoryKey(sch, row); err != nil {
+		return nil, err
+	}
+	return getDynamoDBItem(row)
+}
+
+// getDynamoDBItem will return the item of the row (NULL columns are not stored)
+func getDynamoDBItem(row memoryRow) (map[string]types.AttributeValue, error) {
+	item := make(map[string]types.AttributeValue, len(row))
+	for column, value := range row {
+		if value == nil {
+			continue
+		}
+		attribute, err := getDynamoDBAttribute(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, column)
+		}
+		item[column] = attribute
+	}
+	return item, nil
+}
+
+// getDynamoDBAttribute will return the attribute value of the (driver) value
+func getDynamoDBAttribute(value interface{}) (types.AttributeValue, error) {
+	converted, err := getMemoryValue(value)
+	if err != nil {
+		return nil, err
+	}
+	switch v := converted.(type) {
+	case nil:
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	case int64:
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(v, 10)}, nil
+	case float64:
+		return &types.AttributeValueMemberN{Value: strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case bool:
+		return &types.AttributeValueMemberBOOL{Value: v}, nil
+	case []byte:
+		return &types.AttributeValueMemberB{Value: v}, nil
+	case time.Time:
+		return &types.AttributeValueMemberS{Value: v.UTC().Format(dynamoDBTimeLayout)}, nil
+	case string:
+		return &types.AttributeValueMemberS{Value: v}, nil
+	}
+	return &types.AttributeValueMemberS{Value: fmt.Sprint(converted)}, nil
+}
+
+// getDynamoDBRow will return the row of the item (the column types of the model)
+func getDynamoDBRow(sch *schema.Schema, item map[string]types.AttributeValue) (memoryRow, error) {
+	row := make(memoryRow, len(sch.DBNames))
+	for _, column := range sch.DBNames {
+		field := sch.FieldsByDBName[column]
+		switch attribute := item[column].(type) {
+		case nil, *types.AttributeValueMemberNULL:
+			row[column] = nil
+		case *types.AttributeValueMemberN:
+			if field.DataType == schema.Int || field.DataType == schema.Uint {
+				number, err := strconv.ParseInt(attribute.Value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s", err, column)
+				}
+				row[column] = number
+				continue
+			}
+			number, err := strconv.ParseFloat(attribute.Value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", err, column)
+			}
+			row[column] = number
+		case *types.AttributeValueMemberS:
+			if field.DataType == schema.Time {
+				parsed, err := time.Parse(dynamoDBTimeLayout, attribute.Value)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s", err, column)
+				}
+				row[column] = parsed
+				continue
+			}
+			row[column] = attribute.Value
+		case *types.AttributeValueMemberBOOL:
+			row[column] = attribute.Value
+		case *types.AttributeValueMemberB:
+			row[column] = attribute.Value
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, column)
+		}
+	}
+	return row, nil
+}
+
+// getWithDynamoDB will get the model, or the models matching the conditions
+//
+// A model is read using GetItem if the conditions are its primary key, otherwise the table is scanned
+// (the ordering and the pagination are applied to the matching items)
+func (c *Client) getWithDynamoDB(ctx context.Context, models interface{}, conditions map[string]interface{},
+	fieldResults interface{}, queryParams *QueryParams, total *int64,
+) error {
+	store := c.options.dynamoDB
+	sch, err := store.parse(models)
+	if err != nil {
+		return err
+	}
+
+	// Get the item using the primary key
+	if key, ok := getDynamoDBKey(sch, conditions); ok && !IsModelSlice(models) {
+		c.DebugLog(ctx, fmt.Sprintf(dynamoDBLogLine, "getItem", sch.Table, conditions))
+		var output *dynamodb.GetItemOutput
+		if output, err = store.client.GetItem(ctx, &dynamodb.GetItemInput{
+			Key:       key,
+			TableName: aws.String(sch.Table),
+		}); err != nil {
+			return err
+		} else if len(output.Item) == 0 {
+			return ErrNoResults
+		}
+		var row memoryRow
+		if row, err = getDynamoDBRow(sch, output.Item); err != nil {
+			return err
+		}
+		return setMemoryRow(ctx, sch, row, reflect.Indirect(reflect.ValueOf(models)))
+	}
+
+	rows, err := c.scanWithDynamoDB(ctx, sch, conditions)
+	if err != nil {
+		return err
+	}
+	return c.setMemoryQueryResults(ctx, models, rows, fieldResults, queryParams, total)
+}
+
+// getDynamoDBKey will return the key of the item if the conditions are the primary key values (only)
+func getDynamoDBKey(sch *schema.Schema, conditions map[string]interface{}) (map[string]types.AttributeValue, bool) {
+	if len(sch.PrimaryFieldDBNames) == 0 || len(conditions) != len(sch.PrimaryFieldDBNames) {
+		return nil, false
+	}
+	key := make(map[string]types.AttributeValue, len(conditions))
+	for _, column := range sch.PrimaryFieldDBNames {
+		value, ok := conditions[column]
+		if !ok || value == nil || reflect.ValueOf(value).Kind() == reflect.Map {
+			return nil, false
+		}
+		attribute, err := getDynamoDBAttribute(value)
+		if err != nil {
+			return nil, false
+		}
+		key[column] = attribute
+	}
+	return key, true
+}
+
+// scanWithDynamoDB will return the rows of the model's table matching the conditions (all the scan pages)
+func (c *Client) scanWithDynamoDB(ctx context.Context, sch *schema.Schema,
+	conditions map[string]interface{},
+) ([]memoryRow, error) {
+	input := &dynamodb.ScanInput{TableName: aws.String(sch.Table)}
+	expression := newDynamoDBExpression(c)
+	filter, err := expression.build(conditions, conditionAnd)
+	if err != nil {
+		return nil, err
+	} else if len(filter) > 0 {
+		input.FilterExpression = aws.String(filter)
+		input.ExpressionAttributeNames = expression.names
+		if len(expression.values) > 0 {
+			input.ExpressionAttributeValues = expression.values
+		}
+	}
+
+	c.DebugLog(ctx, fmt.Sprintf(dynamoDBLogLine, "scan", sch.Table, conditions))
+	rows := make([]memoryRow, 0)
+	for {
+		output, scanErr := c.options.dynamoDB.client.Scan(ctx, input)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		for _, item := range output.Items {
+			row, rowErr := getDynamoDBRow(sch, item)
+			if rowErr != nil {
+				return nil, rowErr
+			}
+			rows = append(rows, row)
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			return rows, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// createInBatchesWithDynamoDB will write the models using BatchWriteItem (existing items are replaced)
+//
+// The unprocessed items of a batch are sent again after a backoff (ErrUnprocessedItems if some items are never
+// written)
+func (c *Client) createInBatchesWithDynamoDB(ctx context.Context, models interface{}, batchSize int,
+	opts ...BatchOps,
+) error {
+	if getBatchOptions(opts...).skipDuplicates {
+		return fmt.Errorf("%w: skip duplicates", ErrUnsupportedOperator) // BatchWriteItem has no conditions
+	}
+	slice := reflect.Indirect(reflect.ValueOf(models))
+	if slice.Kind() != reflect.Slice {
+		return errors.New("field: models is not a slice, found: " + slice.Kind().String())
+	}
+	store := c.options.dynamoDB
+	sch, err := store.parse(models)
+	if err != nil {
+		return err
+	}
+	if batchSize < 1 || batchSize > dynamoDBMaxBatchSize {
+		batchSize = dynamoDBMaxBatchSize
+	}
+
+	c.DebugLog(ctx, fmt.Sprintf(dynamoDBLogLine, "batchWriteItem", sch.Table, slice.Len()))
+	for start := 0; start < slice.Len(); start += batchSize {
+		requests := make([]types.WriteRequest, 0, batchSize)
+		for index := start; index < min(start+batchSize, slice.Len()); index++ {
+			var item map[string]types.AttributeValue
+			if item, err = getDynamoDBModelItem(ctx, sch, slice.Index(index).Interface(), true); err != nil {
+				return err
+			}
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+		}
+		unprocessed := map[string][]types.WriteRequest{sch.Table: requests}
+		for attempt := 0; attempt < dynamoDBMaxBatchAttempts && len(unprocessed) > 0; attempt++ {
+			if attempt > 0 {
+				if err = waitDynamoDBBatchRetry(ctx, attempt); err != nil {
+					return err
+				}
+			}
+			var output *dynamodb.BatchWriteItemOutput
+			if output, err = store.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: unprocessed,
+			}); err != nil {
+				return err
+			}
+			unprocessed = output.UnprocessedItems
+		}
+		if len(unprocessed) > 0 {
+			return fmt.Errorf("%w: %d items of %s", ErrUnprocessedItems, len(unprocessed[sch.Table]), sch.Table)
+		}
+	}
+	return nil
+}
+
+// waitDynamoDBBatchRetry will wait before sending the unprocessed items again (exponential backoff)
+func waitDynamoDBBatchRetry(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(getRetryDelay(getRetryPolicy(RetryPolicy{}), attempt))
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// newDynamoDBExpression will return a new (empty) condition expression
+func newDynamoDBExpression(client *Client) *dynamoDBExpression {
+	return &dynamoDBExpression{
+		client: client,
+		names:  make(map[string]string),
+		values: make(map[string]types.AttributeValue),
+	}
+}
+
+// build will return the condition expression of the conditions (joined using the $and or $or operator)
+//
+// Returns ErrUnsupportedOperator for the operators DynamoDB does not support (see: Engine.SupportsOperator)
+func (e *dynamoDBExpression) build(conditions map[string]interface{}, join string) (string, error) {
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // Same expression for the same conditions
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		condition := conditions[key]
+		var part string
+		var err error
+		switch {
+		case key == conditionAnd || key == conditionOr:
+			items := getMemoryConditionList(condition)
+			subParts := make([]string, 0, len(items))
+			for _, item := range items {
+				var subPart string
+				if subPart, err = e.build(item, conditionAnd); err != nil {
+					return "", err
+				} else if len(subPart) > 0 {
+					subParts = append(subParts, subPart)
+				}
+			}
+			part = joinDynamoDBExpressions(subParts, key)
+		case strings.HasPrefix(key, "$"):
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedOperator, key)
+		case StringInSlice(key, e.client.GetArrayFields()) || StringInSlice(key, e.client.GetObjectFields()):
+			return "", fmt.Errorf("%w: JSON field %s", ErrUnsupportedOperator, key)
+		default:
+			if part, err = e.field(key, condition); err != nil {
+				return "", err
+			}
+		}
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+	return joinDynamoDBExpressions(parts, join), nil
+}
+
+// field will return the condition expression of the column (a value, or a map of operators)
+func (e *dynamoDBExpression) field(column string, condition interface{}) (string, error) {
+	name := e.name(column)
+	if condition == nil {
+		return "attribute_not_exists(" + name + ")", nil
+	}
+	operators, ok := condition.(map[string]interface{})
+	if !ok {
+		if reflect.ValueOf(condition).Kind() != reflect.Map {
+			value, err := e.value(condition)
+			if err != nil {
+				return "", err
+			}
+			return name + " = " + value, nil
+		}
+		operators = make(map[string]interface{})
+		for _, key := range reflect.ValueOf(condition).MapKeys() {
+			operators[fmt.Sprint(key.Interface())] = reflect.ValueOf(condition).MapIndex(key).Interface()
+		}
+	}
+
+	keys := make([]string, 0, len(operators))
+	for operator := range operators {
+		if !DynamoDB.SupportsOperator(operator) {
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedOperator, operator)
+		}
+		keys = append(keys, operator)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, operator := range keys {
+		part, err := e.operator(name, operator, operators[operator])
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, part)
+	}
+	return joinDynamoDBExpressions(parts, conditionAnd), nil
+}
+
+// operator will return the condition expression of the operator (IE: $gt) for the attribute name
+func (e *dynamoDBExpression) operator(name, operator string, operand interface{}) (string, error) {
+	comparisons := map[string]string{
+		conditionEquals:             " = ",
+		conditionNotEquals:          " <> ",
+		conditionGreaterThan:        " > ",
+		conditionGreaterThanOrEqual: " >= ",
+		conditionLessThan:           " < ",
+		conditionLessThanOrEqual:    " <= ",
+	}
+	if comparison, ok := comparisons[operator]; ok {
+		value, err := e.value(operand)
+		if err != nil {
+			return "", err
+		}
+		return name + comparison + value, nil
+	}
+
+	switch operator {
+	case conditionExists:
+		if exists, _ := operand.(bool); exists {
+			return "attribute_exists(" + name + ")", nil
+		}
+		return "attribute_not_exists(" + name + ")", nil
+	case conditionEqOrNull:
+		value, err := e.value(operand)
+		if err != nil {
+			return "", err
+		}
+		return "(" + name + " = " + value + " OR attribute_not_exists(" + name + "))", nil
+	case conditionStartsWith:
+		value, err := e.value(fmt.Sprint(operand))
+		if err != nil {
+			return "", err
+		}
+		return "begins_with(" + name + ", " + value + ")", nil
+	case conditionBetween:
+		bounds, ok := getBetweenBounds(operand)
+		if !ok {
+			return "(attribute_exists(" + name + ") AND attribute_not_exists(" + name + "))", nil // No records
+		}
+		from, err := e.value(bounds[0])
+		if err != nil {
+			return "", err
+		}
+		to, err := e.value(bounds[1])
+		if err != nil {
+			return "", err
+		}
+		return name + " BETWEEN " + from + " AND " + to, nil
+	}
+
+	// $in
+	list := reflect.ValueOf(operand)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return "", fmt.Errorf("%w: %s (not a list)", ErrUnsupportedOperator, operator)
+	} else if list.Len() == 0 {
+		return "(attribute_exists(" + name + ") AND attribute_not_exists(" + name + "))", nil // No records
+	}
+	values := make([]string, 0, list.Len())
+	for index := 0; index < list.Len(); index++ {
+		value, err := e.value(list.Index(index).Interface())
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+	return name + " IN (" + strings.Join(values, ", ") + ")", nil
+}
+
+// name will return the placeholder of the attribute name (IE: #n0)
+func (e *dynamoDBExpression) name(column string) string {
+	for placeholder, existing := range e.names {
+		if existing == column {
+			return placeholder
+		}
+	}
+	placeholder := "#n" + strconv.Itoa(len(e.names))
+	e.names[placeholder] = column
+	return placeholder
+}
+
+// value will return the placeholder of the attribute value (IE: :v0)
+func (e *dynamoDBExpression) value(value interface{}) (string, error) {
+	attribute, err := getDynamoDBAttribute(value)
+	if err != nil {
+		return "", err
+	}
+	placeholder := ":v" + strconv.Itoa(len(e.values))
+	e.values[placeholder] = attribute
+	return placeholder, nil
+}
+
+// joinDynamoDBExpressions will join the expressions using AND ($and) or OR ($or)
+func joinDynamoDBExpressions(parts []string, join string) string {
+	if len(parts) == 0 {
+		return ""
+	} else if len(parts) == 1 {
+		return parts[0]
+	} else if join == conditionOr {
+		return "(" + strings.Join(parts, " OR ") + ")"
+	}
+	return "(" + strings.Join(parts, " AND ") + ")"
+}
@@ -0,0 +1,216 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDynamoDB is a DynamoDB client storing the items in memory (by table and "id")
+type testDynamoDB struct {
+	batches     int
+	items       map[string]map[string]map[string]types.AttributeValue
+	scans       []*dynamodb.ScanInput
+	unprocessed bool // The first BatchWriteItem request leaves the last item unprocessed
+}
+
+// BatchWriteItem will put the items (see: dynamoDBAPI)
+func (d *testDynamoDB) BatchWriteItem(_ context.Context, params *dynamodb.BatchWriteItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.BatchWriteItemOutput, error) {
+	d.batches++
+	output := &dynamodb.BatchWriteItemOutput{}
+	for table, requests := range params.RequestItems {
+		if d.unprocessed && d.batches == 1 && len(requests) > 1 {
+			output.UnprocessedItems = map[string][]types.WriteRequest{table: requests[len(requests)-1:]}
+			requests = requests[:len(requests)-1]
+		}
+		for _, request := range requests {
+			d.put(table, request.PutRequest.Item)
+		}
+	}
+	return output, nil
+}
+
+// GetItem will return the item of the key (see: dynamoDBAPI)
+func (d *testDynamoDB) GetItem(_ context.Context, params *dynamodb.GetItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.GetItemOutput, error) {
+	id := params.Key[sqlIDField].(*types.AttributeValueMemberS).Value
+	return &dynamodb.GetItemOutput{Item: d.items[aws.ToString(params.TableName)][id]}, nil
+}
+
+// PutItem will put the item (see: dynamoDBAPI)
+func (d *testDynamoDB) PutItem(_ context.Context, params *dynamodb.PutItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.PutItemOutput, error) {
+	table := aws.ToString(params.TableName)
+	id := params.Item[sqlIDField].(*types.AttributeValueMemberS).Value
+	if _, exists := d.items[table][id]; exists && params.ConditionExpression != nil {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	d.put(table, params.Item)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+// Scan will return all the items of the table (the filter is not applied), see: dynamoDBAPI
+func (d *testDynamoDB) Scan(_ context.Context, params *dynamodb.ScanInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.ScanOutput, error) {
+	d.scans = append(d.scans, params)
+	output := &dynamodb.ScanOutput{}
+	for _, item := range d.items[aws.ToString(params.TableName)] {
+		output.Items = append(output.Items, item)
+	}
+	return output, nil
+}
+
+// put will store the item
+func (d *testDynamoDB) put(table string, item map[string]types.AttributeValue) {
+	if d.items[table] == nil {
+		d.items[table] = make(map[string]map[string]types.AttributeValue)
+	}
+	d.items[table][item[sqlIDField].(*types.AttributeValueMemberS).Value] = item
+}
+
+// testDynamoDBClient will generate a test client using DynamoDB (in-memory items)
+func testDynamoDBClient(ctx context.Context, t *testing.T) (*Client, *testDynamoDB) {
+	client, err := NewClient(ctx, WithDynamoDB(&DynamoDBConfig{
+		CommonConfig: CommonConfig{TablePrefix: "dyn"},
+		Region:       "us-east-1",
+	}))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = client.Close(ctx)
+	})
+	fake := &testDynamoDB{items: make(map[string]map[string]map[string]types.AttributeValue)}
+	client.(*Client).options.dynamoDB.client = fake
+	return client.(*Client), fake
+}
+
+// TestClient_DynamoDB will test the model methods using the DynamoDB engine
+func TestClient_DynamoDB(t *testing.T) {
+	t.Run("save and get", func(t *testing.T) {
+		ctx := context.Background()
+		client, fake := testDynamoDBClient(ctx, t)
+		assert.Equal(t, DynamoDB, client.Engine())
+
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "dynamo-1", Name: "alice", Amount: 10})
+		item := fake.items["dyn_test_sql_models"]["dynamo-1"]
+		require.NotNil(t, item)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "10"}, item["amount"])
+
+		err := client.SaveModel(ctx, &testSQLModel{ID: "dynamo-1"}, nil, true, true)
+		require.ErrorIs(t, err, ErrDuplicateKey)
+
+		// Primary key conditions use GetItem
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{"id": "dynamo-1"}, 0, false))
+		assert.Equal(t, "alice", model.Name)
+		assert.Equal(t, int64(10), model.Amount)
+		assert.Empty(t, fake.scans)
+
+		err = client.GetModel(ctx, &testSQLModel{}, map[string]interface{}{"id": "dynamo-2"}, 0, false)
+		require.ErrorIs(t, err, ErrNoResults)
+	})
+
+	t.Run("get models", func(t *testing.T) {
+		ctx := context.Background()
+		client, fake := testDynamoDBClient(ctx, t)
+
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "dynamo-1", Name: "alice", Amount: 10},
+			&testSQLModel{ID: "dynamo-2", Name: "bob", Amount: 20},
+		)
+
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{
+			"amount": map[string]interface{}{conditionGreaterThan: 5},
+		}, &QueryParams{OrderByField: "amount", SortDirection: SortDesc}, nil, 0))
+		require.Len(t, models, 2)
+		assert.Equal(t, "bob", models[0].Name)
+
+		require.Len(t, fake.scans, 1)
+		assert.Equal(t, "#n0 > :v0", aws.ToString(fake.scans[0].FilterExpression))
+
+		err := client.GetModels(ctx, &models, map[string]interface{}{
+			"name": map[string]interface{}{conditionRegex: "^a"},
+		}, nil, nil, 0)
+		require.ErrorIs(t, err, ErrUnsupportedOperator)
+	})
+
+	t.Run("create in batches", func(t *testing.T) {
+		ctx := context.Background()
+		client, fake := testDynamoDBClient(ctx, t)
+		fake.unprocessed = true
+
+		records := []*testSQLModel{{ID: "dynamo-1"}, {ID: "dynamo-2"}, {ID: "dynamo-3"}}
+		require.NoError(t, client.CreateInBatches(ctx, records, 100))
+		assert.Len(t, fake.items["dyn_test_sql_models"], 3)
+		assert.Equal(t, 2, fake.batches)
+
+		err := client.CreateInBatches(ctx, records, 100, WithSkipDuplicates())
+		require.ErrorIs(t, err, ErrUnsupportedOperator)
+	})
+
+	t.Run("create in batches stops waiting on cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		client, fake := testDynamoDBClient(ctx, t)
+		fake.unprocessed = true
+		cancel()
+
+		records := []*testSQLModel{{ID: "dynamo-1"}, {ID: "dynamo-2"}}
+		require.ErrorIs(t, client.CreateInBatches(ctx, records, 100), context.Canceled)
+		assert.Equal(t, 1, fake.batches)
+	})
+
+	t.Run("unsupported methods", func(t *testing.T) {
+		ctx := context.Background()
+		client, _ := testDynamoDBClient(ctx, t)
+
+		_, err := client.GetModelCount(ctx, &testSQLModel{}, nil, 0)
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// Test_dynamoDBExpression will test the method build()
+func Test_dynamoDBExpression(t *testing.T) {
+	client := &Client{options: defaultClientOptions()}
+
+	t.Run("operators", func(t *testing.T) {
+		expression := newDynamoDBExpression(client)
+		filter, err := expression.build(map[string]interface{}{
+			"amount":     map[string]interface{}{conditionBetween: []int{1, 5}},
+			"deleted_at": nil,
+			"name":       map[string]interface{}{conditionIn: []string{"alice", "bob"}},
+			conditionOr: []map[string]interface{}{
+				{"status": "active"},
+				{"status": map[string]interface{}{conditionStartsWith: "pending"}},
+			},
+		}, conditionAnd)
+		require.NoError(t, err)
+		assert.Equal(t, "((#n0 = :v0 OR begins_with(#n0, :v1)) AND #n1 BETWEEN :v2 AND :v3 AND "+
+			"attribute_not_exists(#n2) AND #n3 IN (:v4, :v5))", filter)
+		assert.Equal(t, map[string]string{"#n0": "status", "#n1": "amount", "#n2": "deleted_at", "#n3": "name"},
+			expression.names)
+		assert.Equal(t, &types.AttributeValueMemberN{Value: "1"}, expression.values[":v2"])
+		assert.Equal(t, &types.AttributeValueMemberS{Value: "bob"}, expression.values[":v5"])
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := newDynamoDBExpression(client).build(map[string]interface{}{
+			"name": map[string]interface{}{conditionLike: "a%"},
+		}, conditionAnd)
+		require.ErrorIs(t, err, ErrUnsupportedOperator)
+
+		_, err = newDynamoDBExpression(client).build(map[string]interface{}{
+			metadataField: map[string]interface{}{"plan": "pro"},
+		}, conditionAnd)
+		require.ErrorIs(t, err, ErrUnsupportedOperator)
+	})
+}
@@ -5,7 +5,9 @@ type Engine string
 
 // Supported engines (databases)
 const (
+	DynamoDB   Engine = "dynamodb"
 	Empty      Engine = "empty"
+	Memory     Engine = "memory"
 	MongoDB    Engine = "mongodb"
 	MySQL      Engine = "mysql"
 	PostgreSQL Engine = "postgresql"
@@ -26,6 +28,23 @@ var SQLDatabases = []Engine{
 	SQLite,
 }
 
+// dynamoDBOperators is the list of condition operators supported by DynamoDB (condition expressions)
+var dynamoDBOperators = []string{
+	conditionAnd,
+	conditionBetween,
+	conditionEqOrNull,
+	conditionEquals,
+	conditionExists,
+	conditionGreaterThan,
+	conditionGreaterThanOrEqual,
+	conditionIn,
+	conditionLessThan,
+	conditionLessThanOrEqual,
+	conditionNotEquals,
+	conditionOr,
+	conditionStartsWith,
+}
+
 // String is the string version of engine
 func (e Engine) String() string {
 	return string(e)
@@ -36,6 +55,16 @@ func (e Engine) IsEmpty() bool {
 	return e == Empty
 }
 
+// SupportsOperator will return true if the engine supports the condition operator (IE: $regex)
+//
+// Conditions using an unsupported operator return ErrUnsupportedOperator
+func (e Engine) SupportsOperator(operator string) bool {
+	if e == DynamoDB {
+		return StringInSlice(operator, dynamoDBOperators)
+	}
+	return true
+}
+
 // IsSQLEngine check whether the string already is in the slice
 func IsSQLEngine(e Engine) bool {
 	for _, b := range SQLDatabases {
@@ -41,3 +41,18 @@ func TestIsSQLEngine(t *testing.T) {
 		assert.False(t, IsSQLEngine(Empty))
 	})
 }
+
+// TestEngine_SupportsOperator will test the method SupportsOperator()
+func TestEngine_SupportsOperator(t *testing.T) {
+	t.Run("all operators", func(t *testing.T) {
+		assert.True(t, MySQL.SupportsOperator(conditionRegex))
+		assert.True(t, MongoDB.SupportsOperator(conditionBitsAllSet))
+	})
+
+	t.Run("dynamodb", func(t *testing.T) {
+		assert.True(t, DynamoDB.SupportsOperator(conditionIn))
+		assert.True(t, DynamoDB.SupportsOperator(conditionStartsWith))
+		assert.False(t, DynamoDB.SupportsOperator(conditionRegex))
+		assert.False(t, DynamoDB.SupportsOperator(conditionLike))
+	})
+}
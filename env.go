@@ -0,0 +1,173 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidEnvConfig is when an environment variable has an invalid value (see: NewClientFromEnv)
+var ErrInvalidEnvConfig = errors.New("invalid environment variable")
+
+// Environment variables (without the prefix) that are not configuration fields
+const (
+	envCockroachDB  = "COCKROACH_DB"  // true if the PostgreSQL engine is a CockroachDB cluster
+	envEngine       = "ENGINE"        // mysql, postgresql, sqlite, mongodb, dynamodb or memory (default is sqlite)
+	envReplicaHosts = "REPLICA_HOSTS" // Comma separated replica hosts (host or host:port, same credentials as the source)
+)
+
+// NewClientFromEnv will create a new client using the configuration from the environment variables
+//
+// The variables are the prefix and the upper case configuration field (IE: DATASTORE_HOST for SQLConfig.Host,
+// DATASTORE_MAX_OPEN_CONNECTIONS, DATASTORE_URI for MongoDBConfig.URI), and the engine is set by DATASTORE_ENGINE
+// Durations use the Go format (IE: 10s), missing variables use the defaults of the engine options
+// The options are applied after the environment configuration (and validated by NewClient)
+func NewClientFromEnv(ctx context.Context, prefix string, opts ...ClientOps) (ClientInterface, error) {
+	envOpts, err := getEnvClientOptions(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(ctx, append(envOpts, opts...)...)
+}
+
+// getEnvClientOptions will return the client options for the engine configured by the environment variables
+func getEnvClientOptions(prefix string) ([]ClientOps, error) {
+	if len(prefix) > 0 && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	engine := Engine(strings.ToLower(os.Getenv(prefix + envEngine)))
+	switch engine {
+	case MySQL, PostgreSQL:
+		config := &SQLConfig{}
+		if err := loadEnvConfig(prefix, config); err != nil {
+			return nil, err
+		}
+		configs := []*SQLConfig{config}
+		for _, host := range strings.Split(os.Getenv(prefix+envReplicaHosts), ",") {
+			if host = strings.TrimSpace(host); len(host) == 0 {
+				continue
+			}
+			replica := *config
+			replica.Host, replica.Replica = host, true
+			if index := strings.LastIndex(host, ":"); index > 0 {
+				replica.Host, replica.Port = host[:index], host[index+1:]
+			}
+			configs = append(configs, &replica)
+		}
+		opts := []ClientOps{WithSQL(engine, configs)}
+		cockroachDB, err := getEnvBool(prefix + envCockroachDB)
+		if err != nil {
+			return nil, err
+		} else if cockroachDB {
+			opts = append(opts, WithCockroachDB())
+		}
+		return opts, nil
+	case MongoDB:
+		config := &MongoDBConfig{}
+		if err := loadEnvConfig(prefix, config); err != nil {
+			return nil, err
+		}
+		return []ClientOps{WithMongo(config)}, nil
+	case DynamoDB:
+		config := &DynamoDBConfig{}
+		if err := loadEnvConfig(prefix, config); err != nil {
+			return nil, err
+		}
+		return []ClientOps{WithDynamoDB(config)}, nil
+	case Memory:
+		config := &MemoryConfig{}
+		if err := loadEnvConfig(prefix, config); err != nil {
+			return nil, err
+		}
+		return []ClientOps{WithMemory(config)}, nil
+	case SQLite, "":
+		config := &SQLiteConfig{
+			CommonConfig: CommonConfig{TablePrefix: defaultTablePrefix},
+			DatabasePath: defaultSQLiteFileName,
+			Shared:       defaultSQLiteSharing,
+		}
+		if err := loadEnvConfig(prefix, config); err != nil {
+			return nil, err
+		}
+		return []ClientOps{WithSQLite(config)}, nil
+	}
+	return nil, fmt.Errorf("%w: %s%s=%s", ErrUnsupportedEngine, prefix, envEngine, engine)
+}
+
+// loadEnvConfig will set the fields of the configuration (pointer to a struct) from the environment variables
+// named after the mapstructure tags (embedded configurations are squashed, other types are skipped)
+func loadEnvConfig(prefix string, config interface{}) error {
+	value := reflect.ValueOf(config).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := loadEnvConfig(prefix, value.Field(i).Addr().Interface()); err != nil {
+				return err
+			}
+			continue
+		} else if len(name) == 0 || name == "-" {
+			continue
+		}
+
+		key := prefix + strings.ToUpper(name)
+		raw, found := os.LookupEnv(key)
+		if !found {
+			continue
+		}
+		if err := setEnvField(value.Field(i), raw); err != nil {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidEnvConfig, key, err.Error())
+		}
+	}
+	return nil
+}
+
+// setEnvField will set the field from the environment variable value (string, bool, integer or duration)
+func setEnvField(field reflect.Value, raw string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	default: // IE: session variables
+	}
+	return nil
+}
+
+// getEnvBool will return the boolean environment variable (false if not set)
+func getEnvBool(key string) (bool, error) {
+	raw, found := os.LookupEnv(key)
+	if !found || len(raw) == 0 {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s: %s", ErrInvalidEnvConfig, key, err.Error())
+	}
+	return b, nil
+}
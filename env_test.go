@@ -0,0 +1,108 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewClientFromEnv will test the method NewClientFromEnv()
+func TestNewClientFromEnv(t *testing.T) {
+	t.Run("sqlite", func(t *testing.T) {
+		t.Setenv("TEST_ENGINE", "sqlite")
+		t.Setenv("TEST_DATABASE_PATH", "")
+		t.Setenv("TEST_SHARED", "false")
+		t.Setenv("TEST_TABLE_PREFIX", "env")
+
+		client, err := NewClientFromEnv(context.Background(), "TEST", WithDebugging())
+		require.NoError(t, err)
+		defer func() {
+			_ = client.Close(context.Background())
+		}()
+		assert.Equal(t, SQLite, client.Engine())
+		assert.Equal(t, "env_model", client.GetTableName("model"))
+		assert.True(t, client.IsDebug())
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		t.Setenv("TEST_ENGINE", "memory")
+		t.Setenv("TEST_TABLE_PREFIX", "env")
+
+		client, err := NewClientFromEnv(context.Background(), "TEST")
+		require.NoError(t, err)
+		defer func() {
+			_ = client.Close(context.Background())
+		}()
+		assert.Equal(t, Memory, client.Engine())
+		assert.Equal(t, "env_model", client.GetTableName("model"))
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		t.Setenv("TEST_MAX_OPEN_CONNECTIONS", "many")
+		_, err := NewClientFromEnv(context.Background(), "TEST_")
+		require.ErrorIs(t, err, ErrInvalidEnvConfig)
+		assert.Contains(t, err.Error(), "TEST_MAX_OPEN_CONNECTIONS")
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		t.Setenv("TEST_ENGINE", "oracle")
+		_, err := NewClientFromEnv(context.Background(), "TEST")
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// Test_getEnvClientOptions will test the method getEnvClientOptions()
+func Test_getEnvClientOptions(t *testing.T) {
+	t.Run("sql with replicas", func(t *testing.T) {
+		t.Setenv("TEST_ENGINE", "PostgreSQL")
+		t.Setenv("TEST_HOST", "source")
+		t.Setenv("TEST_USER", "app")
+		t.Setenv("TEST_PASSWORD", "secret")
+		t.Setenv("TEST_TX_TIMEOUT", "3s")
+		t.Setenv("TEST_REPLICA_HOSTS", "replica-1, replica-2:5433")
+		t.Setenv("TEST_COCKROACH_DB", "true")
+
+		opts, err := getEnvClientOptions("TEST")
+		require.NoError(t, err)
+		options := applyTestClientOptions(opts...)
+		assert.Equal(t, PostgreSQL, options.engine)
+		assert.True(t, options.cockroachDB)
+		require.Len(t, options.sqlConfigs, 3)
+		assert.Equal(t, "source", options.sqlConfigs[0].Host)
+		assert.Equal(t, "secret", options.sqlConfigs[0].Password)
+		assert.Equal(t, 3*time.Second, options.sqlConfigs[0].TxTimeout)
+		assert.Equal(t, defaultPostgreSQLPort, options.sqlConfigs[0].Port)
+		assert.Equal(t, "replica-1", options.sqlConfigs[1].Host)
+		assert.True(t, options.sqlConfigs[1].Replica)
+		assert.Equal(t, "app", options.sqlConfigs[1].User)
+		assert.Equal(t, "replica-2", options.sqlConfigs[2].Host)
+		assert.Equal(t, "5433", options.sqlConfigs[2].Port)
+	})
+
+	t.Run("mongodb", func(t *testing.T) {
+		t.Setenv("TEST_ENGINE", "mongodb")
+		t.Setenv("TEST_URI", "mongodb://localhost:27017")
+		t.Setenv("TEST_DATABASE_NAME", "app")
+		t.Setenv("TEST_TRANSACTIONS", "1")
+		t.Setenv("TEST_MAX_CONNECTION_IDLE_TIME", "1m")
+
+		opts, err := getEnvClientOptions("TEST")
+		require.NoError(t, err)
+		options := applyTestClientOptions(opts...)
+		require.NotNil(t, options.mongoDBConfig)
+		assert.Equal(t, "mongodb://localhost:27017", options.mongoDBConfig.URI)
+		assert.Equal(t, "app", options.mongoDBConfig.DatabaseName)
+		assert.True(t, options.mongoDBConfig.Transactions)
+		assert.Equal(t, time.Minute, options.mongoDBConfig.MaxConnectionIdleTime)
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		t.Setenv("TEST_ENGINE", "mysql")
+		t.Setenv("TEST_TX_TIMEOUT", "5")
+		_, err := getEnvClientOptions("TEST")
+		require.ErrorIs(t, err, ErrInvalidEnvConfig)
+	})
+}
@@ -0,0 +1,50 @@
+package datastore
+
+import (
+	"database/sql"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+)
+
+// ephemeralNamePattern is the characters replaced in the name of an ephemeral database
+var ephemeralNamePattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// ephemeralCounter makes the names of the ephemeral databases unique in the process
+var ephemeralCounter atomic.Uint64
+
+// CleanupRegistrar registers the functions called when a test finishes (IE: *testing.T or *testing.B)
+type CleanupRegistrar interface {
+	Cleanup(fn func())
+}
+
+// NewEphemeralSQLiteConfig will return the configuration of a unique in-memory SQLite database (IE: parallel tests)
+//
+// The name (IE: t.Name()) is sanitized and made unique: file:memdb_<name>_<number>?mode=memory&cache=shared
+// The database is kept alive until the cleanup registered on tb, even if the pool closes its idle connections
+// (a nil tb skips the registration, the database is dropped when the client is closed)
+func NewEphemeralSQLiteConfig(tb CleanupRegistrar, name string) *SQLiteConfig {
+	name = ephemeralNamePattern.ReplaceAllString(name, "_")
+	config := &SQLiteConfig{
+		DatabasePath: "file:memdb_" + name + "_" + strconv.FormatUint(ephemeralCounter.Add(1), 10) +
+			"?mode=memory&cache=shared",
+		Shared: false, // Already in the path
+	}
+	if tb == nil {
+		return config
+	}
+
+	// Keep a connection open (shared in-memory databases are dropped with the last connection)
+	keepAlive, err := sql.Open(getSQLiteRegexpDriver(), config.DatabasePath)
+	if err == nil {
+		keepAlive.SetMaxIdleConns(1)
+		if err = keepAlive.Ping(); err != nil { // The client reports the connection errors
+			_ = keepAlive.Close()
+			return config
+		}
+		tb.Cleanup(func() {
+			_ = keepAlive.Close()
+		})
+	}
+	return config
+}
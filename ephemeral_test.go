@@ -0,0 +1,52 @@
+package datastore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCleanupRegistrar will record the cleanup functions
+type testCleanupRegistrar struct {
+	cleanups []func()
+}
+
+// Cleanup will record the cleanup function
+func (r *testCleanupRegistrar) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+// TestNewEphemeralSQLiteConfig will test the method NewEphemeralSQLiteConfig()
+func TestNewEphemeralSQLiteConfig(t *testing.T) {
+	t.Run("unique names", func(t *testing.T) {
+		first := NewEphemeralSQLiteConfig(nil, "TestName/sub test")
+		second := NewEphemeralSQLiteConfig(nil, "TestName/sub test")
+		assert.NotEqual(t, first.DatabasePath, second.DatabasePath)
+		assert.True(t, strings.HasPrefix(first.DatabasePath, "file:memdb_TestName_sub_test_"))
+		assert.True(t, strings.HasSuffix(first.DatabasePath, "?mode=memory&cache=shared"))
+		assert.False(t, first.Shared)
+	})
+
+	t.Run("kept alive until the cleanup", func(t *testing.T) {
+		ctx := context.Background()
+		registrar := &testCleanupRegistrar{}
+		config := NewEphemeralSQLiteConfig(registrar, t.Name())
+		require.Len(t, registrar.cleanups, 1)
+
+		client, deferFunc := testClient(ctx, t, WithSQLite(config), WithAutoMigrate(&testSQLModel{}))
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "ephemeral-1"})
+		deferFunc()
+
+		// The database survives the client
+		var models []*testSQLModel
+		client, deferFunc = testClient(ctx, t, WithSQLite(config))
+		defer deferFunc()
+		require.NoError(t, client.GetModels(ctx, &models, nil, nil, nil, defaultDatabaseMaxTimeout))
+		assert.Len(t, models, 1)
+
+		registrar.cleanups[0]()
+	})
+}
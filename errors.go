@@ -7,6 +7,9 @@ import (
 // ErrUnsupportedEngine is used when the engine given is not a known datastore engine
 var ErrUnsupportedEngine = errors.New("unsupported datastore engine")
 
+// ErrUnsupportedOperator is used when a condition operator is not supported by the engine (see: SupportsOperator)
+var ErrUnsupportedOperator = errors.New("unsupported condition operator")
+
 // ErrNoResults error when no results are found
 var ErrNoResults = errors.New("no results found")
 
@@ -27,3 +30,18 @@ var ErrUnknownSQL = errors.New("unknown sql implementation")
 
 // ErrNotImplemented is an error when a method is not implemented
 var ErrNotImplemented = errors.New("not implemented")
+
+// ErrNoFieldsToUpdate is when a partial update is requested without any fields
+var ErrNoFieldsToUpdate = errors.New("no fields to update")
+
+// ErrInvalidUpsertColumn is when an upsert conflict or update column is not a valid column name
+var ErrInvalidUpsertColumn = errors.New("invalid upsert column")
+
+// ErrUnsupportedIncrementType is when the column to increment is not a numeric column (or the wrong numeric type)
+var ErrUnsupportedIncrementType = errors.New("unsupported column type for increment")
+
+// ErrMissingConditions is when a method requires conditions and none are given
+var ErrMissingConditions = errors.New("missing conditions")
+
+// ErrModelNotSaved is when a model was not saved because a previous model in the same transaction failed
+var ErrModelNotSaved = errors.New("model not saved, a previous model in the transaction failed")
@@ -0,0 +1,198 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ModelEvent is a model lifecycle event (see: SubscribeModelEvents)
+type ModelEvent string
+
+// Model lifecycle events
+const (
+	EventCreated ModelEvent = "created" // SaveModel (or SaveModels) with a new record, FindOrCreateModel, CreateInBatches
+	EventDeleted ModelEvent = "deleted" // DeleteModel (including soft deletes)
+	EventUpdated ModelEvent = "updated" // SaveModel with an existing record, UpsertModel and the partial updates
+)
+
+// ErrInvalidModelEvent is when the model event (or handler) for a subscription is invalid
+var ErrInvalidModelEvent = errors.New("invalid model event or handler")
+
+// ModelEventHandler is run after the write of the model was committed (see: SubscribeModelEvents)
+type ModelEventHandler func(ctx context.Context, event ModelEvent, model interface{})
+
+// modelEvents are the registered model event handlers (by model name and event)
+type modelEvents struct {
+	handlers map[string]map[ModelEvent][]ModelEventHandler // Handlers (by model name and event)
+	mu       sync.RWMutex                                  // Lock for the handlers
+}
+
+// SubscribeModelEvents will run the handler after each committed create, update or delete of the model
+//
+// Handlers run in-process (in the order subscribed) after the transaction is committed (see: Transaction.OnCommit),
+// nothing is run for rolled back writes. A panic in a handler is recovered (logged) and does not affect the write
+//
+// The partial updates (UpdateModelFields, UpsertModel, IncrementModel, UpdateModelMetadata and the array field
+// methods) do not modify the given model, their handlers receive a copy reloaded from the source database
+func (c *Client) SubscribeModelEvents(model interface{}, event ModelEvent, handler ModelEventHandler) error {
+	if !isValidModelEvent(event) || handler == nil {
+		return ErrInvalidModelEvent
+	}
+	modelName := GetModelName(model)
+	if modelName == nil {
+		return ErrUnknownCollection
+	}
+
+	subscribed := c.options.modelEvents
+	subscribed.mu.Lock()
+	defer subscribed.mu.Unlock()
+	if subscribed.handlers == nil {
+		subscribed.handlers = make(map[string]map[ModelEvent][]ModelEventHandler)
+	}
+	if subscribed.handlers[*modelName] == nil {
+		subscribed.handlers[*modelName] = make(map[ModelEvent][]ModelEventHandler)
+	}
+	subscribed.handlers[*modelName][event] = append(subscribed.handlers[*modelName][event], handler)
+	return nil
+}
+
+// getModelEventHandlers will return a copy of the handlers for the model and event (nil if none)
+func (c *Client) getModelEventHandlers(model interface{}, event ModelEvent) []ModelEventHandler {
+	subscribed := c.options.modelEvents
+	if subscribed == nil {
+		return nil
+	}
+	modelName := GetModelName(model)
+	if modelName == nil {
+		return nil
+	}
+
+	subscribed.mu.RLock()
+	defer subscribed.mu.RUnlock()
+	handlers := subscribed.handlers[*modelName][event]
+	if len(handlers) == 0 {
+		return nil
+	}
+	return append([]ModelEventHandler(nil), handlers...)
+}
+
+// queueModelEvent will run the handlers of the model event after the transaction is committed
+func (c *Client) queueModelEvent(ctx context.Context, tx *Transaction, event ModelEvent, model interface{}) {
+	c.queueCacheInvalidation(ctx, tx, model)
+	handlers := c.getModelEventHandlers(model, event)
+	if len(handlers) == 0 {
+		return
+	}
+	tx.OnCommit(func() {
+		c.runModelEventHandlers(ctx, handlers, event, model)
+	})
+}
+
+// emitModelEvent will run the handlers of the model event right away (a write outside a transaction)
+func (c *Client) emitModelEvent(ctx context.Context, event ModelEvent, model interface{}) {
+	c.invalidateCache(ctx, model)
+	c.runModelEventHandlers(ctx, c.getModelEventHandlers(model, event), event, model)
+}
+
+// emitModelEvents will run the handlers of the model event for each model of the slice (IE: CreateInBatches)
+func (c *Client) emitModelEvents(ctx context.Context, event ModelEvent, models interface{}) {
+	c.invalidateCache(ctx, models)
+	value := reflect.Indirect(reflect.ValueOf(models))
+	if value.Kind() != reflect.Slice || value.Len() == 0 {
+		return
+	}
+	first := value.Index(0)
+	if first.Kind() != reflect.Ptr && first.CanAddr() {
+		first = first.Addr()
+	}
+	handlers := c.getModelEventHandlers(first.Interface(), event)
+	if len(handlers) == 0 {
+		return
+	}
+	for index := 0; index < value.Len(); index++ {
+		model := value.Index(index)
+		if model.Kind() != reflect.Ptr && model.CanAddr() {
+			model = model.Addr()
+		}
+		c.runModelEventHandlers(ctx, handlers, event, model.Interface())
+	}
+}
+
+// queueModelUpdate will run the EventUpdated handlers of a partial update after the transaction is committed
+//
+// The handlers receive a copy of the model reloaded from the source database (see: getReloadedModel)
+func (c *Client) queueModelUpdate(ctx context.Context, tx *Transaction, model interface{}) {
+	c.queueCacheInvalidation(ctx, tx, model)
+	handlers := c.getModelEventHandlers(model, EventUpdated)
+	if len(handlers) == 0 {
+		return
+	}
+	tx.OnCommit(func() {
+		c.runModelEventHandlers(ctx, handlers, EventUpdated, c.getReloadedModel(ctx, model))
+	})
+}
+
+// emitModelUpdate will run the EventUpdated handlers of a partial update right away (outside a transaction)
+//
+// The handlers receive a copy of the model reloaded from the source database (see: getReloadedModel)
+func (c *Client) emitModelUpdate(ctx context.Context, model interface{}) {
+	c.invalidateCache(ctx, model)
+	handlers := c.getModelEventHandlers(model, EventUpdated)
+	if len(handlers) == 0 {
+		return
+	}
+	c.runModelEventHandlers(ctx, handlers, EventUpdated, c.getReloadedModel(ctx, model))
+}
+
+// getReloadedModel will return a copy of the model reloaded from the source database (primary key based)
+//
+// The model itself is returned if it can not be reloaded (IE: deleted in the meantime)
+func (c *Client) getReloadedModel(ctx context.Context, model interface{}) interface{} {
+	modelType := reflect.TypeOf(model)
+	if modelType == nil || modelType.Kind() != reflect.Ptr {
+		return model
+	}
+	primaryKey, err := c.getModelPrimaryKey(model)
+	if err != nil {
+		return model
+	}
+	reloaded := reflect.New(modelType.Elem()).Interface()
+	if err = c.GetModel(ctx, reloaded, primaryKey, defaultDatabaseMaxTimeout, true); err != nil {
+		c.DebugLog(ctx, fmt.Sprintf("failed to reload the model for the %s event: %s", EventUpdated, err.Error()))
+		return model
+	}
+	return reloaded
+}
+
+// runModelEventHandlers will run the handlers (in order) for the model
+func (c *Client) runModelEventHandlers(ctx context.Context, handlers []ModelEventHandler, event ModelEvent,
+	model interface{},
+) {
+	for _, handler := range handlers {
+		c.runModelEventHandler(ctx, handler, event, model)
+	}
+}
+
+// runModelEventHandler will run the handler, recovering (and logging) a panic
+func (c *Client) runModelEventHandler(ctx context.Context, handler ModelEventHandler, event ModelEvent,
+	model interface{},
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.DebugLog(ctx, fmt.Sprintf("panic recovered in %s event handler: %v", event, r))
+		}
+	}()
+	handler(ctx, event, model)
+}
+
+// isValidModelEvent will return true if the model event is known
+func isValidModelEvent(event ModelEvent) bool {
+	switch event {
+	case EventCreated, EventDeleted, EventUpdated:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_SubscribeModelEvents will test the method SubscribeModelEvents()
+func TestClient_SubscribeModelEvents(t *testing.T) {
+	t.Run("invalid event or handler", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite, modelEvents: &modelEvents{}}}
+		handler := func(context.Context, ModelEvent, interface{}) {}
+		require.ErrorIs(t, client.SubscribeModelEvents(&testSQLModel{}, "archived", handler), ErrInvalidModelEvent)
+		require.ErrorIs(t, client.SubscribeModelEvents(&testSQLModel{}, EventCreated, nil), ErrInvalidModelEvent)
+		require.ErrorIs(t, client.SubscribeModelEvents(nil, EventCreated, handler), ErrUnknownCollection)
+		assert.Empty(t, client.options.modelEvents.handlers)
+	})
+
+	t.Run("[sqlite] created, updated and deleted", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var events []string
+		handler := func(_ context.Context, event ModelEvent, model interface{}) {
+			events = append(events, string(event)+":"+model.(*testSQLModel).ID)
+		}
+		for _, event := range []ModelEvent{EventCreated, EventUpdated, EventDeleted} {
+			require.NoError(t, client.SubscribeModelEvents(&testSQLModel{}, event, handler))
+		}
+
+		model := &testSQLModel{ID: "event-1", Name: "a"}
+		require.NoError(t, client.SaveModelAuto(ctx, model, true))
+		model.Name = "b"
+		require.NoError(t, client.SaveModelAuto(ctx, model, false))
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.DeleteModel(ctx, model, tx, false)
+		}))
+		assert.Equal(t, []string{"created:event-1", "updated:event-1", "deleted:event-1"}, events)
+	})
+
+	t.Run("[sqlite] subscribed while reading the handlers", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, client.SubscribeModelEvents(&testSQLModel{}, EventCreated,
+					func(context.Context, ModelEvent, interface{}) {}))
+			}()
+			go func() {
+				defer wg.Done()
+				_ = client.(*Client).getModelEventHandlers(&testSQLModel{}, EventCreated)
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, client.(*Client).getModelEventHandlers(&testSQLModel{}, EventCreated), 5)
+	})
+
+	t.Run("[sqlite] fired after the commit", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var created []string
+		require.NoError(t, client.SubscribeModelEvents(&testSQLModel{}, EventCreated,
+			func(_ context.Context, _ ModelEvent, model interface{}) {
+				created = append(created, model.(*testSQLModel).ID)
+			}))
+
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			errs := client.SaveModels(ctx, []interface{}{
+				&testSQLModel{ID: "event-1"}, &testSQLModel{ID: "event-2"},
+			}, tx, true)
+			require.Nil(t, errs)
+			assert.Empty(t, created)
+			return nil
+		}))
+		assert.Equal(t, []string{"event-1", "event-2"}, created)
+
+		// Rolled back writes are not fired
+		require.Error(t, client.NewTx(ctx, func(tx *Transaction) error {
+			require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "event-3"}, tx, true, false))
+			return errors.New("failed")
+		}))
+		assert.Equal(t, []string{"event-1", "event-2"}, created)
+	})
+
+	t.Run("[sqlite] panic in a handler", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		called := false
+		require.NoError(t, client.SubscribeModelEvents(&testSQLModel{}, EventCreated,
+			func(context.Context, ModelEvent, interface{}) { panic("handler") }))
+		require.NoError(t, client.SubscribeModelEvents(&testSQLModel{}, EventCreated,
+			func(context.Context, ModelEvent, interface{}) { called = true }))
+
+		require.NoError(t, client.SaveModelAuto(ctx, &testSQLModel{ID: "event-1"}, true))
+		assert.True(t, called)
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("[sqlite] partial updates, upserts and batches", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var events []string
+		handler := func(_ context.Context, event ModelEvent, model interface{}) {
+			record := model.(*testSQLModel)
+			events = append(events, fmt.Sprintf("%s:%s:%s:%d", event, record.ID, record.Name, record.Amount))
+		}
+		for _, event := range []ModelEvent{EventCreated, EventUpdated} {
+			require.NoError(t, client.SubscribeModelEvents(&testSQLModel{}, event, handler))
+		}
+
+		require.NoError(t, client.CreateInBatches(ctx, []*testSQLModel{
+			{ID: "event-1", Name: "a"}, {ID: "event-2", Name: "b"},
+		}, 10))
+		assert.Equal(t, []string{"created:event-1:a:0", "created:event-2:b:0"}, events)
+
+		// The handlers receive the reloaded record
+		events = nil
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.UpdateModelFields(ctx, &testSQLModel{ID: "event-1"}, map[string]interface{}{"amount": 5}, tx, false)
+		}))
+		_, err := client.IncrementModel(ctx, &testSQLModel{ID: "event-1"}, "amount", 2)
+		require.NoError(t, err)
+		require.NoError(t, client.UpsertModel(ctx, &testSQLModel{ID: "event-2", Name: "c"}, nil, []string{"name"}))
+		assert.Equal(t, []string{"updated:event-1:a:5", "updated:event-1:a:7", "updated:event-2:c:0"}, events)
+
+		// Created only if missing
+		events = nil
+		created, err := client.FindOrCreateModel(ctx, &testSQLModel{ID: "event-3", Name: "d"},
+			map[string]interface{}{"name": "d"}, nil)
+		require.NoError(t, err)
+		assert.True(t, created)
+		created, err = client.FindOrCreateModel(ctx, &testSQLModel{ID: "event-4", Name: "d"},
+			map[string]interface{}{"name": "d"}, nil)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, []string{"created:event-3:d:0"}, events)
+	})
+}
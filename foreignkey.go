@@ -0,0 +1,114 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidForeignKeyAction is when the referential action is not a known action
+var ErrInvalidForeignKeyAction = errors.New("invalid foreign key action")
+
+// ForeignKeyAction is the referential action of a foreign key (ON DELETE / ON UPDATE)
+type ForeignKeyAction string
+
+// Foreign key actions
+const (
+	ForeignKeyCascade    ForeignKeyAction = "CASCADE"
+	ForeignKeyNoAction   ForeignKeyAction = "NO ACTION"
+	ForeignKeyRestrict   ForeignKeyAction = "RESTRICT"
+	ForeignKeySetDefault ForeignKeyAction = "SET DEFAULT"
+	ForeignKeySetNull    ForeignKeyAction = "SET NULL"
+)
+
+// ForeignKeyOptions are the options for EnsureForeignKey()
+type ForeignKeyOptions struct {
+	Deferrable bool             // Check the constraint at the end of the transaction (PostgreSQL)
+	NotValid   bool             // Skip validating the existing rows, only new rows are checked (PostgreSQL)
+	OnDelete   ForeignKeyAction // Action when the referenced row is deleted
+	OnUpdate   ForeignKeyAction // Action when the referenced key is updated
+}
+
+// EnsureForeignKey will create a foreign key constraint from the model column to the reference model column
+// (if it does not exist)
+//
+// For services that rely on database-enforced referential integrity, see: CommonConfig.EnableForeignKeys
+// SQLite does not support adding constraints to existing tables, MongoDB does not have foreign keys
+func (c *Client) EnsureForeignKey(ctx context.Context, model interface{}, column string,
+	reference interface{}, referenceColumn string, fkOptions *ForeignKeyOptions) error {
+
+	if c.Engine() == SQLite {
+		return ErrNotImplemented
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	// Get the table names
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return err
+	}
+	var referenceTable string
+	if referenceTable, err = c.getModelTableName(reference); err != nil {
+		return err
+	}
+	constraintName := "fk_" + tableName + "_" + column
+
+	// Build the constraint
+	query, err := getForeignKeyQuery(c.Engine(), tableName, constraintName, column,
+		referenceTable, referenceColumn, fkOptions)
+	if err != nil {
+		return err
+	}
+
+	db := c.options.db.WithContext(ctx)
+	if db.Migrator().HasConstraint(model, constraintName) {
+		return nil
+	}
+	return db.Exec(query).Error
+}
+
+// getForeignKeyQuery will return the ALTER TABLE query for adding the foreign key constraint
+func getForeignKeyQuery(engine Engine, tableName, constraintName, column, referenceTable, referenceColumn string,
+	fkOptions *ForeignKeyOptions) (string, error) {
+
+	if fkOptions == nil {
+		fkOptions = &ForeignKeyOptions{}
+	}
+
+	query := "ALTER TABLE " + tableName + " ADD CONSTRAINT " + constraintName +
+		" FOREIGN KEY (" + column + ") REFERENCES " + referenceTable + " (" + referenceColumn + ")"
+	if fkOptions.OnDelete != "" {
+		if !isValidForeignKeyAction(fkOptions.OnDelete) {
+			return "", ErrInvalidForeignKeyAction
+		}
+		query += " ON DELETE " + strings.ToUpper(string(fkOptions.OnDelete))
+	}
+	if fkOptions.OnUpdate != "" {
+		if !isValidForeignKeyAction(fkOptions.OnUpdate) {
+			return "", ErrInvalidForeignKeyAction
+		}
+		query += " ON UPDATE " + strings.ToUpper(string(fkOptions.OnUpdate))
+	}
+
+	// Deferred and unvalidated constraints (PostgreSQL only)
+	if engine == PostgreSQL {
+		if fkOptions.Deferrable {
+			query += " DEFERRABLE INITIALLY DEFERRED"
+		}
+		if fkOptions.NotValid {
+			query += " NOT VALID"
+		}
+	}
+	return query, nil
+}
+
+// isValidForeignKeyAction will return true if the action is a known referential action
+func isValidForeignKeyAction(action ForeignKeyAction) bool {
+	switch ForeignKeyAction(strings.ToUpper(string(action))) {
+	case ForeignKeyCascade, ForeignKeyNoAction, ForeignKeyRestrict, ForeignKeySetDefault, ForeignKeySetNull:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,61 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_EnsureForeignKey will test the method EnsureForeignKey()
+func TestClient_EnsureForeignKey(t *testing.T) {
+	t.Run("sqlite is not implemented", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		err := client.EnsureForeignKey(ctx, &testSQLModel{}, "name", &testSQLModel{}, "id", nil)
+		require.ErrorIs(t, err, ErrNotImplemented)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: MongoDB}}
+		err := client.EnsureForeignKey(context.Background(), &testSQLModel{}, "name", &testSQLModel{}, "id", nil)
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// Test_getForeignKeyQuery will test the method getForeignKeyQuery()
+func Test_getForeignKeyQuery(t *testing.T) {
+	t.Run("no options", func(t *testing.T) {
+		query, err := getForeignKeyQuery(MySQL, "orders", "fk_orders_user_id", "user_id", "users", "id", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "ALTER TABLE orders ADD CONSTRAINT fk_orders_user_id FOREIGN KEY (user_id) REFERENCES users (id)", query)
+	})
+
+	t.Run("actions", func(t *testing.T) {
+		query, err := getForeignKeyQuery(MySQL, "orders", "fk_orders_user_id", "user_id", "users", "id",
+			&ForeignKeyOptions{OnDelete: ForeignKeyCascade, OnUpdate: "set null", Deferrable: true, NotValid: true})
+		require.NoError(t, err)
+		assert.Equal(t, "ALTER TABLE orders ADD CONSTRAINT fk_orders_user_id FOREIGN KEY (user_id) REFERENCES users (id)"+
+			" ON DELETE CASCADE ON UPDATE SET NULL", query)
+	})
+
+	t.Run("postgresql deferred and not valid", func(t *testing.T) {
+		query, err := getForeignKeyQuery(PostgreSQL, "orders", "fk_orders_user_id", "user_id", "users", "id",
+			&ForeignKeyOptions{OnDelete: ForeignKeyRestrict, Deferrable: true, NotValid: true})
+		require.NoError(t, err)
+		assert.Equal(t, "ALTER TABLE orders ADD CONSTRAINT fk_orders_user_id FOREIGN KEY (user_id) REFERENCES users (id)"+
+			" ON DELETE RESTRICT DEFERRABLE INITIALLY DEFERRED NOT VALID", query)
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		_, err := getForeignKeyQuery(PostgreSQL, "orders", "fk_orders_user_id", "user_id", "users", "id",
+			&ForeignKeyOptions{OnDelete: "DROP TABLE users"})
+		require.ErrorIs(t, err, ErrInvalidForeignKeyAction)
+
+		_, err = getForeignKeyQuery(PostgreSQL, "orders", "fk_orders_user_id", "user_id", "users", "id",
+			&ForeignKeyOptions{OnUpdate: "nothing"})
+		require.ErrorIs(t, err, ErrInvalidForeignKeyAction)
+	})
+}
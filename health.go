@@ -0,0 +1,138 @@
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Health check node names (see: NodeHealth)
+const (
+	healthNodePrimary       = "primary"  // MongoDB primary (the only MongoDB node checked)
+	healthNodeReplicaPrefix = "replica-" // SQL replicas (replica-1, replica-2...)
+	healthNodeSource        = "source"   // SQL source, memory store or DynamoDB endpoint
+)
+
+// ErrNotConnected is when the client is not connected to the datastore (IE: closed)
+var ErrNotConnected = errors.New("datastore is not connected")
+
+// NodeHealth is the health of a datastore node (see: HealthCheck)
+type NodeHealth struct {
+	Error   string        `json:"error,omitempty"` // Error of the check (empty if healthy)
+	Healthy bool          `json:"healthy"`         // The node is reachable
+	Latency time.Duration `json:"latency"`         // Round trip of the check
+	Name    string        `json:"name"`            // Node name: source, replica-1, primary
+	Replica bool          `json:"replica"`         // The node is a read replica
+}
+
+// HealthReport is the health of the datastore nodes (see: HealthCheck)
+type HealthReport struct {
+	Degraded bool          `json:"degraded"` // A replica is not healthy (the reads use the other nodes)
+	Engine   Engine        `json:"engine"`   // Datastore engine
+	Healthy  bool          `json:"healthy"`  // The source (or primary) is healthy
+	Nodes    []*NodeHealth `json:"nodes"`    // Health of each node (source first)
+}
+
+// Ping will check the connection of the source database (or the MongoDB primary)
+//
+// Used for liveness and readiness probes, see HealthCheck for the health of each node
+func (c *Client) Ping(ctx context.Context) error {
+	if c.Engine() == MongoDB {
+		if c.options.mongoDB == nil {
+			return ErrNotConnected
+		}
+		return c.options.mongoDB.Client().Ping(ctx, readpref.Primary())
+	} else if c.Engine() == Memory {
+		if c.options.memory == nil {
+			return ErrNotConnected
+		}
+		return nil
+	} else if c.Engine() == DynamoDB {
+		return c.pingDynamoDB(ctx)
+	}
+
+	// SQL (source database)
+	db, err := c.getSourceDB()
+	if err != nil {
+		return err
+	}
+	return db.PingContext(ctx)
+}
+
+// HealthCheck will check the source database, each read replica and the MongoDB primary, returning the
+// latency and the error of each node
+//
+// The report is healthy if the source is reachable, and degraded if a replica is not reachable. MongoDB
+// reports a single node (the primary), the driver selects the replica set members (the report is never degraded)
+func (c *Client) HealthCheck(ctx context.Context) *HealthReport {
+	report := &HealthReport{Engine: c.Engine()}
+
+	// Source (or primary)
+	name := healthNodeSource
+	if c.Engine() == MongoDB {
+		name = healthNodePrimary
+	}
+	source := checkNodeHealth(name, false, func() error {
+		return c.Ping(ctx)
+	})
+	report.Nodes = append(report.Nodes, source)
+	report.Healthy = source.Healthy
+
+	// Read replicas (MySQL and PostgreSQL)
+	if IsSQLEngine(c.Engine()) && c.options.replicas != nil {
+		for index, replica := range c.options.replicas.readReplicas() {
+			node := checkNodeHealth(healthNodeReplicaPrefix+strconv.Itoa(index+1), true, func() error {
+				return replica.PingContext(ctx)
+			})
+			report.Nodes = append(report.Nodes, node)
+			report.Degraded = report.Degraded || !node.Healthy
+		}
+	}
+	return report
+}
+
+// checkNodeHealth will return the health of the node using the check
+func checkNodeHealth(name string, replica bool, check func() error) *NodeHealth {
+	start := time.Now()
+	err := check()
+	node := &NodeHealth{Healthy: err == nil, Latency: time.Since(start), Name: name, Replica: replica}
+	if err != nil {
+		node.Error = err.Error()
+	}
+	return node
+}
+
+// getSourceDB will return the source database of a SQL datastore
+func (c *Client) getSourceDB() (*sql.DB, error) {
+	if c.options.replicas != nil && c.options.replicas.source != nil {
+		return c.options.replicas.source, nil
+	}
+	if c.options.db == nil {
+		return nil, ErrNotConnected
+	}
+	return c.options.db.DB()
+}
+
+// pingDynamoDB will check the DynamoDB endpoint (ListTables with a limit of 1)
+//
+// Clients without ListTables (IE: test doubles) are not checked
+func (c *Client) pingDynamoDB(ctx context.Context) error {
+	if c.options.dynamoDB == nil {
+		return ErrNotConnected
+	}
+	lister, ok := c.options.dynamoDB.client.(interface {
+		ListTables(ctx context.Context, params *dynamodb.ListTablesInput,
+			optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error)
+	})
+	if !ok {
+		return nil
+	}
+	_, err := lister.ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(1)})
+	return err
+}
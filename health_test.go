@@ -0,0 +1,104 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_Ping will test the method Ping()
+func TestClient_Ping(t *testing.T) {
+	t.Run("sqlite", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		require.NoError(t, client.Ping(ctx))
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t)
+		defer deferFunc()
+		require.NoError(t, client.Ping(ctx))
+	})
+
+	t.Run("dynamodb without ListTables", func(t *testing.T) {
+		ctx := context.Background()
+		client, _ := testDynamoDBClient(ctx, t)
+		require.NoError(t, client.Ping(ctx))
+	})
+
+	t.Run("closed client", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t)
+		deferFunc()
+		require.ErrorIs(t, client.Ping(ctx), ErrNotConnected)
+
+		client, deferFunc = testSQLiteClient(ctx, t)
+		deferFunc()
+		require.ErrorIs(t, client.Ping(ctx), ErrNotConnected)
+	})
+}
+
+// TestClient_HealthCheck will test the method HealthCheck()
+func TestClient_HealthCheck(t *testing.T) {
+	t.Run("sqlite", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		report := client.HealthCheck(ctx)
+		assert.Equal(t, SQLite, report.Engine)
+		assert.True(t, report.Healthy)
+		assert.False(t, report.Degraded)
+		require.Len(t, report.Nodes, 1)
+		assert.Equal(t, "source", report.Nodes[0].Name)
+		assert.True(t, report.Nodes[0].Healthy)
+		assert.Empty(t, report.Nodes[0].Error)
+		assert.Positive(t, report.Nodes[0].Latency)
+	})
+
+	t.Run("replicas", func(t *testing.T) {
+		ctx := context.Background()
+		client, err := NewClient(ctx, WithSQLConnection(PostgreSQL, testReplicaDB(t, "health_source"), ""))
+		require.NoError(t, err)
+		defer func() {
+			_ = client.Close(ctx)
+		}()
+
+		replicaA := testReplicaDB(t, "health_replica_a")
+		replicaB := testReplicaDB(t, "health_replica_b")
+		require.NoError(t, client.UpdateReplicas(ctx, []*SQLConfig{
+			{ExistingConnection: replicaA}, {ExistingConnection: replicaB},
+		}))
+
+		report := client.HealthCheck(ctx)
+		assert.True(t, report.Healthy)
+		assert.False(t, report.Degraded)
+		require.Len(t, report.Nodes, 3)
+		assert.Equal(t, "replica-1", report.Nodes[1].Name)
+		assert.True(t, report.Nodes[1].Replica)
+
+		// A replica is down
+		require.NoError(t, replicaB.Close())
+		report = client.HealthCheck(ctx)
+		assert.True(t, report.Healthy)
+		assert.True(t, report.Degraded)
+		assert.True(t, report.Nodes[1].Healthy)
+		assert.False(t, report.Nodes[2].Healthy)
+		assert.NotEmpty(t, report.Nodes[2].Error)
+	})
+
+	t.Run("closed client", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t)
+		deferFunc()
+
+		report := client.HealthCheck(ctx)
+		assert.False(t, report.Healthy)
+		require.Len(t, report.Nodes, 1)
+		assert.Equal(t, ErrNotConnected.Error(), report.Nodes[0].Error)
+	})
+}
@@ -0,0 +1,64 @@
+package datastore
+
+import (
+	"errors"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+)
+
+// ErrUnknownIndexHint is when the index hint has not been registered (see: WithIndexHint)
+var ErrUnknownIndexHint = errors.New("unknown index hint")
+
+// indexNamePattern is the allowed format for an index name in a hint (prevents injection)
+var indexNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// IndexHint is a vetted index hint for a hot query where the planner picks the wrong index
+//
+// MySQL: USE INDEX (or FORCE INDEX), SQLite: INDEXED BY (always forced), MongoDB: hint()
+// PostgreSQL does not support index hints (the hint is ignored)
+type IndexHint struct {
+	Force bool   // Use FORCE INDEX instead of USE INDEX (MySQL)
+	Index string // Name of the index
+}
+
+// getIndexHint will return the registered index hint
+func (c *Client) getIndexHint(name string) (*IndexHint, error) {
+	hint, ok := c.options.indexHints[name]
+	if !ok {
+		return nil, ErrUnknownIndexHint
+	}
+	return hint, nil
+}
+
+// applyIndexHint will add the index hint to the table of the query (SQL)
+func (c *Client) applyIndexHint(tx *gorm.DB, model interface{}, name string) (*gorm.DB, error) {
+	hint, err := c.getIndexHint(name)
+	if err != nil {
+		return nil, err
+	}
+	if c.Engine() == PostgreSQL {
+		return tx, nil
+	}
+
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return nil, err
+	}
+	if c.Engine() == SQLite {
+		return tx.Table(tableName + " INDEXED BY " + hint.Index), nil
+	} else if hint.Force {
+		return tx.Table(tableName + " FORCE INDEX (" + hint.Index + ")"), nil
+	}
+	return tx.Table(tableName + " USE INDEX (" + hint.Index + ")"), nil
+}
+
+// getMongoIndexHint will return the find options for the index hint (MongoDB)
+func (c *Client) getMongoIndexHint(name string) (*options.FindOptions, error) {
+	hint, err := c.getIndexHint(name)
+	if err != nil {
+		return nil, err
+	}
+	return options.Find().SetHint(hint.Index), nil
+}
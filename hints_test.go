@@ -0,0 +1,52 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_IndexHint will test using a registered index hint in QueryParams
+func TestClient_IndexHint(t *testing.T) {
+	records := []*testSQLModel{
+		{ID: "hint-1", Name: "alice"},
+		{ID: "hint-2", Name: "bob"},
+	}
+
+	t.Run("unknown hint", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		var models []*testSQLModel
+		err := client.GetModels(ctx, &models, nil, &QueryParams{IndexHint: "by_name"}, nil, defaultDatabaseMaxTimeout)
+		require.ErrorIs(t, err, ErrUnknownIndexHint)
+	})
+
+	t.Run("registered hint", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithIndexHint("by_name", IndexHint{Index: "idx_hint_name"}))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+		require.NoError(t, client.Execute("CREATE INDEX idx_hint_name ON "+testSQLTableName+" (name)").Error)
+
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{"name": "bob"},
+			&QueryParams{IndexHint: "by_name"}, nil, defaultDatabaseMaxTimeout))
+		require.Len(t, models, 1)
+		assert.Equal(t, "hint-2", models[0].ID)
+	})
+
+	t.Run("registered hint with a missing index", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithIndexHint("by_name", IndexHint{Index: "idx_missing"}))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		var models []*testSQLModel
+		require.Error(t, client.GetModels(ctx, &models, nil, &QueryParams{IndexHint: "by_name"}, nil, defaultDatabaseMaxTimeout))
+	})
+}
@@ -0,0 +1,76 @@
+package datastore
+
+import (
+	"regexp"
+	"strings"
+)
+
+// identifierPattern is the format of an identifier that does not need quoting (IE: created_at)
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sqlReservedWords are the common reserved words of MySQL, PostgreSQL and SQLite (quoted as identifiers)
+var sqlReservedWords = map[string]bool{
+	"all": true, "analyze": true, "and": true, "as": true, "asc": true, "between": true, "both": true,
+	"by": true, "case": true, "check": true, "collate": true, "column": true, "constraint": true,
+	"create": true, "cross": true, "current_date": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "default": true, "delete": true, "desc": true, "distinct": true, "drop": true,
+	"else": true, "end": true, "exists": true, "false": true, "fetch": true, "for": true, "foreign": true,
+	"from": true, "full": true, "grant": true, "group": true, "groups": true, "having": true, "in": true,
+	"index": true, "inner": true, "insert": true, "interval": true, "into": true, "is": true, "join": true,
+	"key": true, "keys": true, "leading": true, "left": true, "like": true, "limit": true, "lock": true,
+	"match": true, "natural": true, "not": true, "null": true, "offset": true, "on": true, "only": true,
+	"option": true, "or": true, "order": true, "outer": true, "primary": true, "range": true, "rank": true,
+	"read": true, "references": true, "release": true, "replace": true, "returning": true, "right": true,
+	"row": true, "rows": true, "select": true, "set": true, "show": true, "table": true, "then": true,
+	"to": true, "trailing": true, "trigger": true, "true": true, "union": true, "unique": true,
+	"update": true, "user": true, "using": true, "values": true, "when": true, "where": true,
+	"window": true, "with": true,
+}
+
+// quoteIdentifier will return the identifier (column or table.column) quoted for the engine if needed
+//
+// Reserved words and names with other characters are quoted (MySQL: backticks, others: double quotes),
+// the quote character is escaped by doubling it (prevents injection)
+func quoteIdentifier(engine Engine, name string) string {
+	parts := strings.Split(name, ".")
+	for _, part := range parts {
+		if len(part) == 0 { // Not a qualified name (IE: a.b), quote the whole name
+			return quoteIdentifierPart(engine, name)
+		}
+	}
+	for i, part := range parts {
+		parts[i] = quoteIdentifierPart(engine, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteIdentifierPart will return the part of an identifier quoted for the engine if needed
+func quoteIdentifierPart(engine Engine, part string) string {
+	if identifierPattern.MatchString(part) && !sqlReservedWords[strings.ToLower(part)] {
+		return part
+	} else if engine == MySQL {
+		return "`" + strings.ReplaceAll(part, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+}
+
+// isValidObjectKey will return true if the key of a JSON object can be used in a condition
+// (the quotes and the backslash can not be escaped the same way by all the engines)
+func isValidObjectKey(key string) bool {
+	return len(key) > 0 && !strings.ContainsAny(key, `'"\`)
+}
+
+// getJSONPath will return the JSON path of the (nested) object keys (IE: '$.testId."test-key"')
+//
+// Keys that are not identifiers are quoted (MySQL and SQLite)
+func getJSONPath(keys ...string) string {
+	path := "'$"
+	for _, key := range keys {
+		if identifierPattern.MatchString(key) {
+			path += "." + key
+		} else {
+			path += `."` + key + `"`
+		}
+	}
+	return path + "'"
+}
@@ -0,0 +1,44 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_quoteIdentifier will test the method quoteIdentifier()
+func Test_quoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		engine   Engine
+		input    string
+		expected string
+	}{
+		{"plain", MySQL, "created_at", "created_at"},
+		{"MySQL reserved word", MySQL, "order", "`order`"},
+		{"reserved word case", PostgreSQL, "Group", `"Group"`},
+		{"SQLite characters", SQLite, "first name", `"first name"`},
+		{"MySQL escaped quote", MySQL, "a`b", "`a``b`"},
+		{"Postgres escaped quote", PostgreSQL, `a"b`, `"a""b"`},
+		{"qualified name", MySQL, "table.key", "`table`.`key`"},
+		{"empty part", PostgreSQL, "a..b", `"a..b"`},
+		{"leading digit", SQLite, "1st", `"1st"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, quoteIdentifier(test.engine, test.input))
+		})
+	}
+}
+
+// Test_getJSONPath will test the methods getJSONPath() and isValidObjectKey()
+func Test_getJSONPath(t *testing.T) {
+	assert.Equal(t, "'$.test_key'", getJSONPath("test_key"))
+	assert.Equal(t, `'$.testId."test-key"'`, getJSONPath("testId", "test-key"))
+
+	assert.True(t, isValidObjectKey("test key"))
+	assert.False(t, isValidObjectKey(""))
+	assert.False(t, isValidObjectKey("key'"))
+	assert.False(t, isValidObjectKey(`key"`))
+	assert.False(t, isValidObjectKey(`key\`))
+}
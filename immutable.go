@@ -0,0 +1,146 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrInvalidCacheTTL is when the cache TTL for an immutable model is zero or negative
+var ErrInvalidCacheTTL = errors.New("cache ttl must be greater than zero")
+
+// immutableModels are the registered immutable models (by model name) and their cached records
+type immutableModels struct {
+	entries map[string]*immutableEntry // Cached records (by model name and primary key)
+	mu      sync.RWMutex               // Lock for the models and entries
+	ttls    map[string]time.Duration   // Registered models (by model name) and their cache TTL
+}
+
+// immutableEntry is a cached record of an immutable model
+type immutableEntry struct {
+	expires time.Time   // Time the entry expires
+	value   interface{} // Copy of the record (struct value)
+}
+
+// RegisterImmutableModel will mark the model as immutable, GetModelByID results are cached in-process for the TTL
+//
+// The records are never invalidated (updates are not visible until the entry expires), only use it for
+// reference or configuration tables that do not change (the cached struct is a shallow copy)
+func (c *Client) RegisterImmutableModel(model interface{}, ttl time.Duration) error {
+	if ttl <= 0 {
+		return ErrInvalidCacheTTL
+	}
+	modelName := GetModelName(model)
+	if modelName == nil {
+		return ErrUnknownCollection
+	}
+
+	registered := c.options.immutableModels
+	registered.mu.Lock()
+	defer registered.mu.Unlock()
+	if registered.ttls == nil {
+		registered.ttls = make(map[string]time.Duration)
+	}
+	registered.ttls[*modelName] = ttl
+	return nil
+}
+
+// GetModelByID will get the model by its (single) primary key
+//
+// Models registered using RegisterImmutableModel() are served from the in-process cache, except for
+// masked readers (see: WithMaskedReader) and locked reads (see: WithRowLock)
+func (c *Client) GetModelByID(
+	ctx context.Context,
+	model interface{},
+	id interface{},
+	timeout time.Duration,
+) error {
+	column := mongoIDField
+	if c.Engine() != MongoDB {
+		var err error
+		if column, err = c.getSinglePrimaryKeyColumn(model); err != nil {
+			return err
+		}
+	}
+	conditions := map[string]interface{}{column: id}
+
+	key, ttl := c.getImmutableCacheKey(ctx, model, id)
+	if ttl <= 0 {
+		return c.GetModel(ctx, model, conditions, timeout, false)
+	}
+
+	// Serve from the cache
+	if c.loadImmutableModel(key, model) {
+		c.recordResultSize(ctx, metricGetModel, model)
+		return c.mapResults(ctx, model)
+	}
+
+	if err := c.GetModel(ctx, model, conditions, timeout, false); err != nil {
+		return err // Missing records are not cached
+	}
+	c.storeImmutableModel(key, model, ttl)
+	return nil
+}
+
+// getImmutableCacheKey will return the cache key and TTL for the model (zero TTL if the result is not cached)
+func (c *Client) getImmutableCacheKey(ctx context.Context, model interface{}, id interface{}) (string, time.Duration) {
+	registered := c.options.immutableModels
+	if registered == nil || IsMaskedReader(ctx) || getRowLock(ctx) != nil {
+		return "", 0
+	}
+	modelName := GetModelName(model)
+	if modelName == nil {
+		return "", 0
+	}
+
+	registered.mu.RLock()
+	ttl := registered.ttls[*modelName]
+	registered.mu.RUnlock()
+
+	// The deleted records are cached separately (see: IncludeDeleted)
+	return fmt.Sprintf("%s:%t:%T:%v", *modelName, isIncludeDeleted(ctx), id, id), ttl
+}
+
+// loadImmutableModel will copy the cached record into the model (false if not found or expired)
+func (c *Client) loadImmutableModel(key string, model interface{}) bool {
+	registered := c.options.immutableModels
+	registered.mu.RLock()
+	entry, ok := registered.entries[key]
+	registered.mu.RUnlock()
+	if !ok || time.Now().After(entry.expires) {
+		return false
+	}
+
+	dest := reflect.ValueOf(model)
+	value := reflect.ValueOf(entry.value)
+	if dest.Kind() != reflect.Ptr || dest.IsNil() || dest.Elem().Type() != value.Type() {
+		return false
+	}
+	dest.Elem().Set(value)
+	return true
+}
+
+// storeImmutableModel will cache a copy of the record (expired entries are removed)
+func (c *Client) storeImmutableModel(key string, model interface{}, ttl time.Duration) {
+	value := reflect.ValueOf(model)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return
+	}
+
+	registered := c.options.immutableModels
+	registered.mu.Lock()
+	defer registered.mu.Unlock()
+	now := time.Now()
+	if registered.entries == nil {
+		registered.entries = make(map[string]*immutableEntry)
+	}
+	for existing, entry := range registered.entries {
+		if now.After(entry.expires) {
+			delete(registered.entries, existing)
+		}
+	}
+	registered.entries[key] = &immutableEntry{expires: now.Add(ttl), value: value.Elem().Interface()}
+}
@@ -0,0 +1,123 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_RegisterImmutableModel will test the method RegisterImmutableModel()
+func TestClient_RegisterImmutableModel(t *testing.T) {
+	t.Run("invalid ttl", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite, immutableModels: &immutableModels{}}}
+		require.ErrorIs(t, client.RegisterImmutableModel(&testSQLModel{}, 0), ErrInvalidCacheTTL)
+		require.ErrorIs(t, client.RegisterImmutableModel(&testSQLModel{}, -time.Second), ErrInvalidCacheTTL)
+		assert.Empty(t, client.options.immutableModels.ttls)
+	})
+
+	t.Run("unknown model", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite}}
+		require.ErrorIs(t, client.RegisterImmutableModel(nil, time.Minute), ErrUnknownCollection)
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite, immutableModels: &immutableModels{}}}
+		require.NoError(t, client.RegisterImmutableModel(&testSQLModel{}, time.Minute))
+		assert.Equal(t, time.Minute, client.options.immutableModels.ttls["test_sql_model"])
+	})
+}
+
+// TestClient_GetModelByID will test the method GetModelByID()
+func TestClient_GetModelByID(t *testing.T) {
+	t.Run("not cached", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "by-id-1", Name: "first"})
+
+		model := new(testSQLModel)
+		require.NoError(t, client.GetModelByID(ctx, model, "by-id-1", defaultDatabaseMaxTimeout))
+		assert.Equal(t, "first", model.Name)
+
+		// Changes are visible right away
+		require.NoError(t, client.ExecuteContext(
+			ctx, "UPDATE "+testSQLTableName+" SET name = ? WHERE id = ?", "changed", "by-id-1",
+		).Error)
+		model = new(testSQLModel)
+		require.NoError(t, client.GetModelByID(ctx, model, "by-id-1", defaultDatabaseMaxTimeout))
+		assert.Equal(t, "changed", model.Name)
+
+		require.ErrorIs(t, client.GetModelByID(ctx, new(testSQLModel), "missing", defaultDatabaseMaxTimeout),
+			ErrNoResults)
+	})
+
+	t.Run("cached immutable model", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "by-id-2", Name: "first", Amount: 5})
+		require.NoError(t, client.RegisterImmutableModel(&testSQLModel{}, time.Minute))
+
+		model := new(testSQLModel)
+		require.NoError(t, client.GetModelByID(ctx, model, "by-id-2", defaultDatabaseMaxTimeout))
+		assert.Equal(t, "first", model.Name)
+
+		// The cached record is returned (no invalidation)
+		require.NoError(t, client.ExecuteContext(
+			ctx, "UPDATE "+testSQLTableName+" SET name = ? WHERE id = ?", "changed", "by-id-2",
+		).Error)
+		model = new(testSQLModel)
+		require.NoError(t, client.GetModelByID(ctx, model, "by-id-2", defaultDatabaseMaxTimeout))
+		assert.Equal(t, "first", model.Name)
+		assert.Equal(t, int64(5), model.Amount)
+
+		// Modifying the result does not change the cache
+		model.Name = "modified"
+		model = new(testSQLModel)
+		require.NoError(t, client.GetModelByID(ctx, model, "by-id-2", defaultDatabaseMaxTimeout))
+		assert.Equal(t, "first", model.Name)
+
+		// GetModel is not cached
+		model = new(testSQLModel)
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "by-id-2"},
+			defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "changed", model.Name)
+
+		// Missing records are not cached
+		require.ErrorIs(t, client.GetModelByID(ctx, new(testSQLModel), "by-id-3", defaultDatabaseMaxTimeout),
+			ErrNoResults)
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "by-id-3", Name: "third"})
+		model = new(testSQLModel)
+		require.NoError(t, client.GetModelByID(ctx, model, "by-id-3", defaultDatabaseMaxTimeout))
+		assert.Equal(t, "third", model.Name)
+	})
+
+	t.Run("expired entry", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "by-id-4", Name: "first"})
+		require.NoError(t, client.RegisterImmutableModel(&testSQLModel{}, 20*time.Millisecond))
+
+		require.NoError(t, client.GetModelByID(ctx, new(testSQLModel), "by-id-4", defaultDatabaseMaxTimeout))
+		require.NoError(t, client.ExecuteContext(
+			ctx, "UPDATE "+testSQLTableName+" SET name = ? WHERE id = ?", "changed", "by-id-4",
+		).Error)
+		time.Sleep(30 * time.Millisecond)
+
+		model := new(testSQLModel)
+		require.NoError(t, client.GetModelByID(ctx, model, "by-id-4", defaultDatabaseMaxTimeout))
+		assert.Equal(t, "changed", model.Name)
+	})
+
+	t.Run("composite primary key", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithAutoMigrate(&testCompositeModel{}))
+		defer deferFunc()
+		require.ErrorIs(t, client.GetModelByID(ctx, new(testCompositeModel), "id", defaultDatabaseMaxTimeout),
+			ErrCompositePrimaryKey)
+	})
+}
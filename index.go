@@ -2,15 +2,23 @@ package datastore
 
 import (
 	"context"
+	"errors"
+	"strconv"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrInvalidIndexColumn is when the column (or the index name) is not a valid identifier (IE: letters, digits and _)
+var ErrInvalidIndexColumn = errors.New("invalid index column")
+
 // IndexExists check whether the given index exists in the datastore
 func (c *Client) IndexExists(tableName, indexName string) (bool, error) {
 	if c.Engine() == MySQL {
 		return c.indexExistsMySQL(tableName, indexName)
+	} else if c.isCockroachDB() {
+		return c.indexExistsCockroachDB(tableName, indexName)
 	}
 
 	return false, ErrUnknownSQL
@@ -84,3 +92,48 @@ func (c *Client) IndexMetadata(tableName, field string) error {
 
 	return nil
 }
+
+// EnsureCaseInsensitiveUnique will create a case-insensitive unique index for the model column (if it does not exist)
+//
+// PostgreSQL & SQLite: unique index on LOWER(column)
+// MySQL: unique functional index on the column using the utf8mb4_0900_ai_ci collation (requires MySQL 8.0.13+)
+// MongoDB: unique index using a collation strength of 2 (case-insensitive)
+func (c *Client) EnsureCaseInsensitiveUnique(ctx context.Context, model interface{}, column string) error {
+
+	// Get the table name
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return err
+	}
+
+	// The column and the index name are used in the statement (not bound)
+	indexName := "idx_" + tableName + "_" + column + "_ci"
+	if !indexNamePattern.MatchString(column) || !indexNamePattern.MatchString(indexName) {
+		return ErrInvalidIndexColumn
+	}
+
+	if c.Engine() == PostgreSQL || c.Engine() == SQLite {
+		return c.options.db.WithContext(ctx).Exec(
+			`CREATE UNIQUE INDEX IF NOT EXISTS ` + indexName + ` ON ` + tableName + ` (LOWER(` + column + `))`,
+		).Error
+	} else if c.Engine() == MySQL {
+		var exists bool
+		if exists, err = c.indexExistsMySQL(tableName, indexName); err != nil || exists {
+			return err
+		}
+		return c.options.db.WithContext(ctx).Exec(
+			`CREATE UNIQUE INDEX ` + indexName + ` ON ` + tableName +
+				` ((CAST(` + column + ` AS CHAR(` + strconv.Itoa(int(defaultFieldStringSize)) + `) CHARACTER SET utf8mb4) COLLATE utf8mb4_0900_ai_ci))`,
+		).Error
+	} else if c.Engine() == MongoDB {
+		return createMongoIndex(ctx, c.options, tableName, true, mongo.IndexModel{
+			Keys: bson.D{{Key: column, Value: 1}},
+			Options: options.Index().SetName(indexName).SetUnique(true).SetCollation(&options.Collation{
+				Locale:   defaultCollationLocale,
+				Strength: 2,
+			}),
+		})
+	}
+
+	return ErrUnsupportedEngine
+}
@@ -0,0 +1,44 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_EnsureCaseInsensitiveUnique will test the method EnsureCaseInsensitiveUnique()
+func TestClient_EnsureCaseInsensitiveUnique(t *testing.T) {
+	t.Run("[sqlite] duplicate with different case", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		require.NoError(t, client.EnsureCaseInsensitiveUnique(ctx, &testSQLModel{}, "name"))
+
+		// Running again is a no-op
+		require.NoError(t, client.EnsureCaseInsensitiveUnique(ctx, &testSQLModel{}, "name"))
+
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "ci-1", Name: "Test@Example.com"})
+
+		err := client.NewTx(ctx, func(tx *Transaction) error {
+			return client.SaveModel(ctx, &testSQLModel{ID: "ci-2", Name: "test@example.COM"}, tx, true, true)
+		})
+		require.Error(t, err)
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("invalid column", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		for _, column := range []string{"", "name) WHERE (1=1", "name; DROP TABLE test_sql_models"} {
+			require.ErrorIs(t, client.EnsureCaseInsensitiveUnique(ctx, &testSQLModel{}, column), ErrInvalidIndexColumn)
+		}
+	})
+}
@@ -2,6 +2,8 @@ package datastore
 
 import (
 	"context"
+	"database/sql"
+	"io"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -10,27 +12,81 @@ import (
 
 // StorageService is the storage related methods
 type StorageService interface {
+	AnalyzeTable(ctx context.Context, model interface{}) error
+	AnonymizeModels(ctx context.Context, model interface{}, conditions map[string]interface{},
+		fieldRules map[string]Anonymizer) (int64, error)
+	AppendToArrayField(ctx context.Context, model interface{}, field string, values ...string) error
 	AutoMigrateDatabase(ctx context.Context, models ...interface{}) error
-	CreateInBatches(ctx context.Context, models interface{}, batchSize int) error
+	BatchGetByKeys(ctx context.Context, models interface{}, keyColumn string, keys []string,
+		timeout time.Duration) (map[string]interface{}, error)
+	CaptureQueries(ctx context.Context, fn func(ctx context.Context) error) ([]CapturedQuery, error)
+	CreateInBatches(ctx context.Context, models interface{}, batchSize int, opts ...BatchOps) error
+	CreateMaskedView(ctx context.Context, model interface{}) error
 	CustomWhere(tx CustomWhereInterface, conditions map[string]interface{}, engine Engine) interface{}
+	DeleteBlob(ctx context.Context, name string) error
+	DecrementModel(ctx context.Context, model interface{},
+		fieldName string, decrement int64) (newValue int64, err error)
+	DeleteModel(ctx context.Context, model interface{}, tx *Transaction, commitTx bool) error
+	EnsureCaseInsensitiveUnique(ctx context.Context, model interface{}, column string) error
+	EnsureForeignKey(ctx context.Context, model interface{}, column string, reference interface{},
+		referenceColumn string, fkOptions *ForeignKeyOptions) error
 	Execute(query string) *gorm.DB
+	ExecuteContext(ctx context.Context, query string, args ...interface{}) *gorm.DB
+	ExecuteResult(ctx context.Context, query string, args ...interface{}) (int64, error)
+	FindOrCreateModel(ctx context.Context, model interface{}, conditions map[string]interface{},
+		tx *Transaction) (bool, error)
+	GenerateMigrationSQL(ctx context.Context, models ...interface{}) (string, error)
+	GetBlobReader(ctx context.Context, name string) (io.ReadCloser, error)
 	GetModel(ctx context.Context, model interface{}, conditions map[string]interface{},
 		timeout time.Duration, forceWriteDB bool) error
+	GetModelByID(ctx context.Context, model interface{}, id interface{}, timeout time.Duration) error
 	GetModels(ctx context.Context, models interface{}, conditions map[string]interface{}, queryParams *QueryParams,
 		fieldResults interface{}, timeout time.Duration) error
+	GetModelsPaged(ctx context.Context, models interface{}, conditions map[string]interface{}, queryParams *QueryParams,
+		timeout time.Duration) (*PagedResult, error)
+	GetModelsByCursor(ctx context.Context, models interface{}, conditions map[string]interface{},
+		cursorParams *CursorParams, timeout time.Duration) (Cursor, error)
 	GetModelCount(ctx context.Context, model interface{}, conditions map[string]interface{},
 		timeout time.Duration) (int64, error)
 	GetModelsAggregate(ctx context.Context, models interface{}, conditions map[string]interface{},
 		aggregateColumn string, timeout time.Duration) (map[string]interface{}, error)
+	GetModelsAggregateInto(ctx context.Context, models interface{}, conditions map[string]interface{},
+		aggregateColumn string, results interface{}, timeout time.Duration) error
+	GetTombstones(ctx context.Context, since time.Time, limit int) ([]*Tombstone, error)
 	HasMigratedModel(modelType string) bool
 	IncrementModel(ctx context.Context, model interface{},
 		fieldName string, increment int64) (newValue int64, err error)
+	IncrementModelFloat(ctx context.Context, model interface{},
+		fieldName string, increment float64) (newValue float64, err error)
 	IndexExists(tableName, indexName string) (bool, error)
 	IndexMetadata(tableName, field string) error
-	NewTx(ctx context.Context, fn func(*Transaction) error) error
-	NewRawTx() (*Transaction, error)
+	ListTables(ctx context.Context) ([]string, error)
+	ModelExists(ctx context.Context, model interface{}, conditions map[string]interface{},
+		timeout time.Duration) (bool, error)
+	NewCausalSession(ctx context.Context, fn func(ctx context.Context) error) error
+	NewTx(ctx context.Context, fn func(*Transaction) error, txOptions ...*TxOptions) error
+	NewTxWithRetry(ctx context.Context, fn func(*Transaction) error, policy RetryPolicy,
+		txOptions ...*TxOptions) error
+	NewRawTx(txOptions ...*TxOptions) (*Transaction, error)
+	NewSnapshotTx(ctx context.Context, fn func(ctx context.Context) error) error
+	OptimizeTable(ctx context.Context, model interface{}) error
+	PutBlob(ctx context.Context, name string, reader io.Reader) error
 	Raw(query string) *gorm.DB
+	RawContext(ctx context.Context, query string, args ...interface{}) *gorm.DB
+	RemoveFromArrayField(ctx context.Context, model interface{}, field string, values ...string) error
 	SaveModel(ctx context.Context, model interface{}, tx *Transaction, newRecord, commitTx bool) error
+	SaveModelAuto(ctx context.Context, model interface{}, newRecord bool) error
+	SaveModels(ctx context.Context, models []interface{}, tx *Transaction, newRecord bool) []error
+	ScanForInvalidRows(ctx context.Context, model interface{}, validators ...RowValidator) (*ScanReport, error)
+	SQLDB() (*sql.DB, string, error)
+	TableStats(ctx context.Context, model interface{}) (*TableStats, error)
+	TimeSeriesCount(ctx context.Context, model interface{}, conditions map[string]interface{}, dateField string,
+		granularity BucketGranularity, tz *time.Location) ([]Bucket, error)
+	UpdateModelFields(ctx context.Context, model interface{}, fields map[string]interface{}, tx *Transaction,
+		commitTx bool) error
+	UpdateModelMetadata(ctx context.Context, model interface{}, jsonField string, patch map[string]interface{},
+		mode JSONPatchMode) error
+	UpsertModel(ctx context.Context, model interface{}, conflictColumns []string, updateColumns []string) error
 }
 
 // GetterInterface is the getter methods
@@ -52,8 +108,22 @@ type ClientInterface interface {
 	Close(ctx context.Context) error
 	Debug(on bool)
 	DebugLog(ctx context.Context, text string)
+	EnforceRetention(ctx context.Context) (map[string]int64, error)
+	EffectiveConfig() *ClientConfig
 	Engine() Engine
+	FlushSearchSync(ctx context.Context) error
+	HealthCheck(ctx context.Context) *HealthReport
 	IsAutoMigrate() bool
 	IsDebug() bool
 	IsNewRelicEnabled() bool
+	NewQueryScope(ctx context.Context) context.Context
+	Ping(ctx context.Context) error
+	Reconfigure(opts ...ClientOps)
+	RegisterDerivedColumn(ctx context.Context, derived *DerivedColumn) error
+	RegisterImmutableModel(model interface{}, ttl time.Duration) error
+	RegisterModelDefaults(model interface{}, defaults Defaults) error
+	RegisterRetention(model interface{}, policy RetentionPolicy) error
+	StartRetention(ctx context.Context, interval time.Duration)
+	SubscribeModelEvents(model interface{}, event ModelEvent, handler ModelEventHandler) error
+	UpdateReplicas(ctx context.Context, configs []*SQLConfig) error
 }
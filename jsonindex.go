@@ -0,0 +1,133 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// jsonIndexChecks are the JSON conditions (table, field and key) already checked for a missing index
+type jsonIndexChecks struct {
+	checked sync.Map // Checked conditions (by table.field.key)
+}
+
+// jsonCondition is a condition on a custom JSON field (array or object) emitted by whereSlice or whereObject
+type jsonCondition struct {
+	field  string // JSON column
+	key    string // Object key (empty for arrays)
+	object bool   // Object (whereObject) vs array (whereSlice)
+}
+
+// suggestJSONIndexes will log (once) the index DDL for the JSON conditions on fields without an index
+//
+// Only in debug mode, PostgreSQL suggests a GIN index, MySQL a multi-valued index (arrays) and
+// SQLite an expression index (object keys)
+func (c *Client) suggestJSONIndexes(db *gorm.DB, conditions map[string]interface{}, engine Engine) {
+	if !c.IsDebug() || c.options.jsonIndexChecks == nil || db == nil || db.Statement.Model == nil ||
+		!IsSQLEngine(engine) {
+		return
+	}
+	jsonConditions := c.getJSONConditions(conditions)
+	if len(jsonConditions) == 0 {
+		return
+	}
+	tableName, err := c.getModelTableName(db.Statement.Model)
+	if err != nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, condition := range jsonConditions {
+		statement, pattern := getJSONIndexSuggestion(engine, tableName, condition)
+		if statement == "" {
+			continue
+		} else if _, checked := c.options.jsonIndexChecks.checked.LoadOrStore(statement, true); checked {
+			continue
+		}
+		if exists, existsErr := c.jsonIndexExists(ctx, engine, tableName, pattern); existsErr != nil || exists {
+			continue
+		}
+		c.DebugLog(ctx, fmt.Sprintf(
+			"no index found for the JSON condition on %s.%s, suggested index: %s", tableName, condition.field, statement,
+		))
+	}
+}
+
+// getJSONConditions will return the conditions on the custom JSON fields (including $and and $or)
+func (c *Client) getJSONConditions(conditions map[string]interface{}) (jsonConditions []jsonCondition) {
+	for key, condition := range conditions {
+		if key == conditionAnd || key == conditionOr {
+			if items, ok := condition.([]map[string]interface{}); ok {
+				for _, item := range items {
+					jsonConditions = append(jsonConditions, c.getJSONConditions(item)...)
+				}
+			}
+		} else if StringInSlice(key, c.GetArrayFields()) {
+			jsonConditions = append(jsonConditions, jsonCondition{field: key})
+		} else if StringInSlice(key, c.GetObjectFields()) {
+			raw, _ := json.Marshal(condition) //nolint:errchkjson // same handling as whereObject
+			var object map[string]interface{}
+			_ = json.Unmarshal(raw, &object)
+			objectKeys := make([]string, 0, len(object))
+			for objectKey := range object {
+				objectKeys = append(objectKeys, objectKey)
+			}
+			sort.Strings(objectKeys)
+			for _, objectKey := range objectKeys {
+				jsonConditions = append(jsonConditions, jsonCondition{field: key, key: objectKey, object: true})
+			}
+		}
+	}
+	return
+}
+
+// getJSONIndexSuggestion will return the index DDL matching the JSON condition and the LIKE pattern for
+// finding an existing index (empty if no index can be used by the condition)
+func getJSONIndexSuggestion(engine Engine, tableName string, condition jsonCondition) (statement, pattern string) {
+	indexName := "idx_" + tableName + "_" + condition.field
+	switch {
+	case engine == PostgreSQL:
+		return "CREATE INDEX " + indexName + " ON " + tableName + " USING gin ((" + condition.field +
+			"::jsonb) jsonb_path_ops)", "%" + condition.field + "%"
+	case engine == MySQL && !condition.object:
+		return "CREATE INDEX " + indexName + " ON " + tableName + " ((CAST(" + condition.field +
+			" AS CHAR(255) ARRAY)))", "%" + condition.field + "%"
+	case engine == SQLite && condition.object && indexNamePattern.MatchString(condition.key):
+		return "CREATE INDEX " + indexName + "_" + condition.key + " ON " + tableName + " (JSON_EXTRACT(" +
+			condition.field + ", '$." + condition.key + "'))", "%" + condition.field + "%$." + condition.key + "'%"
+	}
+	return "", ""
+}
+
+// jsonIndexExists will return true if an index on the table matches the pattern (column or expression)
+func (c *Client) jsonIndexExists(ctx context.Context, engine Engine, tableName, pattern string) (bool, error) {
+	var query string
+	switch engine {
+	case PostgreSQL:
+		query = "SELECT COUNT(*) FROM pg_indexes WHERE tablename = ? AND indexdef LIKE ?"
+	case MySQL:
+		query = "SELECT COUNT(*) FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = DATABASE() " +
+			"AND TABLE_NAME = ? AND (COLUMN_NAME LIKE ? OR EXPRESSION LIKE ?)"
+	case SQLite:
+		query = "SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql LIKE ?"
+	default:
+		return false, ErrUnknownSQL
+	}
+
+	args := []interface{}{tableName, pattern}
+	if engine == MySQL {
+		args = append(args, pattern)
+	}
+	var count int64
+	if err := c.options.db.WithContext(ctx).Raw(query, args...).Scan(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,102 @@
+package datastore
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	zLogger "github.com/mrz1836/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testInfoLogger is a logger that records all info messages
+type testInfoLogger struct {
+	zLogger.GormLoggerInterface
+	messages []string
+	mu       sync.Mutex
+}
+
+// Info will record the message
+func (l *testInfoLogger) Info(_ context.Context, message string, _ ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, message)
+}
+
+// SetMode will return the same logger (keeps the recorded messages)
+func (l *testInfoLogger) SetMode(_ zLogger.GormLogLevel) zLogger.GormLoggerInterface {
+	return l
+}
+
+// suggestions will return the recorded index suggestions
+func (l *testInfoLogger) suggestions() (suggestions []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, message := range l.messages {
+		if strings.Contains(message, "suggested index") {
+			suggestions = append(suggestions, message)
+		}
+	}
+	return
+}
+
+// testJSONModel is a model with a JSON (metadata) column
+type testJSONModel struct {
+	ID       string `gorm:"primaryKey"`
+	Metadata string `gorm:"column:metadata"`
+}
+
+// TestClient_suggestJSONIndexes will test the method suggestJSONIndexes()
+func TestClient_suggestJSONIndexes(t *testing.T) {
+	t.Run("suggested once for a field without an index", func(t *testing.T) {
+		ctx := context.Background()
+		l := &testInfoLogger{GormLoggerInterface: zLogger.NewGormLogger(false, 4)}
+		client, deferFunc := testSQLiteClient(ctx, t, WithLogger(l), WithDebugging(), WithAutoMigrate(&testJSONModel{}))
+		defer deferFunc()
+		require.NoError(t, client.ExecuteContext(ctx,
+			"CREATE INDEX idx_test_json_models_metadata_size ON test_json_models (JSON_EXTRACT(metadata, '$.size'))").Error)
+
+		for i := 0; i < 2; i++ {
+			var models []*testJSONModel
+			err := client.GetModels(ctx, &models, map[string]interface{}{
+				metadataField: map[string]interface{}{"color": "red", "size": "large"},
+			}, nil, nil, defaultDatabaseMaxTimeout)
+			require.ErrorIs(t, err, ErrNoResults)
+		}
+
+		suggestions := l.suggestions()
+		require.Len(t, suggestions, 1)
+		assert.Contains(t, suggestions[0],
+			"CREATE INDEX idx_test_json_models_metadata_color ON test_json_models (JSON_EXTRACT(metadata, '$.color'))")
+	})
+
+	t.Run("not in debug mode", func(t *testing.T) {
+		ctx := context.Background()
+		l := &testInfoLogger{GormLoggerInterface: zLogger.NewGormLogger(false, 4)}
+		client, deferFunc := testSQLiteClient(ctx, t, WithLogger(l), WithAutoMigrate(&testJSONModel{}))
+		defer deferFunc()
+
+		var models []*testJSONModel
+		_ = client.GetModels(ctx, &models, map[string]interface{}{
+			metadataField: map[string]interface{}{"color": "red"},
+		}, nil, nil, defaultDatabaseMaxTimeout)
+		assert.Empty(t, l.suggestions())
+	})
+}
+
+// TestGetJSONIndexSuggestion will test the method getJSONIndexSuggestion()
+func TestGetJSONIndexSuggestion(t *testing.T) {
+	statement, _ := getJSONIndexSuggestion(PostgreSQL, "xpubs", jsonCondition{field: "metadata", key: "a", object: true})
+	assert.Equal(t, "CREATE INDEX idx_xpubs_metadata ON xpubs USING gin ((metadata::jsonb) jsonb_path_ops)", statement)
+
+	statement, _ = getJSONIndexSuggestion(MySQL, "xpubs", jsonCondition{field: "tags"})
+	assert.Equal(t, "CREATE INDEX idx_xpubs_tags ON xpubs ((CAST(tags AS CHAR(255) ARRAY)))", statement)
+
+	statement, _ = getJSONIndexSuggestion(MySQL, "xpubs", jsonCondition{field: "metadata", key: "a", object: true})
+	assert.Empty(t, statement)
+
+	statement, _ = getJSONIndexSuggestion(SQLite, "xpubs", jsonCondition{field: "tags"})
+	assert.Empty(t, statement)
+}
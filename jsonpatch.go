@@ -0,0 +1,202 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-datastore/nrgorm"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/gorm"
+)
+
+// JSONPatchMode is how UpdateModelMetadata applies the patch to the JSON field
+type JSONPatchMode string
+
+// JSON patch modes
+const (
+	JSONPatchDeleteKeys JSONPatchMode = "delete_keys" // Remove the patch keys (the values are ignored)
+	JSONPatchMerge      JSONPatchMode = "merge"       // Set the patch keys (the other keys are kept)
+	JSONPatchReplace    JSONPatchMode = "replace"     // Replace the whole document with the patch
+)
+
+// ErrInvalidJSONPatch is when the JSON field, a patch key or the patch mode is invalid
+var ErrInvalidJSONPatch = errors.New("invalid json patch")
+
+// UpdateModelMetadata will patch the JSON field of the model (primary key based) in a single statement
+//
+// SQL: JSON_SET / JSON_REMOVE (MySQL and SQLite), jsonb || / jsonb - (PostgreSQL), MongoDB: $set / $unset paths
+// Only the top level keys are patched, the model itself is not modified (reload it to see the changes)
+func (c *Client) UpdateModelMetadata(
+	ctx context.Context,
+	model interface{},
+	jsonField string,
+	patch map[string]interface{},
+	mode JSONPatchMode,
+) (err error) {
+	if !indexNamePattern.MatchString(jsonField) ||
+		(mode != JSONPatchDeleteKeys && mode != JSONPatchMerge && mode != JSONPatchReplace) ||
+		(len(patch) == 0 && mode != JSONPatchReplace) {
+		return ErrInvalidJSONPatch
+	}
+	keys := make([]string, 0, len(patch))
+	for key := range patch {
+		if !indexNamePattern.MatchString(key) {
+			return ErrInvalidJSONPatch
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Run the update event (and invalidate the cached reads) after the patch (see: SubscribeModelEvents)
+	defer func() {
+		if err == nil {
+			c.emitModelUpdate(ctx, model)
+		}
+	}()
+
+	if c.Engine() == MongoDB {
+		start := time.Now()
+		return newMongoQueryError("update", model, nil, start,
+			c.patchJSONWithMongo(ctx, model, jsonField, keys, patch, mode))
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	expression, err := getJSONPatchExpression(c.Engine(), jsonField, keys, patch, mode)
+	if err != nil {
+		return err
+	}
+	primaryKey, err := c.getModelPrimaryKey(model)
+	if err != nil {
+		return err
+	}
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return err
+	}
+
+	// Set the NewRelic txn
+	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+	return c.options.db.WithContext(ctx).Table(tableName).Where(primaryKey).
+		UpdateColumn(jsonField, expression).Error
+}
+
+// getJSONPatchExpression will return the SQL expression that patches the JSON field
+func getJSONPatchExpression(engine Engine, jsonField string, keys []string, patch map[string]interface{},
+	mode JSONPatchMode,
+) (interface{}, error) {
+
+	// Replace the whole document
+	if mode == JSONPatchReplace {
+		if patch == nil {
+			patch = map[string]interface{}{}
+		}
+		raw, err := json.Marshal(patch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidJSONPatch, err)
+		}
+		return string(raw), nil
+	}
+
+	// PostgreSQL uses the jsonb operators
+	if engine == PostgreSQL {
+		if mode == JSONPatchDeleteKeys {
+			args := make([]interface{}, 0, len(keys))
+			for _, key := range keys {
+				args = append(args, key)
+			}
+			return gorm.Expr("COALESCE("+jsonField+"::jsonb, '{}'::jsonb)"+
+				strings.Repeat(" - ?::text", len(keys)), args...), nil
+		}
+		raw, err := json.Marshal(patch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidJSONPatch, err)
+		}
+		return gorm.Expr("COALESCE("+jsonField+"::jsonb, '{}'::jsonb) || ?::jsonb", string(raw)), nil
+	}
+
+	// MySQL and SQLite use the JSON functions (paths are $.key)
+	if mode == JSONPatchDeleteKeys {
+		paths := make([]string, 0, len(keys))
+		for _, key := range keys {
+			paths = append(paths, "'$."+key+"'")
+		}
+		return gorm.Expr("JSON_REMOVE(COALESCE(" + jsonField + ", '{}'), " + strings.Join(paths, ", ") + ")"), nil
+	}
+	castValue := "JSON(?)"
+	if engine == MySQL {
+		castValue = "CAST(? AS JSON)"
+	}
+	sets := make([]string, 0, len(keys))
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		raw, err := json.Marshal(patch[key])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidJSONPatch, err)
+		}
+		sets = append(sets, "'$."+key+"', "+castValue)
+		args = append(args, string(raw))
+	}
+	return gorm.Expr("JSON_SET(COALESCE("+jsonField+", '{}'), "+strings.Join(sets, ", ")+")", args...), nil
+}
+
+// patchJSONWithMongo will patch the JSON (object) field using $set / $unset on the dotted paths
+func (c *Client) patchJSONWithMongo(
+	ctx context.Context,
+	model interface{},
+	jsonField string,
+	keys []string,
+	patch map[string]interface{},
+	mode JSONPatchMode,
+) (err error) {
+	collectionName := GetModelTableName(model)
+	if collectionName == nil {
+		return ErrUnknownCollection
+	}
+
+	// Set the collection
+	collection := c.getMongoWriteCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	)
+
+	var primaryKey map[string]interface{}
+	if primaryKey, err = c.getModelPrimaryKey(model); err != nil {
+		return err
+	}
+
+	var update bson.M
+	switch mode {
+	case JSONPatchReplace:
+		if patch == nil {
+			patch = map[string]interface{}{}
+		}
+		update = bson.M{conditionSet: bson.M{jsonField: patch}}
+	case JSONPatchMerge:
+		fields := bson.M{}
+		for _, key := range keys {
+			fields[jsonField+"."+key] = patch[key]
+		}
+		update = bson.M{conditionSet: fields}
+	case JSONPatchDeleteKeys:
+		fields := bson.M{}
+		for _, key := range keys {
+			fields[jsonField+"."+key] = ""
+		}
+		update = bson.M{conditionUnSet: fields}
+	}
+
+	c.DebugLog(ctx, fmt.Sprintf(logLine, "update", *collectionName, update))
+
+	if _, err = collection.UpdateOne(ctx, primaryKey, update); err != nil {
+		c.DebugLog(ctx, fmt.Sprintf(logErrorLine, "error", *collectionName, err, update))
+	}
+
+	return
+}
@@ -0,0 +1,127 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/clause"
+)
+
+// TestClient_UpdateModelMetadata will test the method UpdateModelMetadata()
+func TestClient_UpdateModelMetadata(t *testing.T) {
+	t.Run("invalid patch", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite}}
+		ctx := context.Background()
+		model := &testJSONModel{ID: "patch"}
+		require.ErrorIs(t, client.UpdateModelMetadata(ctx, model, "metadata;", map[string]interface{}{"a": 1},
+			JSONPatchMerge), ErrInvalidJSONPatch)
+		require.ErrorIs(t, client.UpdateModelMetadata(ctx, model, "metadata", map[string]interface{}{"a.b": 1},
+			JSONPatchMerge), ErrInvalidJSONPatch)
+		require.ErrorIs(t, client.UpdateModelMetadata(ctx, model, "metadata", map[string]interface{}{"a": 1},
+			"append"), ErrInvalidJSONPatch)
+		require.ErrorIs(t, client.UpdateModelMetadata(ctx, model, "metadata", nil, JSONPatchMerge),
+			ErrInvalidJSONPatch)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		require.ErrorIs(t, client.UpdateModelMetadata(context.Background(), &testJSONModel{ID: "patch"}, "metadata",
+			map[string]interface{}{"a": 1}, JSONPatchMerge), ErrUnsupportedEngine)
+	})
+
+	t.Run("[sqlite] merge, delete keys and replace", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithAutoMigrate(&testJSONModel{}))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testJSONModel{ID: "patch-1", Metadata: `{"keep":"yes","remove":1}`})
+
+		getMetadata := func() map[string]interface{} {
+			model := &testJSONModel{}
+			require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "patch-1"},
+				defaultDatabaseMaxTimeout, false))
+			var metadata map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(model.Metadata), &metadata))
+			return metadata
+		}
+
+		model := &testJSONModel{ID: "patch-1"}
+		require.NoError(t, client.UpdateModelMetadata(ctx, model, "metadata", map[string]interface{}{
+			"added": map[string]interface{}{"nested": true}, "keep": "updated", "count": 2,
+		}, JSONPatchMerge))
+		assert.Equal(t, map[string]interface{}{
+			"added": map[string]interface{}{"nested": true}, "count": float64(2), "keep": "updated", "remove": float64(1),
+		}, getMetadata())
+
+		require.NoError(t, client.UpdateModelMetadata(ctx, model, "metadata", map[string]interface{}{
+			"remove": nil, "added": nil, "missing": nil,
+		}, JSONPatchDeleteKeys))
+		assert.Equal(t, map[string]interface{}{"count": float64(2), "keep": "updated"}, getMetadata())
+
+		require.NoError(t, client.UpdateModelMetadata(ctx, model, "metadata", map[string]interface{}{
+			"only": "this",
+		}, JSONPatchReplace))
+		assert.Equal(t, map[string]interface{}{"only": "this"}, getMetadata())
+
+		require.NoError(t, client.UpdateModelMetadata(ctx, model, "metadata", nil, JSONPatchReplace))
+		assert.Empty(t, getMetadata())
+	})
+
+	t.Run("[sqlite] null field", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithAutoMigrate(&testJSONModel{}))
+		defer deferFunc()
+		require.NoError(t, client.ExecuteContext(ctx, "INSERT INTO test_json_models (id) VALUES (?)", "patch-2").Error)
+
+		require.NoError(t, client.UpdateModelMetadata(ctx, &testJSONModel{ID: "patch-2"}, "metadata",
+			map[string]interface{}{"key": "value"}, JSONPatchMerge))
+		model := &testJSONModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "patch-2"},
+			defaultDatabaseMaxTimeout, false))
+		assert.JSONEq(t, `{"key":"value"}`, model.Metadata)
+	})
+}
+
+// TestGetJSONPatchExpression will test the method getJSONPatchExpression()
+func TestGetJSONPatchExpression(t *testing.T) {
+	patch := map[string]interface{}{"b": "two", "a": 1}
+	keys := []string{"a", "b"}
+
+	t.Run("postgresql", func(t *testing.T) {
+		expression, err := getJSONPatchExpression(PostgreSQL, "metadata", keys, patch, JSONPatchMerge)
+		require.NoError(t, err)
+		assert.Equal(t, "COALESCE(metadata::jsonb, '{}'::jsonb) || ?::jsonb", expression.(clause.Expr).SQL)
+		assert.Equal(t, []interface{}{`{"a":1,"b":"two"}`}, expression.(clause.Expr).Vars)
+
+		expression, err = getJSONPatchExpression(PostgreSQL, "metadata", keys, patch, JSONPatchDeleteKeys)
+		require.NoError(t, err)
+		assert.Equal(t, "COALESCE(metadata::jsonb, '{}'::jsonb) - ?::text - ?::text", expression.(clause.Expr).SQL)
+		assert.Equal(t, []interface{}{"a", "b"}, expression.(clause.Expr).Vars)
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		expression, err := getJSONPatchExpression(MySQL, "metadata", keys, patch, JSONPatchMerge)
+		require.NoError(t, err)
+		assert.Equal(t, "JSON_SET(COALESCE(metadata, '{}'), '$.a', CAST(? AS JSON), '$.b', CAST(? AS JSON))",
+			expression.(clause.Expr).SQL)
+		assert.Equal(t, []interface{}{"1", `"two"`}, expression.(clause.Expr).Vars)
+
+		expression, err = getJSONPatchExpression(MySQL, "metadata", keys, patch, JSONPatchDeleteKeys)
+		require.NoError(t, err)
+		assert.Equal(t, "JSON_REMOVE(COALESCE(metadata, '{}'), '$.a', '$.b')", expression.(clause.Expr).SQL)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		expression, err := getJSONPatchExpression(MySQL, "metadata", keys, patch, JSONPatchReplace)
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1,"b":"two"}`, expression)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := getJSONPatchExpression(SQLite, "metadata", []string{"a"},
+			map[string]interface{}{"a": make(chan int)}, JSONPatchMerge)
+		require.ErrorIs(t, err, ErrInvalidJSONPatch)
+	})
+}
@@ -0,0 +1,72 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+// ErrInvalidLockTable is when a table in RowLock.Of is not a valid table name
+var ErrInvalidLockTable = errors.New("invalid row lock table name")
+
+// LockMode is the strength of the row lock
+type LockMode string
+
+// Lock modes
+const (
+	LockForShare  LockMode = "SHARE"  // FOR SHARE (MySQL 8+, PostgreSQL)
+	LockForUpdate LockMode = "UPDATE" // FOR UPDATE
+)
+
+// RowLock is the row locking for a select (SELECT ... FOR UPDATE [OF table] [NOWAIT | SKIP LOCKED])
+type RowLock struct {
+	Mode       LockMode // Lock strength (default is LockForUpdate)
+	NoWait     bool     // Fail instead of waiting for locked rows
+	Of         string   // Only lock the rows of this table (joined queries, keeps the lock scope minimal)
+	SkipLocked bool     // Skip the locked rows instead of waiting
+}
+
+// rowLockKey is the context key for the row lock
+type rowLockKey struct{}
+
+// WithRowLock will lock the rows selected by GetModel or GetModels (MySQL and PostgreSQL)
+//
+// Locked reads always use the source (write) database, SQLite and MongoDB ignore the lock
+func WithRowLock(ctx context.Context, lock RowLock) context.Context {
+	return context.WithValue(ctx, rowLockKey{}, &lock)
+}
+
+// getRowLock will return the row lock (if found)
+func getRowLock(ctx context.Context) *RowLock {
+	lock, _ := ctx.Value(rowLockKey{}).(*RowLock)
+	return lock
+}
+
+// applyRowLock will add the locking clause to the query (if set using WithRowLock)
+func (c *Client) applyRowLock(ctx context.Context, tx *gorm.DB) (*gorm.DB, error) {
+	lock := getRowLock(ctx)
+	if lock == nil || (c.Engine() != MySQL && c.Engine() != PostgreSQL) {
+		return tx, nil
+	}
+
+	locking := clause.Locking{Strength: string(LockForUpdate)}
+	if lock.Mode != "" {
+		locking.Strength = string(lock.Mode)
+	}
+	if len(lock.Of) > 0 {
+		if !indexNamePattern.MatchString(lock.Of) {
+			return nil, ErrInvalidLockTable
+		}
+		locking.Table = clause.Table{Name: lock.Of}
+	}
+	if lock.NoWait {
+		locking.Options = "NOWAIT"
+	} else if lock.SkipLocked {
+		locking.Options = "SKIP LOCKED"
+	}
+
+	return tx.Clauses(dbresolver.Write, locking), nil
+}
@@ -0,0 +1,76 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestClient_applyRowLock will test the method applyRowLock()
+func TestClient_applyRowLock(t *testing.T) {
+	// testLockQuery will return the SQL of a locked select (PostgreSQL, no connection)
+	testLockQuery := func(t *testing.T, lock *RowLock) (string, error) {
+		db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+			DisableAutomaticPing: true,
+			DryRun:               true,
+		})
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		if lock != nil {
+			ctx = WithRowLock(ctx, *lock)
+		}
+		client := &Client{options: &clientOptions{db: db, engine: PostgreSQL}}
+		var lockErr error
+		query := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			if tx, lockErr = client.applyRowLock(ctx, tx); lockErr != nil {
+				return db
+			}
+			return tx.Find(&[]*testSQLModel{})
+		})
+		return query, lockErr
+	}
+
+	t.Run("no lock", func(t *testing.T) {
+		query, err := testLockQuery(t, nil)
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM "test_sql_models"`, query)
+	})
+
+	t.Run("for update of table", func(t *testing.T) {
+		query, err := testLockQuery(t, &RowLock{Of: testSQLTableName, SkipLocked: true})
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM "test_sql_models" FOR UPDATE OF "test_sql_models" SKIP LOCKED`, query)
+	})
+
+	t.Run("for share nowait", func(t *testing.T) {
+		query, err := testLockQuery(t, &RowLock{Mode: LockForShare, NoWait: true})
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM "test_sql_models" FOR SHARE NOWAIT`, query)
+	})
+
+	t.Run("invalid table", func(t *testing.T) {
+		_, err := testLockQuery(t, &RowLock{Of: "users; DROP TABLE users"})
+		require.ErrorIs(t, err, ErrInvalidLockTable)
+	})
+
+	t.Run("sqlite ignores the lock", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "lock-1", Name: "alice"})
+
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(WithRowLock(ctx, RowLock{Of: testSQLTableName}), &models, nil, nil, nil, defaultDatabaseMaxTimeout))
+		require.Len(t, models, 1)
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(WithRowLock(ctx, RowLock{}), model,
+			map[string]interface{}{sqlIDField: "lock-1"}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "alice", model.Name)
+	})
+}
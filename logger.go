@@ -1,18 +1,90 @@
 package datastore
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	zLogger "github.com/mrz1836/go-logger"
 	gLogger "gorm.io/gorm/logger"
 )
 
 // DatabaseLogWrapper is a special wrapper for the GORM logger
+//
+// The logger and slow query threshold can be replaced at runtime (see: Client.Reconfigure)
 type DatabaseLogWrapper struct {
 	zLogger.GormLoggerInterface
+	diagnostics        *deadlockDiagnostics // Captures engine diagnostics on deadlocks (see: WithDeadlockDiagnostics)
+	mu                 sync.RWMutex         // Lock for the logger and slow query threshold
+	slowQueryThreshold time.Duration        // Custom slow query threshold (zero uses the logger's default)
+}
+
+// getLogger will return the logger and the slow query threshold
+func (d *DatabaseLogWrapper) getLogger() (zLogger.GormLoggerInterface, time.Duration) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.GormLoggerInterface, d.slowQueryThreshold
+}
+
+// setLogger will replace the logger and the slow query threshold (shared by all existing connections)
+func (d *DatabaseLogWrapper) setLogger(logger zLogger.GormLoggerInterface, slowQueryThreshold time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.GormLoggerInterface = logger
+	d.slowQueryThreshold = slowQueryThreshold
+}
+
+// Error will log the error message
+func (d *DatabaseLogWrapper) Error(ctx context.Context, s string, v ...interface{}) {
+	logger, _ := d.getLogger()
+	logger.Error(ctx, s, v...)
+}
+
+// Info will log the info message
+func (d *DatabaseLogWrapper) Info(ctx context.Context, s string, v ...interface{}) {
+	logger, _ := d.getLogger()
+	logger.Info(ctx, s, v...)
+}
+
+// Warn will log the warning message
+func (d *DatabaseLogWrapper) Warn(ctx context.Context, s string, v ...interface{}) {
+	logger, _ := d.getLogger()
+	logger.Warn(ctx, s, v...)
+}
+
+// GetMode will return the log mode
+func (d *DatabaseLogWrapper) GetMode() zLogger.GormLogLevel {
+	logger, _ := d.getLogger()
+	return logger.GetMode()
+}
+
+// SetMode will set the log mode
+func (d *DatabaseLogWrapper) SetMode(level zLogger.GormLogLevel) zLogger.GormLoggerInterface {
+	logger, _ := d.getLogger()
+	return logger.SetMode(level)
+}
+
+// GetStackLevel will return the stack level
+func (d *DatabaseLogWrapper) GetStackLevel() int {
+	logger, _ := d.getLogger()
+	return logger.GetStackLevel()
+}
+
+// SetStackLevel will set the stack level
+func (d *DatabaseLogWrapper) SetStackLevel(level int) {
+	logger, _ := d.getLogger()
+	logger.SetStackLevel(level)
 }
 
 // LogMode will set the log level/mode
 func (d *DatabaseLogWrapper) LogMode(level gLogger.LogLevel) gLogger.Interface {
-	newLogger := *d
+	logger, slowQueryThreshold := d.getLogger()
+	newLogger := &DatabaseLogWrapper{
+		GormLoggerInterface: logger,
+		diagnostics:         d.diagnostics,
+		slowQueryThreshold:  slowQueryThreshold,
+	}
 	if level == gLogger.Info {
 		newLogger.SetMode(zLogger.Info)
 	} else if level == gLogger.Warn {
@@ -23,5 +95,50 @@ func (d *DatabaseLogWrapper) LogMode(level gLogger.LogLevel) gLogger.Interface {
 		newLogger.SetMode(zLogger.Silent)
 	}
 
-	return &newLogger
+	return newLogger
+}
+
+// Trace will log the SQL query, flagging it as slow if it exceeds the custom slow query threshold
+func (d *DatabaseLogWrapper) Trace(ctx context.Context, begin time.Time,
+	fc func() (sql string, rowsAffected int64), err error) {
+
+	// Record the query if capturing (see: CaptureQueries)
+	if capture := getQueryCapture(ctx); capture != nil {
+		sql, rows := fc()
+		capture.add(ctx, CapturedQuery{
+			Duration: time.Since(begin),
+			Error:    err,
+			Query:    sql,
+			Rows:     rows,
+		})
+	}
+
+	// Capture the diagnostics on a deadlock
+	if err != nil {
+		d.diagnostics.check(err)
+	}
+
+	// No custom threshold, or the query failed (errors are handled by the logger)
+	logger, slowQueryThreshold := d.getLogger()
+	if slowQueryThreshold <= 0 || err != nil {
+		logger.Trace(ctx, begin, fc, err)
+		return
+	}
+
+	// Slow query (using the custom threshold)
+	if elapsed := time.Since(begin); elapsed > slowQueryThreshold {
+		if logger.GetMode() >= zLogger.Warn {
+			sql, rows := fc()
+			logger.Warn(ctx, fmt.Sprintf(
+				"SLOW SQL >= %v, duration: %.3fms, rows: %d, sql: %s",
+				slowQueryThreshold, float64(elapsed.Nanoseconds())/1e6, rows, sql,
+			))
+		}
+		return
+	}
+
+	// Default tracing (info)
+	if logger.GetMode() == zLogger.Info {
+		logger.Trace(ctx, begin, fc, err)
+	}
 }
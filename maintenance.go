@@ -0,0 +1,91 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrOutsideMaintenanceWindow is when a maintenance operation is run outside the maintenance window
+var ErrOutsideMaintenanceWindow = errors.New("outside of the maintenance window")
+
+// MaintenanceWindow is the daily window (UTC) for maintenance operations (IE: 2h to 4h)
+//
+// The window can wrap around midnight (IE: 23h to 1h)
+type MaintenanceWindow struct {
+	End   time.Duration // Time of day the window ends (UTC)
+	Start time.Duration // Time of day the window starts (UTC)
+}
+
+// Contains will return true if the time is inside the maintenance window
+func (w *MaintenanceWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	timeOfDay := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+	if w.Start <= w.End {
+		return timeOfDay >= w.Start && timeOfDay < w.End
+	}
+	return timeOfDay >= w.Start || timeOfDay < w.End
+}
+
+// OptimizeTable will reclaim space and refresh the planner statistics for the model's table
+//
+// PostgreSQL: VACUUM (ANALYZE), MySQL: OPTIMIZE TABLE, SQLite: VACUUM (entire database), MongoDB: compact
+// CockroachDB: ANALYZE (space is reclaimed by the garbage collection)
+// Returns ErrOutsideMaintenanceWindow if a window is set and the current time is outside it (see: WithMaintenanceWindow)
+func (c *Client) OptimizeTable(ctx context.Context, model interface{}) error {
+	if window := c.options.maintenanceWindow; window != nil && !window.Contains(time.Now()) {
+		return ErrOutsideMaintenanceWindow
+	}
+
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return err
+	}
+
+	if c.Engine() == MongoDB {
+		return c.options.mongoDB.RunCommand(ctx, bson.D{{Key: "compact", Value: tableName}}).Err()
+	} else if c.isCockroachDB() {
+		return c.options.db.WithContext(ctx).Exec("ANALYZE " + tableName).Error
+	} else if c.Engine() == PostgreSQL {
+		return c.options.db.WithContext(ctx).Exec("VACUUM (ANALYZE) " + tableName).Error
+	} else if c.Engine() == MySQL {
+		return c.options.db.WithContext(ctx).Exec("OPTIMIZE TABLE " + tableName).Error
+	} else if c.Engine() == SQLite {
+		return c.options.db.WithContext(ctx).Exec("VACUUM").Error
+	}
+	return ErrUnsupportedEngine
+}
+
+// AnalyzeTable will refresh the planner statistics for the model's table
+//
+// PostgreSQL & SQLite: ANALYZE, MySQL: ANALYZE TABLE, MongoDB: planCacheClear (queries are re-planned)
+func (c *Client) AnalyzeTable(ctx context.Context, model interface{}) error {
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return err
+	}
+
+	if c.Engine() == MongoDB {
+		return c.options.mongoDB.RunCommand(ctx, bson.D{{Key: "planCacheClear", Value: tableName}}).Err()
+	} else if c.Engine() == PostgreSQL || c.Engine() == SQLite {
+		return c.options.db.WithContext(ctx).Exec("ANALYZE " + tableName).Error
+	} else if c.Engine() == MySQL {
+		return c.options.db.WithContext(ctx).Exec("ANALYZE TABLE " + tableName).Error
+	}
+	return ErrUnsupportedEngine
+}
+
+// analyzeAfterBulkLoad will refresh the planner statistics if the bulk operation inserted enough rows
+//
+// The bulk operation already succeeded, so a failure is only logged (see: WithAnalyzeAfterBulkLoad)
+func (c *Client) analyzeAfterBulkLoad(ctx context.Context, models interface{}, rows int64) {
+	if c.options.analyzeAfterRows <= 0 || rows < int64(c.options.analyzeAfterRows) {
+		return
+	}
+	if err := c.AnalyzeTable(ctx, models); err != nil {
+		c.warnLog(ctx, fmt.Sprintf("failed to analyze after a bulk load of %d rows: %s", rows, err.Error()))
+	}
+}
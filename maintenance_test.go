@@ -0,0 +1,100 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMaintenanceWindow_Contains will test the method Contains()
+func TestMaintenanceWindow_Contains(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("same day window", func(t *testing.T) {
+		window := &MaintenanceWindow{Start: 2 * time.Hour, End: 4 * time.Hour}
+		assert.True(t, window.Contains(day.Add(2*time.Hour)))
+		assert.True(t, window.Contains(day.Add(3*time.Hour+59*time.Minute)))
+		assert.False(t, window.Contains(day.Add(4*time.Hour)))
+		assert.False(t, window.Contains(day.Add(time.Hour)))
+	})
+
+	t.Run("window wraps around midnight", func(t *testing.T) {
+		window := &MaintenanceWindow{Start: 23 * time.Hour, End: time.Hour}
+		assert.True(t, window.Contains(day.Add(23*time.Hour+30*time.Minute)))
+		assert.True(t, window.Contains(day.Add(30*time.Minute)))
+		assert.False(t, window.Contains(day.Add(12*time.Hour)))
+	})
+
+	t.Run("time zones are converted to UTC", func(t *testing.T) {
+		window := &MaintenanceWindow{Start: 2 * time.Hour, End: 4 * time.Hour}
+		assert.True(t, window.Contains(day.Add(3*time.Hour).In(time.FixedZone("EST", -5*60*60))))
+	})
+}
+
+// TestClient_OptimizeTable will test the method OptimizeTable()
+func TestClient_OptimizeTable(t *testing.T) {
+	t.Run("sqlite vacuum", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		require.NoError(t, client.OptimizeTable(ctx, &testSQLModel{}))
+	})
+
+	t.Run("outside the maintenance window", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Now().UTC()
+		start := time.Duration(now.Hour()+1) % 24 * time.Hour
+		client, deferFunc := testSQLiteClient(ctx, t, WithMaintenanceWindow(start, start+time.Hour))
+		defer deferFunc()
+		require.ErrorIs(t, client.OptimizeTable(ctx, &testSQLModel{}), ErrOutsideMaintenanceWindow)
+	})
+}
+
+// TestClient_AnalyzeTable will test the method AnalyzeTable() and analyzing after a bulk load
+func TestClient_AnalyzeTable(t *testing.T) {
+	records := []*testSQLModel{
+		{ID: "analyze-1", Name: "alice"},
+		{ID: "analyze-2", Name: "bob"},
+		{ID: "analyze-3", Name: "carol"},
+	}
+
+	// testStatRows will return the number of planner statistic rows for the test table (SQLite)
+	testStatRows := func(t *testing.T, client ClientInterface) int64 {
+		var count int64
+		if err := client.Raw("SELECT COUNT(*) FROM sqlite_stat1 WHERE tbl = '" + testSQLTableName + "'").Scan(&count).Error; err != nil {
+			return 0 // Table is created on the first ANALYZE
+		}
+		return count
+	}
+
+	t.Run("analyze table", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		require.NoError(t, client.AnalyzeTable(ctx, &testSQLModel{}))
+		assert.Positive(t, testStatRows(t, client))
+	})
+
+	t.Run("bulk load below the threshold", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithAnalyzeAfterBulkLoad(10))
+		defer deferFunc()
+
+		require.NoError(t, client.CreateInBatches(ctx, records, 2))
+		assert.Zero(t, testStatRows(t, client))
+	})
+
+	t.Run("bulk load above the threshold", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithAnalyzeAfterBulkLoad(3))
+		defer deferFunc()
+
+		require.NoError(t, client.CreateInBatches(ctx, records, 2))
+		assert.Positive(t, testStatRows(t, client))
+	})
+}
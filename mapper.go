@@ -0,0 +1,144 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidMapperDestination is when the result mapper destination is not a pointer
+var ErrInvalidMapperDestination = errors.New("result mapper destination must be a non-nil pointer")
+
+// ResultMapper will map the results (src) into the destination (dest) (IE: protobuf messages or API DTOs)
+type ResultMapper func(src, dest interface{}) error
+
+// mapIntoKey is the context key for the result mapper destination
+type mapIntoKey struct{}
+
+// MapInto will set the destination for the results of GetModel or GetModels
+//
+// The results are mapped into dest using the client's ResultMapper (see: WithResultMapper)
+func MapInto(ctx context.Context, dest interface{}) context.Context {
+	return context.WithValue(ctx, mapIntoKey{}, dest)
+}
+
+// mapResults will map the results into the destination set using MapInto() (if found)
+func (c *Client) mapResults(ctx context.Context, results interface{}) error {
+	dest := ctx.Value(mapIntoKey{})
+	if dest == nil || results == nil {
+		return nil
+	}
+	mapper := c.options.resultMapper
+	if mapper == nil {
+		mapper = DefaultResultMapper
+	}
+	return mapper(results, dest)
+}
+
+// DefaultResultMapper is a reflection-based mapper that matches the fields by their json tag (or field name)
+//
+// Supports structs, slices and maps of structs, pointers are allocated as needed
+// Fields without a match (or with an incompatible type) are skipped
+func DefaultResultMapper(src, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		return ErrInvalidMapperDestination
+	}
+	mapValue(reflect.ValueOf(src), destValue.Elem())
+	return nil
+}
+
+// mapValue will map the source value into the destination value
+func mapValue(src, dest reflect.Value) {
+	for src.Kind() == reflect.Ptr || src.Kind() == reflect.Interface {
+		if src.IsNil() {
+			return
+		}
+		src = src.Elem()
+	}
+	if !src.IsValid() || !dest.CanSet() {
+		return
+	}
+
+	// Assignable types are copied as-is
+	if src.Type().AssignableTo(dest.Type()) {
+		dest.Set(src)
+		return
+	}
+
+	switch dest.Kind() { //nolint:exhaustive // all other kinds are converted (if possible)
+	case reflect.Ptr:
+		if dest.IsNil() {
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		mapValue(src, dest.Elem())
+	case reflect.Struct:
+		if src.Kind() == reflect.Struct {
+			mapStruct(src, dest)
+		} else if src.Kind() == reflect.Map && src.Type().Key().Kind() == reflect.String {
+			mapMapToStruct(src, dest)
+		}
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			return
+		}
+		slice := reflect.MakeSlice(dest.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			mapValue(src.Index(i), slice.Index(i))
+		}
+		dest.Set(slice)
+	default:
+		// Numbers are not converted to strings (runes)
+		if src.Type().ConvertibleTo(dest.Type()) && src.Kind() != reflect.Struct &&
+			(dest.Kind() != reflect.String || src.Kind() == reflect.String) {
+			dest.Set(src.Convert(dest.Type()))
+		}
+	}
+}
+
+// mapStruct will map the source struct fields into the destination struct fields (by json name)
+func mapStruct(src, dest reflect.Value) {
+	srcFields := make(map[string]reflect.Value)
+	for _, field := range reflect.VisibleFields(src.Type()) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		if name := getMapperFieldName(field); name != "" {
+			srcFields[name] = src.FieldByIndex(field.Index)
+		}
+	}
+
+	for _, field := range reflect.VisibleFields(dest.Type()) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		if value, ok := srcFields[getMapperFieldName(field)]; ok {
+			mapValue(value, dest.FieldByIndex(field.Index))
+		}
+	}
+}
+
+// mapMapToStruct will map the map values into the destination struct fields (by json name)
+func mapMapToStruct(src, dest reflect.Value) {
+	for _, field := range reflect.VisibleFields(dest.Type()) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		if value := src.MapIndex(reflect.ValueOf(getMapperFieldName(field)).Convert(src.Type().Key())); value.IsValid() {
+			mapValue(value, dest.FieldByIndex(field.Index))
+		}
+	}
+}
+
+// getMapperFieldName will return the json name of the field (or the field name, empty if skipped)
+func getMapperFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
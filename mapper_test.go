@@ -0,0 +1,99 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModelDTO is a test DTO for the result mapper
+type testModelDTO struct {
+	Amount  *int32 `json:"amount"`
+	Ignored string `json:"-"`
+	Key     string `json:"id"`
+	Name    string `json:"name"`
+}
+
+// TestDefaultResultMapper will test the method DefaultResultMapper()
+func TestDefaultResultMapper(t *testing.T) {
+	t.Run("invalid destination", func(t *testing.T) {
+		require.ErrorIs(t, DefaultResultMapper(&testSQLModel{}, testModelDTO{}), ErrInvalidMapperDestination)
+		require.ErrorIs(t, DefaultResultMapper(&testSQLModel{}, nil), ErrInvalidMapperDestination)
+	})
+
+	t.Run("struct by json tags", func(t *testing.T) {
+		dto := &testModelDTO{}
+		require.NoError(t, DefaultResultMapper(&testSQLModel{ID: "dto-1", Name: "alice", Amount: 10}, dto))
+		assert.Equal(t, "dto-1", dto.Key)
+		assert.Equal(t, "alice", dto.Name)
+		require.NotNil(t, dto.Amount)
+		assert.Equal(t, int32(10), *dto.Amount)
+		assert.Empty(t, dto.Ignored)
+	})
+
+	t.Run("slice of structs", func(t *testing.T) {
+		var dtos []*testModelDTO
+		require.NoError(t, DefaultResultMapper(&[]*testSQLModel{{ID: "dto-1"}, {ID: "dto-2"}}, &dtos))
+		require.Len(t, dtos, 2)
+		assert.Equal(t, "dto-2", dtos[1].Key)
+	})
+
+	t.Run("map to struct", func(t *testing.T) {
+		dto := &testModelDTO{}
+		require.NoError(t, DefaultResultMapper(map[string]interface{}{"id": "dto-1", "name": 123}, dto))
+		assert.Equal(t, "dto-1", dto.Key)
+		assert.Empty(t, dto.Name)
+	})
+}
+
+// TestClient_mapResults will test the method mapResults() using GetModel() and GetModels()
+func TestClient_mapResults(t *testing.T) {
+	records := []*testSQLModel{
+		{ID: "map-1", Name: "alice", Amount: 10},
+		{ID: "map-2", Name: "bob", Amount: 20},
+	}
+
+	t.Run("GetModel maps into the destination", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		dto := &testModelDTO{}
+		require.NoError(t, client.GetModel(MapInto(ctx, dto), &testSQLModel{},
+			map[string]interface{}{sqlIDField: "map-1"}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "alice", dto.Name)
+	})
+
+	t.Run("GetModels maps into the destination", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		var dtos []testModelDTO
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(MapInto(ctx, &dtos), &models, nil,
+			&QueryParams{OrderByField: sqlIDField, SortDirection: SortAsc}, nil, defaultDatabaseMaxTimeout))
+		require.Len(t, dtos, 2)
+		assert.Equal(t, "bob", dtos[1].Name)
+	})
+
+	t.Run("custom mapper", func(t *testing.T) {
+		ctx := context.Background()
+		errMapper := errors.New("mapper failed")
+		client, deferFunc := testSQLiteClient(ctx, t, WithResultMapper(func(_, _ interface{}) error {
+			return errMapper
+		}))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		require.ErrorIs(t, client.GetModel(MapInto(ctx, &testModelDTO{}), &testSQLModel{},
+			map[string]interface{}{sqlIDField: "map-1"}, defaultDatabaseMaxTimeout, false), errMapper)
+		require.NoError(t, client.GetModel(ctx, &testSQLModel{},
+			map[string]interface{}{sqlIDField: "map-1"}, defaultDatabaseMaxTimeout, false))
+	})
+}
@@ -0,0 +1,282 @@
+package datastore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/gorm"
+)
+
+// MaskType is the type of masking applied to a column
+type MaskType string
+
+// Mask types
+const (
+	MaskHash   MaskType = "hash"   // SHA-256 (hex) of the value (string columns)
+	MaskNull   MaskType = "null"   // Value is removed (zero value / NULL)
+	MaskRedact MaskType = "redact" // Value is replaced with a fixed placeholder (string columns)
+)
+
+// Masking related settings
+const (
+	maskedViewSuffix    = "_masked" // Suffix for the masked view name (IE: users_masked)
+	maskedRedactedValue = "****"    // Placeholder for redacted values
+)
+
+// MaskSpec is the masking to apply per column (IE: {"email": MaskHash})
+type MaskSpec map[string]MaskType
+
+// maskedReaderKey is the context key for flagging a masked reader
+type maskedReaderKey struct{}
+
+// WithMaskedReader will flag the context as a PII-limited reader
+//
+// GetModel and GetModels return the masked projection for models registered using WithMaskedReads()
+// SQL engines select the masked expressions (the projection of CreateMaskedView) so the PII is not loaded, the
+// other engines (and MaskHash on SQLite) mask the loaded results
+func WithMaskedReader(ctx context.Context) context.Context {
+	return context.WithValue(ctx, maskedReaderKey{}, true)
+}
+
+// IsMaskedReader will return true if the context is flagged as a masked reader
+func IsMaskedReader(ctx context.Context) bool {
+	flagged, _ := ctx.Value(maskedReaderKey{}).(bool)
+	return flagged
+}
+
+// CreateMaskedView will create (or replace) a read-only view of the model with the masking applied (IE: users_masked)
+//
+// Useful for granting PII-limited database users access to the view instead of the table
+// MongoDB does not support MaskHash in views
+func (c *Client) CreateMaskedView(ctx context.Context, model interface{}) error {
+	spec := c.getMaskSpec(model)
+	if len(spec) == 0 {
+		return errors.New("no mask spec found for model, see: WithMaskedReads()")
+	}
+
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return err
+	}
+	viewName := tableName + maskedViewSuffix
+
+	// Create a Mongo view using a projection
+	if c.Engine() == MongoDB {
+		return c.createMaskedViewWithMongo(ctx, tableName, viewName, spec)
+	}
+
+	// Select all columns (masked where needed)
+	columns, err := c.getMaskedSelect(model, spec)
+	if err != nil {
+		return err
+	}
+	query := "VIEW " + viewName + " AS SELECT " + strings.Join(columns, ", ") + " FROM " + tableName
+
+	db := c.options.db.WithContext(ctx)
+	if c.Engine() == SQLite {
+		if err = db.Exec("DROP VIEW IF EXISTS " + viewName).Error; err != nil {
+			return err
+		}
+		return db.Exec("CREATE " + query).Error
+	}
+	return db.Exec("CREATE OR REPLACE " + query).Error
+}
+
+// getMaskedSelect will return the SQL select expressions of all the model's columns (masked where needed)
+func (c *Client) getMaskedSelect(model interface{}, spec MaskSpec) ([]string, error) {
+	stmt := &gorm.Statement{DB: c.options.db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	columns := make([]string, 0, len(stmt.Schema.DBNames))
+	for _, column := range stmt.Schema.DBNames {
+		expression, err := c.getMaskExpression(column, spec[column])
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, expression)
+	}
+	return columns, nil
+}
+
+// getMaskedColumns will return the masked select expressions of the model for a masked reader (SQL engines)
+//
+// Returns nil if the reads are not masked, or the masking can not be selected (IE: MaskHash on SQLite), the
+// results are then masked after loading (see: maskResults)
+func (c *Client) getMaskedColumns(ctx context.Context, model interface{}) []string {
+	if !IsMaskedReader(ctx) || !IsSQLEngine(c.Engine()) {
+		return nil
+	}
+	spec := c.getMaskSpec(model)
+	if len(spec) == 0 {
+		return nil
+	}
+	columns, err := c.getMaskedSelect(model, spec)
+	if err != nil {
+		return nil
+	}
+	return columns
+}
+
+// getMaskExpression will return the SQL select expression for the masked column
+func (c *Client) getMaskExpression(column string, maskType MaskType) (string, error) {
+	switch maskType {
+	case MaskHash:
+		if c.Engine() == PostgreSQL {
+			return "encode(sha256(convert_to(" + column + ", 'UTF8')), 'hex') AS " + column, nil
+		} else if c.Engine() == MySQL {
+			return "SHA2(" + column + ", 256) AS " + column, nil
+		}
+		return "", ErrNotImplemented // SQLite does not have a built-in hash function
+	case MaskNull:
+		return "NULL AS " + column, nil
+	case MaskRedact:
+		return "'" + maskedRedactedValue + "' AS " + column, nil
+	default:
+		return column, nil
+	}
+}
+
+// createMaskedViewWithMongo will create (or replace) a Mongo view with the masking applied
+func (c *Client) createMaskedViewWithMongo(ctx context.Context, collectionName, viewName string, spec MaskSpec) error {
+
+	// Sort the columns for a stable projection
+	columns := make([]string, 0, len(spec))
+	for column := range spec {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	project := bson.D{}
+	for _, column := range columns {
+		maskType := spec[column]
+		if column == sqlIDField {
+			column = mongoIDField
+		}
+		switch maskType {
+		case MaskHash:
+			return ErrNotImplemented
+		case MaskNull:
+			project = append(project, bson.E{Key: column, Value: nil})
+		case MaskRedact:
+			project = append(project, bson.E{Key: column, Value: bson.M{"$literal": maskedRedactedValue}})
+		}
+	}
+
+	// Replace the existing view
+	if err := c.options.mongoDB.Collection(viewName).Drop(ctx); err != nil {
+		return err
+	}
+	return c.options.mongoDB.CreateView(ctx, viewName, collectionName, bson.A{bson.M{"$set": project}})
+}
+
+// getMaskSpec will return the mask spec registered for the model (if found)
+func (c *Client) getMaskSpec(model interface{}) MaskSpec {
+	if len(c.options.maskSpecs) == 0 {
+		return nil
+	}
+	modelName := GetModelName(model)
+	if modelName == nil {
+		return nil
+	}
+	return c.options.maskSpecs[*modelName]
+}
+
+// maskResults will apply the masking to the results (single model or slice of models/map results) for masked readers
+//
+// Results already masked by the query are not changed (see: getMaskedColumns)
+func (c *Client) maskResults(ctx context.Context, model, results interface{}) {
+	if results == nil || !IsMaskedReader(ctx) {
+		return
+	}
+	spec := c.getMaskSpec(model)
+	if len(spec) == 0 || c.getMaskedColumns(ctx, model) != nil {
+		return
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(results))
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		for i := 0; i < value.Len(); i++ {
+			maskModel(value.Index(i), spec)
+		}
+		return
+	}
+	maskModel(value, spec)
+}
+
+// maskModel will apply the masking to the fields of a single model (or the columns of a map result)
+func maskModel(value reflect.Value, spec MaskSpec) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Map {
+		maskMap(value, spec)
+		return
+	} else if value.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, field := range reflect.VisibleFields(value.Type()) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		for column, maskType := range spec {
+			if !isColumnField(field, column) {
+				continue
+			}
+			fieldValue := value.FieldByIndex(field.Index)
+			if fieldValue.CanSet() {
+				fieldValue.Set(getMaskedValue(fieldValue, maskType))
+			}
+		}
+	}
+}
+
+// maskMap will apply the masking to the columns of a map result (IE: map[string]interface{} or bson.M)
+func maskMap(value reflect.Value, spec MaskSpec) {
+	if value.IsNil() || value.Type().Key().Kind() != reflect.String {
+		return
+	}
+	for column, maskType := range spec {
+		keys := []string{column}
+		if column == sqlIDField {
+			keys = append(keys, mongoIDField)
+		}
+		for _, key := range keys {
+			mapKey := reflect.ValueOf(key).Convert(value.Type().Key())
+			columnValue := value.MapIndex(mapKey)
+			if !columnValue.IsValid() {
+				continue
+			}
+			masked := getMaskedValue(columnValue, maskType)
+			if !masked.Type().AssignableTo(value.Type().Elem()) {
+				masked = reflect.Zero(value.Type().Elem())
+			}
+			value.SetMapIndex(mapKey, masked)
+		}
+	}
+}
+
+// getMaskedValue will return the masked value (hashed or redacted strings, otherwise the zero value)
+func getMaskedValue(value reflect.Value, maskType MaskType) reflect.Value {
+	zero := reflect.Zero(value.Type())
+	for value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.String && maskType == MaskHash {
+		hash := sha256.Sum256([]byte(value.String()))
+		return reflect.ValueOf(hex.EncodeToString(hash[:])).Convert(value.Type())
+	} else if value.Kind() == reflect.String && maskType == MaskRedact {
+		return reflect.ValueOf(maskedRedactedValue).Convert(value.Type())
+	}
+	return zero
+}
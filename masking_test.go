@@ -0,0 +1,166 @@
+package datastore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestClient_MaskedReads will test the option WithMaskedReads() and the masked reader context
+func TestClient_MaskedReads(t *testing.T) {
+	hash := sha256.Sum256([]byte("alice"))
+	spec := MaskSpec{"name": MaskHash, "amount": MaskNull}
+	records := []*testSQLModel{
+		{ID: "mask-1", Name: "alice", Amount: 10},
+		{ID: "mask-2", Name: "bob", Amount: 20},
+	}
+
+	t.Run("unflagged callers get the raw values", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithMaskedReads(&testSQLModel{}, spec))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "mask-1"}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "alice", model.Name)
+		assert.Equal(t, int64(10), model.Amount)
+	})
+
+	t.Run("masked readers get the masked projection", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithMaskedReads(&testSQLModel{}, spec))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		maskedCtx := WithMaskedReader(ctx)
+		assert.True(t, IsMaskedReader(maskedCtx))
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(maskedCtx, model, map[string]interface{}{sqlIDField: "mask-1"}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, hex.EncodeToString(hash[:]), model.Name)
+		assert.Equal(t, int64(0), model.Amount)
+		assert.Equal(t, "mask-1", model.ID)
+
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(maskedCtx, &models, nil, &QueryParams{OrderByField: sqlIDField}, nil, defaultDatabaseMaxTimeout))
+		require.Len(t, models, 2)
+		assert.Equal(t, hex.EncodeToString(hash[:]), models[0].Name)
+		assert.NotEqual(t, "bob", models[1].Name)
+		assert.Equal(t, int64(0), models[1].Amount)
+	})
+
+	t.Run("masked in the query (the PII is not loaded)", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithMaskedReads(&testSQLModel{}, MaskSpec{"name": MaskRedact}))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		model := &testSQLModel{}
+		var models []*testSQLModel
+		queries, err := client.CaptureQueries(WithMaskedReader(ctx), func(ctx context.Context) error {
+			if err := client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "mask-1"},
+				defaultDatabaseMaxTimeout, false); err != nil {
+				return err
+			}
+			return client.GetModels(ctx, &models, nil, &QueryParams{OrderByField: sqlIDField}, nil,
+				defaultDatabaseMaxTimeout)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, maskedRedactedValue, model.Name)
+		assert.Equal(t, int64(10), model.Amount)
+		require.Len(t, models, 2)
+		assert.Equal(t, maskedRedactedValue, models[1].Name)
+
+		var maskedQueries int
+		for _, query := range queries {
+			assert.NotContains(t, query.Query, "alice")
+			if strings.Contains(query.Query, "'"+maskedRedactedValue+"' AS name") {
+				maskedQueries++
+			}
+		}
+		assert.Equal(t, 2, maskedQueries)
+	})
+
+	t.Run("models without a spec are not masked", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(WithMaskedReader(ctx), model, map[string]interface{}{sqlIDField: "mask-2"}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "bob", model.Name)
+	})
+
+	t.Run("[memory] map field results are masked", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t, WithMaskedReads(&testSQLModel{}, spec))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		var fieldResults []map[string]interface{}
+		require.NoError(t, client.GetModels(WithMaskedReader(ctx), &[]*testSQLModel{}, nil,
+			&QueryParams{OrderByField: sqlIDField}, &fieldResults, defaultDatabaseMaxTimeout))
+		require.Len(t, fieldResults, 2)
+		assert.Equal(t, hex.EncodeToString(hash[:]), fieldResults[0]["name"])
+		assert.Nil(t, fieldResults[0]["amount"])
+		assert.Equal(t, "mask-1", fieldResults[0][sqlIDField])
+		assert.NotEqual(t, "bob", fieldResults[1]["name"])
+	})
+}
+
+// Test_maskModel will test the method maskModel()
+func Test_maskModel(t *testing.T) {
+	t.Run("mongo map result", func(t *testing.T) {
+		result := bson.M{mongoIDField: "mask-1", "name": "alice", "amount": int64(10)}
+		maskModel(reflect.ValueOf(result), MaskSpec{sqlIDField: MaskRedact, "name": MaskRedact, "amount": MaskNull})
+		assert.Equal(t, bson.M{mongoIDField: maskedRedactedValue, "name": maskedRedactedValue, "amount": nil}, result)
+	})
+
+	t.Run("typed map result", func(t *testing.T) {
+		result := map[string]int64{"amount": 10, "total": 2}
+		maskModel(reflect.ValueOf(&result), MaskSpec{"amount": MaskRedact})
+		assert.Equal(t, map[string]int64{"amount": 0, "total": 2}, result)
+	})
+}
+
+// TestClient_CreateMaskedView will test the method CreateMaskedView()
+func TestClient_CreateMaskedView(t *testing.T) {
+	t.Run("missing spec", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		require.Error(t, client.CreateMaskedView(ctx, &testSQLModel{}))
+	})
+
+	t.Run("hash is not supported by SQLite", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithMaskedReads(&testSQLModel{}, MaskSpec{"name": MaskHash}))
+		defer deferFunc()
+
+		require.ErrorIs(t, client.CreateMaskedView(ctx, &testSQLModel{}), ErrNotImplemented)
+	})
+
+	t.Run("redacted view", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithMaskedReads(&testSQLModel{}, MaskSpec{"name": MaskRedact}))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "view-1", Name: "alice", Amount: 5})
+
+		require.NoError(t, client.CreateMaskedView(ctx, &testSQLModel{}))
+		require.NoError(t, client.CreateMaskedView(ctx, &testSQLModel{})) // Replaces the view
+
+		var names []string
+		require.NoError(t, client.Raw("SELECT name FROM "+testSQLTableName+maskedViewSuffix).Scan(&names).Error)
+		assert.Equal(t, []string{maskedRedactedValue}, names)
+	})
+}
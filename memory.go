@@ -0,0 +1,1071 @@
+package datastore
+
+import (
+	"cmp"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// memoryLogLine is the debug log line of the Memory engine
+const memoryLogLine = "MEMORY %s %s: %+v\n"
+
+// ErrUnknownColumn is when a column is not a column of the model (Memory engine)
+var ErrUnknownColumn = errors.New("unknown column")
+
+// memoryTimeLayouts are the layouts of the time conditions given as text (Memory engine)
+var memoryTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+type (
+
+	// memoryStore is the in-process store of the Memory engine (see: WithMemory)
+	//
+	// The rows hold the driver values of the columns (IE: int64, string, time.Time) as a SQL database would,
+	// the callers hold the lock when using the tables
+	memoryStore struct {
+		mu      sync.RWMutex
+		namer   schema.NamingStrategy   // Same table and column names as the SQL engines
+		schemas *sync.Map               // Parsed model schemas
+		tables  map[string]*memoryTable // Tables (by table name)
+	}
+
+	// memoryTable is a table of the Memory engine
+	memoryTable struct {
+		keys     []string             // Primary keys (insertion order)
+		rows     map[string]memoryRow // Rows (by primary key)
+		sequence int64                // Last auto-increment value
+	}
+
+	// memoryRow is a row of the Memory engine (column: value)
+	memoryRow map[string]interface{}
+)
+
+// newMemoryStore will return a new (empty) store
+func newMemoryStore(tablePrefix string) *memoryStore {
+	if len(tablePrefix) > 0 {
+		tablePrefix += "_"
+	}
+	return &memoryStore{
+		namer:   schema.NamingStrategy{TablePrefix: tablePrefix},
+		schemas: &sync.Map{},
+		tables:  make(map[string]*memoryTable),
+	}
+}
+
+// parse will parse the model (or slice of models) using the GORM naming strategy
+func (m *memoryStore) parse(model interface{}) (*schema.Schema, error) {
+	return parseModelType(model, m.schemas, m.namer)
+}
+
+// parseModelType will parse the model type (model, or slice of models) without a GORM database
+func parseModelType(model interface{}, schemas *sync.Map, namer schema.NamingStrategy) (*schema.Schema, error) {
+	modelType := GetModelType(model)
+	if modelType.Kind() != reflect.Struct {
+		return nil, ErrUnknownCollection
+	}
+	return schema.Parse(reflect.New(modelType).Interface(), schemas, namer)
+}
+
+// getTable will return the table (created on the first use), the caller must hold the write lock
+func (m *memoryStore) getTable(tableName string) *memoryTable {
+	table, ok := m.tables[tableName]
+	if !ok {
+		table = &memoryTable{rows: make(map[string]memoryRow)}
+		m.tables[tableName] = table
+	}
+	return table
+}
+
+// lookupTable will return the table (an empty table if not used yet), it does not modify the store (read paths)
+func (m *memoryStore) lookupTable(tableName string) *memoryTable {
+	if table, ok := m.tables[tableName]; ok {
+		return table
+	}
+	return &memoryTable{rows: make(map[string]memoryRow)}
+}
+
+// put will insert or replace the row
+func (t *memoryTable) put(key string, row memoryRow) {
+	if _, ok := t.rows[key]; !ok {
+		t.keys = append(t.keys, key)
+	}
+	t.rows[key] = row
+}
+
+// remove will delete the row (returns false if not found)
+func (t *memoryTable) remove(key string) bool {
+	if _, ok := t.rows[key]; !ok {
+		return false
+	}
+	delete(t.rows, key)
+	for index, existing := range t.keys {
+		if existing == key {
+			t.keys = append(t.keys[:index], t.keys[index+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// find will return the rows matching the filter (insertion order)
+func (t *memoryTable) find(filter func(row memoryRow) bool) []memoryRow {
+	rows := make([]memoryRow, 0)
+	for _, key := range t.keys {
+		if row := t.rows[key]; filter(row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// saveWithMemory will create or update the model (returns the number of rows saved)
+func (c *Client) saveWithMemory(ctx context.Context, model interface{}, newRecord bool) (int64, error) {
+	store := c.options.memory
+	sch, err := store.parse(model)
+	if err != nil {
+		return 0, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	c.DebugLog(ctx, fmt.Sprintf(memoryLogLine, "save", sch.Table, model))
+	if _, err = saveMemoryModel(ctx, sch, store.getTable(sch.Table), model, newRecord); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// saveMemoryModel will create (ErrDuplicateKey if the primary key exists) or replace the row of the model
+//
+// The auto-increment primary key and the auto create/update times are set on the model (as GORM does)
+func saveMemoryModel(ctx context.Context, sch *schema.Schema, table *memoryTable, model interface{},
+	newRecord bool,
+) (string, error) {
+	value := reflect.Indirect(reflect.ValueOf(model))
+	if err := setMemoryDefaults(ctx, sch, table, value, newRecord); err != nil {
+		return "", err
+	}
+	row, err := getMemoryRow(ctx, sch, value)
+	if err != nil {
+		return "", err
+	}
+	key, err := getMemoryKey(sch, row)
+	if err != nil {
+		return "", err
+	}
+	if _, exists := table.rows[key]; exists && newRecord {
+		return "", ErrDuplicateKey
+	}
+	table.put(key, row)
+	return key, nil
+}
+
+// setMemoryDefaults will set the auto-increment primary key and the auto create/update times of the model
+func setMemoryDefaults(ctx context.Context, sch *schema.Schema, table *memoryTable, value reflect.Value,
+	newRecord bool,
+) error {
+	now := time.Now()
+	for _, field := range sch.Fields {
+		fieldValue, isZero := field.ValueOf(ctx, value)
+		switch {
+		case field.AutoIncrement && field == sch.PrioritizedPrimaryField:
+			if !isZero {
+				if id, ok := getMemoryComparable(fieldValue).(int64); ok && id > table.sequence {
+					table.sequence = id
+				}
+				continue
+			} else if !newRecord {
+				continue
+			}
+			table.sequence++
+			if err := field.Set(ctx, value, table.sequence); err != nil {
+				return err
+			}
+		case field.AutoCreateTime > 0 && newRecord && isZero,
+			field.AutoUpdateTime > 0 && (!newRecord || isZero):
+			if err := field.Set(ctx, value, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getMemoryRow will return the row of the model (driver values of the columns)
+func getMemoryRow(ctx context.Context, sch *schema.Schema, value reflect.Value) (memoryRow, error) {
+	row := make(memoryRow, len(sch.DBNames))
+	for _, column := range sch.DBNames {
+		field := sch.FieldsByDBName[column]
+		if !field.Creatable && !field.Readable {
+			continue
+		}
+		fieldValue, _ := field.ValueOf(ctx, value)
+		converted, err := getMemoryValue(fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, column)
+		}
+		row[column] = converted
+	}
+	return row, nil
+}
+
+// getMemoryValue will convert the value into a driver value (JSON for values without a driver conversion)
+func getMemoryValue(value interface{}) (interface{}, error) {
+	converted, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil { // Slices, maps and structs without a Valuer (IE: serializer:json)
+		data, jsonErr := json.Marshal(value)
+		if jsonErr != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+	if data, ok := converted.([]byte); ok {
+		return append([]byte(nil), data...), nil
+	}
+	return converted, nil
+}
+
+// setMemoryRow will set the fields of the model from the row
+func setMemoryRow(ctx context.Context, sch *schema.Schema, row memoryRow, value reflect.Value) error {
+	for _, column := range sch.DBNames {
+		field := sch.FieldsByDBName[column]
+		stored, ok := row[column]
+		if !ok || !field.Readable {
+			continue
+		}
+		if data, isBytes := stored.([]byte); isBytes {
+			stored = append([]byte(nil), data...)
+		}
+		if err := field.Set(ctx, value, stored); err != nil {
+			text, isText := stored.(string) // Stored as JSON (see: getMemoryValue)
+			if !isText || json.Unmarshal([]byte(text), field.ReflectValueOf(ctx, value).Addr().Interface()) != nil {
+				return fmt.Errorf("%w: %s", err, column)
+			}
+		}
+	}
+	return nil
+}
+
+// setMemoryResults will set the results (slice of models, or maps of the columns) from the rows
+func (c *Client) setMemoryResults(ctx context.Context, results interface{}, rows []memoryRow) error {
+	slice := reflect.Indirect(reflect.ValueOf(results))
+	if slice.Kind() != reflect.Slice {
+		return errors.New("field: result is not a slice, found: " + slice.Kind().String())
+	}
+	elemType := slice.Type().Elem()
+	items := reflect.MakeSlice(slice.Type(), 0, len(rows))
+
+	// Maps of the columns (IE: []map[string]interface{})
+	if elemType.Kind() == reflect.Map {
+		for _, row := range rows {
+			item := reflect.MakeMapWithSize(elemType, len(row))
+			for column, stored := range row {
+				columnValue := reflect.Zero(elemType.Elem())
+				if stored != nil && reflect.TypeOf(stored).AssignableTo(elemType.Elem()) {
+					columnValue = reflect.ValueOf(stored)
+				}
+				item.SetMapIndex(reflect.ValueOf(column).Convert(elemType.Key()), columnValue)
+			}
+			items = reflect.Append(items, item)
+		}
+		slice.Set(items)
+		return nil
+	}
+
+	// Models (or pointers to models)
+	structType := elemType
+	if elemType.Kind() == reflect.Ptr {
+		structType = elemType.Elem()
+	}
+	sch, err := c.parseModelSchema(reflect.New(structType).Interface())
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		item := reflect.New(structType)
+		if err = setMemoryRow(ctx, sch, row, item.Elem()); err != nil {
+			return err
+		}
+		if elemType.Kind() == reflect.Ptr {
+			items = reflect.Append(items, item)
+		} else {
+			items = reflect.Append(items, item.Elem())
+		}
+	}
+	slice.Set(items)
+	return nil
+}
+
+// getMemoryKey will return the primary key of the row (the primary key values)
+func getMemoryKey(sch *schema.Schema, row memoryRow) (string, error) {
+	if len(sch.PrimaryFieldDBNames) == 0 {
+		return "", ErrMissingPrimaryKey
+	}
+	values := make([]string, 0, len(sch.PrimaryFieldDBNames))
+	for _, column := range sch.PrimaryFieldDBNames {
+		if isZeroValue(row[column]) {
+			return "", ErrMissingPrimaryKey
+		}
+		values = append(values, fmt.Sprint(row[column]))
+	}
+	return strings.Join(values, "\x00"), nil
+}
+
+// getMemoryModelKey will return the primary key of the model
+func getMemoryModelKey(ctx context.Context, sch *schema.Schema, model interface{}) (string, error) {
+	row, err := getMemoryRow(ctx, sch, reflect.Indirect(reflect.ValueOf(model)))
+	if err != nil {
+		return "", err
+	}
+	return getMemoryKey(sch, row)
+}
+
+// updateFieldsWithMemory will update the given fields of the model (returns the number of rows updated)
+//
+// The fields are set on the model (as GORM does), the auto update times are set
+func (c *Client) updateFieldsWithMemory(ctx context.Context, model interface{},
+	fields map[string]interface{},
+) (int64, error) {
+	store := c.options.memory
+	sch, err := store.parse(model)
+	if err != nil {
+		return 0, err
+	}
+	var key string
+	if key, err = getMemoryModelKey(ctx, sch, model); err != nil {
+		return 0, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	c.DebugLog(ctx, fmt.Sprintf(memoryLogLine, "update", sch.Table, fields))
+	table := store.getTable(sch.Table)
+	existing, ok := table.rows[key]
+	if !ok {
+		return 0, nil
+	}
+
+	// Set the fields on the model (converted to the field types)
+	value := reflect.Indirect(reflect.ValueOf(model))
+	columns := make([]string, 0, len(fields))
+	for name, fieldValue := range fields {
+		field := sch.LookUpField(name)
+		if field == nil || len(field.DBName) == 0 {
+			return 0, fmt.Errorf("%w: %s", ErrUnknownColumn, name)
+		} else if err = field.Set(ctx, value, fieldValue); err != nil {
+			return 0, err
+		}
+		columns = append(columns, field.DBName)
+	}
+	for _, field := range sch.Fields {
+		if field.AutoUpdateTime > 0 && len(field.DBName) > 0 {
+			if err = field.Set(ctx, value, time.Now()); err != nil {
+				return 0, err
+			}
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	// Update the columns of the row
+	var row memoryRow
+	if row, err = getMemoryRow(ctx, sch, value); err != nil {
+		return 0, err
+	}
+	updated := copyMemoryRow(existing)
+	for _, column := range columns {
+		updated[column] = row[column]
+	}
+	table.rows[key] = updated
+	return 1, nil
+}
+
+// upsertWithMemory will insert the model, or update the given columns of the row matching the conflict columns
+//
+// No conflict columns uses the primary key, no update columns updates all the columns (except the primary key,
+// conflict and auto create time columns)
+func (c *Client) upsertWithMemory(ctx context.Context, model interface{}, conflictColumns []string,
+	updateColumns []string,
+) error {
+	store := c.options.memory
+	sch, err := store.parse(model)
+	if err != nil {
+		return err
+	}
+	if len(conflictColumns) == 0 {
+		conflictColumns = sch.PrimaryFieldDBNames
+	}
+	for _, column := range append(append([]string{}, conflictColumns...), updateColumns...) {
+		if sch.LookUpField(column) == nil {
+			return fmt.Errorf("%w: %s", ErrInvalidUpsertColumn, column)
+		}
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	c.DebugLog(ctx, fmt.Sprintf(memoryLogLine, "upsert", sch.Table, model))
+	table := store.getTable(sch.Table)
+	value := reflect.Indirect(reflect.ValueOf(model))
+	if err = setMemoryDefaults(ctx, sch, table, value, true); err != nil {
+		return err
+	}
+	var row memoryRow
+	if row, err = getMemoryRow(ctx, sch, value); err != nil {
+		return err
+	}
+
+	// Find the conflicting row (insert if none)
+	var conflictKey string
+	for _, key := range table.keys {
+		if matchMemoryColumns(table.rows[key], row, conflictColumns) {
+			conflictKey = key
+			break
+		}
+	}
+	if len(conflictKey) == 0 {
+		_, err = saveMemoryModel(ctx, sch, table, model, true)
+		return err
+	}
+
+	// Update the columns
+	if len(updateColumns) == 0 {
+		for _, column := range sch.DBNames {
+			field := sch.FieldsByDBName[column]
+			if !field.PrimaryKey && field.AutoCreateTime == 0 && !StringInSlice(column, conflictColumns) {
+				updateColumns = append(updateColumns, column)
+			}
+		}
+	}
+	updated := copyMemoryRow(table.rows[conflictKey])
+	for _, column := range updateColumns {
+		column = sch.LookUpField(column).DBName
+		updated[column] = row[column]
+	}
+	table.rows[conflictKey] = updated
+	return nil
+}
+
+// findOrCreateWithMemory will find the model using the conditions, or create it (returns true if created)
+func (c *Client) findOrCreateWithMemory(ctx context.Context, model interface{},
+	conditions map[string]interface{},
+) (bool, error) {
+	store := c.options.memory
+	sch, err := store.parse(model)
+	if err != nil {
+		return false, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	c.DebugLog(ctx, fmt.Sprintf(memoryLogLine, "findOrCreate", sch.Table, conditions))
+	table := store.getTable(sch.Table)
+	if rows := table.find(func(row memoryRow) bool {
+		return c.matchMemoryConditions(row, conditions)
+	}); len(rows) > 0 {
+		return false, setMemoryRow(ctx, sch, rows[0], reflect.Indirect(reflect.ValueOf(model)))
+	}
+
+	if _, err = saveMemoryModel(ctx, sch, table, model, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// deleteWithMemory will delete (or mark as deleted) the model (returns the number of rows deleted or marked)
+func (c *Client) deleteWithMemory(ctx context.Context, model interface{}, softDelete bool) (int64, error) {
+	store := c.options.memory
+	sch, err := store.parse(model)
+	if err != nil {
+		return 0, err
+	}
+	var key string
+	if key, err = getMemoryModelKey(ctx, sch, model); err != nil {
+		return 0, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	c.DebugLog(ctx, fmt.Sprintf(memoryLogLine, "delete", sch.Table, model))
+	table := store.getTable(sch.Table)
+	if !softDelete {
+		if table.remove(key) {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	existing, ok := table.rows[key]
+	if !ok {
+		return 0, nil
+	}
+	updated := copyMemoryRow(existing)
+	updated[softDeleteField] = time.Now().UTC()
+	table.rows[key] = updated
+	return 1, nil
+}
+
+// createInBatchesWithMemory will create all the models (none are created if one fails)
+func (c *Client) createInBatchesWithMemory(ctx context.Context, models interface{}, opts ...BatchOps) error {
+	slice := reflect.Indirect(reflect.ValueOf(models))
+	if slice.Kind() != reflect.Slice {
+		return errors.New("field: models is not a slice, found: " + slice.Kind().String())
+	}
+	store := c.options.memory
+	sch, err := store.parse(models)
+	if err != nil {
+		return err
+	}
+	batch := getBatchOptions(opts...)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	c.DebugLog(ctx, fmt.Sprintf(memoryLogLine, "insertMany", sch.Table, slice.Len()))
+
+	// Check all the rows before creating any
+	table := store.getTable(sch.Table)
+	keys := make([]string, 0, slice.Len())
+	rows := make([]memoryRow, 0, slice.Len())
+	for index := 0; index < slice.Len(); index++ {
+		value := reflect.Indirect(slice.Index(index))
+		if err = setMemoryDefaults(ctx, sch, table, value, true); err != nil {
+			return err
+		}
+		var row memoryRow
+		if row, err = getMemoryRow(ctx, sch, value); err != nil {
+			return err
+		}
+		var key string
+		if key, err = getMemoryKey(sch, row); err != nil {
+			return err
+		}
+		if _, exists := table.rows[key]; exists || StringInSlice(key, keys) {
+			if batch.skipDuplicates {
+				continue
+			}
+			return ErrDuplicateKey
+		}
+		keys = append(keys, key)
+		rows = append(rows, row)
+	}
+	for index, key := range keys {
+		table.put(key, rows[index])
+	}
+	return nil
+}
+
+// getWithMemory will get the model (first matching row), or the models matching the conditions
+//
+// The total count of matching rows (without the pagination) is set for the models (if total is set)
+func (c *Client) getWithMemory(ctx context.Context, models interface{}, conditions map[string]interface{},
+	fieldResults interface{}, queryParams *QueryParams, total *int64,
+) error {
+	rows, err := c.findWithMemory(ctx, models, conditions)
+	if err != nil {
+		return err
+	}
+	return c.setMemoryQueryResults(ctx, models, rows, fieldResults, queryParams, total)
+}
+
+// setMemoryQueryResults will set the model (first row), or the models from the rows (ordered and paginated
+// using the query params)
+//
+// The total count of the rows (without the pagination) is set for the models (if total is set)
+func (c *Client) setMemoryQueryResults(ctx context.Context, models interface{}, rows []memoryRow,
+	fieldResults interface{}, queryParams *QueryParams, total *int64,
+) error {
+
+	// Single model
+	if !IsModelSlice(models) {
+		if len(rows) == 0 {
+			return ErrNoResults
+		}
+		sch, err := c.parseModelSchema(models)
+		if err != nil {
+			return err
+		}
+		return setMemoryRow(ctx, sch, rows[0], reflect.Indirect(reflect.ValueOf(models)))
+	}
+
+	if total != nil {
+		*total = int64(len(rows))
+	}
+	if queryParams == nil {
+		queryParams = &QueryParams{}
+	}
+
+	// Use the order fields/sort
+	if orderSpecs := queryParams.getOrderSpecs(); len(orderSpecs) > 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			for _, orderSpec := range orderSpecs {
+				result := compareMemoryOrder(rows[i][orderSpec.Field], rows[j][orderSpec.Field])
+				if orderSpec.SortDirection == SortDesc {
+					result = -result
+				}
+				if result != 0 {
+					return result < 0
+				}
+			}
+			return false
+		})
+	}
+
+	// Use the limit and offset
+	if queryParams.Page > 0 && queryParams.PageSize > 0 {
+		offset := (queryParams.Page - 1) * queryParams.PageSize
+		if offset >= len(rows) {
+			rows = rows[:0]
+		} else {
+			rows = rows[offset:min(offset+queryParams.PageSize, len(rows))]
+		}
+	}
+
+	if len(rows) == 0 {
+		return ErrNoResults
+	} else if fieldResults != nil {
+		return c.setMemoryResults(ctx, fieldResults, rows)
+	}
+	return c.setMemoryResults(ctx, models, rows)
+}
+
+// findWithMemory will return the rows of the model's table matching the conditions (insertion order)
+func (c *Client) findWithMemory(ctx context.Context, model interface{},
+	conditions map[string]interface{},
+) ([]memoryRow, error) {
+	store := c.options.memory
+	sch, err := store.parse(model)
+	if err != nil {
+		return nil, err
+	}
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	c.DebugLog(ctx, fmt.Sprintf(memoryLogLine, "find", sch.Table, conditions))
+	return store.lookupTable(sch.Table).find(func(row memoryRow) bool {
+		return c.matchMemoryConditions(row, conditions)
+	}), nil
+}
+
+// countWithMemory will return the number of rows matching the conditions
+func (c *Client) countWithMemory(ctx context.Context, model interface{},
+	conditions map[string]interface{},
+) (int64, error) {
+	rows, err := c.findWithMemory(ctx, model, conditions)
+	return int64(len(rows)), err
+}
+
+// aggregateWithMemory will return the number of matching rows grouped by the aggregate column
+//
+// Time values are grouped by day (YYYYMMDD)
+func (c *Client) aggregateWithMemory(ctx context.Context, models interface{}, conditions map[string]interface{},
+	aggregateColumn string,
+) ([]aggregateRow, error) {
+	rows, err := c.findWithMemory(ctx, models, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{})
+	counts := make(map[string]int64)
+	for _, row := range rows {
+		key := row[aggregateColumn]
+		switch value := key.(type) {
+		case time.Time:
+			key = value.Format("20060102")
+		case []byte:
+			key = string(value)
+		}
+		keys[getAggregateKey(key)] = key
+		counts[getAggregateKey(key)]++
+	}
+
+	results := make([]aggregateRow, 0, len(counts))
+	for text, count := range counts {
+		results = append(results, aggregateRow{count: count, key: keys[text]})
+	}
+	return results, nil
+}
+
+// incrementWithMemory will increment the column of the model's row and return the new value
+func incrementWithMemory[T int64 | float64](
+	ctx context.Context,
+	c *Client,
+	model interface{},
+	fieldName string,
+	increment T,
+	convert func(value interface{}) (T, error),
+) (newValue T, err error) {
+	store := c.options.memory
+	var sch *schema.Schema
+	if sch, err = store.parse(model); err != nil {
+		return 0, err
+	}
+	field := sch.LookUpField(fieldName)
+	if field == nil || len(field.DBName) == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownColumn, fieldName)
+	}
+	var key string
+	if key, err = getMemoryModelKey(ctx, sch, model); err != nil {
+		return 0, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	c.DebugLog(ctx, fmt.Sprintf(memoryLogLine, "increment", sch.Table, model))
+	table := store.getTable(sch.Table)
+	existing, ok := table.rows[key]
+	if !ok {
+		return 0, ErrNoResults
+	}
+	var current T
+	if current, err = convertIncrementValue(fieldName, existing[field.DBName], convert); err != nil {
+		return 0, err
+	}
+	newValue = current + increment
+
+	updated := copyMemoryRow(existing)
+	updated[field.DBName] = newValue
+	table.rows[key] = updated
+	return newValue, nil
+}
+
+// copyMemoryRow will return a copy of the row
+func copyMemoryRow(row memoryRow) memoryRow {
+	copied := make(memoryRow, len(row))
+	for column, value := range row {
+		copied[column] = value
+	}
+	return copied
+}
+
+// matchMemoryColumns will return true if the rows have the same (not null) values for the columns
+func matchMemoryColumns(row, other memoryRow, columns []string) bool {
+	for _, column := range columns {
+		if result, ok := compareMemoryValues(row[column], other[column]); !ok || result != 0 {
+			return false
+		}
+	}
+	return len(columns) > 0
+}
+
+// matchMemoryConditions will return true if the row matches the conditions (same rules as processConditions)
+func (c *Client) matchMemoryConditions(row memoryRow, conditions map[string]interface{}) bool {
+	for key, condition := range conditions {
+		switch {
+		case key == conditionAnd:
+			for _, item := range getMemoryConditionList(condition) {
+				if !c.matchMemoryConditions(row, item) {
+					return false
+				}
+			}
+		case key == conditionOr:
+			items := getMemoryConditionList(condition)
+			matched := len(items) == 0
+			for _, item := range items {
+				if c.matchMemoryConditions(row, item) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		case StringInSlice(key, c.GetArrayFields()):
+			if !matchMemoryArray(row[key], condition) {
+				return false
+			}
+		case StringInSlice(key, c.GetObjectFields()):
+			if !matchMemoryObject(row[key], condition) {
+				return false
+			}
+		default:
+			if !matchMemoryField(row[key], condition) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// getMemoryConditionList will return the conditions of an $and / $or condition
+func getMemoryConditionList(condition interface{}) []map[string]interface{} {
+	switch items := condition.(type) {
+	case []map[string]interface{}:
+		return items
+	case []interface{}:
+		list := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			if conditions, ok := item.(map[string]interface{}); ok {
+				list = append(list, conditions)
+			}
+		}
+		return list
+	}
+	var list []map[string]interface{}
+	data, _ := json.Marshal(condition) //nolint:errchkjson // same conversion as processMongoConditions
+	_ = json.Unmarshal(data, &list)
+	return list
+}
+
+// matchMemoryField will return true if the value matches the condition (a value, or a map of operators)
+func matchMemoryField(value, condition interface{}) bool {
+	if condition == nil {
+		return value == nil
+	}
+	operators, ok := condition.(map[string]interface{})
+	if !ok {
+		if reflect.ValueOf(condition).Kind() != reflect.Map {
+			result, comparable := compareMemoryValues(value, condition)
+			return comparable && result == 0
+		}
+		data, _ := json.Marshal(condition) //nolint:errchkjson // same conversion as processConditions
+		_ = json.Unmarshal(data, &operators)
+	}
+	for operator, operand := range operators {
+		if !matchMemoryOperator(value, operator, operand, operators) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchMemoryOperator will return true if the value matches the operator (IE: $gt), NULL values only match
+// $exists (false) and $eqOrNull
+func matchMemoryOperator(value interface{}, operator string, operand interface{},
+	operators map[string]interface{},
+) bool {
+	result, comparable := compareMemoryValues(value, operand)
+	switch operator {
+	case conditionEquals:
+		return comparable && result == 0
+	case conditionNotEquals:
+		return comparable && result != 0
+	case conditionGreaterThan:
+		return comparable && result > 0
+	case conditionGreaterThanOrEqual:
+		return comparable && result >= 0
+	case conditionLessThan:
+		return comparable && result < 0
+	case conditionLessThanOrEqual:
+		return comparable && result <= 0
+	case conditionEqOrNull:
+		return value == nil || (comparable && result == 0)
+	case conditionExists:
+		exists, _ := operand.(bool)
+		return (value != nil) == exists
+	case conditionIn:
+		list := reflect.ValueOf(operand)
+		if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+			return false
+		}
+		for index := 0; index < list.Len(); index++ {
+			if result, comparable = compareMemoryValues(value, list.Index(index).Interface()); comparable && result == 0 {
+				return true
+			}
+		}
+		return false
+	case conditionBetween:
+		bounds, ok := getBetweenBounds(operand)
+		return ok && matchMemoryOperator(value, conditionGreaterThanOrEqual, bounds[0], operators) &&
+			matchMemoryOperator(value, conditionLessThanOrEqual, bounds[1], operators)
+	case conditionBitsAllSet, conditionBitsAnySet:
+		mask, ok := getBitMask(operand)
+		bits, isInt := getMemoryComparable(value).(int64)
+		if !ok || !isInt {
+			return false
+		} else if operator == conditionBitsAllSet {
+			return bits&mask == mask
+		}
+		return bits&mask != 0
+	case conditionLike, conditionILike:
+		pattern := likeToRegex(fmt.Sprint(operand))
+		if operator == conditionILike {
+			pattern = "(?is)" + pattern
+		} else {
+			pattern = "(?s)" + pattern
+		}
+		return matchMemoryRegex(pattern, value)
+	case conditionStartsWith, conditionEndsWith:
+		text, ok := getMemoryComparable(value).(string)
+		if !ok {
+			return false
+		} else if operator == conditionStartsWith {
+			return strings.HasPrefix(text, fmt.Sprint(operand))
+		}
+		return strings.HasSuffix(text, fmt.Sprint(operand))
+	case conditionRegex:
+		pattern, caseInsensitive := getRegexCondition(Memory, operand, operators[conditionRegexOptions])
+		if caseInsensitive {
+			pattern = "(?i)" + fmt.Sprint(pattern)
+		}
+		return matchMemoryRegex(fmt.Sprint(pattern), value)
+	case conditionRegexOptions:
+		return true // Used by the $regex condition
+	}
+	return false
+}
+
+// matchMemoryRegex will return true if the (text) value matches the regular expression
+func matchMemoryRegex(pattern string, value interface{}) bool {
+	matched, err := sqliteRegexp(pattern, getMemoryComparable(value)) // Same compiled pattern cache
+	return err == nil && matched
+}
+
+// matchMemoryArray will return true if the JSON array contains the value (see: whereSlice)
+func matchMemoryArray(value, condition interface{}) bool {
+	var items []interface{}
+	if text, ok := getMemoryComparable(value).(string); !ok || json.Unmarshal([]byte(text), &items) != nil {
+		return false
+	}
+	for _, item := range items {
+		if fmt.Sprint(item) == fmt.Sprint(condition) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMemoryObject will return true if the JSON object contains the keys and values (see: whereObject)
+func matchMemoryObject(value, condition interface{}) bool {
+	var stored, expected interface{}
+	if text, ok := getMemoryComparable(value).(string); !ok || json.Unmarshal([]byte(text), &stored) != nil {
+		return false
+	}
+	data, err := json.Marshal(condition)
+	if err != nil || json.Unmarshal(data, &expected) != nil {
+		return false
+	}
+	return containsMemoryJSON(stored, expected)
+}
+
+// containsMemoryJSON will return true if the decoded JSON value contains the expected value (nested objects
+// are matched by their keys)
+func containsMemoryJSON(value, expected interface{}) bool {
+	expectedObject, ok := expected.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(value, expected)
+	} else if _, isOperator := getObjectOperators(expectedObject); isOperator {
+		return matchMemoryField(value, expectedObject)
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for key, expectedValue := range expectedObject {
+		if !containsMemoryJSON(object[key], expectedValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// getMemoryComparable will return the comparable value: nil, int64, float64, bool, string or time.Time
+func getMemoryComparable(value interface{}) interface{} {
+	converted, err := getMemoryValue(value)
+	if err != nil {
+		return nil
+	} else if data, ok := converted.([]byte); ok {
+		return string(data)
+	}
+	return converted
+}
+
+// compareMemoryValues will compare the values (-1, 0, +1), returns false if the values can not be compared
+// (IE: NULL values)
+//
+// Numbers and times given as text are converted (as SQLite does)
+func compareMemoryValues(value, other interface{}) (int, bool) {
+	value, other = getMemoryComparable(value), getMemoryComparable(other)
+	if value == nil || other == nil {
+		return 0, false
+	}
+
+	switch v := value.(type) {
+	case int64:
+		if o, ok := other.(int64); ok {
+			return cmp.Compare(v, o), true
+		}
+		o, ok := getMemoryFloat(other)
+		return cmp.Compare(float64(v), o), ok
+	case float64:
+		o, ok := getMemoryFloat(other)
+		return cmp.Compare(v, o), ok
+	case time.Time:
+		o, ok := getMemoryTime(other)
+		return v.Compare(o), ok
+	case bool:
+		o, ok := other.(bool)
+		if !ok || v == o {
+			return 0, ok
+		} else if v {
+			return 1, true
+		}
+		return -1, true
+	case string:
+		switch o := other.(type) {
+		case string:
+			return strings.Compare(v, o), true
+		case int64, float64, time.Time, bool:
+			result, ok := compareMemoryValues(other, value)
+			return -result, ok
+		}
+	}
+	return 0, false
+}
+
+// compareMemoryOrder will compare the values for sorting (NULL values first)
+func compareMemoryOrder(value, other interface{}) int {
+	if result, ok := compareMemoryValues(value, other); ok {
+		return result
+	} else if value == nil && other != nil {
+		return -1
+	} else if value != nil && other == nil {
+		return 1
+	}
+	return 0
+}
+
+// getMemoryFloat will return the number (numbers given as text are parsed)
+func getMemoryFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		number, err := strconv.ParseFloat(v, 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// getMemoryTime will return the time (times given as text are parsed)
+func getMemoryTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range memoryTimeLayouts {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				return parsed, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
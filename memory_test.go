@@ -0,0 +1,199 @@
+package datastore
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMemoryClient will generate a test client using the Memory engine
+func testMemoryClient(ctx context.Context, t *testing.T, opts ...ClientOps) (ClientInterface, func()) {
+	opts = append([]ClientOps{
+		WithMemory(&MemoryConfig{CommonConfig: CommonConfig{TablePrefix: "mem"}}),
+	}, opts...)
+	return testClient(ctx, t, opts...)
+}
+
+// TestClient_Memory will test the model methods using the Memory engine
+func TestClient_Memory(t *testing.T) {
+	t.Run("save and get", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t)
+		defer deferFunc()
+		assert.Equal(t, Memory, client.Engine())
+
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "memory-1", Name: "alice", Amount: 10})
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{"id": "memory-1"}, 0, false))
+		assert.Equal(t, "alice", model.Name)
+		assert.Equal(t, int64(10), model.Amount)
+
+		err := client.GetModel(ctx, &testSQLModel{}, map[string]interface{}{"id": "memory-2"}, 0, false)
+		require.ErrorIs(t, err, ErrNoResults)
+
+		// Saving a new record twice is a duplicate
+		err = client.SaveModel(ctx, &testSQLModel{ID: "memory-1"}, nil, true, true)
+		require.ErrorIs(t, err, ErrDuplicateKey)
+	})
+
+	t.Run("get models, count and exists", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t)
+		defer deferFunc()
+
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "memory-1", Name: "alice", Amount: 10},
+			&testSQLModel{ID: "memory-2", Name: "bob", Amount: 20},
+			&testSQLModel{ID: "memory-3", Name: "carol", Amount: 30},
+		)
+
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{
+			"amount": map[string]interface{}{conditionGreaterThan: 10},
+		}, &QueryParams{OrderByField: "amount", SortDirection: SortDesc, Page: 1, PageSize: 1}, nil, 0))
+		require.Len(t, models, 1)
+		assert.Equal(t, "carol", models[0].Name)
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, map[string]interface{}{
+			conditionOr: []map[string]interface{}{{"name": "alice"}, {"name": "bob"}},
+		}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		var exists bool
+		exists, err = client.ModelExists(ctx, &testSQLModel{}, map[string]interface{}{"name": "dave"}, 0)
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		var results map[string]interface{}
+		results, err = client.GetModelsAggregate(ctx, &[]*testSQLModel{}, nil, "amount", 0)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"10": int64(1), "20": int64(1), "30": int64(1)}, results)
+	})
+
+	t.Run("update, increment and delete", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t)
+		defer deferFunc()
+
+		model := &testSQLModel{ID: "memory-1", Name: "alice", Amount: 10}
+		testSaveModels(ctx, t, client, model)
+
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.UpdateModelFields(ctx, model, map[string]interface{}{"name": "alicia"}, tx, true)
+		}))
+
+		newValue, err := client.IncrementModel(ctx, model, "amount", 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(15), newValue)
+
+		found := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, found, map[string]interface{}{"id": "memory-1"}, 0, false))
+		assert.Equal(t, "alicia", found.Name)
+		assert.Equal(t, int64(15), found.Amount)
+
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.DeleteModel(ctx, found, tx, true)
+		}))
+		err = client.GetModel(ctx, &testSQLModel{}, map[string]interface{}{"id": "memory-1"}, 0, false)
+		require.ErrorIs(t, err, ErrNoResults)
+	})
+
+	t.Run("find or create and upsert", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t)
+		defer deferFunc()
+
+		model := &testSQLModel{ID: "memory-1", Name: "alice"}
+		created, err := client.FindOrCreateModel(ctx, model, map[string]interface{}{"id": "memory-1"}, nil)
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		created, err = client.FindOrCreateModel(ctx, &testSQLModel{ID: "memory-1", Name: "other"},
+			map[string]interface{}{"id": "memory-1"}, nil)
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		require.NoError(t, client.UpsertModel(ctx, &testSQLModel{ID: "memory-1", Name: "bob", Amount: 3},
+			[]string{"id"}, []string{"amount"}))
+
+		found := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, found, map[string]interface{}{"id": "memory-1"}, 0, false))
+		assert.Equal(t, "alice", found.Name)
+		assert.Equal(t, int64(3), found.Amount)
+	})
+
+	t.Run("create in batches", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t)
+		defer deferFunc()
+
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "memory-2", Name: "existing"})
+
+		records := []*testSQLModel{{ID: "memory-1"}, {ID: "memory-2"}, {ID: "memory-3"}}
+		require.ErrorIs(t, client.CreateInBatches(ctx, records, 2), ErrDuplicateKey)
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		require.NoError(t, client.CreateInBatches(ctx, records, 2, WithSkipDuplicates()))
+		count, err = client.GetModelCount(ctx, &testSQLModel{}, nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("concurrent reads of a new table", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testMemoryClient(ctx, t)
+		defer deferFunc()
+
+		var wg sync.WaitGroup
+		for index := 0; index < 10; index++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, 0)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(0), count)
+			}()
+		}
+		wg.Wait()
+
+		// Reads do not create the table
+		assert.Empty(t, client.(*Client).options.memory.tables)
+	})
+}
+
+// Test_matchMemoryField will test the method matchMemoryField()
+func Test_matchMemoryField(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		value     interface{}
+		condition interface{}
+		expected  bool
+	}{
+		{"equal", "alice", "alice", true},
+		{"not equal", "alice", "bob", false},
+		{"numbers", int64(10), 10, true},
+		{"null", nil, nil, true},
+		{"null value", nil, "alice", false},
+		{"greater than", int64(10), map[string]interface{}{conditionGreaterThan: 5}, true},
+		{"less than", int64(10), map[string]interface{}{conditionLessThan: 5}, false},
+		{"in", "bob", map[string]interface{}{conditionIn: []string{"alice", "bob"}}, true},
+		{"exists", nil, map[string]interface{}{conditionExists: false}, true},
+		{"time", now, map[string]interface{}{conditionLessThanOrEqual: now.Add(time.Second)}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, matchMemoryField(test.value, test.condition))
+		})
+	}
+}
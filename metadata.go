@@ -0,0 +1,63 @@
+package datastore
+
+import "strings"
+
+// MetadataEquals will return the condition for a metadata key equal to the value
+//
+// SQL engines match the JSON (metadata) column, MongoDB matches the key / value pairs, combine several metadata
+// conditions using $and (IE: {"$and": []map[string]interface{}{MetadataEquals(...), MetadataExists(...)}})
+func MetadataEquals(key string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		metadataField: map[string]interface{}{key: value},
+	}
+}
+
+// MetadataExists will return the condition for a metadata key being set (any value)
+func MetadataExists(key string) map[string]interface{} {
+	return map[string]interface{}{
+		metadataField: map[string]interface{}{
+			key: map[string]interface{}{conditionExists: true},
+		},
+	}
+}
+
+// MetadataIn will return the condition for a metadata key equal to one of the values (no values match no records)
+func MetadataIn(key string, values ...interface{}) map[string]interface{} {
+	if values == nil {
+		values = []interface{}{}
+	}
+	return map[string]interface{}{
+		metadataField: map[string]interface{}{
+			key: map[string]interface{}{conditionIn: values},
+		},
+	}
+}
+
+// getMetadataOperator will return the operator and operand of a metadata key condition ($exists or $in)
+func getMetadataOperator(condition interface{}) (string, interface{}, bool) {
+	operators, ok := condition.(map[string]interface{})
+	if !ok || len(operators) != 1 {
+		return "", nil, false
+	}
+	for _, operator := range []string{conditionExists, conditionIn} {
+		if operand, found := operators[operator]; found {
+			return operator, operand, true
+		}
+	}
+	return "", nil, false
+}
+
+// getObjectOperators will return the operators of an object key condition (IE: {"$gt": 5, "$lte": 10}),
+// all the keys must be operators
+func getObjectOperators(condition interface{}) (map[string]interface{}, bool) {
+	operators, ok := condition.(map[string]interface{})
+	if !ok || len(operators) == 0 {
+		return nil, false
+	}
+	for operator := range operators {
+		if !strings.HasPrefix(operator, "$") {
+			return nil, false
+		}
+	}
+	return operators, true
+}
@@ -0,0 +1,122 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetadataConditions will test the methods MetadataEquals(), MetadataExists() and MetadataIn()
+func TestMetadataConditions(t *testing.T) {
+	t.Run("condition structure", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{
+			metadataField: map[string]interface{}{"plan": "pro"},
+		}, MetadataEquals("plan", "pro"))
+
+		assert.Equal(t, map[string]interface{}{
+			metadataField: map[string]interface{}{"plan": map[string]interface{}{conditionExists: true}},
+		}, MetadataExists("plan"))
+
+		assert.Equal(t, map[string]interface{}{
+			metadataField: map[string]interface{}{"plan": map[string]interface{}{conditionIn: []interface{}{"pro", "team"}}},
+		}, MetadataIn("plan", "pro", "team"))
+
+		assert.Equal(t, map[string]interface{}{
+			metadataField: map[string]interface{}{"plan": map[string]interface{}{conditionIn: []interface{}{}}},
+		}, MetadataIn("plan"))
+	})
+
+	t.Run("sql", func(t *testing.T) {
+		query, vars := testWhereObject(SQLite, metadataField, MetadataEquals("plan", "pro")[metadataField])
+		assert.Equal(t, "JSON_EXTRACT(metadata, '$.plan') = @var0", query)
+		assert.Equal(t, map[string]interface{}{"var0": "pro"}, vars)
+
+		query, _ = testWhereObject(MySQL, metadataField, MetadataExists("plan")[metadataField])
+		assert.Equal(t, "JSON_EXTRACT(metadata, '$.plan') IS NOT NULL", query)
+
+		query, _ = testWhereObject(PostgreSQL, metadataField, MetadataExists("plan")[metadataField])
+		assert.Equal(t, "(metadata::jsonb -> 'plan') IS NOT NULL", query)
+
+		query, vars = testWhereObject(SQLite, metadataField, MetadataIn("plan", "pro", 2)[metadataField])
+		assert.Equal(t, "JSON_EXTRACT(metadata, '$.plan') IN (@var0, @var1)", query)
+		assert.Equal(t, map[string]interface{}{"var0": "pro", "var1": float64(2)}, vars)
+
+		query, vars = testWhereObject(PostgreSQL, metadataField, MetadataIn("plan", "pro", 2)[metadataField])
+		assert.Equal(t, "(metadata::jsonb @> @var0::jsonb OR metadata::jsonb @> @var1::jsonb)", query)
+		assert.Equal(t, map[string]interface{}{"var0": `{"plan":"pro"}`, "var1": `{"plan":2}`}, vars)
+
+		query, _ = testWhereObject(MySQL, metadataField, MetadataIn("plan")[metadataField])
+		assert.Equal(t, "1 = 0", query)
+	})
+
+	t.Run("quoted values (bound, not in the SQL)", func(t *testing.T) {
+		for _, engine := range []Engine{MySQL, PostgreSQL, SQLite} {
+			query, vars := testWhereObject(engine, metadataField, MetadataIn("owner", "o'brien", `x\' OR 1=1 --`)[metadataField])
+			assert.NotContains(t, query, "o'brien", engine)
+			assert.NotContains(t, query, "OR 1=1", engine)
+			assert.Len(t, vars, 2, engine)
+		}
+	})
+
+	t.Run("[sqlite] quoted values", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithAutoMigrate(&testJSONModel{}))
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testJSONModel{ID: "quoted-1", Metadata: `{"owner":"o'brien","seats":2}`},
+			&testJSONModel{ID: "quoted-2", Metadata: `{"owner":"smith","seats":3}`},
+		)
+
+		for _, conditions := range []map[string]interface{}{
+			MetadataEquals("owner", "o'brien"),
+			MetadataIn("owner", "o'brien", `x\' OR 1=1 --`),
+			{metadataField: map[string]interface{}{"owner": map[string]interface{}{conditionLessThanOrEqual: "o'brien"}}},
+		} {
+			var models []*testJSONModel
+			require.NoError(t, client.GetModels(ctx, &models, conditions, nil, nil, defaultDatabaseMaxTimeout))
+			require.Len(t, models, 1)
+			assert.Equal(t, "quoted-1", models[0].ID)
+		}
+
+		var models []*testJSONModel
+		err := client.GetModels(ctx, &models, MetadataEquals("owner", `x\' OR 1=1 --`), nil, nil,
+			defaultDatabaseMaxTimeout)
+		require.ErrorIs(t, err, ErrNoResults)
+	})
+
+	t.Run("mongo", func(t *testing.T) {
+		queryConditions := getMongoQueryConditions(nil, MetadataEquals("plan", "pro"), nil)
+		assert.Equal(t, map[string]interface{}{conditionAnd: []map[string]interface{}{{
+			metadataField + ".k": "plan",
+			metadataField + ".v": "pro",
+		}}}, queryConditions)
+
+		queryConditions = getMongoQueryConditions(nil, MetadataExists("plan"), nil)
+		assert.Equal(t, map[string]interface{}{conditionAnd: []map[string]interface{}{{
+			metadataField + ".k": "plan",
+		}}}, queryConditions)
+
+		queryConditions = getMongoQueryConditions(nil, MetadataIn("plan", "pro", "team"), nil)
+		assert.Equal(t, map[string]interface{}{conditionAnd: []map[string]interface{}{{
+			metadataField + ".k": "plan",
+			metadataField + ".v": map[string]interface{}{conditionIn: []interface{}{"pro", "team"}},
+		}}}, queryConditions)
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		stored := `{"plan":"pro","seats":2}`
+		assert.True(t, matchMemoryObject(stored, MetadataEquals("plan", "pro")[metadataField]))
+		assert.True(t, matchMemoryObject(stored, MetadataExists("seats")[metadataField]))
+		assert.False(t, matchMemoryObject(stored, MetadataExists("owner")[metadataField]))
+		assert.True(t, matchMemoryObject(stored, MetadataIn("seats", 1, 2)[metadataField]))
+		assert.False(t, matchMemoryObject(stored, MetadataIn("plan", "team")[metadataField]))
+		assert.True(t, matchMemoryObject(stored, map[string]interface{}{
+			"seats": map[string]interface{}{conditionGreaterThan: 1, conditionLessThanOrEqual: 2},
+		}))
+		assert.False(t, matchMemoryObject(stored, map[string]interface{}{
+			"seats": map[string]interface{}{conditionGreaterThan: 2},
+		}))
+	})
+}
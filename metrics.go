@@ -0,0 +1,103 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Metric settings
+const (
+	maxApproximateSizeDepth = 8            // Max depth when estimating result sizes (guards against cycles)
+	metricGetModel          = "get_model"  // Operation name for GetModel
+	metricGetModels         = "get_models" // Operation name for GetModels
+)
+
+// MetricsRecorder is the interface for reporting datastore metrics (IE: Prometheus, StatsD, NewRelic)
+type MetricsRecorder interface {
+	RecordResultSize(ctx context.Context, operation, tableName string, rows, approximateBytes int)
+}
+
+// recordResultSize will report the result size and warn if a GetModels result exceeds the threshold
+func (c *Client) recordResultSize(ctx context.Context, operation string, results interface{}) {
+	if c.options.metrics == nil && c.options.resultSizeWarning <= 0 {
+		return
+	}
+
+	// Count the rows and estimate the size
+	value := reflect.Indirect(reflect.ValueOf(results))
+	rows := 1
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		rows = value.Len()
+	}
+	size := getApproximateSize(value, 0)
+
+	tableName := ""
+	if name := GetModelTableName(results); name != nil {
+		tableName = *name
+	}
+
+	if c.options.metrics != nil {
+		c.options.metrics.RecordResultSize(ctx, operation, tableName, rows, size)
+	}
+
+	// Large results should be paginated
+	if operation == metricGetModels && c.options.resultSizeWarning > 0 &&
+		rows > c.options.resultSizeWarning {
+		c.warnLog(ctx, fmt.Sprintf(
+			"large result: %s returned %d rows (~%d bytes) from %s, exceeding the threshold of %d rows",
+			operation, rows, size, tableName, c.options.resultSizeWarning,
+		))
+	}
+}
+
+// getApproximateSize will return the approximate in-memory size (bytes) of the value
+func getApproximateSize(value reflect.Value, depth int) int {
+	if depth > maxApproximateSizeDepth {
+		return 0
+	}
+	depth++
+
+	switch value.Kind() { //nolint:exhaustive // all other kinds are a fixed size
+	case reflect.Invalid:
+		return 0
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return int(value.Type().Size())
+		}
+		return int(value.Type().Size()) + getApproximateSize(value.Elem(), depth)
+	case reflect.String:
+		return int(value.Type().Size()) + value.Len()
+	case reflect.Slice, reflect.Array:
+		size := 0
+		if value.Kind() == reflect.Slice {
+			size = int(value.Type().Size())
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return size + value.Len()
+		}
+		for i := 0; i < value.Len(); i++ {
+			size += getApproximateSize(value.Index(i), depth)
+		}
+		return size
+	case reflect.Map:
+		size := int(value.Type().Size())
+		iter := value.MapRange()
+		for iter.Next() {
+			size += getApproximateSize(iter.Key(), depth) + getApproximateSize(iter.Value(), depth)
+		}
+		return size
+	case reflect.Struct:
+		size := 0
+		for i := 0; i < value.NumField(); i++ {
+			if field := value.Type().Field(i); !field.IsExported() { // Internals are not followed (IE: time.Time)
+				size += int(field.Type.Size())
+			} else {
+				size += getApproximateSize(value.Field(i), depth)
+			}
+		}
+		return size
+	default:
+		return int(value.Type().Size())
+	}
+}
@@ -0,0 +1,105 @@
+package datastore
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	zLogger "github.com/mrz1836/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testResultSize is a recorded result size
+type testResultSize struct {
+	bytes     int
+	operation string
+	rows      int
+	tableName string
+}
+
+// testMetricsRecorder records all result sizes
+type testMetricsRecorder struct {
+	mu    sync.Mutex
+	sizes []testResultSize
+}
+
+// RecordResultSize will record the result size
+func (r *testMetricsRecorder) RecordResultSize(_ context.Context, operation, tableName string, rows, approximateBytes int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sizes = append(r.sizes, testResultSize{
+		bytes:     approximateBytes,
+		operation: operation,
+		rows:      rows,
+		tableName: tableName,
+	})
+}
+
+// TestClient_recordResultSize will test the method recordResultSize()
+func TestClient_recordResultSize(t *testing.T) {
+	records := []*testSQLModel{
+		{ID: "size-1", Name: "alice"},
+		{ID: "size-2", Name: "bob"},
+		{ID: "size-3", Name: "carol"},
+	}
+
+	t.Run("result sizes are recorded", func(t *testing.T) {
+		ctx := context.Background()
+		recorder := &testMetricsRecorder{}
+		client, deferFunc := testSQLiteClient(ctx, t, WithMetrics(recorder))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(ctx, &models, nil, nil, nil, defaultDatabaseMaxTimeout))
+		require.NoError(t, client.GetModel(ctx, &testSQLModel{}, map[string]interface{}{sqlIDField: "size-1"}, defaultDatabaseMaxTimeout, false))
+
+		require.Len(t, recorder.sizes, 2)
+		assert.Equal(t, metricGetModels, recorder.sizes[0].operation)
+		assert.Equal(t, testSQLTableName, recorder.sizes[0].tableName)
+		assert.Equal(t, 3, recorder.sizes[0].rows)
+		assert.Positive(t, recorder.sizes[0].bytes)
+		assert.Equal(t, metricGetModel, recorder.sizes[1].operation)
+		assert.Equal(t, 1, recorder.sizes[1].rows)
+		assert.Less(t, recorder.sizes[1].bytes, recorder.sizes[0].bytes)
+	})
+
+	t.Run("large results are logged", func(t *testing.T) {
+		ctx := context.Background()
+		l := &testWarnLogger{GormLoggerInterface: zLogger.NewGormLogger(false, 4)}
+		client, deferFunc := testSQLiteClient(ctx, t, WithLogger(l), WithResultSizeWarning(2))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, records...)
+
+		var models []*testSQLModel
+		require.NoError(t, client.GetModels(ctx, &models, nil, nil, nil, defaultDatabaseMaxTimeout))
+		require.Len(t, l.warnings, 1)
+		assert.Contains(t, l.warnings[0], "returned 3 rows")
+
+		models = nil
+		require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{"name": "bob"}, nil, nil, defaultDatabaseMaxTimeout))
+		assert.Len(t, l.warnings, 1)
+	})
+}
+
+// TestGetApproximateSize will test the method getApproximateSize()
+func TestGetApproximateSize(t *testing.T) {
+	assert.Equal(t, 0, getApproximateSize(reflect.Value{}, 0))
+	assert.Equal(t, 8, getApproximateSize(reflect.ValueOf(int64(1)), 0))
+	assert.Equal(t, 16+5, getApproximateSize(reflect.ValueOf("hello"), 0))
+	assert.Equal(t, 24+3, getApproximateSize(reflect.ValueOf([]byte("abc")), 0))
+
+	short := getApproximateSize(reflect.ValueOf(&testSQLModel{Name: "a"}), 0)
+	long := getApproximateSize(reflect.ValueOf(&testSQLModel{Name: "a much longer name"}), 0)
+	assert.Equal(t, 17, long-short)
+
+	// Cycles are not followed forever
+	type node struct {
+		Next *node
+	}
+	n := &node{}
+	n.Next = n
+	assert.Positive(t, getApproximateSize(reflect.ValueOf(n), 0))
+}
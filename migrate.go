@@ -2,8 +2,11 @@ package datastore
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/newrelic/go-agent/v3/newrelic"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -25,7 +28,9 @@ func (c *Client) AutoMigrateDatabase(ctx context.Context, models ...interface{})
 	if c.Engine() != MySQL &&
 		c.Engine() != PostgreSQL &&
 		c.Engine() != SQLite &&
-		c.Engine() != MongoDB {
+		c.Engine() != MongoDB &&
+		c.Engine() != Memory &&
+		c.Engine() != DynamoDB {
 		return ErrUnsupportedEngine
 	}
 
@@ -46,15 +51,93 @@ func (c *Client) AutoMigrateDatabase(ctx context.Context, models ...interface{})
 		c.options.migratedModels,
 	))
 
+	// Memory tables are created on the first write, DynamoDB tables are provisioned outside the datastore
+	if c.Engine() == Memory || c.Engine() == DynamoDB {
+		return nil
+	}
+
 	// Migrate database for Mongo
 	if c.Engine() == MongoDB {
 		return autoMigrateMongoDatabase(ctx, c.Engine(), c.options, models...)
 	}
 
+	// Create the partitioned tables for time-series models
+	if err := c.createTimeSeriesTables(ctx, models...); err != nil {
+		return err
+	}
+
+	// Delegate the schema changes of large MySQL tables (IE: gh-ost or pt-online-schema-change)
+	if c.Engine() == MySQL && c.options.schemaChanges != nil {
+		return c.migrateWithSchemaChanges(ctx, models...)
+	}
+
 	// Migrate database for SQL (using GORM)
 	return autoMigrateSQLDatabase(ctx, c.Engine(), c.options.db, c.IsDebug(), c.options.loggerDB, models...)
 }
 
+// GenerateMigrationSQL will return the DDL statements that AutoMigrateDatabase would run for the models (SQL only)
+//
+// The schema is read from the database, but no statements are executed (IE: for DBA review in change-controlled
+// environments). The partitioned tables of time-series models are not included.
+func (c *Client) GenerateMigrationSQL(ctx context.Context, models ...interface{}) (string, error) {
+	if !IsSQLEngine(c.Engine()) {
+		return "", ErrUnsupportedEngine
+	}
+
+	statements, err := c.planMigration(ctx, models...)
+	if err != nil || len(statements) == 0 {
+		return "", err
+	}
+	return strings.Join(statements, ";\n") + ";\n", nil
+}
+
+// planMigration will return the DDL statements that GORM would run to migrate the models (nothing is executed)
+func (c *Client) planMigration(ctx context.Context, models ...interface{}) ([]string, error) {
+
+	// Capture the statements instead of executing them
+	capture := &ddlCapturePool{ConnPool: c.options.db.Statement.ConnPool, dialector: c.options.db.Dialector}
+	sessionDb := c.options.db.Session(&gorm.Session{
+		Context: ctx,
+		Logger:  c.options.db.Logger.LogMode(logger.Silent),
+		NewDB:   true,
+	})
+	sessionDb.Statement.ConnPool = capture
+
+	// Run the auto migrate method (same settings as autoMigrateSQLDatabase)
+	if c.Engine() == MySQL {
+		sessionDb = sessionDb.Set("gorm:table_options", "ENGINE=InnoDB")
+	}
+	if err := sessionDb.AutoMigrate(models...); err != nil {
+		return nil, err
+	}
+	return capture.statements, nil
+}
+
+// ddlCapturePool is a connection pool that records the executed statements without running them (reads are allowed)
+//
+// It acts as a transaction (TxCommitter) so the dbresolver plugin does not switch the connection pool
+type ddlCapturePool struct {
+	gorm.ConnPool
+	dialector  gorm.Dialector // Used to inline the statement arguments
+	statements []string       // Captured statements
+}
+
+// ExecContext will record the statement (nothing is executed)
+func (p *ddlCapturePool) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	p.statements = append(p.statements, p.dialector.Explain(query, args...))
+	return driver.RowsAffected(0), nil
+}
+
+// Commit is a no-op (see: gorm.TxCommitter)
+func (p *ddlCapturePool) Commit() error {
+	return nil
+}
+
+// Rollback is a no-op (see: gorm.TxCommitter)
+func (p *ddlCapturePool) Rollback() error {
+	return nil
+}
+
 // IsAutoMigrate returns whether auto migration is on
 func (c *Client) IsAutoMigrate() bool {
 	return c.options.autoMigrate
@@ -62,9 +145,13 @@ func (c *Client) IsAutoMigrate() bool {
 
 // autoMigrateMongoDatabase will start a new database for Mongo
 func autoMigrateMongoDatabase(ctx context.Context, _ Engine, options *clientOptions,
-	_ ...interface{}) error {
+	models ...interface{}) error {
 
-	var err error
+	// Create the time-series collections
+	err := createTimeSeriesCollections(ctx, options, models...)
+	if err != nil {
+		return err
+	}
 
 	if options.fields.customMongoIndexer != nil {
 		for collectionName, idx := range options.fields.customMongoIndexer() {
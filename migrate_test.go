@@ -1 +1,42 @@
 package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_GenerateMigrationSQL will test the method GenerateMigrationSQL()
+func TestClient_GenerateMigrationSQL(t *testing.T) {
+	t.Run("new table", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		script, err := client.GenerateMigrationSQL(ctx, &testSoftDeleteModel{})
+		require.NoError(t, err)
+		assert.Contains(t, script, "CREATE TABLE `test_soft_delete_models`")
+		assert.Contains(t, script, "`deleted_at` datetime")
+
+		// Nothing was executed
+		assert.False(t, client.(*Client).options.db.Migrator().HasTable(&testSoftDeleteModel{}))
+	})
+
+	t.Run("already migrated", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		script, err := client.GenerateMigrationSQL(ctx, &testSQLModel{})
+		require.NoError(t, err)
+		assert.Empty(t, script)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: MongoDB}}
+		_, err := client.GenerateMigrationSQL(context.Background(), &testSQLModel{})
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,7 +34,30 @@ func (c *Client) SaveModel(
 			// set the context to the session context -> mongo transaction
 			sessionContext = *tx.mongoTx
 		}
-		return c.saveWithMongo(sessionContext, model, newRecord)
+		start := time.Now()
+		rows, err := c.saveWithMongo(sessionContext, model, newRecord)
+		if err != nil {
+			return newMongoQueryError("save", model, nil, start, err)
+		}
+		tx.addRowsAffected(rows)
+		c.queueModelEvent(ctx, tx, getSaveModelEvent(newRecord), model)
+		return nil
+	} else if c.Engine() == Memory {
+		rows, err := c.saveWithMemory(ctx, model, newRecord)
+		if err != nil {
+			return err
+		}
+		tx.addRowsAffected(rows)
+		c.queueModelEvent(ctx, tx, getSaveModelEvent(newRecord), model)
+		return nil
+	} else if c.Engine() == DynamoDB {
+		rows, err := c.saveWithDynamoDB(ctx, model, newRecord)
+		if err != nil {
+			return err
+		}
+		tx.addRowsAffected(rows)
+		c.queueModelEvent(ctx, tx, getSaveModelEvent(newRecord), model)
+		return nil
 	} else if !IsSQLEngine(c.Engine()) {
 		return ErrUnsupportedEngine
 	}
@@ -51,20 +75,28 @@ func (c *Client) SaveModel(
 	if err := tx.sqlTx.Error; err != nil {
 		return err
 	}
+	end, err := tx.watchdog.begin()
+	if err != nil {
+		return err
+	}
+	defer end()
 
 	// Create vs Update
+	var result *gorm.DB
 	if newRecord {
-		if err := tx.sqlTx.Omit(clause.Associations).Create(model).Error; err != nil {
-			_ = tx.Rollback()
+		if result = c.setQueryComment(ctx, tx.sqlTx).Omit(clause.Associations).Create(model); result.Error != nil {
+			_ = tx.rollbackFailed()
 			// todo add duplicate key check for MySQL, Postgres and SQLite
-			return err
+			return result.Error
 		}
 	} else {
-		if err := tx.sqlTx.Omit(clause.Associations).Save(model).Error; err != nil {
-			_ = tx.Rollback()
-			return err
+		if result = c.setQueryComment(ctx, tx.sqlTx).Omit(clause.Associations).Save(model); result.Error != nil {
+			_ = tx.rollbackFailed()
+			return result.Error
 		}
 	}
+	tx.addRowsAffected(result.RowsAffected)
+	c.queueModelEvent(ctx, tx, getSaveModelEvent(newRecord), model)
 
 	// Commit & check for errors
 	if commitTx {
@@ -77,16 +109,430 @@ func (c *Client) SaveModel(
 	return nil
 }
 
+// SaveModelAuto will create or update a model (primary key based) using its own transaction
+//
+// The transaction is committed, or rolled back on any error (SQL, MongoDB or no transaction engines)
+func (c *Client) SaveModelAuto(
+	ctx context.Context,
+	model interface{},
+	newRecord bool,
+) error {
+	if c.Engine() != MongoDB && c.Engine() != Memory && c.Engine() != DynamoDB && !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	return c.NewTx(ctx, func(tx *Transaction) error {
+		return c.SaveModel(ctx, model, tx, newRecord, true)
+	})
+}
+
+// SaveModels will create or update the models in the transaction (the caller commits the transaction)
+//
+// New records are created using batched INSERTs (consecutive models of the same type), existing records are
+// updated one by one. Returns nil if all the models were saved, otherwise an error per model (same order):
+// the models after a failure are not saved (ErrModelNotSaved) and the transaction is rolled back
+func (c *Client) SaveModels(
+	ctx context.Context,
+	models []interface{},
+	tx *Transaction,
+	newRecord bool,
+) []error {
+	if len(models) == 0 {
+		return nil
+	}
+
+	// MongoDB (saved one by one in the session)
+	if c.Engine() == MongoDB {
+		sessionContext := ctx //nolint:contextcheck // we need to overwrite the ctx for transaction support
+		if tx.mongoTx != nil {
+			// set the context to the session context -> mongo transaction
+			sessionContext = *tx.mongoTx
+		}
+		for index, model := range models {
+			start := time.Now()
+			rows, err := c.saveWithMongo(sessionContext, model, newRecord)
+			if err != nil {
+				return getSaveModelsErrors(len(models), index, index+1,
+					newMongoQueryError("save", model, nil, start, err))
+			}
+			tx.addRowsAffected(rows)
+			c.queueModelEvent(ctx, tx, getSaveModelEvent(newRecord), model)
+		}
+		return nil
+	} else if c.Engine() == Memory { // Saved one by one (applied right away)
+		for index, model := range models {
+			rows, err := c.saveWithMemory(ctx, model, newRecord)
+			if err != nil {
+				return getSaveModelsErrors(len(models), index, index+1, err)
+			}
+			tx.addRowsAffected(rows)
+			c.queueModelEvent(ctx, tx, getSaveModelEvent(newRecord), model)
+		}
+		return nil
+	} else if !IsSQLEngine(c.Engine()) {
+		return getSaveModelsErrors(len(models), 0, len(models), ErrUnsupportedEngine)
+	}
+
+	// Set the NewRelic txn
+	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+	if err := tx.sqlTx.Error; err != nil {
+		return getSaveModelsErrors(len(models), 0, len(models), err)
+	}
+	end, err := tx.watchdog.begin()
+	if err != nil {
+		return getSaveModelsErrors(len(models), 0, len(models), err)
+	}
+	defer end()
+
+	// Update the records one by one
+	if !newRecord {
+		for index, model := range models {
+			result := c.setQueryComment(ctx, tx.sqlTx).Omit(clause.Associations).Save(model)
+			if result.Error != nil {
+				_ = tx.rollbackFailed()
+				return getSaveModelsErrors(len(models), index, index+1, result.Error)
+			}
+			tx.addRowsAffected(result.RowsAffected)
+			c.queueModelEvent(ctx, tx, EventUpdated, model)
+		}
+		return nil
+	}
+
+	// Create the records using a batched insert (per run of the same model type)
+	for start := 0; start < len(models); {
+		modelType := reflect.TypeOf(models[start])
+		end := start + 1
+		for end < len(models) && reflect.TypeOf(models[end]) == modelType {
+			end++
+		}
+		batch := reflect.MakeSlice(reflect.SliceOf(modelType), 0, end-start)
+		for _, model := range models[start:end] {
+			batch = reflect.Append(batch, reflect.ValueOf(model))
+		}
+		result := c.setQueryComment(ctx, tx.sqlTx).Omit(clause.Associations).CreateInBatches(batch.Interface(), defaultSaveModelsBatchSize)
+		if result.Error != nil {
+			_ = tx.rollbackFailed()
+			return getSaveModelsErrors(len(models), start, end, result.Error)
+		}
+		tx.addRowsAffected(result.RowsAffected)
+		for _, model := range models[start:end] {
+			c.queueModelEvent(ctx, tx, EventCreated, model)
+		}
+		start = end
+	}
+	return nil
+}
+
+// getSaveModelEvent will return the model event of a save (created or updated)
+func getSaveModelEvent(newRecord bool) ModelEvent {
+	if newRecord {
+		return EventCreated
+	}
+	return EventUpdated
+}
+
+// getSaveModelsErrors will return the errors per model, the failed models (from start to end) get the error and
+// the models after them ErrModelNotSaved
+func getSaveModelsErrors(count, start, end int, err error) []error {
+	errs := make([]error, count)
+	for index := start; index < count; index++ {
+		if index < end {
+			errs[index] = err
+		} else {
+			errs[index] = ErrModelNotSaved
+		}
+	}
+	return errs
+}
+
+// UpdateModelFields will update only the given fields (column: value) of the model (primary key based)
+//
+// Unlike SaveModel, the untouched columns are not rewritten (SQL: UPDATE of the named columns, MongoDB: $set)
+func (c *Client) UpdateModelFields(
+	ctx context.Context,
+	model interface{},
+	fields map[string]interface{},
+	tx *Transaction,
+	commitTx bool,
+) error {
+	if len(fields) == 0 {
+		return ErrNoFieldsToUpdate
+	}
+
+	// MongoDB (does not support transactions at this time)
+	if c.Engine() == MongoDB {
+		sessionContext := ctx //nolint:contextcheck // we need to overwrite the ctx for transaction support
+		if tx.mongoTx != nil {
+			// set the context to the session context -> mongo transaction
+			sessionContext = *tx.mongoTx
+		}
+		start := time.Now()
+		rows, err := c.updateFieldsWithMongo(sessionContext, model, fields)
+		if err != nil {
+			return newMongoQueryError("update", model, nil, start, err)
+		}
+		tx.addRowsAffected(rows)
+		c.queueModelUpdate(ctx, tx, model)
+		return nil
+	} else if c.Engine() == Memory {
+		rows, err := c.updateFieldsWithMemory(ctx, model, fields)
+		if err != nil {
+			return err
+		}
+		tx.addRowsAffected(rows)
+		c.queueModelUpdate(ctx, tx, model)
+		return nil
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	// Set the NewRelic txn
+	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+	// Capture any panics
+	defer func() {
+		if r := recover(); r != nil {
+			c.DebugLog(context.Background(), fmt.Sprintf("panic recovered: %v", r))
+			_ = tx.Rollback()
+		}
+	}()
+	if err := tx.sqlTx.Error; err != nil {
+		return err
+	}
+	end, err := tx.watchdog.begin()
+	if err != nil {
+		return err
+	}
+	defer end()
+
+	// Get the primary key of the model
+	primaryKey, err := c.getModelPrimaryKey(model)
+	if err != nil {
+		return err
+	}
+
+	// Update the fields
+	result := c.setQueryComment(ctx, tx.sqlTx).Model(model).Omit(clause.Associations).Where(primaryKey).Updates(fields)
+	if err = result.Error; err != nil {
+		_ = tx.rollbackFailed()
+		return err
+	}
+	tx.addRowsAffected(result.RowsAffected)
+	c.queueModelUpdate(ctx, tx, model)
+
+	// Commit & check for errors
+	if commitTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpsertModel will insert the model, or update the given columns if it conflicts with an existing record
+//
+// SQL: INSERT ... ON CONFLICT DO UPDATE (ON DUPLICATE KEY UPDATE for MySQL), MongoDB: updateOne with upsert
+// No conflict columns uses the primary key, no update columns updates all (non-conflict) columns
+//
+// Runs the EventUpdated handlers (the record may have been inserted), see: SubscribeModelEvents
+func (c *Client) UpsertModel(
+	ctx context.Context,
+	model interface{},
+	conflictColumns []string,
+	updateColumns []string,
+) (err error) {
+	for _, column := range append(append([]string{}, conflictColumns...), updateColumns...) {
+		if !indexNamePattern.MatchString(column) {
+			return ErrInvalidUpsertColumn
+		}
+	}
+
+	// Run the update event (and invalidate the cached reads) after the upsert
+	defer func() {
+		if err == nil {
+			c.emitModelUpdate(ctx, model)
+		}
+	}()
+
+	if c.Engine() == MongoDB {
+		start := time.Now()
+		return newMongoQueryError("upsert", model, nil, start,
+			c.upsertWithMongo(ctx, model, conflictColumns, updateColumns))
+	} else if c.Engine() == Memory {
+		return c.upsertWithMemory(ctx, model, conflictColumns, updateColumns)
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	// Set the NewRelic txn
+	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+	onConflict := clause.OnConflict{UpdateAll: len(updateColumns) == 0}
+	for _, column := range conflictColumns {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+	if len(updateColumns) > 0 {
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns)
+	}
+
+	return c.options.db.WithContext(ctx).Omit(clause.Associations).Clauses(onConflict).Create(model).Error
+}
+
+// FindOrCreateModel will find the model using the conditions, or create it (atomically) if missing
+//
+// The model is set to the existing record when found, otherwise the model (which should contain the
+// condition values) is created, returns true if the model was created
+// SQL engines use a single INSERT (ON CONFLICT DO NOTHING) and find the record again if another
+// caller created it first, MongoDB uses a single upsert ($setOnInsert)
+func (c *Client) FindOrCreateModel(
+	ctx context.Context,
+	model interface{},
+	conditions map[string]interface{},
+	tx *Transaction,
+) (created bool, err error) {
+	if len(conditions) == 0 {
+		return false, ErrMissingConditions
+	}
+
+	// Run the create event (and invalidate the cached reads) after the transaction is committed (if given)
+	defer func() {
+		if err != nil || !created {
+			return
+		} else if tx != nil {
+			c.queueModelEvent(ctx, tx, EventCreated, model)
+			return
+		}
+		c.emitModelEvent(ctx, EventCreated, model)
+	}()
+
+	// Exclude the soft-deleted records
+	conditions = c.getSoftDeleteConditions(ctx, model, conditions)
+
+	// MongoDB (does not support transactions at this time)
+	if c.Engine() == MongoDB {
+		sessionContext := ctx //nolint:contextcheck // we need to overwrite the ctx for transaction support
+		if tx != nil && tx.mongoTx != nil {
+			// set the context to the session context -> mongo transaction
+			sessionContext = *tx.mongoTx
+		}
+		start := time.Now()
+		created, err = c.findOrCreateWithMongo(sessionContext, model, conditions)
+		return created, newMongoQueryError("findOrCreate", model, conditions, start, err)
+	} else if c.Engine() == Memory {
+		return c.findOrCreateWithMemory(ctx, model, conditions)
+	} else if !IsSQLEngine(c.Engine()) {
+		return false, ErrUnsupportedEngine
+	}
+
+	// Set the NewRelic txn
+	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+	// Use the transaction (if given)
+	db := c.options.db.WithContext(ctx)
+	if tx != nil && tx.sqlTx != nil {
+		if err = tx.sqlTx.Error; err != nil {
+			return false, err
+		}
+		var end func()
+		if end, err = tx.watchdog.begin(); err != nil {
+			return false, err
+		}
+		defer end()
+		db = tx.sqlTx.WithContext(ctx)
+	}
+
+	// Find using a new model (the primary key of the given model is not a condition)
+	find := func() error {
+		found := reflect.New(reflect.TypeOf(model).Elem()).Interface()
+		query := db.Model(found)
+		gtx := gormWhere{tx: query}
+		query = c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB)
+		if findErr := query.First(found).Error; findErr != nil {
+			return findErr
+		}
+		reflect.ValueOf(model).Elem().Set(reflect.ValueOf(found).Elem())
+		return nil
+	}
+	if err = find(); err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	// Create (a conflict means another caller created the record first)
+	result := db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+	if result.Error != nil {
+		return false, result.Error
+	} else if result.RowsAffected > 0 {
+		return true, nil
+	}
+	if err = find(); errors.Is(err, gorm.ErrRecordNotFound) {
+		// The conflict was on a key that does not match the conditions
+		return false, ErrDuplicateKey
+	}
+	return false, err
+}
+
 // IncrementModel will increment the given field atomically in the database and return the new value
+//
+// Use a negative increment to decrement (see: DecrementModel), the column must be an integer column
 func (c *Client) IncrementModel(
 	ctx context.Context,
 	model interface{},
 	fieldName string,
 	increment int64,
 ) (newValue int64, err error) {
+	return incrementModel(ctx, c, model, fieldName, increment, convertToInt64)
+}
+
+// DecrementModel will decrement the given field atomically in the database and return the new value
+func (c *Client) DecrementModel(
+	ctx context.Context,
+	model interface{},
+	fieldName string,
+	decrement int64,
+) (newValue int64, err error) {
+	return c.IncrementModel(ctx, model, fieldName, -decrement)
+}
+
+// IncrementModelFloat will increment the given float field atomically in the database and return the new value
+//
+// Use a negative increment to decrement
+func (c *Client) IncrementModelFloat(
+	ctx context.Context,
+	model interface{},
+	fieldName string,
+	increment float64,
+) (newValue float64, err error) {
+	return incrementModel(ctx, c, model, fieldName, increment, convertToFloat64)
+}
+
+// incrementModel will increment the given field atomically (converting the current value of the column)
+func incrementModel[T int64 | float64](
+	ctx context.Context,
+	c *Client,
+	model interface{},
+	fieldName string,
+	increment T,
+	convert func(value interface{}) (T, error),
+) (newValue T, err error) {
+
+	// Run the update event (and invalidate the cached reads) after the increment (see: SubscribeModelEvents)
+	defer func() {
+		if err == nil {
+			c.emitModelUpdate(ctx, model)
+		}
+	}()
 
 	if c.Engine() == MongoDB {
-		return c.incrementWithMongo(ctx, model, fieldName, increment)
+		start := time.Now()
+		var value interface{}
+		if value, err = c.incrementWithMongo(ctx, model, fieldName, increment); err == nil {
+			newValue, err = convertIncrementValue(fieldName, value, convert)
+		}
+		return newValue, newMongoQueryError("increment", model, nil, start, err)
+	} else if c.Engine() == Memory {
+		return incrementWithMemory(ctx, c, model, fieldName, increment, convert)
 	} else if !IsSQLEngine(c.Engine()) {
 		return 0, ErrUnsupportedEngine
 	}
@@ -97,15 +543,15 @@ func (c *Client) IncrementModel(
 	// Create a new transaction
 	if err = c.options.db.Transaction(func(tx *gorm.DB) error {
 
-		// Get the id of the model
-		id := GetModelStringAttribute(model, sqlIDFieldProper)
-		if id == nil {
-			return errors.New("model is missing an " + sqlIDFieldProper + " field")
+		// Get the primary key of the model
+		primaryKey, pkErr := c.getModelPrimaryKey(model)
+		if pkErr != nil {
+			return pkErr
 		}
 
 		// Get model if exist
 		var result map[string]interface{}
-		if err = tx.Model(&model).Clauses(clause.Locking{Strength: "UPDATE"}).Where(sqlIDField+" = ?", id).First(&result).Error; err != nil {
+		if err = tx.Model(&model).Clauses(clause.Locking{Strength: "UPDATE"}).Where(primaryKey).First(&result).Error; err != nil {
 			return err
 		}
 
@@ -115,8 +561,12 @@ func (c *Client) IncrementModel(
 		}
 
 		// Increment Counter
-		newValue = convertToInt64(result[fieldName]) + increment
-		return tx.Model(&model).Where(sqlIDField+" = ?", id).Update(fieldName, newValue).Error
+		current, convertErr := convertIncrementValue(fieldName, result[fieldName], convert)
+		if convertErr != nil {
+			return convertErr
+		}
+		newValue = current + increment
+		return tx.Model(&model).Where(primaryKey).Update(fieldName, newValue).Error
 	}); err != nil {
 		return
 	}
@@ -125,33 +575,129 @@ func (c *Client) IncrementModel(
 }
 
 // CreateInBatches create all the models given in batches
+//
+// See WithUnorderedBatch() and WithSkipDuplicates() for controlling partial failures
+// Runs the EventCreated handlers for each model (including the skipped duplicates), see: SubscribeModelEvents
 func (c *Client) CreateInBatches(
 	ctx context.Context,
 	models interface{},
 	batchSize int,
+	opts ...BatchOps,
+) error {
+	if err := c.createInBatches(ctx, models, batchSize, opts...); err != nil {
+		// A partial failure may have created some of the models
+		c.invalidateCache(ctx, models)
+		return err
+	}
+	c.emitModelEvents(ctx, EventCreated, models)
+	return nil
+}
+
+// createInBatches will create all the models given in batches (using the engine)
+func (c *Client) createInBatches(
+	ctx context.Context,
+	models interface{},
+	batchSize int,
+	opts ...BatchOps,
 ) error {
 	if c.Engine() == MongoDB {
-		return c.CreateInBatchesMongo(ctx, models, batchSize)
+		return c.CreateInBatchesMongo(ctx, models, batchSize, opts...)
+	} else if c.Engine() == Memory {
+		return c.createInBatchesWithMemory(ctx, models, opts...)
+	} else if c.Engine() == DynamoDB {
+		return c.createInBatchesWithDynamoDB(ctx, models, batchSize, opts...)
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
 	}
 
-	tx := c.options.db.CreateInBatches(models, batchSize)
-	return tx.Error
+	db := c.options.db
+	if getBatchOptions(opts...).skipDuplicates {
+		db = db.Clauses(clause.OnConflict{DoNothing: true})
+	}
+	tx := db.CreateInBatches(models, batchSize)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	c.analyzeAfterBulkLoad(ctx, models, tx.RowsAffected)
+	return nil
+}
+
+// convertIncrementValue will convert the value of the column (NULL is zero)
+func convertIncrementValue[T int64 | float64](fieldName string, value interface{},
+	convert func(value interface{}) (T, error),
+) (T, error) {
+	if value == nil {
+		return 0, nil
+	}
+	converted, err := convert(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", err, fieldName)
+	}
+	return converted, nil
 }
 
 // convertToInt64 will convert an interface to an int64
-func convertToInt64(i interface{}) int64 {
+func convertToInt64(i interface{}) (int64, error) {
 	switch v := i.(type) {
+	case int64:
+		return v, nil
 	case int:
-		return int64(v)
+		return int64(v), nil
 	case int32:
-		return int64(v)
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
 	case uint32:
-		return int64(v)
+		return int64(v), nil
 	case uint64:
-		return int64(v) //nolint:gosec // for huge numbers this could be a problem
+		return int64(v), nil //nolint:gosec // for huge numbers this could be a problem
+	case []byte:
+		return parseIncrementInt(string(v))
+	case string:
+		return parseIncrementInt(v)
 	}
 
-	return i.(int64)
+	return 0, fmt.Errorf("%w: %T", ErrUnsupportedIncrementType, i)
+}
+
+// parseIncrementInt will parse an integer returned as text (IE: MySQL)
+func parseIncrementInt(text string) (int64, error) {
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrUnsupportedIncrementType, text)
+	}
+	return value, nil
+}
+
+// convertToFloat64 will convert an interface to a float64 (integers are converted)
+func convertToFloat64(i interface{}) (float64, error) {
+	switch v := i.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case []byte:
+		return parseIncrementFloat(string(v))
+	case string:
+		return parseIncrementFloat(v)
+	}
+
+	value, err := convertToInt64(i)
+	if err != nil {
+		return 0, err
+	}
+	return float64(value), nil
+}
+
+// parseIncrementFloat will parse a float returned as text (IE: MySQL decimal)
+func parseIncrementFloat(text string) (float64, error) {
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrUnsupportedIncrementType, text)
+	}
+	return value, nil
 }
 
 type gormWhere struct {
@@ -177,9 +723,45 @@ func (c *Client) GetModel(
 	forceWriteDB bool,
 ) error {
 
+	// Exclude the soft-deleted records
+	conditions = c.getSoftDeleteConditions(ctx, model, conditions)
+
+	// Normalize the conditions (see: WithConditionNormalization)
+	var err error
+	if conditions, err = c.normalizeQueryConditions(conditions); err != nil {
+		return err
+	}
+
+	// Serve from the cache (see: WithCache)
+	cacheKey := c.getCacheKey(ctx, cacheOpGetModel, model, conditions, forceWriteDB)
+	if c.loadCache(ctx, cacheKey, model) {
+		c.recordResultSize(ctx, metricGetModel, model)
+		return c.mapResults(ctx, model)
+	}
+
 	// Switch on the datastore engines
 	if c.Engine() == MongoDB { // Get using Mongo
-		return c.getWithMongo(ctx, model, conditions, nil, nil)
+		start := time.Now()
+		if err := c.getWithMongo(ctx, model, conditions, nil, nil); err != nil {
+			return newMongoQueryError("find", model, conditions, start, err)
+		}
+		c.storeCache(ctx, cacheKey, model)
+		c.recordResultSize(ctx, metricGetModel, model)
+		c.maskResults(ctx, model, model)
+		return c.mapResults(ctx, model)
+	} else if c.Engine() == Memory || c.Engine() == DynamoDB {
+		if c.Engine() == Memory {
+			err = c.getWithMemory(ctx, model, conditions, nil, nil, nil)
+		} else {
+			err = c.getWithDynamoDB(ctx, model, conditions, nil, nil, nil)
+		}
+		if err != nil {
+			return err
+		}
+		c.storeCache(ctx, cacheKey, model)
+		c.recordResultSize(ctx, metricGetModel, model)
+		c.maskResults(ctx, model, model)
+		return c.mapResults(ctx, model)
 	} else if !IsSQLEngine(c.Engine()) {
 		return ErrUnsupportedEngine
 	}
@@ -188,29 +770,47 @@ func (c *Client) GetModel(
 	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
 
 	// Create a new context, and new db tx
-	ctxDB, cancel := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+	ctxDB, cancel, err := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
-	// Get the model data using a select
+	// Get the model data using a select (masked readers select the masked expressions)
 	// todo: optimize by specific fields
+	var selection interface{} = "*"
+	if masked := c.getMaskedColumns(ctx, model); len(masked) > 0 {
+		selection = masked
+	}
 	var tx *gorm.DB
-	if forceWriteDB { // Use the "write" database for this query (Only MySQL and Postgres)
+	if forceWriteDB || isCausalSession(ctx) { // Use the "write" database for this query (Only MySQL and Postgres)
 		if c.Engine() == MySQL || c.Engine() == PostgreSQL {
-			tx = ctxDB.Clauses(dbresolver.Write).Select("*")
+			tx = ctxDB.Clauses(dbresolver.Write).Select(selection)
 		} else {
-			tx = ctxDB.Select("*")
+			tx = ctxDB.Select(selection)
 		}
 	} else { // Use a replica if found
-		tx = ctxDB.Select("*")
+		tx = ctxDB.Select(selection)
+	}
+
+	// Lock the rows
+	if tx, err = c.applyRowLock(ctx, tx); err != nil {
+		return err
 	}
 
 	// Add conditions
 	if len(conditions) > 0 {
 		gtx := gormWhere{tx: tx}
-		return checkResult(c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB).Find(model))
+		tx = c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB)
 	}
 
-	return checkResult(tx.Find(model))
+	if err = checkResult(tx.Find(model)); err != nil {
+		return err
+	}
+	c.storeCache(ctx, cacheKey, model)
+	c.recordResultSize(ctx, metricGetModel, model)
+	c.maskResults(ctx, model, model)
+	return c.mapResults(ctx, model)
 }
 
 // GetModels will return a slice of models based on the given conditions
@@ -222,11 +822,27 @@ func (c *Client) GetModels(
 	fieldResults interface{},
 	timeout time.Duration,
 ) error {
+	return c.getModels(ctx, models, conditions, queryParams, fieldResults, timeout, nil)
+}
+
+// getModels will get the models (see: GetModels) and the total count of matching records (if total is set)
+func (c *Client) getModels(
+	ctx context.Context,
+	models interface{},
+	conditions map[string]interface{},
+	queryParams *QueryParams,
+	fieldResults interface{},
+	timeout time.Duration,
+	total *int64,
+) error {
 
 	if queryParams == nil {
 		// init a new empty object for the default queryParams
 		queryParams = &QueryParams{}
 	}
+	// Set the registered model defaults (see: RegisterModelDefaults)
+	timeout = c.applyModelDefaults(models, queryParams, timeout)
+
 	// Set default page size
 	if queryParams.Page > 0 && queryParams.PageSize < 1 {
 		queryParams.PageSize = defaultPageSize
@@ -235,13 +851,66 @@ func (c *Client) GetModels(
 	// lower case the sort direction (asc / desc)
 	queryParams.SortDirection = strings.ToLower(queryParams.SortDirection)
 
+	// Exclude the soft-deleted records
+	conditions = c.getSoftDeleteConditions(ctx, models, conditions)
+
+	// Normalize the conditions (see: WithConditionNormalization)
+	var err error
+	if conditions, err = c.normalizeQueryConditions(conditions); err != nil {
+		return err
+	}
+
+	// Skip the total count (see: TotalCountNone)
+	if queryParams.TotalCountMode == TotalCountNone {
+		total = nil
+	}
+
 	// Switch on the datastore engines
 	if c.Engine() == MongoDB { // Get using Mongo
-		return c.getWithMongo(ctx, models, conditions, fieldResults, queryParams)
+		start := time.Now()
+		if total != nil {
+			if *total, err = c.countWithMongo(
+				ctx, models, copyConditions(conditions), queryParams.TotalCountMode == TotalCountEstimated,
+			); err != nil {
+				return newMongoQueryError("count", models, conditions, start, err)
+			}
+		}
+		err = newMongoQueryError("find", models, conditions, start,
+			c.getWithMongo(ctx, models, conditions, fieldResults, queryParams))
+	} else if c.Engine() == Memory {
+		err = c.getWithMemory(ctx, models, conditions, fieldResults, queryParams, total)
+	} else if c.Engine() == DynamoDB {
+		err = c.getWithDynamoDB(ctx, models, conditions, fieldResults, queryParams, total)
 	} else if !IsSQLEngine(c.Engine()) {
 		return ErrUnsupportedEngine
+	} else {
+		err = c.find(ctx, models, conditions, queryParams, fieldResults, timeout, total)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Record the result size
+	if fieldResults != nil {
+		c.recordResultSize(ctx, metricGetModels, fieldResults)
+	} else {
+		c.recordResultSize(ctx, metricGetModels, models)
+	}
+
+	// Convert the map results
+	if err = c.convertMapResults(fieldResults); err != nil {
+		return err
+	}
+
+	// Mask the results (masked readers)
+	c.maskResults(ctx, models, models)
+	c.maskResults(ctx, models, fieldResults)
+
+	// Map the results (if requested)
+	if fieldResults != nil {
+		return c.mapResults(ctx, fieldResults)
 	}
-	return c.find(ctx, models, conditions, queryParams, fieldResults, timeout)
+	return c.mapResults(ctx, models)
 }
 
 // GetModelCount will return a count of the model matching conditions
@@ -252,33 +921,122 @@ func (c *Client) GetModelCount(
 	timeout time.Duration,
 ) (int64, error) {
 
+	// Exclude the soft-deleted records
+	conditions = c.getSoftDeleteConditions(ctx, model, conditions)
+
+	// Normalize the conditions (see: WithConditionNormalization)
+	var err error
+	if conditions, err = c.normalizeQueryConditions(conditions); err != nil {
+		return 0, err
+	}
+
+	// Serve from the cache (see: WithCache)
+	var count int64
+	cacheKey := c.getCacheKey(ctx, cacheOpModelCount, model, conditions, false)
+	if c.loadCache(ctx, cacheKey, &count) {
+		return count, nil
+	}
+
 	// Switch on the datastore engines
 	if c.Engine() == MongoDB {
-		return c.countWithMongo(ctx, model, conditions)
+		start := time.Now()
+		count, err = c.countWithMongo(ctx, model, conditions, false)
+		err = newMongoQueryError("count", model, conditions, start, err)
+	} else if c.Engine() == Memory {
+		count, err = c.countWithMemory(ctx, model, conditions)
 	} else if !IsSQLEngine(c.Engine()) {
 		return 0, ErrUnsupportedEngine
+	} else {
+		count, err = c.count(ctx, model, conditions, timeout)
+	}
+	if err == nil {
+		c.storeCache(ctx, cacheKey, count)
+	}
+	return count, err
+}
+
+// ModelExists will return true if a record matches the conditions (without getting the record)
+//
+// SQL engines use SELECT 1 ... LIMIT 1, MongoDB counts with a limit of 1
+func (c *Client) ModelExists(
+	ctx context.Context,
+	model interface{},
+	conditions map[string]interface{},
+	timeout time.Duration,
+) (bool, error) {
+
+	// Exclude the soft-deleted records
+	conditions = c.getSoftDeleteConditions(ctx, model, conditions)
+
+	// Normalize the conditions (see: WithConditionNormalization)
+	var err error
+	if conditions, err = c.normalizeQueryConditions(conditions); errors.Is(err, ErrEmptyResultGuaranteed) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// Switch on the datastore engines
+	if c.Engine() == MongoDB {
+		start := time.Now()
+		var exists bool
+		exists, err = c.existsWithMongo(ctx, model, conditions)
+		return exists, newMongoQueryError("exists", model, conditions, start, err)
+	} else if c.Engine() == Memory {
+		count, err := c.countWithMemory(ctx, model, conditions)
+		return count > 0, err
+	} else if !IsSQLEngine(c.Engine()) {
+		return false, ErrUnsupportedEngine
 	}
 
-	return c.count(ctx, model, conditions, timeout)
+	// Set the NewRelic txn
+	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+	// Create a new context, and new db tx
+	ctxDB, cancel, err := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+	if err != nil {
+		return false, err
+	}
+	defer cancel()
+
+	tx := c.useWriteDBInSession(ctx, ctxDB.Model(model))
+	if len(conditions) > 0 {
+		gtx := gormWhere{tx: tx}
+		tx = c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB)
+	}
+
+	var found []int
+	if err = tx.Select("1").Limit(1).Scan(&found).Error; err != nil {
+		return false, err
+	}
+	return len(found) > 0, nil
 }
 
 // GetModelsAggregate will return an aggregate count of the model matching conditions
+//
+// The keys are the text of the aggregate column values (dates are formatted as YYYYMMDD), see:
+// GetModelsAggregateInto for typed keys
 func (c *Client) GetModelsAggregate(ctx context.Context, models interface{},
 	conditions map[string]interface{}, aggregateColumn string, timeout time.Duration) (map[string]interface{}, error) {
 
-	// Switch on the datastore engines
-	if c.Engine() == MongoDB {
-		return c.aggregateWithMongo(ctx, models, conditions, aggregateColumn, timeout)
-	} else if !IsSQLEngine(c.Engine()) {
-		return nil, ErrUnsupportedEngine
+	rows, err := c.getAggregateRows(ctx, models, conditions, aggregateColumn, timeout)
+	if err != nil {
+		return nil, err
 	}
 
-	return c.aggregate(ctx, models, conditions, aggregateColumn, timeout)
+	// Create the result
+	aggregateResult := make(map[string]interface{}, len(rows))
+	for _, row := range rows {
+		aggregateResult[getAggregateKey(row.key)] = row.count
+	}
+	return aggregateResult, nil
 }
 
 // find will get records and return
+//
+// The total count of matching records (without the pagination) is set using the same WHERE clause (if total is set)
 func (c *Client) find(ctx context.Context, result interface{}, conditions map[string]interface{},
-	queryParams *QueryParams, fieldResults interface{}, timeout time.Duration) error {
+	queryParams *QueryParams, fieldResults interface{}, timeout time.Duration, total *int64) error {
 
 	// Find the type
 	if reflect.TypeOf(result).Elem().Kind() != reflect.Slice {
@@ -289,10 +1047,47 @@ func (c *Client) find(ctx context.Context, result interface{}, conditions map[st
 	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
 
 	// Create a new context, and new db tx
-	ctxDB, cancel := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+	ctxDB, cancel, err := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+	if err != nil {
+		return err
+	}
 	defer cancel()
 
-	tx := ctxDB.Model(result)
+	tx := c.useWriteDBInSession(ctx, ctxDB.Model(result))
+
+	// Add conditions
+	if len(conditions) > 0 {
+		gtx := gormWhere{tx: tx}
+		tx = c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB)
+	}
+
+	// Count the matching records (before the locking and pagination)
+	if total != nil && queryParams.TotalCountMode == TotalCountEstimated {
+		if err := c.estimateCount(tx.Session(&gorm.Session{}), result, total); err != nil {
+			return err
+		}
+	} else if total != nil {
+		if err := checkResult(tx.Session(&gorm.Session{}).Count(total)); err != nil {
+			return err
+		}
+	}
+
+	// Use a registered index hint
+	if len(queryParams.IndexHint) > 0 {
+		if tx, err = c.applyIndexHint(tx, result, queryParams.IndexHint); err != nil {
+			return err
+		}
+	}
+
+	// Lock the rows
+	if tx, err = c.applyRowLock(ctx, tx); err != nil {
+		return err
+	}
+
+	// Masked readers select the masked expressions (after the count)
+	if masked := c.getMaskedColumns(ctx, result); len(masked) > 0 {
+		tx = tx.Select(masked)
+	}
 
 	// Create the offset
 	offset := (queryParams.Page - 1) * queryParams.PageSize
@@ -302,26 +1097,18 @@ func (c *Client) find(ctx context.Context, result interface{}, conditions map[st
 		tx = tx.Limit(queryParams.PageSize).Offset(offset)
 	}
 
-	// Use an order field/sort
-	if len(queryParams.OrderByField) > 0 {
+	// Use the order fields/sort
+	for _, orderSpec := range queryParams.getOrderSpecs() {
 		tx = tx.Order(clause.OrderByColumn{
 			Column: clause.Column{
-				Name: queryParams.OrderByField,
+				Name: quoteIdentifier(c.Engine(), orderSpec.Field),
+				Raw:  true,
 			},
-			Desc: strings.ToLower(queryParams.SortDirection) == SortDesc,
+			Desc: orderSpec.SortDirection == SortDesc,
 		})
 	}
 
 	// Check for errors or no records found
-	if len(conditions) > 0 {
-		gtx := gormWhere{tx: tx}
-		if fieldResults != nil {
-			return checkResult(c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB).Find(fieldResults))
-		}
-		return checkResult(c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB).Find(result))
-	}
-
-	// Skip the conditions
 	if fieldResults != nil {
 		return checkResult(tx.Find(fieldResults))
 	}
@@ -336,10 +1123,13 @@ func (c *Client) count(ctx context.Context, model interface{}, conditions map[st
 	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
 
 	// Create a new context, and new db tx
-	ctxDB, cancel := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+	ctxDB, cancel, err := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+	if err != nil {
+		return 0, err
+	}
 	defer cancel()
 
-	tx := ctxDB.Model(model)
+	tx := c.useWriteDBInSession(ctx, ctxDB.Model(model))
 
 	// Check for errors or no records found
 	if len(conditions) > 0 {
@@ -349,14 +1139,14 @@ func (c *Client) count(ctx context.Context, model interface{}, conditions map[st
 		return count, err
 	}
 	var count int64
-	err := checkResult(tx.Count(&count))
+	err = checkResult(tx.Count(&count))
 
 	return count, err
 }
 
-// find will get records and return
+// aggregate will get the count of the records grouped by the aggregate column
 func (c *Client) aggregate(ctx context.Context, model interface{}, conditions map[string]interface{},
-	aggregateColumn string, timeout time.Duration) (map[string]interface{}, error) {
+	aggregateColumn string, timeout time.Duration) ([]aggregateRow, error) {
 
 	// Find the type
 	if reflect.TypeOf(model).Elem().Kind() != reflect.Slice {
@@ -367,62 +1157,96 @@ func (c *Client) aggregate(ctx context.Context, model interface{}, conditions ma
 	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
 
 	// Create a new context, and new db tx
-	ctxDB, cancel := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+	ctxDB, cancel, err := createCtx(ctx, c.options.db, timeout, c.IsDebug(), c.options.loggerDB)
+	if err != nil {
+		return nil, err
+	}
 	defer cancel()
 
 	// Get the tx
 	tx := ctxDB.Model(model)
-
-	// Check for errors or no records found
-	var aggregate []map[string]interface{}
 	if len(conditions) > 0 {
 		gtx := gormWhere{tx: tx}
-		err := checkResult(c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB).Model(model).Group(aggregateColumn).Scan(&aggregate))
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		aggregateCol := aggregateColumn
-
-		// Check for a known date field
-		if StringInSlice(aggregateCol, DateFields) {
-			if c.Engine() == MySQL {
-				aggregateCol = "DATE_FORMAT(" + aggregateCol + ", '%Y%m%d')"
-			} else if c.Engine() == Postgres {
-				aggregateCol = "to_char(" + aggregateCol + ", 'YYYYMMDD')"
-			} else {
-				aggregateCol = "strftime('%Y%m%d', " + aggregateCol + ")"
-			}
-		}
-		err := checkResult(tx.Select(aggregateCol + " as _id, COUNT(id) AS count").Group(aggregateCol).Scan(&aggregate))
-		if err != nil {
-			return nil, err
+		tx = c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB).Model(model)
+	}
+
+	aggregateCol := quoteIdentifier(c.Engine(), aggregateColumn)
+
+	// Check for a known date field
+	if StringInSlice(aggregateColumn, DateFields) {
+		if c.Engine() == MySQL {
+			aggregateCol = "DATE_FORMAT(" + aggregateCol + ", '%Y%m%d')"
+		} else if c.Engine() == Postgres {
+			aggregateCol = "to_char(" + aggregateCol + ", 'YYYYMMDD')"
+		} else {
+			aggregateCol = "strftime('%Y%m%d', " + aggregateCol + ")"
 		}
 	}
 
+	// Check for errors or no records found
+	var aggregate []map[string]interface{}
+	if err := checkResult(tx.Select(aggregateCol + " as " + mongoIDField + ", COUNT(id) AS " + accumulationCountField).
+		Clauses(clause.GroupBy{Columns: []clause.Column{{Name: aggregateCol, Raw: true}}}).
+		Scan(&aggregate)); err != nil {
+		return nil, err
+	}
+
 	// Create the result
-	aggregateResult := make(map[string]interface{})
+	rows := make([]aggregateRow, 0, len(aggregate))
 	for _, item := range aggregate {
-		key := item[mongoIDField].(string)
-		aggregateResult[key] = item[accumulationCountField]
+		if err := c.convertRow(item); err != nil {
+			return nil, err
+		}
+		rows = append(rows, aggregateRow{
+			count: getScannedValue(item[accumulationCountField]),
+			key:   getScannedValue(item[mongoIDField]),
+		})
 	}
 
-	return aggregateResult, nil
+	return rows, nil
 }
 
 // Execute a SQL query
+//
+// Prefer ExecuteContext() for bind arguments (never concatenate values into the query) and cancellation
 func (c *Client) Execute(query string) *gorm.DB {
+	return c.ExecuteContext(context.Background(), query)
+}
+
+// ExecuteContext will execute a SQL query using the context and the bind arguments (IE: "WHERE id = ?", id)
+//
+// Returns nil for non-SQL engines
+func (c *Client) ExecuteContext(ctx context.Context, query string, args ...interface{}) *gorm.DB {
 	if IsSQLEngine(c.Engine()) {
-		return c.options.db.Exec(query)
+		return c.options.db.WithContext(ctx).Exec(query, args...)
 	}
 
 	return nil
 }
 
+// ExecuteResult will execute a SQL query and return the number of rows affected
+func (c *Client) ExecuteResult(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	if !IsSQLEngine(c.Engine()) {
+		return 0, ErrUnsupportedEngine
+	}
+
+	result := c.options.db.WithContext(ctx).Exec(query, args...)
+	return result.RowsAffected, result.Error
+}
+
 // Raw a raw SQL query
+//
+// Prefer RawContext() for bind arguments (never concatenate values into the query) and cancellation
 func (c *Client) Raw(query string) *gorm.DB {
+	return c.RawContext(context.Background(), query)
+}
+
+// RawContext will create a raw SQL query using the context and the bind arguments (IE: "WHERE id = ?", id)
+//
+// Returns nil for non-SQL engines
+func (c *Client) RawContext(ctx context.Context, query string, args ...interface{}) *gorm.DB {
 	if IsSQLEngine(c.Engine()) {
-		return c.options.db.Raw(query)
+		return c.options.db.WithContext(ctx).Raw(query, args...)
 	}
 
 	return nil
@@ -444,11 +1268,57 @@ func checkResult(result *gorm.DB) error {
 	return nil
 }
 
-// createCtx will make a new DB context
+// createCtx will make a new DB context (an error if there is no time left on the context, see: getBudgetTimeout)
 func createCtx(ctx context.Context, db *gorm.DB, timeout time.Duration, debug bool,
-	optionalLogger logger.Interface) (*gorm.DB, context.CancelFunc) {
+	optionalLogger logger.Interface) (*gorm.DB, context.CancelFunc, error) {
+
+	// Limit the timeout by any remaining budget
+	var err error
+	if timeout, err = getBudgetTimeout(ctx, timeout); err != nil {
+		return nil, nil, err
+	}
+
+	// Read using the transaction (see: NewSnapshotTx and ReadContext), the read is running until canceled
+	end := func() {}
+	if readTx := getReadTx(ctx); readTx != nil {
+		if end, err = getReadWatchdog(ctx).begin(); err != nil {
+			return nil, nil, err
+		}
+		db = readTx
+	}
+
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	cancel := func() {
+		cancelTimeout()
+		end()
+	}
+	return db.Session(getGormSessionConfig(db.PrepareStmt, debug, optionalLogger)).WithContext(ctx), cancel, nil
+}
 
-	var cancel context.CancelFunc
-	ctx, cancel = context.WithTimeout(ctx, timeout)
-	return db.Session(getGormSessionConfig(db.PrepareStmt, debug, optionalLogger)).WithContext(ctx), cancel
+// getModelTableName will return the full table (or collection) name for the given model
+func (c *Client) getModelTableName(model interface{}) (string, error) {
+
+	// Mongo uses the model's table name (with prefix)
+	if c.Engine() == MongoDB {
+		collectionName := GetModelTableName(model)
+		if collectionName == nil {
+			return "", ErrUnknownCollection
+		}
+		return setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName), nil
+	} else if c.Engine() == Memory {
+		sch, err := c.options.memory.parse(model)
+		if err != nil {
+			return "", err
+		}
+		return sch.Table, nil
+	} else if !IsSQLEngine(c.Engine()) {
+		return "", ErrUnsupportedEngine
+	}
+
+	// Parse the model using the GORM naming strategy (includes the prefix)
+	stmt := &gorm.Statement{DB: c.options.db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
 }
@@ -1 +1,504 @@
 package datastore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_getModelTableName will test the method getModelTableName()
+func TestClient_getModelTableName(t *testing.T) {
+	t.Run("[sqlite] no prefix", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		tableName, err := client.(*Client).getModelTableName(&testSQLModel{})
+		require.NoError(t, err)
+		assert.Equal(t, testSQLTableName, tableName)
+	})
+
+	t.Run("[sqlite] with prefix", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testClient(ctx, t, WithSQLite(&SQLiteConfig{
+			CommonConfig: CommonConfig{TablePrefix: testTablePrefix},
+			DatabasePath: "file:memdb_model_table_name?mode=memory&cache=shared",
+		}))
+		defer deferFunc()
+
+		tableName, err := client.(*Client).getModelTableName(&testSQLModel{})
+		require.NoError(t, err)
+		assert.Equal(t, testTablePrefix+"_"+testSQLTableName, tableName)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: defaultClientOptions()}
+		_, err := client.getModelTableName(&testSQLModel{})
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// TestClient_ExecuteResult will test the method ExecuteResult()
+func TestClient_ExecuteResult(t *testing.T) {
+	t.Run("rows affected", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "exec-1", Amount: 1},
+			&testSQLModel{ID: "exec-2", Amount: 1},
+			&testSQLModel{ID: "exec-3", Amount: 5},
+		)
+
+		rows, err := client.ExecuteResult(ctx, "UPDATE "+testSQLTableName+" SET amount = ? WHERE amount = ?", 2, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), rows)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		rows, err := client.ExecuteResult(ctx, "UPDATE missing_table SET amount = 1")
+		require.Error(t, err)
+		assert.Zero(t, rows)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: MongoDB}}
+		_, err := client.ExecuteResult(context.Background(), "UPDATE test SET amount = 1")
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// TestClient_ExecuteContext will test the methods ExecuteContext() and RawContext()
+func TestClient_ExecuteContext(t *testing.T) {
+	t.Run("bind arguments", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "context-1", Name: "a"})
+
+		result := client.ExecuteContext(ctx, "UPDATE "+testSQLTableName+" SET name = ? WHERE id = ?", "x' OR '1'='1", "context-1")
+		require.NoError(t, result.Error)
+		assert.Equal(t, int64(1), result.RowsAffected)
+
+		var name string
+		require.NoError(t, client.RawContext(ctx, "SELECT name FROM "+testSQLTableName+" WHERE id = ?", "context-1").
+			Scan(&name).Error)
+		assert.Equal(t, "x' OR '1'='1", name)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		require.ErrorIs(t, client.ExecuteContext(canceled, "SELECT 1").Error, context.Canceled)
+
+		var count int64
+		require.ErrorIs(t, client.RawContext(canceled, "SELECT 1").Scan(&count).Error, context.Canceled)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: MongoDB}}
+		assert.Nil(t, client.ExecuteContext(context.Background(), "SELECT 1"))
+		assert.Nil(t, client.RawContext(context.Background(), "SELECT 1"))
+	})
+}
+
+// TestClient_SaveModelAuto will test the method SaveModelAuto()
+func TestClient_SaveModelAuto(t *testing.T) {
+	t.Run("create and update", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		model := &testSQLModel{ID: "auto-1", Name: "created"}
+		require.NoError(t, client.SaveModelAuto(ctx, model, true))
+
+		model.Name = "updated"
+		require.NoError(t, client.SaveModelAuto(ctx, model, false))
+
+		found := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, found, map[string]interface{}{sqlIDField: "auto-1"},
+			defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "updated", found.Name)
+	})
+
+	t.Run("failed save is rolled back", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "auto-2", Name: "existing"})
+
+		require.Error(t, client.SaveModelAuto(ctx, &testSQLModel{ID: "auto-2", Name: "duplicate"}, true))
+
+		// The client can still write (no transaction left open)
+		require.NoError(t, client.SaveModelAuto(ctx, &testSQLModel{ID: "auto-3", Name: "next"}, true))
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		require.ErrorIs(t, client.SaveModelAuto(context.Background(), &testSQLModel{}, true), ErrUnsupportedEngine)
+	})
+}
+
+// TestClient_SaveModels will test the method SaveModels()
+func TestClient_SaveModels(t *testing.T) {
+	t.Run("create and update", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithAutoMigrate(&testBalanceModel{}))
+		defer deferFunc()
+
+		models := []interface{}{
+			&testSQLModel{ID: "bulk-1", Name: "first"},
+			&testSQLModel{ID: "bulk-2", Name: "second"},
+			&testBalanceModel{ID: "bulk-3", Balance: 1.5},
+			&testSQLModel{ID: "bulk-4", Name: "fourth"},
+		}
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			assert.Nil(t, client.SaveModels(ctx, models, tx, true))
+			return nil
+		}))
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+		balance := &testBalanceModel{}
+		require.NoError(t, client.GetModel(ctx, balance, map[string]interface{}{sqlIDField: "bulk-3"},
+			defaultDatabaseMaxTimeout, false))
+		assert.InDelta(t, 1.5, balance.Balance, 0.0001)
+
+		// Update the existing records
+		models[0].(*testSQLModel).Name = "updated"
+		models[3].(*testSQLModel).Amount = 7
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			assert.Nil(t, client.SaveModels(ctx, []interface{}{models[0], models[3]}, tx, false))
+			return nil
+		}))
+		found := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, found, map[string]interface{}{sqlIDField: "bulk-1"},
+			defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "updated", found.Name)
+		found = &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, found, map[string]interface{}{sqlIDField: "bulk-4"},
+			defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, int64(7), found.Amount)
+	})
+
+	t.Run("failed batch is rolled back", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithAutoMigrate(&testBalanceModel{}))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "bulk-5", Name: "existing"})
+
+		var errs []error
+		require.Error(t, client.NewTx(ctx, func(tx *Transaction) error {
+			errs = client.SaveModels(ctx, []interface{}{
+				&testBalanceModel{ID: "bulk-6"},
+				&testSQLModel{ID: "bulk-5", Name: "duplicate"},
+				&testSQLModel{ID: "bulk-7", Name: "new"},
+				&testBalanceModel{ID: "bulk-8"},
+			}, tx, true)
+			return errors.Join(errs...)
+		}))
+		require.Len(t, errs, 4)
+		require.NoError(t, errs[0])
+		require.Error(t, errs[1])
+		require.Error(t, errs[2]) // Same batch as the duplicate
+		require.ErrorIs(t, errs[3], ErrModelNotSaved)
+
+		count, err := client.GetModelCount(ctx, &testBalanceModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("no models", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite}}
+		assert.Nil(t, client.SaveModels(context.Background(), nil, nil, true))
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		errs := client.SaveModels(context.Background(), []interface{}{&testSQLModel{}, &testSQLModel{}}, nil, true)
+		require.Len(t, errs, 2)
+		require.ErrorIs(t, errs[0], ErrUnsupportedEngine)
+		require.ErrorIs(t, errs[1], ErrUnsupportedEngine)
+	})
+}
+
+// TestClient_UpdateModelFields will test the method UpdateModelFields()
+func TestClient_UpdateModelFields(t *testing.T) {
+	t.Run("only the named columns", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "update-1", Name: "alice", Amount: 5})
+
+		// A stale copy of the model must not overwrite the untouched columns
+		stale := &testSQLModel{ID: "update-1", Name: "alice", Amount: 1}
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.UpdateModelFields(ctx, stale, map[string]interface{}{"name": "bob"}, tx, true)
+		}))
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{
+			sqlIDField: "update-1",
+		}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "bob", model.Name)
+		assert.Equal(t, int64(5), model.Amount)
+	})
+
+	t.Run("missing primary key", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		err := client.NewTx(ctx, func(tx *Transaction) error {
+			return client.UpdateModelFields(ctx, &testSQLModel{}, map[string]interface{}{"name": "bob"}, tx, true)
+		})
+		require.ErrorIs(t, err, ErrMissingPrimaryKey)
+	})
+
+	t.Run("no fields", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite}}
+		err := client.UpdateModelFields(context.Background(), &testSQLModel{ID: "update-1"}, nil, nil, true)
+		require.ErrorIs(t, err, ErrNoFieldsToUpdate)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		err := client.UpdateModelFields(context.Background(), &testSQLModel{ID: "update-1"},
+			map[string]interface{}{"name": "bob"}, nil, true)
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// TestClient_UpsertModel will test the method UpsertModel()
+func TestClient_UpsertModel(t *testing.T) {
+	t.Run("insert then update the named columns", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		require.NoError(t, client.UpsertModel(ctx, &testSQLModel{ID: "upsert-1", Name: "alice", Amount: 5},
+			[]string{sqlIDField}, []string{"name"}))
+		require.NoError(t, client.UpsertModel(ctx, &testSQLModel{ID: "upsert-1", Name: "bob", Amount: 1},
+			[]string{sqlIDField}, []string{"name"}))
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{
+			sqlIDField: "upsert-1",
+		}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "bob", model.Name)
+		assert.Equal(t, int64(5), model.Amount)
+	})
+
+	t.Run("update all columns", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		require.NoError(t, client.UpsertModel(ctx, &testSQLModel{ID: "upsert-2", Name: "alice", Amount: 5}, nil, nil))
+		require.NoError(t, client.UpsertModel(ctx, &testSQLModel{ID: "upsert-2", Name: "bob", Amount: 1}, nil, nil))
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{
+			sqlIDField: "upsert-2",
+		}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "bob", model.Name)
+		assert.Equal(t, int64(1), model.Amount)
+	})
+
+	t.Run("invalid column", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite}}
+		err := client.UpsertModel(context.Background(), &testSQLModel{ID: "upsert-3"}, []string{"id; DROP"}, nil)
+		require.ErrorIs(t, err, ErrInvalidUpsertColumn)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		err := client.UpsertModel(context.Background(), &testSQLModel{ID: "upsert-3"}, nil, nil)
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// testBalanceModel is a model with a float column
+type testBalanceModel struct {
+	ID      string  `gorm:"primaryKey"`
+	Balance float64 `gorm:"column:balance"`
+}
+
+// TestClient_IncrementModel will test the methods IncrementModel(), DecrementModel() and IncrementModelFloat()
+func TestClient_IncrementModel(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t, WithAutoMigrate(&testBalanceModel{}))
+	defer deferFunc()
+	testSaveModels(ctx, t, client, &testSQLModel{ID: "increment-1", Name: "counter", Amount: 10})
+	testSaveModels(ctx, t, client, &testBalanceModel{ID: "balance-1", Balance: 1.5})
+
+	t.Run("negative increment and decrement", func(t *testing.T) {
+		newValue, err := client.IncrementModel(ctx, &testSQLModel{ID: "increment-1"}, "amount", -3)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), newValue)
+
+		newValue, err = client.DecrementModel(ctx, &testSQLModel{ID: "increment-1"}, "amount", 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), newValue)
+	})
+
+	t.Run("float", func(t *testing.T) {
+		newValue, err := client.IncrementModelFloat(ctx, &testBalanceModel{ID: "balance-1"}, "balance", 0.25)
+		require.NoError(t, err)
+		assert.InDelta(t, 1.75, newValue, 0.0001)
+
+		newValue, err = client.IncrementModelFloat(ctx, &testBalanceModel{ID: "balance-1"}, "balance", -1.25)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.5, newValue, 0.0001)
+	})
+
+	t.Run("unsupported column type", func(t *testing.T) {
+		_, err := client.IncrementModel(ctx, &testSQLModel{ID: "increment-1"}, "name", 1)
+		require.ErrorIs(t, err, ErrUnsupportedIncrementType)
+
+		_, err = client.IncrementModel(ctx, &testBalanceModel{ID: "balance-1"}, "balance", 1)
+		require.ErrorIs(t, err, ErrUnsupportedIncrementType)
+	})
+}
+
+// TestConvertToInt64 will test the method convertToInt64()
+func TestConvertToInt64(t *testing.T) {
+	for _, value := range []interface{}{int64(3), 3, int32(3), int16(3), int8(3), uint32(3), uint64(3), "3", []byte("3")} {
+		converted, err := convertToInt64(value)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), converted)
+	}
+
+	for _, value := range []interface{}{1.5, "abc", true} {
+		_, err := convertToInt64(value)
+		require.ErrorIs(t, err, ErrUnsupportedIncrementType)
+	}
+}
+
+// TestConvertToFloat64 will test the method convertToFloat64()
+func TestConvertToFloat64(t *testing.T) {
+	for _, value := range []interface{}{1.5, float32(1.5), "1.5", []byte("1.5")} {
+		converted, err := convertToFloat64(value)
+		require.NoError(t, err)
+		assert.InDelta(t, 1.5, converted, 0.0001)
+	}
+
+	converted, err := convertToFloat64(int64(2))
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, converted, 0.0001)
+
+	_, err = convertToFloat64(true)
+	require.ErrorIs(t, err, ErrUnsupportedIncrementType)
+}
+
+// TestClient_FindOrCreateModel will test the method FindOrCreateModel()
+func TestClient_FindOrCreateModel(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+	testSaveModels(ctx, t, client, &testSQLModel{ID: "find-1", Name: "existing", Amount: 5})
+
+	t.Run("missing conditions", func(t *testing.T) {
+		_, err := client.FindOrCreateModel(ctx, &testSQLModel{ID: "find-0"}, nil, nil)
+		require.ErrorIs(t, err, ErrMissingConditions)
+	})
+
+	t.Run("found", func(t *testing.T) {
+		model := &testSQLModel{ID: "find-2", Name: "existing"}
+		created, err := client.FindOrCreateModel(ctx, model, map[string]interface{}{"name": "existing"}, nil)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, "find-1", model.ID)
+		assert.Equal(t, int64(5), model.Amount)
+	})
+
+	t.Run("created", func(t *testing.T) {
+		model := &testSQLModel{ID: "find-3", Name: "new", Amount: 1}
+		created, err := client.FindOrCreateModel(ctx, model, map[string]interface{}{"name": "new"}, nil)
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		created, err = client.FindOrCreateModel(ctx, &testSQLModel{ID: "find-4", Name: "new"},
+			map[string]interface{}{"name": "new"}, nil)
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, map[string]interface{}{"name": "new"}, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("conflict not matching the conditions", func(t *testing.T) {
+		_, err := client.FindOrCreateModel(ctx, &testSQLModel{ID: "find-1", Name: "other"},
+			map[string]interface{}{"name": "other"}, nil)
+		require.ErrorIs(t, err, ErrDuplicateKey)
+	})
+
+	t.Run("in a transaction", func(t *testing.T) {
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			created, err := client.FindOrCreateModel(ctx, &testSQLModel{ID: "find-5", Name: "tx"},
+				map[string]interface{}{"name": "tx"}, tx)
+			require.NoError(t, err)
+			assert.True(t, created)
+			return tx.Commit()
+		}))
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{"name": "tx"}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "find-5", model.ID)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		empty := &Client{options: &clientOptions{engine: Empty}}
+		_, err := empty.FindOrCreateModel(ctx, &testSQLModel{}, map[string]interface{}{"name": "x"}, nil)
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// TestClient_ModelExists will test the method ModelExists()
+func TestClient_ModelExists(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t, WithConditionNormalization())
+	defer deferFunc()
+	testSaveModels(ctx, t, client, &testSQLModel{ID: "exists-1", Name: "existing", Amount: 3})
+
+	exists, err := client.ModelExists(ctx, &testSQLModel{}, map[string]interface{}{"name": "existing"}, defaultDatabaseMaxTimeout)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = client.ModelExists(ctx, &testSQLModel{}, map[string]interface{}{"name": "missing"}, defaultDatabaseMaxTimeout)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = client.ModelExists(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// Never matching conditions do not query the datastore
+	exists, err = client.ModelExists(ctx, &testSQLModel{}, map[string]interface{}{
+		"amount": map[string]interface{}{conditionGreaterThan: 5, conditionLessThan: 2},
+	}, defaultDatabaseMaxTimeout)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		empty := &Client{options: &clientOptions{engine: Empty}}
+		_, err = empty.ModelExists(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
@@ -6,10 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/newrelic/go-agent/v3/integrations/nrmongo"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -20,28 +23,35 @@ const (
 	logErrorLine = "MONGO %s %s: %e: %+v\n"
 )
 
-// saveWithMongo will save a given struct to MongoDB
+// saveWithMongo will save a given struct to MongoDB (returns the number of documents inserted or modified)
 func (c *Client) saveWithMongo(
 	ctx context.Context,
 	model interface{},
 	newRecord bool,
-) (err error) {
+) (rows int64, err error) {
 	collectionName := GetModelTableName(model)
 	if collectionName == nil {
-		return ErrUnknownCollection
+		return 0, ErrUnknownCollection
 	}
 
 	// Set the collection
-	collection := c.options.mongoDB.Collection(
-		setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	collection := c.getMongoWriteCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
 	)
 
 	// Create or update
 	if newRecord {
 		c.DebugLog(ctx, fmt.Sprintf(logLine, "insert", *collectionName, model))
-		_, err = collection.InsertOne(ctx, model)
+		if _, err = collection.InsertOne(
+			ctx, model, options.InsertOne().SetComment(c.getMongoComment(ctx)),
+		); err == nil {
+			rows = 1
+		}
 	} else {
-		id := GetModelStringAttribute(model, sqlIDFieldProper)
+		var primaryKey map[string]interface{}
+		if primaryKey, err = c.getModelPrimaryKey(model); err != nil {
+			return 0, err
+		}
 		update := bson.M{conditionSet: model}
 		unset := GetModelUnset(model)
 		if len(unset) > 0 {
@@ -50,15 +60,18 @@ func (c *Client) saveWithMongo(
 
 		c.DebugLog(ctx, fmt.Sprintf(logLine, "update", *collectionName, model))
 
-		_, err = collection.UpdateOne(
-			ctx, bson.M{mongoIDField: *id}, update,
-		)
+		var result *mongo.UpdateResult
+		if result, err = collection.UpdateOne(
+			ctx, primaryKey, update, options.Update().SetComment(c.getMongoComment(ctx)),
+		); err == nil {
+			rows = result.ModifiedCount
+		}
 	}
 
 	// Check for duplicate key (insert error, record exists)
 	if mongo.IsDuplicateKeyError(err) {
 		c.DebugLog(ctx, fmt.Sprintf(logErrorLine, "error", *collectionName, ErrDuplicateKey, model))
-		return ErrDuplicateKey
+		return 0, ErrDuplicateKey
 	}
 
 	if err != nil {
@@ -68,33 +81,206 @@ func (c *Client) saveWithMongo(
 	return
 }
 
-// incrementWithMongo will save a given struct to MongoDB
+// updateFieldsWithMongo will update the given fields of a model in MongoDB ($set) (returns the number of
+// documents modified)
+func (c *Client) updateFieldsWithMongo(
+	ctx context.Context,
+	model interface{},
+	fields map[string]interface{},
+) (rows int64, err error) {
+	collectionName := GetModelTableName(model)
+	if collectionName == nil {
+		return 0, ErrUnknownCollection
+	}
+
+	// Set the collection
+	collection := c.getMongoWriteCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	)
+
+	var primaryKey map[string]interface{}
+	if primaryKey, err = c.getModelPrimaryKey(model); err != nil {
+		return 0, err
+	}
+
+	c.DebugLog(ctx, fmt.Sprintf(logLine, "update", *collectionName, fields))
+
+	var result *mongo.UpdateResult
+	if result, err = collection.UpdateOne(
+		ctx, primaryKey, bson.M{conditionSet: fields}, options.Update().SetComment(c.getMongoComment(ctx)),
+	); err != nil {
+		c.DebugLog(ctx, fmt.Sprintf(logErrorLine, "error", *collectionName, err, fields))
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// upsertWithMongo will insert or update (conflict columns) a given struct in MongoDB
+func (c *Client) upsertWithMongo(
+	ctx context.Context,
+	model interface{},
+	conflictColumns, updateColumns []string,
+) (err error) {
+	collectionName := GetModelTableName(model)
+	if collectionName == nil {
+		return ErrUnknownCollection
+	}
+
+	// Set the collection
+	collection := c.getMongoWriteCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	)
+
+	var raw []byte
+	if raw, err = bson.Marshal(model); err != nil {
+		return err
+	}
+	var document bson.M
+	if err = bson.Unmarshal(raw, &document); err != nil {
+		return err
+	}
+	var filter, update bson.M
+	if filter, update, err = getMongoUpsert(document, conflictColumns, updateColumns); err != nil {
+		return err
+	}
+
+	c.DebugLog(ctx, fmt.Sprintf(logLine, "upsert", *collectionName, model))
+
+	if _, err = collection.UpdateOne(
+		ctx, filter, update, options.Update().SetUpsert(true).SetComment(c.getMongoComment(ctx)),
+	); err != nil {
+		c.DebugLog(ctx, fmt.Sprintf(logErrorLine, "error", *collectionName, err, model))
+	}
+
+	return
+}
+
+// findOrCreateWithMongo will find the model using the conditions, or insert it (upsert) if missing
+func (c *Client) findOrCreateWithMongo(
+	ctx context.Context,
+	model interface{},
+	conditions map[string]interface{},
+) (created bool, err error) {
+	collectionName := GetModelTableName(model)
+	if collectionName == nil {
+		return false, ErrUnknownCollection
+	}
+
+	// Set the collection
+	collection := c.getMongoWriteCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	)
+
+	var raw []byte
+	if raw, err = bson.Marshal(model); err != nil {
+		return false, err
+	}
+	var document bson.M
+	if err = bson.Unmarshal(raw, &document); err != nil {
+		return false, err
+	}
+	queryConditions := getMongoQueryConditions(model, conditions, c.GetMongoConditionProcessor())
+
+	c.DebugLog(ctx, fmt.Sprintf(logLine, "findOrCreate", *collectionName, queryConditions))
+
+	// The previous document is returned (none if the document was inserted)
+	result := collection.FindOneAndUpdate(
+		ctx, queryConditions, bson.M{conditionSetOnInsert: document},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before).
+			SetComment(c.getMongoComment(ctx)),
+	)
+	if err = result.Err(); errors.Is(err, mongo.ErrNoDocuments) {
+		return true, nil
+	} else if err != nil {
+		c.DebugLog(ctx, fmt.Sprintf(logErrorLine, "error", *collectionName, err, model))
+		if mongo.IsDuplicateKeyError(err) {
+			return false, ErrDuplicateKey
+		}
+		return false, err
+	}
+
+	return false, result.Decode(model)
+}
+
+// getMongoUpsert will return the filter (conflict columns) and the update ($set and $setOnInsert) for an upsert
+func getMongoUpsert(document bson.M, conflictColumns, updateColumns []string) (filter, update bson.M, err error) {
+	if len(conflictColumns) == 0 {
+		conflictColumns = []string{mongoIDField}
+	}
+
+	// Filter on the conflict columns
+	filter = bson.M{}
+	for _, column := range conflictColumns {
+		if column == sqlIDField {
+			column = mongoIDField
+		}
+		value, ok := document[column]
+		if !ok {
+			return nil, nil, ErrMissingPrimaryKey
+		}
+		filter[column] = value
+	}
+
+	// Update the given columns (or all other columns), the rest is only set when inserted
+	set := bson.M{}
+	for _, column := range updateColumns {
+		if column == sqlIDField {
+			column = mongoIDField
+		}
+		if value, ok := document[column]; ok {
+			set[column] = value
+		}
+	}
+	setOnInsert := bson.M{}
+	for column, value := range document {
+		if _, ok := set[column]; ok {
+			continue
+		} else if _, ok = filter[column]; !ok && len(updateColumns) == 0 && column != mongoIDField {
+			set[column] = value
+			continue
+		}
+		setOnInsert[column] = value
+	}
+
+	update = bson.M{}
+	if len(set) > 0 {
+		update[conditionSet] = set
+	}
+	if len(setOnInsert) > 0 {
+		update[conditionSetOnInsert] = setOnInsert
+	}
+	return filter, update, nil
+}
+
+// incrementWithMongo will increment the field of a given struct in MongoDB and return the new (raw) value
 func (c *Client) incrementWithMongo(
 	ctx context.Context,
 	model interface{},
 	fieldName string,
-	increment int64,
-) (newValue int64, err error) {
+	increment interface{},
+) (newValue interface{}, err error) {
 	collectionName := GetModelTableName(model)
 	if collectionName == nil {
 		return newValue, ErrUnknownCollection
 	}
 
 	// Set the collection
-	collection := c.options.mongoDB.Collection(
-		setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	collection := c.getMongoWriteCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
 	)
 
-	id := GetModelStringAttribute(model, sqlIDFieldProper)
-	if id == nil {
-		return newValue, errors.New("can only increment by " + sqlIDField)
+	primaryKey, err := c.getModelPrimaryKey(model)
+	if err != nil {
+		return newValue, err
 	}
 	update := bson.M{conditionIncrement: bson.M{fieldName: increment}}
 
 	c.DebugLog(ctx, fmt.Sprintf(logLine, "increment", *collectionName, model))
 
 	result := collection.FindOneAndUpdate(
-		ctx, bson.M{mongoIDField: *id}, update,
+		ctx, primaryKey, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetComment(c.getMongoComment(ctx)),
 	)
 	if result.Err() != nil {
 		return newValue, result.Err()
@@ -104,22 +290,22 @@ func (c *Client) incrementWithMongo(
 		return
 	}
 	var newModel map[string]interface{}
-	_ = bson.Unmarshal(rawValue, &newModel) // todo: cannot check error, breaks code atm
-
-	newValue = newModel[fieldName].(int64) + increment
-
-	if err != nil {
+	if err = bson.Unmarshal(rawValue, &newModel); err != nil {
 		c.DebugLog(ctx, fmt.Sprintf(logErrorLine, "error", *collectionName, err, model))
+		return
 	}
 
-	return
+	return newModel[fieldName], nil
 }
 
 // CreateInBatchesMongo insert multiple models vai bulk.Write
+//
+// Batches are ordered by default, see WithUnorderedBatch() and WithSkipDuplicates()
 func (c *Client) CreateInBatchesMongo(
 	ctx context.Context,
 	models interface{},
 	batchSize int,
+	opts ...BatchOps,
 ) error {
 
 	collectionName := GetModelTableName(models)
@@ -128,8 +314,8 @@ func (c *Client) CreateInBatchesMongo(
 	}
 
 	mongoModels := make([]mongo.WriteModel, 0)
-	collection := c.GetMongoCollection(*collectionName)
-	bulkOptions := options.BulkWrite().SetOrdered(true)
+	collection := c.getMongoWriteCollection(ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName))
+	batch := getBatchOptions(opts...)
 	count := 0
 
 	if reflect.TypeOf(models).Kind() == reflect.Slice {
@@ -141,8 +327,7 @@ func (c *Client) CreateInBatchesMongo(
 			count++
 
 			if count%batchSize == 0 {
-				_, err := collection.BulkWrite(ctx, mongoModels, bulkOptions)
-				if err != nil {
+				if err := bulkInsertMongo(ctx, collection, mongoModels, batch); err != nil {
 					return err
 				}
 				// reset the bulk
@@ -152,12 +337,12 @@ func (c *Client) CreateInBatchesMongo(
 	}
 
 	if count%batchSize != 0 {
-		_, err := collection.BulkWrite(ctx, mongoModels, bulkOptions)
-		if err != nil {
+		if err := bulkInsertMongo(ctx, collection, mongoModels, batch); err != nil {
 			return err
 		}
 	}
 
+	c.analyzeAfterBulkLoad(ctx, models, int64(count))
 	return nil
 }
 
@@ -175,9 +360,13 @@ func (c *Client) getWithMongo(
 		return ErrUnknownCollection
 	}
 
+	// Kill the query on the server if the context is canceled (see: WithQueryKill)
+	ctx, stopKill := c.startQueryKill(ctx)
+	defer stopKill()
+
 	// Set the collection
-	collection := c.options.mongoDB.Collection(
-		setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	collection := c.getMongoReadCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
 	)
 
 	var fields []string
@@ -201,15 +390,31 @@ func (c *Client) getWithMongo(
 			opts = append(opts, options.Find().SetLimit(int64(queryParams.PageSize)).SetSkip(int64(queryParams.PageSize*(queryParams.Page-1))))
 		}
 
-		if queryParams.OrderByField == sqlIDField {
-			queryParams.OrderByField = mongoIDField // use Mongo _id instead of default id field
+		if queryParams.IndexHint != "" {
+			hintOpts, err := c.getMongoIndexHint(queryParams.IndexHint)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, hintOpts)
 		}
-		if queryParams.OrderByField != "" {
-			sortOrder := 1
-			if queryParams.SortDirection == SortDesc {
-				sortOrder = -1
+
+		if orderSpecs := queryParams.getOrderSpecs(); len(orderSpecs) > 0 {
+			sort := bson.D{}
+			for _, orderSpec := range orderSpecs {
+				if orderSpec.Field == sqlIDField {
+					orderSpec.Field = mongoIDField // use Mongo _id instead of default id field
+				}
+				sortOrder := 1
+				if orderSpec.SortDirection == SortDesc {
+					sortOrder = -1
+				}
+				sort = append(sort, bson.E{Key: orderSpec.Field, Value: sortOrder})
 			}
-			opts = append(opts, options.Find().SetSort(bson.D{{Key: queryParams.OrderByField, Value: sortOrder}}))
+			opts = append(opts, options.Find().SetSort(sort))
+		}
+
+		if comment := c.getQueryComment(ctx); len(comment) > 0 {
+			opts = append(opts, options.Find().SetComment(comment))
 		}
 
 		cursor, err := collection.Find(ctx, queryConditions, opts...)
@@ -243,6 +448,10 @@ func (c *Client) getWithMongo(
 			opts = append(opts, options.FindOne().SetProjection(projection))
 		}
 
+		if comment := c.getQueryComment(ctx); len(comment) > 0 {
+			opts = append(opts, options.FindOne().SetComment(comment))
+		}
+
 		result := collection.FindOne(ctx, queryConditions, opts...)
 		if err := result.Err(); errors.Is(err, mongo.ErrNoDocuments) {
 			c.DebugLog(ctx, fmt.Sprintf(logLine, "result", *collectionName, "no result"))
@@ -268,11 +477,13 @@ func (c *Client) getWithMongo(
 	return nil
 }
 
-// countWithMongo will get a count of all models matching the conditions
+// countWithMongo will get a count of all models matching the conditions (estimated uses the collection metadata
+// when there are no conditions)
 func (c *Client) countWithMongo(
 	ctx context.Context,
 	models interface{},
 	conditions map[string]interface{},
+	estimated bool,
 ) (int64, error) {
 	queryConditions := getMongoQueryConditions(models, conditions, c.GetMongoConditionProcessor())
 	collectionName := GetModelTableName(models)
@@ -280,14 +491,29 @@ func (c *Client) countWithMongo(
 		return 0, ErrUnknownCollection
 	}
 
+	// Kill the query on the server if the context is canceled (see: WithQueryKill)
+	ctx, stopKill := c.startQueryKill(ctx)
+	defer stopKill()
+
 	// Set the collection
-	collection := c.options.mongoDB.Collection(
-		setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	collection := c.getMongoReadCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
 	)
 
 	c.DebugLog(ctx, fmt.Sprintf(logLine, accumulationCountField, *collectionName, queryConditions))
 
-	count, err := collection.CountDocuments(ctx, queryConditions)
+	// Use the collection metadata (only without conditions)
+	if estimated && len(queryConditions) == 0 {
+		return collection.EstimatedDocumentCount(
+			ctx, options.EstimatedDocumentCount().SetComment(c.getMongoComment(ctx)),
+		)
+	}
+
+	countOptions := options.Count()
+	if comment := c.getQueryComment(ctx); len(comment) > 0 {
+		countOptions.SetComment(comment)
+	}
+	count, err := collection.CountDocuments(ctx, queryConditions, countOptions)
 	if err != nil {
 		return 0, err
 	}
@@ -295,6 +521,41 @@ func (c *Client) countWithMongo(
 	return count, nil
 }
 
+// existsWithMongo will return true if a document matches the conditions (count with a limit of 1)
+func (c *Client) existsWithMongo(
+	ctx context.Context,
+	model interface{},
+	conditions map[string]interface{},
+) (bool, error) {
+	queryConditions := getMongoQueryConditions(model, conditions, c.GetMongoConditionProcessor())
+	collectionName := GetModelTableName(model)
+	if collectionName == nil {
+		return false, ErrUnknownCollection
+	}
+
+	// Kill the query on the server if the context is canceled (see: WithQueryKill)
+	ctx, stopKill := c.startQueryKill(ctx)
+	defer stopKill()
+
+	// Set the collection
+	collection := c.getMongoReadCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	)
+
+	c.DebugLog(ctx, fmt.Sprintf(logLine, "exists", *collectionName, queryConditions))
+
+	countOptions := options.Count().SetLimit(1)
+	if comment := c.getQueryComment(ctx); len(comment) > 0 {
+		countOptions.SetComment(comment)
+	}
+	count, err := collection.CountDocuments(ctx, queryConditions, countOptions)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 // aggregateWithMongo will get a count of all models aggregate by aggregateColumn matching the conditions
 func (c *Client) aggregateWithMongo(
 	ctx context.Context,
@@ -302,7 +563,7 @@ func (c *Client) aggregateWithMongo(
 	conditions map[string]interface{},
 	aggregateColumn string,
 	timeout time.Duration,
-) (map[string]interface{}, error) {
+) ([]aggregateRow, error) {
 	queryConditions := getMongoQueryConditions(models, conditions, c.GetMongoConditionProcessor())
 	collectionName := GetModelTableName(models)
 	if collectionName == nil {
@@ -310,8 +571,8 @@ func (c *Client) aggregateWithMongo(
 	}
 
 	// Set the collection
-	collection := c.options.mongoDB.Collection(
-		setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	collection := c.getMongoReadCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
 	)
 
 	c.DebugLog(ctx, fmt.Sprintf(logLine, accumulationCountField, *collectionName, queryConditions))
@@ -363,19 +624,25 @@ func (c *Client) aggregateWithMongo(
 			{Key: conditionMatch, Value: matchStage},
 		}, groupStage}
 
-	// anonymous struct for unmarshalling result bson
+	// anonymous struct for unmarshalling result bson (the group key can be any type)
 	var results []struct {
-		ID    string `bson:"_id"`
-		Count int64  `bson:"count"`
+		ID    interface{} `bson:"_id"`
+		Count int64       `bson:"count"`
 	}
 
 	var aggregateCursor *mongo.Cursor
 	aggregateCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
+	aggregateCtx, stopKill := c.startQueryKill(aggregateCtx) // See: WithQueryKill
+	defer stopKill()
 
 	// Get the aggregation
+	aggregateOptions := options.Aggregate()
+	if comment := c.getQueryComment(aggregateCtx); len(comment) > 0 {
+		aggregateOptions.SetComment(comment)
+	}
 	if aggregateCursor, err = collection.Aggregate(
-		aggregateCtx, pipeline,
+		aggregateCtx, pipeline, aggregateOptions,
 	); err != nil {
 		return nil, err
 	}
@@ -386,12 +653,16 @@ func (c *Client) aggregateWithMongo(
 	}
 
 	// Create the result
-	aggregateResult := make(map[string]interface{})
+	rows := make([]aggregateRow, 0, len(results))
 	for _, result := range results {
-		aggregateResult[result.ID] = result.Count
+		key := result.ID
+		if dateTime, ok := key.(primitive.DateTime); ok {
+			key = dateTime.Time().UTC()
+		}
+		rows = append(rows, aggregateRow{count: result.Count, key: key})
 	}
 
-	return aggregateResult, nil
+	return rows, nil
 }
 
 // GetMongoCollection will get the mongo collection for the given tableName
@@ -502,8 +773,14 @@ func processMongoConditions(conditions *map[string]interface{},
 		customProcessor(conditions)
 	}
 
+	// Transform the $eqOrNull conditions into an $or
+	processMongoEqOrNullConditions(conditions)
+
 	// Handle all conditions post-processing
 	for key, condition := range *conditions {
+		if fieldConditions, ok := condition.(map[string]interface{}); ok {
+			processMongoFieldOperators(fieldConditions)
+		}
 		if key == conditionAnd || key == conditionOr {
 			var slice []map[string]interface{}
 			a, _ := json.Marshal(condition) //nolint:errchkjson // this check might break the current code
@@ -519,6 +796,105 @@ func processMongoConditions(conditions *map[string]interface{},
 	return conditions
 }
 
+// processMongoEqOrNullConditions will transform the $eqOrNull conditions into an $or (added to the $and conditions)
+//
+// {"field": {"$eqOrNull": value}} => {"$or": [{"field": {"$eq": value}}, {"field": nil}]} (nil matches a missing field)
+func processMongoEqOrNullConditions(conditions *map[string]interface{}) {
+	var and []map[string]interface{}
+	for key, condition := range *conditions {
+		fieldConditions, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, ok := fieldConditions[conditionEqOrNull]
+		if !ok {
+			continue
+		}
+		delete(fieldConditions, conditionEqOrNull)
+		if len(fieldConditions) == 0 {
+			delete(*conditions, key)
+		}
+		and = append(and, map[string]interface{}{conditionOr: []map[string]interface{}{
+			{key: map[string]interface{}{conditionEquals: value}},
+			{key: nil},
+		}})
+	}
+	if len(and) == 0 {
+		return
+	}
+
+	if existing, ok := (*conditions)[conditionAnd].([]map[string]interface{}); ok {
+		and = append(existing, and...)
+	}
+	(*conditions)[conditionAnd] = and
+}
+
+// processMongoFieldOperators will transform the SQL style operators of a field into Mongo operators
+//
+// $like, $ilike, $startsWith and $endsWith into a $regex, $between into $gte and $lte
+func processMongoFieldOperators(fieldConditions map[string]interface{}) {
+	if bounds, ok := getBetweenBounds(fieldConditions[conditionBetween]); ok {
+		delete(fieldConditions, conditionBetween)
+		fieldConditions[conditionGreaterThanOrEqual] = bounds[0]
+		fieldConditions[conditionLessThanOrEqual] = bounds[1]
+	}
+	prefix, hasPrefix := fieldConditions[conditionStartsWith].(string)
+	suffix, hasSuffix := fieldConditions[conditionEndsWith].(string)
+	if hasPrefix || hasSuffix { // Anchored prefix expressions can use an index
+		delete(fieldConditions, conditionStartsWith)
+		delete(fieldConditions, conditionEndsWith)
+		var regex string
+		if hasPrefix {
+			regex = "^" + regexp.QuoteMeta(prefix)
+		}
+		if hasPrefix && hasSuffix {
+			regex += ".*"
+		}
+		if hasSuffix {
+			regex += regexp.QuoteMeta(suffix) + "$"
+		}
+		fieldConditions[conditionRegex] = regex
+		fieldConditions[conditionRegexOptions] = "s"
+	}
+	for _, key := range []string{conditionLike, conditionILike} {
+		pattern, ok := fieldConditions[key].(string)
+		if !ok {
+			continue
+		}
+		delete(fieldConditions, key)
+		regexOptions := "s"
+		if key == conditionILike {
+			regexOptions = "is"
+		}
+		fieldConditions[conditionRegex] = likeToRegex(pattern)
+		fieldConditions[conditionRegexOptions] = regexOptions
+	}
+}
+
+// likeToRegex will convert a LIKE pattern (% and _ wildcards, \ escapes) into an anchored regular expression
+func likeToRegex(pattern string) string {
+	var regex strings.Builder
+	regex.WriteString("^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			regex.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			regex.WriteString(".*")
+		case r == '_':
+			regex.WriteString(".")
+		default:
+			regex.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	regex.WriteString("$")
+	return regex.String()
+}
+
 // processMetadataConditions will process metadata conditions
 func processMetadataConditions(conditions *map[string]interface{}) {
 	// marshal / unmarshal into standard map[string]interface{}
@@ -529,6 +905,10 @@ func processMetadataConditions(conditions *map[string]interface{}) {
 	// Loop and create the key associations
 	metadata := make([]map[string]interface{}, 0)
 	for key, value := range r {
+		if operator, operand, ok := getMetadataOperator(value); ok {
+			metadata = append(metadata, getMongoMetadataOperator(key, operator, operand))
+			continue
+		}
 		metadata = append(metadata, map[string]interface{}{
 			metadataField + ".k": key,
 			metadataField + ".v": value,
@@ -551,16 +931,30 @@ func processMetadataConditions(conditions *map[string]interface{}) {
 	delete(*conditions, metadataField)
 }
 
+// getMongoMetadataOperator will return the key / value condition of a metadata key operator ($exists or $in)
+func getMongoMetadataOperator(key, operator string, operand interface{}) map[string]interface{} {
+	if operator == conditionIn {
+		return map[string]interface{}{
+			metadataField + ".k": key,
+			metadataField + ".v": map[string]interface{}{conditionIn: operand},
+		}
+	} else if exists, _ := operand.(bool); !exists {
+		return map[string]interface{}{metadataField + ".k": map[string]interface{}{conditionNotEquals: key}}
+	}
+	return map[string]interface{}{metadataField + ".k": key}
+}
+
 // openMongoDatabase will open a new database or use an existing connection
-func openMongoDatabase(ctx context.Context, config *MongoDBConfig) (*mongo.Database, error) {
+func openMongoDatabase(ctx context.Context, config *MongoDBConfig,
+	diagnostics *deadlockDiagnostics) (*mongo.Database, error) {
 
 	// Use an existing connection
 	if config.ExistingConnection != nil {
 		return config.ExistingConnection, nil
 	}
 
-	// Create the new client
-	nrMon := nrmongo.NewCommandMonitor(nil)
+	// Create the new client (NewRelic wraps the query capture monitor)
+	nrMon := nrmongo.NewCommandMonitor(newQueryCaptureMonitor(diagnostics))
 	client, err := mongo.Connect(
 		ctx,
 		options.Client().SetMonitor(nrMon),
@@ -572,6 +966,7 @@ func openMongoDatabase(ctx context.Context, config *MongoDBConfig) (*mongo.Datab
 
 	// Check the connection
 	if err = client.Ping(ctx, readpref.Primary()); err != nil {
+		_ = client.Disconnect(ctx)
 		return nil, err
 	}
 
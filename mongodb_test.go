@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type mockModel struct {
@@ -93,6 +94,70 @@ func TestClient_getMongoQueryConditions(t *testing.T) {
 		assert.Equal(t, map[string]interface{}{mongoIDField: "identifier"}, queryConditions)
 	})
 
+	t.Run(conditionLike+" and "+conditionILike, func(t *testing.T) {
+		condition := map[string]interface{}{
+			"name":  map[string]interface{}{conditionLike: "%smith_"},
+			"email": map[string]interface{}{conditionILike: "%@EXAMPLE.com"},
+		}
+		queryConditions := getMongoQueryConditions(nil, condition, nil)
+		expected := map[string]interface{}{
+			"name":  map[string]interface{}{conditionRegex: "^.*smith.$", conditionRegexOptions: "s"},
+			"email": map[string]interface{}{conditionRegex: "^.*@EXAMPLE\\.com$", conditionRegexOptions: "is"},
+		}
+		assert.Equal(t, expected, queryConditions)
+	})
+
+	t.Run(conditionBetween, func(t *testing.T) {
+		condition := map[string]interface{}{
+			"amount": map[string]interface{}{conditionBetween: []interface{}{100, 200}},
+		}
+		queryConditions := getMongoQueryConditions(nil, condition, nil)
+		assert.Equal(t, map[string]interface{}{
+			"amount": map[string]interface{}{conditionGreaterThanOrEqual: 100, conditionLessThanOrEqual: 200},
+		}, queryConditions)
+	})
+
+	t.Run(conditionStartsWith+" and "+conditionEndsWith, func(t *testing.T) {
+		condition := map[string]interface{}{
+			"name":  map[string]interface{}{conditionStartsWith: "a.b"},
+			"email": map[string]interface{}{conditionEndsWith: "@example.com"},
+			"both":  map[string]interface{}{conditionStartsWith: "a", conditionEndsWith: "z"},
+		}
+		queryConditions := getMongoQueryConditions(nil, condition, nil)
+		assert.Equal(t, map[string]interface{}{
+			"name":  map[string]interface{}{conditionRegex: "^a\\.b", conditionRegexOptions: "s"},
+			"email": map[string]interface{}{conditionRegex: "@example\\.com$", conditionRegexOptions: "s"},
+			"both":  map[string]interface{}{conditionRegex: "^a.*z$", conditionRegexOptions: "s"},
+		}, queryConditions)
+	})
+
+	t.Run(conditionEqOrNull, func(t *testing.T) {
+		condition := map[string]interface{}{
+			"name":   map[string]interface{}{conditionEqOrNull: "abc"},
+			"amount": 10,
+		}
+		queryConditions := getMongoQueryConditions(nil, condition, nil)
+		assert.Equal(t, map[string]interface{}{
+			"amount": 10,
+			conditionAnd: []map[string]interface{}{{
+				conditionOr: []map[string]interface{}{
+					{"name": map[string]interface{}{conditionEquals: "abc"}},
+					{"name": nil},
+				},
+			}},
+		}, queryConditions)
+	})
+
+	t.Run(conditionRegex, func(t *testing.T) {
+		condition := map[string]interface{}{
+			"name": map[string]interface{}{conditionRegex: "^j", conditionRegexOptions: "i"},
+		}
+		queryConditions := getMongoQueryConditions(nil, condition, nil)
+		assert.Equal(t, map[string]interface{}{
+			"name": map[string]interface{}{conditionRegex: "^j", conditionRegexOptions: "i"},
+		}, queryConditions)
+	})
+
 	t.Run(conditionOr+" "+sqlIDFieldProper, func(t *testing.T) {
 		condition := map[string]interface{}{
 			conditionOr: []map[string]interface{}{{
@@ -472,3 +537,41 @@ func processObjectOutputValueConditions(conditions *map[string]interface{}) {
 
 	delete(*conditions, fieldName)
 }
+
+// Test_likeToRegex will test the method likeToRegex()
+func Test_likeToRegex(t *testing.T) {
+	assert.Equal(t, "^.*smith.*$", likeToRegex("%smith%"))
+	assert.Equal(t, "^a.c$", likeToRegex("a_c"))
+	assert.Equal(t, "^100%$", likeToRegex("100\\%"))
+	assert.Equal(t, "^a\\.b\\(c\\)$", likeToRegex("a.b(c)"))
+}
+
+// Test_getMongoUpsert will test the method getMongoUpsert()
+func Test_getMongoUpsert(t *testing.T) {
+	document := bson.M{mongoIDField: "upsert-1", "name": "alice", "amount": int64(5)}
+
+	t.Run("update the named columns", func(t *testing.T) {
+		filter, update, err := getMongoUpsert(document, []string{sqlIDField}, []string{"name"})
+		require.NoError(t, err)
+		assert.Equal(t, bson.M{mongoIDField: "upsert-1"}, filter)
+		assert.Equal(t, bson.M{
+			conditionSet:         bson.M{"name": "alice"},
+			conditionSetOnInsert: bson.M{mongoIDField: "upsert-1", "amount": int64(5)},
+		}, update)
+	})
+
+	t.Run("update all columns", func(t *testing.T) {
+		filter, update, err := getMongoUpsert(document, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, bson.M{mongoIDField: "upsert-1"}, filter)
+		assert.Equal(t, bson.M{
+			conditionSet:         bson.M{"name": "alice", "amount": int64(5)},
+			conditionSetOnInsert: bson.M{mongoIDField: "upsert-1"},
+		}, update)
+	})
+
+	t.Run("missing conflict column", func(t *testing.T) {
+		_, _, err := getMongoUpsert(document, []string{"email"}, nil)
+		require.ErrorIs(t, err, ErrMissingPrimaryKey)
+	})
+}
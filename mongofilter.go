@@ -0,0 +1,95 @@
+package datastore
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrInvalidCondition is when a condition uses an unknown top-level operator or an invalid $and / $or value
+var ErrInvalidCondition = errors.New("invalid condition")
+
+// BuildMongoFilter will return the Mongo filter for the conditions, using the same processing as the
+// datastore queries (id to _id, metadata, custom processor, operators)
+//
+// The keys are sorted (deterministic), so the filter can be reused with the mongo driver (IE: change streams)
+// The given conditions are not modified
+func BuildMongoFilter(model interface{}, conditions map[string]interface{},
+	processor func(conditions *map[string]interface{}),
+) (bson.D, error) {
+	if err := validateMongoConditions(conditions); err != nil {
+		return nil, err
+	}
+	return toSortedBSON(getMongoQueryConditions(model, copyConditions(conditions), processor)).(bson.D), nil
+}
+
+// validateMongoConditions will check the top-level operators and the $and / $or items
+func validateMongoConditions(conditions map[string]interface{}) error {
+	for key, condition := range conditions {
+		if key == conditionAnd || key == conditionOr {
+			v := reflect.ValueOf(condition)
+			if v.Kind() != reflect.Slice {
+				return fmt.Errorf("%w: %s must be a list of conditions", ErrInvalidCondition, key)
+			}
+			for i := 0; i < v.Len(); i++ {
+				item, ok := v.Index(i).Interface().(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("%w: %s must be a list of conditions", ErrInvalidCondition, key)
+				} else if err := validateMongoConditions(item); err != nil {
+					return err
+				}
+			}
+		} else if strings.HasPrefix(key, "$") {
+			return fmt.Errorf("%w: unknown operator %s", ErrInvalidCondition, key)
+		}
+	}
+	return nil
+}
+
+// toSortedBSON will convert the maps to bson.D (sorted by key) and the slices of maps to bson.A
+func toSortedBSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return sortedBSONDocument(v)
+	case bson.M:
+		return sortedBSONDocument(v)
+	case []map[string]interface{}:
+		items := make(bson.A, 0, len(v))
+		for _, item := range v {
+			items = append(items, sortedBSONDocument(item))
+		}
+		return items
+	case []interface{}:
+		items := make(bson.A, 0, len(v))
+		for _, item := range v {
+			items = append(items, toSortedBSON(item))
+		}
+		return items
+	case bson.A:
+		items := make(bson.A, 0, len(v))
+		for _, item := range v {
+			items = append(items, toSortedBSON(item))
+		}
+		return items
+	}
+	return value
+}
+
+// sortedBSONDocument will convert the map to a bson.D sorted by key
+func sortedBSONDocument(document map[string]interface{}) bson.D {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sorted := make(bson.D, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, bson.E{Key: key, Value: toSortedBSON(document[key])})
+	}
+	return sorted
+}
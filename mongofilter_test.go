@@ -0,0 +1,83 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestBuildMongoFilter will test the method BuildMongoFilter()
+func TestBuildMongoFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sorted filter", func(t *testing.T) {
+		conditions := map[string]interface{}{
+			"name":     "a",
+			sqlIDField: "id-1",
+			"amount":   map[string]interface{}{conditionGreaterThan: 1, conditionLessThan: 5},
+		}
+		filter, err := BuildMongoFilter(&testSQLModel{}, conditions, nil)
+		require.NoError(t, err)
+		assert.Equal(t, bson.D{
+			{Key: mongoIDField, Value: "id-1"},
+			{Key: "amount", Value: bson.D{{Key: conditionGreaterThan, Value: 1}, {Key: conditionLessThan, Value: 5}}},
+			{Key: "name", Value: "a"},
+		}, filter)
+
+		// The conditions are not modified
+		assert.Equal(t, "id-1", conditions[sqlIDField])
+		assert.NotContains(t, conditions, mongoIDField)
+	})
+
+	t.Run("bitwise operators are native", func(t *testing.T) {
+		filter, err := BuildMongoFilter(&testSQLModel{}, map[string]interface{}{
+			"flags": map[string]interface{}{conditionBitsAllSet: 6, conditionBitsAnySet: []int{0}},
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, bson.D{{Key: "flags", Value: bson.D{
+			{Key: conditionBitsAllSet, Value: 6},
+			{Key: conditionBitsAnySet, Value: []int{0}},
+		}}}, filter)
+	})
+
+	t.Run("nil conditions", func(t *testing.T) {
+		filter, err := BuildMongoFilter(&testSQLModel{}, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, bson.D{}, filter)
+	})
+
+	t.Run("$or items", func(t *testing.T) {
+		filter, err := BuildMongoFilter(&testSQLModel{}, map[string]interface{}{
+			conditionOr: []map[string]interface{}{{"name": "a"}, {"name": "b"}},
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, bson.D{{Key: conditionOr, Value: bson.A{
+			bson.D{{Key: "name", Value: "a"}},
+			bson.D{{Key: "name", Value: "b"}},
+		}}}, filter)
+	})
+
+	t.Run("custom processor", func(t *testing.T) {
+		filter, err := BuildMongoFilter(&testSQLModel{}, map[string]interface{}{"name": "a"},
+			func(conditions *map[string]interface{}) {
+				(*conditions)["processed"] = true
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, bson.D{{Key: "name", Value: "a"}, {Key: "processed", Value: true}}, filter)
+	})
+
+	t.Run("invalid conditions", func(t *testing.T) {
+		for _, conditions := range []map[string]interface{}{
+			{"$where": "this.a > 1"},
+			{conditionAnd: "name"},
+			{conditionOr: []interface{}{"name"}},
+			{conditionAnd: []map[string]interface{}{{"$where": "x"}}},
+		} {
+			_, err := BuildMongoFilter(&testSQLModel{}, conditions, nil)
+			require.ErrorIs(t, err, ErrInvalidCondition)
+		}
+	})
+}
@@ -0,0 +1,341 @@
+package datastore
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrEmptyResultGuaranteed is when the conditions can never match a record (IE: $gt 5 AND $lt 2)
+var ErrEmptyResultGuaranteed = errors.New("conditions can never match, empty result guaranteed")
+
+// NormalizeConditions will return the normalized conditions (the given conditions are not modified)
+//
+// Duplicate clauses are removed, $and items (and a single $or item) are merged into the parent conditions,
+// operators on the same field are merged (IE: $gt and $lt) and contradictions return ErrEmptyResultGuaranteed
+// Custom array and object fields are never merged (their equality is a "contains" match)
+func (c *Client) NormalizeConditions(conditions map[string]interface{}) (map[string]interface{}, error) {
+	if conditions == nil {
+		return nil, nil
+	}
+
+	// Only the standard $and and $or items are normalized
+	for _, key := range []string{conditionAnd, conditionOr} {
+		if value, exists := conditions[key]; exists {
+			if _, ok := value.([]map[string]interface{}); !ok {
+				return conditions, nil
+			}
+		}
+	}
+
+	// Process the fields first, then merge the $and and $or items into them
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		if key != conditionAnd && key != conditionOr {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	normalized := make(map[string]interface{}, len(conditions))
+	var remaining []map[string]interface{}
+	for _, key := range keys {
+		value, err := c.normalizeConditionValue(conditions[key])
+		if err != nil {
+			return nil, err
+		}
+		var merged bool
+		if merged, err = c.mergeCondition(normalized, key, value); err != nil {
+			return nil, err
+		} else if !merged {
+			remaining = appendUniqueCondition(remaining, map[string]interface{}{key: value})
+		}
+	}
+
+	// Merge the $and items
+	items, _ := conditions[conditionAnd].([]map[string]interface{})
+	for _, item := range items {
+		normalizedItem, err := c.NormalizeConditions(item)
+		if err != nil {
+			return nil, err
+		}
+		if remaining, err = c.mergeConditions(normalized, normalizedItem, remaining); err != nil {
+			return nil, err
+		}
+	}
+
+	// Remove the duplicate (and never matching) $or items, a single item is merged
+	if items, ok := conditions[conditionOr].([]map[string]interface{}); ok {
+		var or []map[string]interface{}
+		for _, item := range items {
+			normalizedItem, err := c.NormalizeConditions(item)
+			if errors.Is(err, ErrEmptyResultGuaranteed) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			or = appendUniqueCondition(or, normalizedItem)
+		}
+		if len(or) == 0 && len(items) > 0 {
+			return nil, ErrEmptyResultGuaranteed
+		} else if len(or) == 1 {
+			var err error
+			if remaining, err = c.mergeConditions(normalized, or[0], remaining); err != nil {
+				return nil, err
+			}
+		} else if len(or) > 1 {
+			if _, exists := normalized[conditionOr]; exists {
+				remaining = appendUniqueCondition(remaining, map[string]interface{}{conditionOr: or})
+			} else {
+				normalized[conditionOr] = or
+			}
+		}
+	}
+
+	if len(remaining) > 0 {
+		normalized[conditionAnd] = remaining
+	}
+	return normalized, nil
+}
+
+// normalizeQueryConditions will normalize the conditions if enabled (see: WithConditionNormalization)
+func (c *Client) normalizeQueryConditions(conditions map[string]interface{}) (map[string]interface{}, error) {
+	if !c.options.normalizeConditions {
+		return conditions, nil
+	}
+	return c.NormalizeConditions(conditions)
+}
+
+// normalizeConditionValue will check the operators of a field for contradictions (IE: $gt 5 and $lt 2)
+func (c *Client) normalizeConditionValue(value interface{}) (interface{}, error) {
+	operators, ok := value.(map[string]interface{})
+	if !ok || !isOperatorMap(operators) {
+		return value, nil
+	}
+	if err := checkConditionBounds(operators); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// mergeConditions will merge the fields of the item into the conditions (fields that cannot be merged are
+// appended to the remaining $and items)
+func (c *Client) mergeConditions(conditions, item map[string]interface{},
+	remaining []map[string]interface{},
+) ([]map[string]interface{}, error) {
+	keys := make([]string, 0, len(item))
+	for key := range item {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	leftover := make(map[string]interface{})
+	for _, key := range keys {
+		if key == conditionAnd {
+			for _, and := range item[key].([]map[string]interface{}) {
+				remaining = appendUniqueCondition(remaining, and)
+			}
+			continue
+		} else if key == conditionOr {
+			if _, exists := conditions[key]; exists {
+				leftover[key] = item[key]
+			} else {
+				conditions[key] = item[key]
+			}
+			continue
+		}
+		merged, err := c.mergeCondition(conditions, key, item[key])
+		if err != nil {
+			return nil, err
+		} else if !merged {
+			leftover[key] = item[key]
+		}
+	}
+	if len(leftover) > 0 {
+		remaining = appendUniqueCondition(remaining, leftover)
+	}
+	return remaining, nil
+}
+
+// mergeCondition will add the condition for the field, returns false if it cannot be merged with the
+// existing condition (the caller keeps it in the $and items)
+func (c *Client) mergeCondition(conditions map[string]interface{}, key string, value interface{}) (bool, error) {
+	existing, exists := conditions[key]
+	if !exists {
+		conditions[key] = value
+		return true, nil
+	} else if reflect.DeepEqual(existing, value) {
+		return true, nil
+	} else if StringInSlice(key, c.GetArrayFields()) || StringInSlice(key, c.GetObjectFields()) {
+		return false, nil
+	}
+
+	existingOperators, existingIsMap := existing.(map[string]interface{})
+	operators, isMap := value.(map[string]interface{})
+	switch {
+	case existingIsMap && isMap && isOperatorMap(existingOperators) && isOperatorMap(operators):
+		merged := make(map[string]interface{}, len(existingOperators)+len(operators))
+		for operator, operand := range existingOperators {
+			merged[operator] = operand
+		}
+		for operator, operand := range operators {
+			if current, ok := merged[operator]; ok && !reflect.DeepEqual(current, operand) {
+				return false, nil
+			}
+			merged[operator] = operand
+		}
+		if err := checkConditionBounds(merged); err != nil {
+			return false, err
+		}
+		conditions[key] = merged
+		return true, nil
+	case !existingIsMap && !isMap:
+		return compareEqualValues(existing, value)
+	case existingIsMap && !isMap && isOperatorMap(existingOperators):
+		return false, checkConditionBounds(existingOperators, value)
+	case !existingIsMap && isMap && isOperatorMap(operators):
+		return false, checkConditionBounds(operators, existing)
+	}
+	return false, nil
+}
+
+// checkConditionBounds will return ErrEmptyResultGuaranteed if the range operators (and the optional
+// equal values) can never match
+func checkConditionBounds(operators map[string]interface{}, equals ...interface{}) error {
+	if in, ok := operators[conditionIn]; ok {
+		if v := reflect.ValueOf(in); (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Len() == 0 {
+			return ErrEmptyResultGuaranteed
+		}
+	}
+
+	var lower, upper interface{}
+	var lowerStrict, upperStrict bool
+	if value, ok := operators[conditionGreaterThan]; ok {
+		lower, lowerStrict = value, true
+	}
+	if value, ok := operators[conditionGreaterThanOrEqual]; ok {
+		if lower == nil || compareAbove(value, lower) {
+			lower, lowerStrict = value, false
+		}
+	}
+	if value, ok := operators[conditionLessThan]; ok {
+		upper, upperStrict = value, true
+	}
+	if value, ok := operators[conditionLessThanOrEqual]; ok {
+		if upper == nil || compareAbove(upper, value) {
+			upper, upperStrict = value, false
+		}
+	}
+
+	if lower != nil && upper != nil {
+		if result, ok := compareConditionValues(lower, upper); ok &&
+			(result > 0 || (result == 0 && (lowerStrict || upperStrict))) {
+			return ErrEmptyResultGuaranteed
+		}
+	}
+	for _, value := range equals {
+		if value == nil {
+			if lower != nil || upper != nil {
+				return ErrEmptyResultGuaranteed // NULL does not match a range
+			}
+			continue
+		}
+		if lower != nil {
+			if result, ok := compareConditionValues(value, lower); ok && (result < 0 || (result == 0 && lowerStrict)) {
+				return ErrEmptyResultGuaranteed
+			}
+		}
+		if upper != nil {
+			if result, ok := compareConditionValues(value, upper); ok && (result > 0 || (result == 0 && upperStrict)) {
+				return ErrEmptyResultGuaranteed
+			}
+		}
+	}
+	return nil
+}
+
+// compareAbove will return true if a is (known to be) greater than b
+func compareAbove(a, b interface{}) bool {
+	result, ok := compareConditionValues(a, b)
+	return ok && result > 0
+}
+
+// compareConditionValues will compare two numbers or two times (false if they cannot be compared)
+func compareConditionValues(a, b interface{}) (int, bool) {
+	if aTime, ok := a.(time.Time); ok {
+		if bTime, isTime := b.(time.Time); isTime {
+			return aTime.Compare(bTime), true
+		}
+		return 0, false
+	}
+
+	aNumber, aOk := getConditionNumber(a)
+	bNumber, bOk := getConditionNumber(b)
+	if !aOk || !bOk {
+		return 0, false
+	} else if aNumber < bNumber {
+		return -1, true
+	} else if aNumber > bNumber {
+		return 1, true
+	}
+	return 0, true
+}
+
+// getConditionNumber will return the number as a float64 (false if not a number)
+func getConditionNumber(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() { //nolint:exhaustive // only numbers are compared
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// compareEqualValues will return true if two equal conditions are the same (merged), or
+// ErrEmptyResultGuaranteed if they can never both match (IE: a number, string, boolean or time and NULL)
+func compareEqualValues(a, b interface{}) (bool, error) {
+	if a == nil || b == nil {
+		return false, ErrEmptyResultGuaranteed // IS NULL and equal to a value
+	} else if result, ok := compareConditionValues(a, b); ok {
+		if result == 0 {
+			return true, nil
+		}
+		return false, ErrEmptyResultGuaranteed
+	} else if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false, nil
+	}
+	switch a.(type) {
+	case string, bool:
+		return false, ErrEmptyResultGuaranteed // Not equal (checked by the caller)
+	}
+	return false, nil
+}
+
+// isOperatorMap will return true if all the keys are operators (IE: {"$gt": 1, "$lt": 5})
+func isOperatorMap(conditions map[string]interface{}) bool {
+	if len(conditions) == 0 {
+		return false
+	}
+	for key := range conditions {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+// appendUniqueCondition will append the conditions if they are not already in the list
+func appendUniqueCondition(list []map[string]interface{}, conditions map[string]interface{}) []map[string]interface{} {
+	for _, existing := range list {
+		if reflect.DeepEqual(existing, conditions) {
+			return list
+		}
+	}
+	return append(list, conditions)
+}
@@ -0,0 +1,170 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_NormalizeConditions will test the method NormalizeConditions()
+func TestClient_NormalizeConditions(t *testing.T) {
+	client := &Client{options: &clientOptions{
+		engine: SQLite,
+		fields: &fieldConfig{arrayFields: []string{"tags"}},
+	}}
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		expected   map[string]interface{}
+	}{
+		{
+			name:       "nil",
+			conditions: nil,
+			expected:   nil,
+		},
+		{
+			name: "single $and item is collapsed",
+			conditions: map[string]interface{}{
+				conditionAnd: []map[string]interface{}{{"name": "a"}},
+			},
+			expected: map[string]interface{}{"name": "a"},
+		},
+		{
+			name: "duplicate clauses are merged",
+			conditions: map[string]interface{}{
+				"name": "a",
+				conditionAnd: []map[string]interface{}{
+					{"name": "a"},
+					{"amount": map[string]interface{}{conditionGreaterThan: 1}},
+					{"amount": map[string]interface{}{conditionLessThan: 10}},
+				},
+			},
+			expected: map[string]interface{}{
+				"name":   "a",
+				"amount": map[string]interface{}{conditionGreaterThan: 1, conditionLessThan: 10},
+			},
+		},
+		{
+			name: "single $or item is merged",
+			conditions: map[string]interface{}{
+				conditionOr: []map[string]interface{}{{"name": "a"}, {"name": "a"}},
+			},
+			expected: map[string]interface{}{"name": "a"},
+		},
+		{
+			name: "never matching $or items are removed",
+			conditions: map[string]interface{}{
+				conditionOr: []map[string]interface{}{
+					{"name": "a"},
+					{"amount": map[string]interface{}{conditionGreaterThan: 5, conditionLessThan: 2}},
+					{"name": "b"},
+				},
+			},
+			expected: map[string]interface{}{
+				conditionOr: []map[string]interface{}{{"name": "a"}, {"name": "b"}},
+			},
+		},
+		{
+			name: "array fields are not merged",
+			conditions: map[string]interface{}{
+				"tags":       "a",
+				conditionAnd: []map[string]interface{}{{"tags": "b"}},
+			},
+			expected: map[string]interface{}{
+				"tags":       "a",
+				conditionAnd: []map[string]interface{}{{"tags": "b"}},
+			},
+		},
+		{
+			name: "same operator with different values is kept",
+			conditions: map[string]interface{}{
+				"amount":     map[string]interface{}{conditionGreaterThan: 1},
+				conditionAnd: []map[string]interface{}{{"amount": map[string]interface{}{conditionGreaterThan: 2}}},
+			},
+			expected: map[string]interface{}{
+				"amount":     map[string]interface{}{conditionGreaterThan: 1},
+				conditionAnd: []map[string]interface{}{{"amount": map[string]interface{}{conditionGreaterThan: 2}}},
+			},
+		},
+		{
+			name: "time range",
+			conditions: map[string]interface{}{
+				"created_at": map[string]interface{}{
+					conditionGreaterThanOrEqual: now.Add(-time.Hour), conditionLessThan: now,
+				},
+			},
+			expected: map[string]interface{}{
+				"created_at": map[string]interface{}{
+					conditionGreaterThanOrEqual: now.Add(-time.Hour), conditionLessThan: now,
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalized, err := client.NormalizeConditions(test.conditions)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, normalized)
+		})
+	}
+
+	t.Run("contradictions", func(t *testing.T) {
+		for _, conditions := range []map[string]interface{}{
+			{"amount": map[string]interface{}{conditionGreaterThan: 5, conditionLessThan: 2}},
+			{"amount": map[string]interface{}{conditionGreaterThan: 5, conditionLessThanOrEqual: 5}},
+			{"amount": map[string]interface{}{conditionIn: []interface{}{}}},
+			{"created_at": map[string]interface{}{conditionGreaterThan: now, conditionLessThan: now.Add(-time.Hour)}},
+			{"name": "a", conditionAnd: []map[string]interface{}{{"name": "b"}}},
+			{"amount": 1, conditionAnd: []map[string]interface{}{{"amount": map[string]interface{}{conditionGreaterThan: 2}}}},
+			{"name": nil, conditionAnd: []map[string]interface{}{{"name": "a"}}},
+			{conditionAnd: []map[string]interface{}{
+				{"amount": map[string]interface{}{conditionGreaterThan: 5}},
+				{"amount": map[string]interface{}{conditionLessThan: 2}},
+			}},
+			{conditionOr: []map[string]interface{}{
+				{"amount": map[string]interface{}{conditionGreaterThan: 5, conditionLessThan: 2}},
+			}},
+		} {
+			_, err := client.NormalizeConditions(conditions)
+			require.ErrorIs(t, err, ErrEmptyResultGuaranteed, conditions)
+		}
+	})
+
+	t.Run("conditions are not modified", func(t *testing.T) {
+		conditions := map[string]interface{}{
+			"name":       "a",
+			conditionAnd: []map[string]interface{}{{"amount": 1}},
+		}
+		_, err := client.NormalizeConditions(conditions)
+		require.NoError(t, err)
+		assert.Len(t, conditions, 2)
+	})
+}
+
+// TestClient_GetModels_normalizeConditions will test the option WithConditionNormalization()
+func TestClient_GetModels_normalizeConditions(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t, WithConditionNormalization())
+	defer deferFunc()
+	testSaveModels(ctx, t, client, &testSQLModel{ID: "normalize-1", Name: "a", Amount: 3})
+
+	count, err := client.GetModelCount(ctx, &testSQLModel{}, map[string]interface{}{
+		conditionAnd: []map[string]interface{}{
+			{"amount": map[string]interface{}{conditionGreaterThan: 1}},
+			{"amount": map[string]interface{}{conditionLessThan: 5}},
+		},
+	}, defaultDatabaseMaxTimeout)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	var models []*testSQLModel
+	err = client.GetModels(ctx, &models, map[string]interface{}{
+		"amount": map[string]interface{}{conditionGreaterThan: 5, conditionLessThan: 2},
+	}, nil, nil, defaultDatabaseMaxTimeout)
+	require.ErrorIs(t, err, ErrEmptyResultGuaranteed)
+}
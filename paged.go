@@ -0,0 +1,158 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PagedResult is a page of results with the total count of matching records (see: GetModelsPaged)
+type PagedResult struct {
+	Items      interface{}    `json:"items"`       // Models (the given slice)
+	Page       int            `json:"page"`        // Current page (starting at 1)
+	PageSize   int            `json:"page_size"`   // Number of results per page
+	Total      int64          `json:"total"`       // Total number of matching records (zero for TotalCountNone)
+	TotalMode  TotalCountMode `json:"total_mode"`  // How the total was computed (see: QueryParams.TotalCountMode)
+	TotalPages int            `json:"total_pages"` // Total number of pages (zero for TotalCountNone)
+}
+
+// GetModelsPaged will get a page of models and the total count of matching records in one call
+//
+// Defaults to the first page (and the model or default page size), the count uses the same conditions (WHERE clause)
+// The count can be estimated (query planner) or skipped using QueryParams.TotalCountMode
+func (c *Client) GetModelsPaged(
+	ctx context.Context,
+	models interface{},
+	conditions map[string]interface{},
+	queryParams *QueryParams,
+	timeout time.Duration,
+) (*PagedResult, error) {
+
+	// Always use a page
+	params := QueryParams{}
+	if queryParams != nil {
+		params = *queryParams
+	}
+	if params.Page < 1 {
+		params.Page = 1
+	}
+
+	var total int64
+	if err := c.getModels(ctx, models, conditions, &params, nil, timeout, &total); err != nil {
+		return nil, err
+	}
+
+	result := &PagedResult{
+		Items:     models,
+		Page:      params.Page,
+		PageSize:  params.PageSize,
+		Total:     total,
+		TotalMode: params.TotalCountMode,
+	}
+	if len(result.TotalMode) == 0 {
+		result.TotalMode = TotalCountExact
+	}
+	if result.TotalMode != TotalCountNone {
+		result.TotalPages = int((total + int64(params.PageSize) - 1) / int64(params.PageSize))
+	}
+	return result, nil
+}
+
+// estimateCount will set the query planner estimate of the number of matching records (see: TotalCountEstimated)
+//
+// PostgreSQL: EXPLAIN plan rows, MySQL: EXPLAIN rows (filtered), other engines use an exact count
+func (c *Client) estimateCount(tx *gorm.DB, result interface{}, total *int64) error {
+	engine := c.Engine()
+	if engine != PostgreSQL && engine != MySQL {
+		return checkResult(tx.Count(total))
+	}
+
+	// Build the query (without running it)
+	stmt := tx.Session(&gorm.Session{DryRun: true}).Find(result).Statement
+	if stmt.Error != nil {
+		return stmt.Error
+	}
+	explain := tx.Session(&gorm.Session{NewDB: true})
+
+	var err error
+	if engine == PostgreSQL {
+		var plan string
+		if err = explain.Raw("EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...).Row().Scan(&plan); err != nil {
+			return err
+		}
+		*total, err = getPostgresPlanRows([]byte(plan))
+		return err
+	}
+
+	var rows []map[string]interface{}
+	if err = explain.Raw("EXPLAIN "+stmt.SQL.String(), stmt.Vars...).Scan(&rows).Error; err != nil {
+		return err
+	} else if len(rows) == 0 {
+		return checkResult(tx.Count(total))
+	}
+	*total = getMySQLExplainRows(rows[0])
+	return nil
+}
+
+// getPostgresPlanRows will return the estimated rows of the top plan node (EXPLAIN (FORMAT JSON))
+func getPostgresPlanRows(plan []byte) (int64, error) {
+	var explained []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explained); err != nil {
+		return 0, err
+	} else if len(explained) == 0 {
+		return 0, fmt.Errorf("empty query plan: %s", plan)
+	}
+	return int64(explained[0].Plan.Rows), nil
+}
+
+// getMySQLExplainRows will return the estimated rows (rows * filtered %) of the first EXPLAIN row
+func getMySQLExplainRows(row map[string]interface{}) int64 {
+	rows := getExplainNumber(row["rows"])
+	if filtered, ok := row["filtered"]; ok && filtered != nil {
+		rows = rows * getExplainNumber(filtered) / 100
+	}
+	return int64(rows)
+}
+
+// getExplainNumber will return the scanned number (drivers can return []byte, strings or numbers)
+func getExplainNumber(value interface{}) float64 {
+	value = getScannedValue(value)
+	if raw, ok := value.([]byte); ok {
+		value = string(raw)
+	}
+	number, _ := strconv.ParseFloat(fmt.Sprint(value), 64)
+	return number
+}
+
+// copyConditions will return a deep copy of the conditions (nested conditions and slices of conditions)
+//
+// Used when the same conditions are processed twice (processing the MongoDB conditions modifies them)
+func copyConditions(conditions map[string]interface{}) map[string]interface{} {
+	if conditions == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(conditions))
+	for key, value := range conditions {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			copied[key] = copyConditions(v)
+		case []map[string]interface{}:
+			items := make([]map[string]interface{}, 0, len(v))
+			for _, item := range v {
+				items = append(items, copyConditions(item))
+			}
+			copied[key] = items
+		default:
+			copied[key] = value
+		}
+	}
+	return copied
+}
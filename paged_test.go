@@ -0,0 +1,136 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_GetModelsPaged will test the method GetModelsPaged()
+func TestClient_GetModelsPaged(t *testing.T) {
+	t.Run("page with total", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "paged-1", Amount: 10},
+			&testSQLModel{ID: "paged-2", Amount: 10},
+			&testSQLModel{ID: "paged-3", Amount: 10},
+			&testSQLModel{ID: "paged-4", Amount: 1},
+		)
+
+		var models []*testSQLModel
+		result, err := client.GetModelsPaged(ctx, &models, map[string]interface{}{"amount": 10}, &QueryParams{
+			OrderByField: sqlIDField, Page: 2, PageSize: 2, SortDirection: SortAsc,
+		}, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), result.Total)
+		assert.Equal(t, 2, result.TotalPages)
+		assert.Equal(t, 2, result.Page)
+		assert.Equal(t, 2, result.PageSize)
+		require.Len(t, models, 1)
+		assert.Equal(t, "paged-3", models[0].ID)
+		assert.Equal(t, &models, result.Items)
+	})
+
+	t.Run("defaults to the first page", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "paged-1"})
+
+		var models []*testSQLModel
+		result, err := client.GetModelsPaged(ctx, &models, nil, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Page)
+		assert.Equal(t, defaultPageSize, result.PageSize)
+		assert.Equal(t, int64(1), result.Total)
+		assert.Equal(t, 1, result.TotalPages)
+		assert.Len(t, models, 1)
+	})
+
+	t.Run("total count modes", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "paged-1", Amount: 10},
+			&testSQLModel{ID: "paged-2", Amount: 10},
+			&testSQLModel{ID: "paged-3", Amount: 1},
+		)
+		conditions := map[string]interface{}{"amount": 10}
+
+		var models []*testSQLModel
+		result, err := client.GetModelsPaged(ctx, &models, conditions, &QueryParams{PageSize: 1}, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, TotalCountExact, result.TotalMode)
+		assert.Equal(t, int64(2), result.Total)
+
+		// SQLite has no estimate (exact count)
+		models = nil
+		result, err = client.GetModelsPaged(ctx, &models, conditions, &QueryParams{
+			PageSize: 1, TotalCountMode: TotalCountEstimated,
+		}, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, TotalCountEstimated, result.TotalMode)
+		assert.Equal(t, int64(2), result.Total)
+		assert.Equal(t, 2, result.TotalPages)
+
+		models = nil
+		result, err = client.GetModelsPaged(ctx, &models, conditions, &QueryParams{
+			PageSize: 1, TotalCountMode: TotalCountNone,
+		}, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, TotalCountNone, result.TotalMode)
+		assert.Zero(t, result.Total)
+		assert.Zero(t, result.TotalPages)
+		assert.Len(t, models, 1)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		var models []*testSQLModel
+		_, err := client.GetModelsPaged(context.Background(), &models, nil, nil, defaultDatabaseMaxTimeout)
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// Test_getPostgresPlanRows will test the method getPostgresPlanRows()
+func Test_getPostgresPlanRows(t *testing.T) {
+	rows, err := getPostgresPlanRows([]byte(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 1250}}]`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1250), rows)
+
+	_, err = getPostgresPlanRows([]byte(`[]`))
+	require.Error(t, err)
+	_, err = getPostgresPlanRows([]byte(`invalid`))
+	require.Error(t, err)
+}
+
+// Test_getMySQLExplainRows will test the method getMySQLExplainRows()
+func Test_getMySQLExplainRows(t *testing.T) {
+	assert.Equal(t, int64(250), getMySQLExplainRows(map[string]interface{}{
+		"rows": int64(1000), "filtered": []byte("25.00"),
+	}))
+	assert.Equal(t, int64(1000), getMySQLExplainRows(map[string]interface{}{"rows": "1000", "filtered": nil}))
+	assert.Zero(t, getMySQLExplainRows(map[string]interface{}{}))
+}
+
+// Test_copyConditions will test the method copyConditions()
+func Test_copyConditions(t *testing.T) {
+	assert.Nil(t, copyConditions(nil))
+
+	conditions := map[string]interface{}{
+		"name":      map[string]interface{}{conditionEqOrNull: "alice"},
+		conditionOr: []map[string]interface{}{{"amount": 1}},
+	}
+	copied := copyConditions(conditions)
+	assert.Equal(t, conditions, copied)
+
+	delete(copied["name"].(map[string]interface{}), conditionEqOrNull)
+	copied[conditionOr].([]map[string]interface{})[0]["amount"] = 2
+	assert.Equal(t, map[string]interface{}{conditionEqOrNull: "alice"}, conditions["name"])
+	assert.Equal(t, 1, conditions[conditionOr].([]map[string]interface{})[0]["amount"])
+}
@@ -2,21 +2,58 @@ package datastore
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/99designs/gqlgen/graphql"
 )
 
 // QueryParams object to use when limiting and sorting database query results
 type QueryParams struct {
-	Page          int    `json:"page,omitempty"`
-	PageSize      int    `json:"page_size,omitempty"`
-	OrderByField  string `json:"order_by_field,omitempty"`
+	Page           int            `json:"page,omitempty"`
+	PageSize       int            `json:"page_size,omitempty"`
+	OrderByField   string         `json:"order_by_field,omitempty"`
+	SortDirection  string         `json:"sort_direction,omitempty"`
+	IndexHint      string         `json:"index_hint,omitempty"`       // Name of a registered index hint (see: WithIndexHint)
+	OrderBy        []OrderSpec    `json:"order_by,omitempty"`         // Multi-column ordering (takes precedence over OrderByField)
+	TotalCountMode TotalCountMode `json:"total_count_mode,omitempty"` // Total count for GetModelsPaged (empty is exact)
+}
+
+// TotalCountMode is how the total count of matching records is computed (see: GetModelsPaged)
+type TotalCountMode string
+
+// Total count modes
+const (
+	TotalCountEstimated TotalCountMode = "estimated" // Query planner (or collection) estimate, exact when unavailable
+	TotalCountExact     TotalCountMode = "exact"     // COUNT(*) using the same conditions
+	TotalCountNone      TotalCountMode = "none"      // No count (IE: infinite scroll)
+)
+
+// OrderSpec is a single column in a multi-column ordering (IE: created_at DESC, id ASC)
+type OrderSpec struct {
+	Field         string `json:"field"`
 	SortDirection string `json:"sort_direction,omitempty"`
 }
 
+// getOrderSpecs will return the ordering (OrderBy, or the single OrderByField)
+func (q *QueryParams) getOrderSpecs() []OrderSpec {
+	if len(q.OrderBy) > 0 {
+		specs := make([]OrderSpec, 0, len(q.OrderBy))
+		for _, spec := range q.OrderBy {
+			if len(spec.Field) > 0 {
+				specs = append(specs, OrderSpec{Field: spec.Field, SortDirection: strings.ToLower(spec.SortDirection)})
+			}
+		}
+		return specs
+	} else if len(q.OrderByField) > 0 {
+		return []OrderSpec{{Field: q.OrderByField, SortDirection: strings.ToLower(q.SortDirection)}}
+	}
+	return nil
+}
+
 // MarshalQueryParams will marshal the custom type
 func MarshalQueryParams(m QueryParams) graphql.Marshaler {
-	if m.Page == 0 && m.PageSize == 0 && m.OrderByField == "" && m.SortDirection == "" {
+	if m.Page == 0 && m.PageSize == 0 && m.OrderByField == "" && m.SortDirection == "" && m.IndexHint == "" &&
+		len(m.OrderBy) == 0 && m.TotalCountMode == "" {
 		return graphql.Null
 	}
 	return graphql.MarshalAny(m)
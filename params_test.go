@@ -2,6 +2,7 @@ package datastore
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -64,3 +65,56 @@ func TestMetadata_MarshalMetadata(t *testing.T) {
 		assert.Equal(t, `{"page":11,"page_size":35}`+"\n", b.String())
 	})
 }
+
+// TestQueryParams_getOrderSpecs will test the method getOrderSpecs()
+func TestQueryParams_getOrderSpecs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no ordering", func(t *testing.T) {
+		assert.Nil(t, (&QueryParams{}).getOrderSpecs())
+	})
+
+	t.Run("single field", func(t *testing.T) {
+		q := &QueryParams{OrderByField: "created_at", SortDirection: "DESC"}
+		assert.Equal(t, []OrderSpec{{Field: "created_at", SortDirection: SortDesc}}, q.getOrderSpecs())
+	})
+
+	t.Run("multiple fields take precedence", func(t *testing.T) {
+		q := &QueryParams{
+			OrderByField: "name",
+			OrderBy: []OrderSpec{
+				{Field: "created_at", SortDirection: "DESC"},
+				{Field: ""},
+				{Field: "id", SortDirection: SortAsc},
+			},
+		}
+		assert.Equal(t, []OrderSpec{
+			{Field: "created_at", SortDirection: SortDesc},
+			{Field: "id", SortDirection: SortAsc},
+		}, q.getOrderSpecs())
+	})
+}
+
+// TestClient_GetModels_orderBy will test multi-column ordering using SQLite
+func TestClient_GetModels_orderBy(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+	testSaveModels(ctx, t, client,
+		&testSQLModel{ID: "order-1", Amount: 10},
+		&testSQLModel{ID: "order-2", Amount: 20},
+		&testSQLModel{ID: "order-3", Amount: 10},
+	)
+
+	var models []*testSQLModel
+	require.NoError(t, client.GetModels(ctx, &models, nil, &QueryParams{
+		OrderBy: []OrderSpec{
+			{Field: "amount", SortDirection: SortDesc},
+			{Field: sqlIDField, SortDirection: SortDesc},
+		},
+	}, nil, defaultDatabaseMaxTimeout))
+	require.Len(t, models, 3)
+	assert.Equal(t, "order-2", models[0].ID)
+	assert.Equal(t, "order-3", models[1].ID)
+	assert.Equal(t, "order-1", models[2].ID)
+}
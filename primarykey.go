@@ -0,0 +1,110 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrMissingPrimaryKey is when the model's primary key can not be found or is not set
+var ErrMissingPrimaryKey = errors.New("model is missing a primary key value")
+
+// ErrCompositePrimaryKey is when a (batched) operation requires a single column primary key
+var ErrCompositePrimaryKey = errors.New("composite primary keys are not supported")
+
+// PrimaryKeyModel can be implemented by a model to declare its primary key column(s)
+//
+// Without this method the primary key is detected using the GORM schema (SQL) or the _id field (MongoDB)
+type PrimaryKeyModel interface {
+	GetPrimaryKeys() []string
+}
+
+// getModelPrimaryKey will return the conditions for the model's primary key (column: value)
+//
+// Supports custom (IE: uuid) and composite primary keys
+func (c *Client) getModelPrimaryKey(model interface{}) (map[string]interface{}, error) {
+
+	// Declared by the model
+	if m, ok := model.(PrimaryKeyModel); ok && len(m.GetPrimaryKeys()) > 0 {
+		conditions := make(map[string]interface{})
+		for _, column := range m.GetPrimaryKeys() {
+			value, found := getModelColumnField(model, column)
+			if !found || isZeroValue(value) {
+				return nil, ErrMissingPrimaryKey
+			}
+			if c.Engine() == MongoDB && column == sqlIDField {
+				column = mongoIDField
+			}
+			conditions[column] = value
+		}
+		return conditions, nil
+	}
+
+	// Mongo uses the _id field
+	if c.Engine() == MongoDB {
+		value, found := getModelColumnField(model, mongoIDField)
+		if !found || isZeroValue(value) {
+			return nil, ErrMissingPrimaryKey
+		}
+		return map[string]interface{}{mongoIDField: value}, nil
+	}
+
+	// Parse the model using GORM
+	modelSchema, err := c.parseModelSchema(model)
+	if err != nil {
+		return nil, err
+	} else if len(modelSchema.PrimaryFields) == 0 {
+		return nil, ErrMissingPrimaryKey
+	}
+	conditions := make(map[string]interface{})
+	for _, field := range modelSchema.PrimaryFields {
+		value, isZero := field.ValueOf(context.Background(), reflect.ValueOf(model))
+		if isZero {
+			return nil, ErrMissingPrimaryKey
+		}
+		conditions[field.DBName] = value
+	}
+	return conditions, nil
+}
+
+// getSinglePrimaryKeyColumn will return the primary key column of the model (SQL, using the GORM schema)
+func (c *Client) getSinglePrimaryKeyColumn(model interface{}) (string, error) {
+	modelSchema, err := c.parseModelSchema(model)
+	if err != nil {
+		return "", err
+	} else if len(modelSchema.PrimaryFieldDBNames) == 0 {
+		return "", ErrMissingPrimaryKey
+	} else if len(modelSchema.PrimaryFieldDBNames) > 1 {
+		return "", ErrCompositePrimaryKey
+	}
+	return modelSchema.PrimaryFieldDBNames[0], nil
+}
+
+// parseModelSchema will parse the model using GORM (the Memory and DynamoDB engines use their own naming strategy)
+func (c *Client) parseModelSchema(model interface{}) (*schema.Schema, error) {
+	if c.Engine() == Memory {
+		return c.options.memory.parse(model)
+	} else if c.Engine() == DynamoDB {
+		return c.options.dynamoDB.parse(model)
+	}
+	stmt := &gorm.Statement{DB: c.options.db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// isZeroValue will return true if the value is nil or the zero value of its type
+func isZeroValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		return v.IsNil() || v.Elem().IsZero()
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,115 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUUIDModel is a model keyed by a uuid column
+type testUUIDModel struct {
+	UUID  string `gorm:"type:char(36);primaryKey" bson:"_id"`
+	Count int64
+}
+
+// GetModelName will get the model name
+func (m *testUUIDModel) GetModelName() string { return "test_uuid_model" }
+
+// GetModelTableName will get the table name
+func (m *testUUIDModel) GetModelTableName() string { return "test_uuid_models" }
+
+// testCompositeModel is a model keyed by two columns
+type testCompositeModel struct {
+	Owner string `gorm:"type:varchar(64);primaryKey"`
+	Slot  int    `gorm:"primaryKey;autoIncrement:false"`
+	Count int64
+}
+
+// GetModelName will get the model name
+func (m *testCompositeModel) GetModelName() string { return "test_composite_model" }
+
+// GetModelTableName will get the table name
+func (m *testCompositeModel) GetModelTableName() string { return "test_composite_models" }
+
+// testDeclaredKeyModel declares its primary key
+type testDeclaredKeyModel struct {
+	Code string
+	Name string
+}
+
+// GetPrimaryKeys will get the primary key columns
+func (m *testDeclaredKeyModel) GetPrimaryKeys() []string { return []string{"code"} }
+
+// TestClient_getModelPrimaryKey will test the method getModelPrimaryKey()
+func TestClient_getModelPrimaryKey(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+	c := client.(*Client)
+
+	t.Run("id", func(t *testing.T) {
+		primaryKey, err := c.getModelPrimaryKey(&testSQLModel{ID: "pk-1"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{sqlIDField: "pk-1"}, primaryKey)
+	})
+
+	t.Run("uuid", func(t *testing.T) {
+		primaryKey, err := c.getModelPrimaryKey(&testUUIDModel{UUID: "a-b-c"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"uuid": "a-b-c"}, primaryKey)
+	})
+
+	t.Run("composite", func(t *testing.T) {
+		primaryKey, err := c.getModelPrimaryKey(&testCompositeModel{Owner: "alice", Slot: 2})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"owner": "alice", "slot": 2}, primaryKey)
+	})
+
+	t.Run("declared", func(t *testing.T) {
+		primaryKey, err := c.getModelPrimaryKey(&testDeclaredKeyModel{Code: "x1"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"code": "x1"}, primaryKey)
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		_, err := c.getModelPrimaryKey(&testUUIDModel{})
+		require.ErrorIs(t, err, ErrMissingPrimaryKey)
+
+		_, err = c.getModelPrimaryKey(&testDeclaredKeyModel{})
+		require.ErrorIs(t, err, ErrMissingPrimaryKey)
+	})
+}
+
+// TestClient_IncrementModel_PrimaryKey will test the method IncrementModel() with custom primary keys
+func TestClient_IncrementModel_PrimaryKey(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t, WithAutoMigrate(&testUUIDModel{}, &testCompositeModel{}))
+	defer deferFunc()
+
+	t.Run("uuid", func(t *testing.T) {
+		model := &testUUIDModel{UUID: "uuid-1", Count: 5}
+		testSaveModels(ctx, t, client, model)
+
+		newValue, err := client.IncrementModel(ctx, model, "count", 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), newValue)
+	})
+
+	t.Run("composite", func(t *testing.T) {
+		testSaveModels(ctx, t, client,
+			&testCompositeModel{Owner: "alice", Slot: 1, Count: 1},
+			&testCompositeModel{Owner: "alice", Slot: 2, Count: 10},
+		)
+
+		newValue, err := client.IncrementModel(ctx, &testCompositeModel{Owner: "alice", Slot: 2}, "count", 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(11), newValue)
+
+		model := &testCompositeModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{"owner": "alice", "slot": 1},
+			defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, int64(1), model.Count)
+	})
+}
@@ -0,0 +1,126 @@
+package datastore
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Query comment settings
+const (
+	queryCommentCallbackPrefix = "datastore:query_comment" // Prefix for the GORM callback names
+	queryCommentClause         = "DATASTORE_QUERY_COMMENT" // Name of the (trailing) comment clause
+	queryCommentSettingKey     = "datastore:query_comment" // GORM setting key for the comment of transaction statements
+)
+
+// QueryCommentExtractor returns the context values (IE: trace_id, request_id) added to every query as a comment
+// (see: WithQueryComments)
+type QueryCommentExtractor func(ctx context.Context) map[string]string
+
+// getQueryComment will return the comment of the query from the context values and the query ID
+// (empty without values, see: WithQueryKill)
+//
+// The values use the sqlcommenter format (key='value', sorted by key), keys and values are URL encoded so a
+// value can never close the comment (IE: */) or become a MySQL executable comment or an optimizer hint
+func (c *Client) getQueryComment(ctx context.Context) string {
+	if (c.options.queryComments == nil && c.options.queryKill == nil) || ctx == nil {
+		return ""
+	}
+	var values map[string]string
+	if c.options.queryComments != nil {
+		values = c.options.queryComments(ctx)
+	}
+	if queryID := getQueryID(ctx); len(queryID) > 0 {
+		values = maps.Clone(values)
+		if values == nil {
+			values = make(map[string]string, 1)
+		}
+		values[queryIDCommentKey] = queryID
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, url.QueryEscape(key)+"='"+url.QueryEscape(values[key])+"'")
+	}
+	return strings.Join(pairs, ",")
+}
+
+// getMongoComment will return the $comment of the MongoDB operation (nil without a query comment)
+func (c *Client) getMongoComment(ctx context.Context) interface{} {
+	if comment := c.getQueryComment(ctx); len(comment) > 0 {
+		return comment
+	}
+	return nil
+}
+
+// setQueryComment will set the query comment on the statements of a transaction (the transaction does not use
+// the context of the caller)
+func (c *Client) setQueryComment(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if comment := c.getQueryComment(ctx); len(comment) > 0 {
+		return db.Set(queryCommentSettingKey, comment)
+	}
+	return db
+}
+
+// addQueryCommentCallbacks will register the GORM callbacks that append the query comment to every statement
+//
+// The comment is added at the end of the statement, the query errors use the first word (see: QueryError)
+func (c *Client) addQueryCommentCallbacks(db *gorm.DB) {
+	addComment := func(tx *gorm.DB) {
+		stmt := tx.Statement
+		comment := c.getQueryComment(stmt.Context)
+		if value, ok := tx.Get(queryCommentSettingKey); ok && len(comment) == 0 {
+			comment = value.(string)
+		}
+
+		// Raw statements (already built)
+		if stmt.SQL.Len() > 0 {
+			if len(comment) > 0 {
+				query := strings.TrimRight(strings.TrimSpace(stmt.SQL.String()), ";")
+				stmt.SQL.Reset()
+				stmt.SQL.WriteString(query + " /*" + comment + "*/")
+			}
+			return
+		}
+
+		// Built statements (the comment is the last clause)
+		if len(comment) == 0 {
+			delete(stmt.Clauses, queryCommentClause)
+			return
+		}
+		stmt.Clauses[queryCommentClause] = clause.Clause{
+			Builder: func(_ clause.Clause, builder clause.Builder) {
+				builder.WriteString("/*" + comment + "*/")
+			},
+		}
+		for _, name := range stmt.BuildClauses {
+			if name == queryCommentClause {
+				return
+			}
+		}
+		stmt.BuildClauses = append(append(make([]string, 0, len(stmt.BuildClauses)+1),
+			stmt.BuildClauses...), queryCommentClause)
+	}
+
+	callbacks := db.Callback()
+	_ = callbacks.Create().Before("gorm:create").Register(queryCommentCallbackPrefix+"_create", addComment)
+	_ = callbacks.Query().Before("gorm:query").Register(queryCommentCallbackPrefix+"_query", addComment)
+	_ = callbacks.Update().Before("gorm:update").Register(queryCommentCallbackPrefix+"_update", addComment)
+	_ = callbacks.Delete().Before("gorm:delete").Register(queryCommentCallbackPrefix+"_delete", addComment)
+	_ = callbacks.Row().Before("gorm:row").Register(queryCommentCallbackPrefix+"_row", addComment)
+	_ = callbacks.Raw().Before("gorm:raw").Register(queryCommentCallbackPrefix+"_raw", addComment)
+}
@@ -0,0 +1,88 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// testTraceIDKey is the context key of the test trace ID
+type testTraceIDKey struct{}
+
+// testTraceExtractor will return the test trace ID from the context
+func testTraceExtractor(ctx context.Context) map[string]string {
+	traceID, ok := ctx.Value(testTraceIDKey{}).(string)
+	if !ok {
+		return nil
+	}
+	return map[string]string{"trace_id": traceID, "app": "datastore"}
+}
+
+// TestWithQueryComments will test the method WithQueryComments()
+func TestWithQueryComments(t *testing.T) {
+	t.Run("nil extractor", func(t *testing.T) {
+		options := &clientOptions{}
+		WithQueryComments(nil)(options)
+		assert.Nil(t, options.queryComments)
+	})
+
+	t.Run("[sqlite] statements are tagged", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithQueryComments(testTraceExtractor))
+		defer deferFunc()
+
+		var statements []string
+		capture := func(tx *gorm.DB) {
+			statements = append(statements, tx.Statement.SQL.String())
+		}
+		callbacks := client.(*Client).options.db.Callback()
+		require.NoError(t, callbacks.Create().After("gorm:create").Register("test:capture_create", capture))
+		require.NoError(t, callbacks.Query().After("gorm:query").Register("test:capture_query", capture))
+		require.NoError(t, callbacks.Raw().After("gorm:raw").Register("test:capture_raw", capture))
+
+		traceCtx := context.WithValue(ctx, testTraceIDKey{}, "abc*/123")
+		testSaveModels(traceCtx, t, client, &testSQLModel{ID: "comment-1", Name: "a"})
+		require.NotEmpty(t, statements)
+		assert.Contains(t, statements[len(statements)-1], "INSERT")
+		assert.Contains(t, statements[len(statements)-1], "/*app='datastore',trace_id='abc%2A%2F123'*/")
+
+		statements = nil
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(traceCtx, model, map[string]interface{}{sqlIDField: "comment-1"},
+			defaultDatabaseMaxTimeout, false))
+		require.Len(t, statements, 1)
+		assert.Regexp(t, `^SELECT .* /\*app='datastore',trace_id='abc%2A%2F123'\*/$`, statements[0])
+
+		statements = nil
+		require.NoError(t, client.ExecuteContext(traceCtx, "UPDATE "+testSQLTableName+" SET name = 'b';").Error)
+		require.Len(t, statements, 1)
+		assert.Equal(t, "UPDATE "+testSQLTableName+" SET name = 'b' /*app='datastore',trace_id='abc%2A%2F123'*/",
+			statements[0])
+
+		// Without context values
+		statements = nil
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "comment-1"},
+			defaultDatabaseMaxTimeout, false))
+		require.Len(t, statements, 1)
+		assert.NotContains(t, statements[0], "/*")
+		assert.Equal(t, "b", model.Name)
+	})
+}
+
+// TestClient_getQueryComment will test the method getQueryComment()
+func TestClient_getQueryComment(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testTraceIDKey{}, "it's 1")
+
+	client := &Client{options: &clientOptions{}}
+	assert.Empty(t, client.getQueryComment(ctx))
+	assert.Nil(t, client.getMongoComment(ctx))
+
+	client.options.queryComments = testTraceExtractor
+	assert.Equal(t, "app='datastore',trace_id='it%27s+1'", client.getQueryComment(ctx))
+	assert.Equal(t, "app='datastore',trace_id='it%27s+1'", client.getMongoComment(ctx))
+	assert.Empty(t, client.getQueryComment(context.Background()))
+	assert.Nil(t, client.getMongoComment(context.Background()))
+}
@@ -0,0 +1,132 @@
+package datastore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"gorm.io/gorm"
+)
+
+// Query error settings
+const (
+	maxQueryErrorClauseLength = 256                   // Max length of the query clause in a QueryError
+	queryErrorStartKey        = "datastore:start"     // GORM instance key for the query start time
+	queryErrorCallbackPrefix  = "datastore:query_err" // Prefix for the GORM callback names
+)
+
+// QueryError wraps an error returned from a query with the details of the datastore call
+//
+// Use errors.As(err, &queryErr) to get the details, the original error is still matched by errors.Is()
+type QueryError struct {
+	Clause      string        // Query clause (truncated, values are not included)
+	Duration    time.Duration // How long the query took before failing
+	Engine      Engine        // Datastore engine
+	Err         error         // Original error
+	Fingerprint string        // Fingerprint of the query shape (same for all queries with the same shape)
+	Operation   string        // Operation (IE: SELECT, INSERT, find, update)
+	Table       string        // Table or collection name
+}
+
+// Error will return the error message with the query details
+func (e *QueryError) Error() string {
+	return fmt.Sprintf(
+		"%s %s on %s failed after %s (fingerprint: %s, clause: %s): %v",
+		e.Engine, e.Operation, e.Table, e.Duration, e.Fingerprint, e.Clause, e.Err,
+	)
+}
+
+// Unwrap will return the original error
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// newQueryError will wrap the error with the query details (errors that are already wrapped are returned as-is)
+func newQueryError(engine Engine, operation, table, query string, duration time.Duration, err error) error {
+	var queryErr *QueryError
+	if err == nil || errors.As(err, &queryErr) {
+		return err
+	}
+
+	shape := getQueryShape(query)
+	hash := sha256.Sum256([]byte(shape))
+	if len(shape) > maxQueryErrorClauseLength {
+		shape = shape[:maxQueryErrorClauseLength] + "..."
+	}
+
+	return &QueryError{
+		Clause:      strings.TrimSpace(shape),
+		Duration:    duration,
+		Engine:      engine,
+		Err:         err,
+		Fingerprint: hex.EncodeToString(hash[:8]),
+		Operation:   operation,
+		Table:       table,
+	}
+}
+
+// newMongoQueryError will wrap the error from a Mongo operation with the query details
+//
+// Datastore errors (IE: ErrNoResults, ErrDuplicateKey) are returned as-is
+func newMongoQueryError(operation string, model interface{}, conditions map[string]interface{},
+	start time.Time, err error) error {
+
+	if err == nil || errors.Is(err, ErrNoResults) || errors.Is(err, ErrDuplicateKey) ||
+		errors.Is(err, ErrUnknownCollection) || errors.Is(err, ErrMissingPrimaryKey) {
+		return err
+	}
+
+	table := ""
+	if name := GetModelTableName(model); name != nil {
+		table = *name
+	}
+	query := "{}"
+	if len(conditions) > 0 {
+		if b, jsonErr := bson.MarshalExtJSON(conditions, false, false); jsonErr == nil {
+			query = string(b)
+		}
+	}
+	return newQueryError(MongoDB, operation, table, query, time.Since(start), err)
+}
+
+// addQueryErrorCallbacks will register the GORM callbacks that wrap query errors in a QueryError
+func addQueryErrorCallbacks(db *gorm.DB, engine Engine) {
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(queryErrorStartKey, time.Now())
+	}
+	after := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			if tx.Error == nil || errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+				return
+			}
+			statementOperation := operation
+			var duration time.Duration
+			if start, ok := tx.InstanceGet(queryErrorStartKey); ok {
+				duration = time.Since(start.(time.Time))
+			}
+			query := tx.Statement.SQL.String()
+			if statementOperation == "" {
+				statementOperation = strings.ToUpper(strings.Split(strings.TrimSpace(query), " ")[0])
+			}
+			tx.Error = newQueryError(engine, statementOperation, tx.Statement.Table, query, duration, tx.Error)
+		}
+	}
+
+	callbacks := db.Callback()
+	_ = callbacks.Create().Before("gorm:create").Register(queryErrorCallbackPrefix+"_create_before", before)
+	_ = callbacks.Create().After("gorm:create").Register(queryErrorCallbackPrefix+"_create_after", after("INSERT"))
+	_ = callbacks.Query().Before("gorm:query").Register(queryErrorCallbackPrefix+"_query_before", before)
+	_ = callbacks.Query().After("gorm:query").Register(queryErrorCallbackPrefix+"_query_after", after("SELECT"))
+	_ = callbacks.Update().Before("gorm:update").Register(queryErrorCallbackPrefix+"_update_before", before)
+	_ = callbacks.Update().After("gorm:update").Register(queryErrorCallbackPrefix+"_update_after", after("UPDATE"))
+	_ = callbacks.Delete().Before("gorm:delete").Register(queryErrorCallbackPrefix+"_delete_before", before)
+	_ = callbacks.Delete().After("gorm:delete").Register(queryErrorCallbackPrefix+"_delete_after", after("DELETE"))
+	_ = callbacks.Row().Before("gorm:row").Register(queryErrorCallbackPrefix+"_row_before", before)
+	_ = callbacks.Row().After("gorm:row").Register(queryErrorCallbackPrefix+"_row_after", after(""))
+	_ = callbacks.Raw().Before("gorm:raw").Register(queryErrorCallbackPrefix+"_raw_before", before)
+	_ = callbacks.Raw().After("gorm:raw").Register(queryErrorCallbackPrefix+"_raw_after", after(""))
+}
@@ -0,0 +1,107 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewQueryError will test the method newQueryError()
+func TestNewQueryError(t *testing.T) {
+	t.Parallel()
+
+	testErr := errors.New("test error")
+
+	t.Run("nil error", func(t *testing.T) {
+		require.NoError(t, newQueryError(SQLite, "SELECT", "users", "SELECT 1", 0, nil))
+	})
+
+	t.Run("query details", func(t *testing.T) {
+		err := newQueryError(MySQL, "SELECT", "users", "SELECT * FROM users WHERE name = 'alice'", time.Second, testErr)
+		require.ErrorIs(t, err, testErr)
+
+		var queryErr *QueryError
+		require.ErrorAs(t, err, &queryErr)
+		assert.Equal(t, MySQL, queryErr.Engine)
+		assert.Equal(t, "SELECT", queryErr.Operation)
+		assert.Equal(t, "users", queryErr.Table)
+		assert.Equal(t, time.Second, queryErr.Duration)
+		assert.Equal(t, "SELECT * FROM users WHERE name = ?", queryErr.Clause)
+		assert.Len(t, queryErr.Fingerprint, 16)
+		assert.Contains(t, err.Error(), "mysql SELECT on users failed")
+		assert.Contains(t, err.Error(), testErr.Error())
+	})
+
+	t.Run("same shape has the same fingerprint", func(t *testing.T) {
+		var first, second *QueryError
+		require.ErrorAs(t, newQueryError(SQLite, "SELECT", "users", "SELECT * FROM users WHERE id = 1", 0, testErr), &first)
+		require.ErrorAs(t, newQueryError(SQLite, "SELECT", "users", "SELECT * FROM users WHERE id = 2", 0, testErr), &second)
+		assert.Equal(t, first.Fingerprint, second.Fingerprint)
+	})
+
+	t.Run("long clause is truncated", func(t *testing.T) {
+		var queryErr *QueryError
+		query := "SELECT " + strings.Repeat("a, ", 200) + "b FROM users"
+		require.ErrorAs(t, newQueryError(SQLite, "SELECT", "users", query, 0, testErr), &queryErr)
+		assert.Len(t, queryErr.Clause, maxQueryErrorClauseLength+3)
+	})
+
+	t.Run("already wrapped", func(t *testing.T) {
+		err := newQueryError(SQLite, "SELECT", "users", "SELECT 1", 0, testErr)
+		assert.Equal(t, err, newQueryError(SQLite, "UPDATE", "other", "UPDATE 1", 0, err))
+	})
+}
+
+// TestNewMongoQueryError will test the method newMongoQueryError()
+func TestNewMongoQueryError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("datastore errors are not wrapped", func(t *testing.T) {
+		for _, err := range []error{nil, ErrNoResults, ErrDuplicateKey, ErrUnknownCollection} {
+			assert.Equal(t, err, newMongoQueryError("find", &testSQLModel{}, nil, time.Now(), err))
+		}
+	})
+
+	t.Run("query details", func(t *testing.T) {
+		testErr := errors.New("test error")
+		err := newMongoQueryError("find", &testSQLModel{}, map[string]interface{}{"name": "alice"}, time.Now(), testErr)
+
+		var queryErr *QueryError
+		require.ErrorAs(t, err, &queryErr)
+		assert.Equal(t, MongoDB, queryErr.Engine)
+		assert.Equal(t, "find", queryErr.Operation)
+		assert.Equal(t, testSQLTableName, queryErr.Table)
+		assert.Equal(t, `{"name": ?}`, queryErr.Clause)
+	})
+}
+
+// TestClient_QueryError will test wrapping the SQL query errors
+func TestClient_QueryError(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+
+	t.Run("failed query", func(t *testing.T) {
+		var models []*testSQLModel
+		err := client.GetModels(ctx, &models, map[string]interface{}{"missing_column": "value"}, nil, nil, defaultDatabaseMaxTimeout)
+		require.Error(t, err)
+
+		var queryErr *QueryError
+		require.ErrorAs(t, err, &queryErr)
+		assert.Equal(t, SQLite, queryErr.Engine)
+		assert.Equal(t, "SELECT", queryErr.Operation)
+		assert.Equal(t, testSQLTableName, queryErr.Table)
+		assert.Contains(t, queryErr.Clause, "missing_column = ?")
+		assert.NotContains(t, queryErr.Clause, "value")
+	})
+
+	t.Run("no results are not wrapped", func(t *testing.T) {
+		err := client.GetModel(ctx, &testSQLModel{}, map[string]interface{}{sqlIDField: "missing"}, defaultDatabaseMaxTimeout, false)
+		assert.Equal(t, ErrNoResults, err)
+	})
+}
@@ -0,0 +1,232 @@
+package datastore
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+// Query kill settings
+const (
+	defaultQueryKillTimeout = 5 * time.Second             // Default timeout of the kill statements
+	queryIDCommentKey       = "datastore_query_id"        // Query comment key of the query ID
+	queryKillCallbackPrefix = "datastore:query_kill"      // Prefix for the GORM callback names
+	queryKillStopKey        = "datastore:query_kill_stop" // GORM instance key of the stop function of the statement
+)
+
+// queryKillConfig is the configuration for killing the canceled queries (see: WithQueryKill)
+type queryKillConfig struct {
+	timeout time.Duration // Timeout of the kill statements
+}
+
+// queryIDContextKey is the context key of the query ID (added to the query comment)
+type queryIDContextKey struct{}
+
+// supportsQueryKill will return true if the canceled queries are killed on the server
+//
+// SQLite queries are interrupted in-process, CockroachDB does not list the comments of the statements
+func (c *Client) supportsQueryKill() bool {
+	if c.options.queryKill == nil {
+		return false
+	}
+	return c.Engine() == MySQL || c.Engine() == MongoDB || (c.Engine() == PostgreSQL && !c.isCockroachDB())
+}
+
+// startQueryKill will tag the query with a new query ID (see: getQueryComment) and kill the query on the
+// server when the context is canceled (or the deadline is exceeded) before the returned function is called
+func (c *Client) startQueryKill(ctx context.Context) (context.Context, func()) {
+	if ctx == nil || ctx.Done() == nil || !c.supportsQueryKill() {
+		return ctx, func() {}
+	}
+	if len(getQueryID(ctx)) > 0 {
+		return ctx, func() {} // Already tracked
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return ctx, func() {}
+	}
+	queryID := hex.EncodeToString(id)
+	stop := context.AfterFunc(ctx, func() {
+		c.killQuery(queryID)
+	})
+	return context.WithValue(ctx, queryIDContextKey{}, queryID), func() {
+		stop()
+	}
+}
+
+// getQueryID will return the query ID of the context (empty if the query is not tracked)
+func getQueryID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	queryID, _ := ctx.Value(queryIDContextKey{}).(string)
+	return queryID
+}
+
+// getQueryIDComment will return the part of the query comment with the query ID (see: getQueryComment)
+func getQueryIDComment(queryID string) string {
+	return queryIDCommentKey + "='" + queryID + "'"
+}
+
+// killQuery will kill the running query of the query ID on the server (the errors are logged)
+func (c *Client) killQuery(queryID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.options.queryKill.timeout)
+	defer cancel()
+
+	killed, err := c.killServerQuery(ctx, queryID)
+	if err != nil {
+		c.warnLog(ctx, fmt.Sprintf("failed to kill the canceled query %s: %s", queryID, err.Error()))
+		return
+	}
+	if killed > 0 {
+		c.DebugLog(ctx, fmt.Sprintf("killed the canceled query %s (%d operation(s))", queryID, killed))
+	}
+}
+
+// killServerQuery will kill the running operations tagged with the query ID (returns the killed operations)
+//
+// MySQL: KILL QUERY, PostgreSQL: pg_cancel_backend, MongoDB: killOp (the source and the replicas are checked)
+func (c *Client) killServerQuery(ctx context.Context, queryID string) (int, error) {
+	if c.Engine() == MongoDB {
+		return c.killMongoQuery(ctx, queryID)
+	}
+
+	pattern := "%" + getQueryIDComment(queryID) + "%"
+	killed := 0
+	for _, db := range c.getQueryKillDatabases() {
+		var count int
+		var err error
+		if c.Engine() == MySQL {
+			count, err = killMySQLQuery(ctx, db, pattern)
+		} else {
+			err = db.QueryRowContext(ctx,
+				`SELECT COUNT(*) FILTER (WHERE pg_cancel_backend(pid)) FROM pg_stat_activity
+				WHERE pid <> pg_backend_pid() AND query LIKE $1`, pattern,
+			).Scan(&count)
+		}
+		if err != nil {
+			return killed, err
+		}
+		killed += count
+	}
+	return killed, nil
+}
+
+// killMySQLQuery will kill the running MySQL queries matching the pattern (returns the number of killed queries)
+func killMySQLQuery(ctx context.Context, db *sql.DB, pattern string) (int, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT ID FROM information_schema.PROCESSLIST WHERE ID <> CONNECTION_ID() AND INFO LIKE ?", pattern,
+	)
+	if err != nil {
+		return 0, err
+	}
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err = rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Close(); err != nil {
+		return 0, err
+	}
+
+	for index, id := range ids {
+		if _, err = db.ExecContext(ctx, "KILL QUERY "+strconv.FormatUint(id, 10)); err != nil {
+			return index, err
+		}
+	}
+	return len(ids), nil
+}
+
+// killMongoQuery will kill the running MongoDB operations tagged with the query ID ($currentOp and killOp)
+func (c *Client) killMongoQuery(ctx context.Context, queryID string) (int, error) {
+	admin := c.options.mongoDB.Client().Database("admin")
+	cursor, err := admin.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$currentOp", Value: bson.D{}}},
+		{{Key: "$match", Value: bson.D{{Key: "command.comment", Value: primitive.Regex{
+			Pattern: regexp.QuoteMeta(getQueryIDComment(queryID)),
+		}}}}},
+		{{Key: "$project", Value: bson.D{{Key: "opid", Value: 1}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var operations []struct {
+		OpID interface{} `bson:"opid"` // Number (mongod) or string (mongos)
+	}
+	if err = cursor.All(ctx, &operations); err != nil {
+		return 0, err
+	}
+
+	for index, operation := range operations {
+		if err = admin.RunCommand(
+			ctx, bson.D{{Key: "killOp", Value: 1}, {Key: "op", Value: operation.OpID}},
+		).Err(); err != nil {
+			return index, err
+		}
+	}
+	return len(operations), nil
+}
+
+// getQueryKillDatabases will return the SQL databases that can run the query (the source and the replicas)
+func (c *Client) getQueryKillDatabases() []*sql.DB {
+	if c.options.replicas != nil {
+		return c.options.replicas.databases()
+	}
+	if c.options.db == nil {
+		return nil
+	}
+	if db, err := c.options.db.DB(); err == nil {
+		return []*sql.DB{db}
+	}
+	return nil
+}
+
+// addQueryKillCallbacks will register the GORM callbacks that track the statements (see: startQueryKill)
+//
+// The callbacks run before the query comment callbacks (the query ID is added to the comment)
+func (c *Client) addQueryKillCallbacks(db *gorm.DB) {
+	start := func(tx *gorm.DB) {
+		ctx, stop := c.startQueryKill(tx.Statement.Context)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(queryKillStopKey, stop)
+	}
+	stop := func(tx *gorm.DB) {
+		if stop, ok := tx.InstanceGet(queryKillStopKey); ok {
+			stop.(func())()
+		}
+	}
+
+	callbacks := db.Callback()
+	_ = callbacks.Create().Before(queryCommentCallbackPrefix+"_create").
+		Register(queryKillCallbackPrefix+"_create_start", start)
+	_ = callbacks.Create().After("gorm:create").Register(queryKillCallbackPrefix+"_create_stop", stop)
+	_ = callbacks.Query().Before(queryCommentCallbackPrefix+"_query").
+		Register(queryKillCallbackPrefix+"_query_start", start)
+	_ = callbacks.Query().After("gorm:query").Register(queryKillCallbackPrefix+"_query_stop", stop)
+	_ = callbacks.Update().Before(queryCommentCallbackPrefix+"_update").
+		Register(queryKillCallbackPrefix+"_update_start", start)
+	_ = callbacks.Update().After("gorm:update").Register(queryKillCallbackPrefix+"_update_stop", stop)
+	_ = callbacks.Delete().Before(queryCommentCallbackPrefix+"_delete").
+		Register(queryKillCallbackPrefix+"_delete_start", start)
+	_ = callbacks.Delete().After("gorm:delete").Register(queryKillCallbackPrefix+"_delete_stop", stop)
+	_ = callbacks.Row().Before(queryCommentCallbackPrefix+"_row").
+		Register(queryKillCallbackPrefix+"_row_start", start)
+	_ = callbacks.Row().After("gorm:row").Register(queryKillCallbackPrefix+"_row_stop", stop)
+	_ = callbacks.Raw().Before(queryCommentCallbackPrefix+"_raw").
+		Register(queryKillCallbackPrefix+"_raw_start", start)
+	_ = callbacks.Raw().After("gorm:raw").Register(queryKillCallbackPrefix+"_raw_stop", stop)
+}
@@ -0,0 +1,122 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithQueryKill will test the method WithQueryKill()
+func TestWithQueryKill(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithQueryKill(0)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying the default timeout", func(t *testing.T) {
+		options := &clientOptions{}
+		WithQueryKill(0)(options)
+		require.NotNil(t, options.queryKill)
+		assert.Equal(t, defaultQueryKillTimeout, options.queryKill.timeout)
+	})
+
+	t.Run("test applying a timeout", func(t *testing.T) {
+		options := &clientOptions{}
+		WithQueryKill(time.Second)(options)
+		require.NotNil(t, options.queryKill)
+		assert.Equal(t, time.Second, options.queryKill.timeout)
+	})
+}
+
+// TestClient_supportsQueryKill will test the method supportsQueryKill()
+func TestClient_supportsQueryKill(t *testing.T) {
+	tests := []struct {
+		engine      Engine
+		cockroachDB bool
+		expected    bool
+	}{
+		{MySQL, false, true},
+		{PostgreSQL, false, true},
+		{PostgreSQL, true, false},
+		{MongoDB, false, true},
+		{SQLite, false, false},
+		{Memory, false, false},
+		{DynamoDB, false, false},
+	}
+	for _, test := range tests {
+		client := &Client{options: &clientOptions{
+			cockroachDB: test.cockroachDB, engine: test.engine, queryKill: &queryKillConfig{timeout: time.Second},
+		}}
+		assert.Equal(t, test.expected, client.supportsQueryKill(), test.engine.String())
+
+		client.options.queryKill = nil
+		assert.False(t, client.supportsQueryKill(), test.engine.String())
+	}
+}
+
+// TestClient_startQueryKill will test the method startQueryKill()
+func TestClient_startQueryKill(t *testing.T) {
+	t.Run("query id in the comment", func(t *testing.T) {
+		client := &Client{options: &clientOptions{
+			engine:    MySQL,
+			queryKill: &queryKillConfig{timeout: time.Second},
+			replicas:  newReplicaPool(nil),
+		}}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		queryCtx, stop := client.startQueryKill(ctx)
+		defer stop()
+		queryID := getQueryID(queryCtx)
+		require.Len(t, queryID, 16)
+		assert.Equal(t, "datastore_query_id='"+queryID+"'", client.getQueryComment(queryCtx))
+		assert.Empty(t, client.getQueryComment(ctx))
+
+		// Already tracked
+		sameCtx, sameStop := client.startQueryKill(queryCtx)
+		defer sameStop()
+		assert.Equal(t, queryID, getQueryID(sameCtx))
+
+		// With the context values
+		client.options.queryComments = testTraceExtractor
+		traceCtx, traceStop := client.startQueryKill(context.WithValue(ctx, testTraceIDKey{}, "1"))
+		defer traceStop()
+		assert.Equal(t, "app='datastore',datastore_query_id='"+getQueryID(traceCtx)+"',trace_id='1'",
+			client.getQueryComment(traceCtx))
+	})
+
+	t.Run("canceled without databases", func(t *testing.T) {
+		client := &Client{options: &clientOptions{
+			engine:    PostgreSQL,
+			queryKill: &queryKillConfig{timeout: time.Second},
+			replicas:  newReplicaPool(nil),
+		}}
+		ctx, cancel := context.WithCancel(context.Background())
+		_, stop := client.startQueryKill(ctx)
+		cancel()
+		stop()
+
+		killed, err := client.killServerQuery(context.Background(), "0123456789abcdef")
+		require.NoError(t, err)
+		assert.Zero(t, killed)
+	})
+
+	t.Run("not tracked", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		client := &Client{options: &clientOptions{engine: SQLite, queryKill: &queryKillConfig{timeout: time.Second}}}
+		queryCtx, stop := client.startQueryKill(ctx)
+		stop()
+		assert.Empty(t, getQueryID(queryCtx))
+
+		// Without a cancellation
+		client.options.engine = MySQL
+		queryCtx, stop = client.startQueryKill(context.Background())
+		stop()
+		assert.Empty(t, getQueryID(queryCtx))
+	})
+}
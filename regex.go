@@ -0,0 +1,112 @@
+package datastore
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLite REGEXP function
+const (
+	sqliteRegexpCacheSize = 1000             // Max compiled patterns (the cache is cleared when full)
+	sqliteRegexpDriver    = "sqlite3_regexp" // SQLite driver name with the REGEXP function registered
+)
+
+var (
+	sqliteRegexpCache    = &regexpCache{maxEntries: sqliteRegexpCacheSize} // Compiled patterns for the SQLite REGEXP function
+	sqliteRegexpRegister sync.Once                                         // Registers the SQLite driver once
+)
+
+// regexpCache is a capped cache of compiled patterns (cleared when full, patterns are usually a small set)
+type regexpCache struct {
+	maxEntries int                       // Max compiled patterns
+	mu         sync.Mutex                // Lock for the patterns
+	patterns   map[string]*regexp.Regexp // Compiled patterns (by pattern)
+}
+
+// get will return the compiled pattern (compiled and cached if not found)
+func (r *regexpCache) get(pattern string) (*regexp.Regexp, error) {
+	r.mu.Lock()
+	compiled, ok := r.patterns[pattern]
+	r.mu.Unlock()
+	if ok {
+		return compiled, nil
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.patterns == nil || len(r.patterns) >= r.maxEntries {
+		r.patterns = make(map[string]*regexp.Regexp)
+	}
+	r.patterns[pattern] = compiled
+	return compiled, nil
+}
+
+// getSQLiteRegexpDriver will return the SQLite driver name with the REGEXP function (registered on first use)
+//
+// SQLite does not include a REGEXP implementation, ExistingConnection pools need to register their own
+func getSQLiteRegexpDriver() string {
+	sqliteRegexpRegister.Do(func() {
+		sql.Register(sqliteRegexpDriver, &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				return conn.RegisterFunc("regexp", sqliteRegexp, true)
+			},
+		})
+	})
+	return sqliteRegexpDriver
+}
+
+// sqliteRegexp is the SQLite REGEXP function (X REGEXP Y calls regexp(Y, X))
+func sqliteRegexp(pattern string, value interface{}) (bool, error) {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return false, nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return false, nil
+	}
+
+	compiled, err := sqliteRegexpCache.get(pattern)
+	if err != nil {
+		return false, err
+	}
+	return compiled.MatchString(text), nil
+}
+
+// whereRegex generates the regular expression statement (the pattern is a placeholder)
+//
+// MySQL: REGEXP (REGEXP_LIKE for case-insensitive), PostgreSQL: ~ (~*), SQLite: REGEXP
+func whereRegex(engine Engine, k, placeholder string, caseInsensitive bool) string {
+	if engine == PostgreSQL {
+		if caseInsensitive {
+			return k + " ~* " + placeholder
+		}
+		return k + " ~ " + placeholder
+	} else if engine == MySQL && caseInsensitive {
+		return "REGEXP_LIKE(" + k + ", " + placeholder + ", 'i')"
+	}
+	return k + " REGEXP " + placeholder
+}
+
+// getRegexCondition will return the pattern and if the match is case-insensitive ($options: "i")
+func getRegexCondition(engine Engine, pattern interface{}, regexOptions interface{}) (interface{}, bool) {
+	options, _ := regexOptions.(string)
+	caseInsensitive := strings.Contains(options, "i")
+	if caseInsensitive && engine == SQLite {
+		if p, ok := pattern.(string); ok {
+			return "(?i)" + p, true
+		}
+	}
+	return pattern, caseInsensitive
+}
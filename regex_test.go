@@ -0,0 +1,84 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_whereRegex will test the method whereRegex()
+func Test_whereRegex(t *testing.T) {
+	assert.Equal(t, "name REGEXP @var0", whereRegex(MySQL, "name", "@var0", false))
+	assert.Equal(t, "REGEXP_LIKE(name, @var0, 'i')", whereRegex(MySQL, "name", "@var0", true))
+	assert.Equal(t, "name ~ @var0", whereRegex(PostgreSQL, "name", "@var0", false))
+	assert.Equal(t, "name ~* @var0", whereRegex(PostgreSQL, "name", "@var0", true))
+	assert.Equal(t, "name REGEXP @var0", whereRegex(SQLite, "name", "@var0", true))
+}
+
+// Test_sqliteRegexp will test the method sqliteRegexp()
+func Test_sqliteRegexp(t *testing.T) {
+	matched, err := sqliteRegexp("^j.*h$", "john smith")
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	matched, err = sqliteRegexp("^j", []byte("bob"))
+	require.NoError(t, err)
+	assert.False(t, matched)
+
+	matched, err = sqliteRegexp("^j", nil)
+	require.NoError(t, err)
+	assert.False(t, matched)
+
+	_, err = sqliteRegexp("(", "value")
+	require.Error(t, err)
+}
+
+// Test_regexpCache will test the capped cache of the compiled patterns
+func Test_regexpCache(t *testing.T) {
+	cache := &regexpCache{maxEntries: 2}
+	first, err := cache.get("^a")
+	require.NoError(t, err)
+	cached, err := cache.get("^a")
+	require.NoError(t, err)
+	assert.Same(t, first, cached)
+
+	_, err = cache.get("^b")
+	require.NoError(t, err)
+	assert.Len(t, cache.patterns, 2)
+
+	// Cleared when full
+	_, err = cache.get("^c")
+	require.NoError(t, err)
+	assert.Len(t, cache.patterns, 1)
+
+	_, err = cache.get("(")
+	require.Error(t, err)
+	assert.Len(t, cache.patterns, 1)
+}
+
+// TestClient_GetModels_regex will test the $regex condition using SQLite
+func TestClient_GetModels_regex(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+	testSaveModels(ctx, t, client,
+		&testSQLModel{ID: "regex-1", Name: "John Smith"},
+		&testSQLModel{ID: "regex-2", Name: "jane smithers"},
+		&testSQLModel{ID: "regex-3", Name: "Bob Jones"},
+	)
+
+	var models []*testSQLModel
+	require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{
+		"name": map[string]interface{}{conditionRegex: "^J.* Smith"},
+	}, nil, nil, defaultDatabaseMaxTimeout))
+	require.Len(t, models, 1)
+	assert.Equal(t, "regex-1", models[0].ID)
+
+	models = nil
+	require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{
+		"name": map[string]interface{}{conditionRegex: "^j.* smith", conditionRegexOptions: "i"},
+	}, nil, nil, defaultDatabaseMaxTimeout))
+	assert.Len(t, models, 2)
+}
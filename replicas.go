@@ -0,0 +1,252 @@
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	glogger "gorm.io/gorm/logger"
+)
+
+// replicaPool is the connection pool of the read replicas, registered as the only replica of the resolver
+//
+// The replica databases can be replaced at runtime (see: UpdateReplicas), reads use the source database
+// when there are no replicas
+type replicaPool struct {
+	mu       sync.RWMutex
+	replicas []*sql.DB       // Replica databases (random policy)
+	settings []func(*sql.DB) // Connection settings of the resolver (applied to the new replicas)
+	source   *sql.DB         // Source database (used without replicas)
+	users    *sync.WaitGroup // Users of the current databases that have not started their query yet (see: get)
+}
+
+// newReplicaPool will return a new replica pool (the reads use the source until replicas are added)
+func newReplicaPool(source *sql.DB) *replicaPool {
+	return &replicaPool{source: source, users: &sync.WaitGroup{}}
+}
+
+// UpdateReplicas will replace the read replicas at runtime (IE: replicas added or removed by autoscaling)
+//
+// The new replicas are opened (and checked) first, then the reads switch to them and the old replicas are
+// drained (closed after their running queries), an empty list sends the reads to the source database
+// MySQL and PostgreSQL only, the configurations use the defaults of WithSQL (the given configurations are
+// not changed)
+func (c *Client) UpdateReplicas(ctx context.Context, configs []*SQLConfig) error {
+	if (c.Engine() != MySQL && c.Engine() != PostgreSQL) || c.options.replicas == nil {
+		return ErrUnsupportedEngine
+	}
+
+	// Set the defaults of the replica configurations
+	replicaConfigs := make([]*SQLConfig, 0, len(configs))
+	for _, config := range configs {
+		if config == nil {
+			continue
+		}
+		replicaConfig := *config
+		config = &replicaConfig
+		config.Driver = c.Engine().String()
+		config.Replica = true
+		if config.ExistingConnection == nil {
+			config = config.sqlDefaults(c.Engine())
+		}
+		replicaConfigs = append(replicaConfigs, config)
+	}
+
+	// Open the new replicas
+	openConfigs := replicaConfigs
+	if c.isCockroachDB() {
+		openConfigs = getCockroachDBConfigs(openConfigs)
+	}
+	replicas, err := openReplicaDatabases(c.options.loggerDB, openConfigs)
+	if err != nil {
+		return err
+	}
+
+	// Switch the reads and keep the configuration (see: EffectiveConfig)
+	old, users := c.options.replicas.swap(replicas)
+	c.options.runtimeMu.Lock()
+	sqlConfigs := make([]*SQLConfig, 0, len(c.options.sqlConfigs)+len(replicaConfigs))
+	for _, config := range c.options.sqlConfigs {
+		if !config.Replica {
+			sqlConfigs = append(sqlConfigs, config)
+		}
+	}
+	c.options.sqlConfigs = append(sqlConfigs, replicaConfigs...)
+	c.options.runtimeMu.Unlock()
+	c.DebugLog(ctx, fmt.Sprintf("replicas updated: %d replica(s), draining %d replica(s)", len(replicas), len(old)))
+
+	// Wait for the readers that picked an old replica before the switch
+	users.Wait()
+	return closeReplicaDatabases(old)
+}
+
+// openReplicaDatabases will open the replica databases (closing the opened databases on error)
+func openReplicaDatabases(optionalLogger glogger.Interface, configs []*SQLConfig) ([]*sql.DB, error) {
+	replicas := make([]*sql.DB, 0, len(configs))
+	for _, config := range configs {
+		var gormDB *gorm.DB
+		err := registerMySQLTLSConfig(config)
+		if err == nil {
+			gormDB, err = gorm.Open(getDialector(config), getGormConfig(
+				config.TablePrefix, defaultPreparedStatements, config.Debug, config.EnableForeignKeys, optionalLogger,
+			))
+		}
+		var sqlDB *sql.DB
+		if err == nil {
+			sqlDB, err = gormDB.DB()
+		}
+		if err != nil {
+			_ = closeReplicaDatabases(replicas)
+			return nil, err
+		}
+		replicas = append(replicas, sqlDB)
+	}
+	return replicas, nil
+}
+
+// databases will return the source and the replica databases
+func (p *replicaPool) databases() []*sql.DB {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	databases := make([]*sql.DB, 0, len(p.replicas)+1)
+	if p.source != nil {
+		databases = append(databases, p.source)
+	}
+	return append(databases, p.replicas...)
+}
+
+// readReplicas will return the replica databases (without the source)
+func (p *replicaPool) readReplicas() []*sql.DB {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.replicas)
+}
+
+// closeReplicaDatabases will close the replica databases (waiting for the running queries)
+func closeReplicaDatabases(replicas []*sql.DB) error {
+	var errs []error
+	for _, replica := range replicas {
+		if err := replica.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// getReplicaPoolDialector will return the dialector of the replica pool (see: dbresolver.Config)
+//
+// Only the connection pool is used by the resolver (the statements are built by the source)
+func getReplicaPoolDialector(engine Engine, pool *replicaPool) gorm.Dialector {
+	if engine == MySQL {
+		return mysql.New(mysql.Config{Conn: pool, SkipInitializeWithVersion: true})
+	}
+	return postgres.New(postgres.Config{Conn: pool, PreferSimpleProtocol: true})
+}
+
+// get will return a random replica (or the source without replicas)
+//
+// The returned func must be called once the query is started, the replica is not closed before (see: swap)
+func (p *replicaPool) get() (*sql.DB, func()) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	p.users.Add(1)
+	if len(p.replicas) == 0 {
+		return p.source, p.users.Done
+	}
+	return p.replicas[rand.IntN(len(p.replicas))], p.users.Done //nolint:gosec // load balancing only
+}
+
+// swap will replace the replicas (applying the connection settings) and return the old replicas and their
+// users (wait for the users before closing the old replicas)
+func (p *replicaPool) swap(replicas []*sql.DB) ([]*sql.DB, *sync.WaitGroup) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, replica := range replicas {
+		for _, setting := range p.settings {
+			setting(replica)
+		}
+	}
+	old, users := p.replicas, p.users
+	p.replicas, p.users = replicas, &sync.WaitGroup{}
+	return old, users
+}
+
+// close will close the replicas (the source is closed by the client)
+func (p *replicaPool) close() error {
+	old, users := p.swap(nil)
+	users.Wait()
+	return closeReplicaDatabases(old)
+}
+
+// apply will apply the connection setting to the replicas (current and new)
+func (p *replicaPool) apply(setting func(*sql.DB)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.settings = append(p.settings, setting)
+	for _, replica := range p.replicas {
+		setting(replica)
+	}
+}
+
+// PrepareContext will prepare the statement on a replica (see: gorm.ConnPool)
+func (p *replicaPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	db, done := p.get()
+	defer done()
+	return db.PrepareContext(ctx, query)
+}
+
+// ExecContext will execute the statement on a replica (see: gorm.ConnPool)
+func (p *replicaPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	db, done := p.get()
+	defer done()
+	return db.ExecContext(ctx, query, args...)
+}
+
+// QueryContext will run the query on a replica (see: gorm.ConnPool)
+func (p *replicaPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	db, done := p.get()
+	defer done()
+	return db.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext will run the query on a replica (see: gorm.ConnPool)
+func (p *replicaPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	db, done := p.get()
+	defer done()
+	return db.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx will start a (read-only) transaction on a replica (see: gorm.TxBeginner)
+func (p *replicaPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	db, done := p.get()
+	defer done()
+	return db.BeginTx(ctx, opts)
+}
+
+// SetConnMaxIdleTime will set the max idle time of the replica connections (see: dbresolver)
+func (p *replicaPool) SetConnMaxIdleTime(d time.Duration) {
+	p.apply(func(db *sql.DB) { db.SetConnMaxIdleTime(d) })
+}
+
+// SetConnMaxLifetime will set the max lifetime of the replica connections (see: dbresolver)
+func (p *replicaPool) SetConnMaxLifetime(d time.Duration) {
+	p.apply(func(db *sql.DB) { db.SetConnMaxLifetime(d) })
+}
+
+// SetMaxIdleConns will set the max idle connections of the replicas (see: dbresolver)
+func (p *replicaPool) SetMaxIdleConns(n int) {
+	p.apply(func(db *sql.DB) { db.SetMaxIdleConns(n) })
+}
+
+// SetMaxOpenConns will set the max open connections of the replicas (see: dbresolver)
+func (p *replicaPool) SetMaxOpenConns(n int) {
+	p.apply(func(db *sql.DB) { db.SetMaxOpenConns(n) })
+}
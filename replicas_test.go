@@ -0,0 +1,126 @@
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/plugin/dbresolver"
+)
+
+// testReplicaDB will return a separate in-memory database with a marker row (the name of the database)
+func testReplicaDB(t *testing.T, name string) *sql.DB {
+	db, err := sql.Open("sqlite3", "file:"+name+"?mode=memory&cache=shared")
+	require.NoError(t, err)
+	_, err = db.Exec("CREATE TABLE marker (name TEXT); INSERT INTO marker (name) VALUES ('" + name + "')")
+	require.NoError(t, err)
+	return db
+}
+
+// TestClient_UpdateReplicas will test the method UpdateReplicas()
+func TestClient_UpdateReplicas(t *testing.T) {
+	t.Run("unsupported engine", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		require.ErrorIs(t, client.UpdateReplicas(ctx, nil), ErrUnsupportedEngine)
+	})
+
+	t.Run("replicas are swapped and drained", func(t *testing.T) {
+		ctx := context.Background()
+		source := testReplicaDB(t, "replica_source")
+		client, err := NewClient(ctx, WithSQLConnection(PostgreSQL, source, ""))
+		require.NoError(t, err)
+		defer func() {
+			_ = client.Close(ctx)
+		}()
+
+		getMarker := func() string {
+			var name string
+			require.NoError(t, client.Raw("SELECT name FROM marker").Scan(&name).Error)
+			return name
+		}
+		assert.Equal(t, "replica_source", getMarker())
+
+		replicaA := testReplicaDB(t, "replica_a")
+		require.NoError(t, client.UpdateReplicas(ctx, []*SQLConfig{{ExistingConnection: replicaA}, nil}))
+		assert.Equal(t, "replica_a", getMarker())
+
+		// Writes still use the source
+		var name string
+		require.NoError(t, client.Raw("SELECT name FROM marker").Clauses(dbresolver.Write).Scan(&name).Error)
+		assert.Equal(t, "replica_source", name)
+
+		replicaB := testReplicaDB(t, "replica_b")
+		require.NoError(t, client.UpdateReplicas(ctx, []*SQLConfig{{ExistingConnection: replicaB}}))
+		assert.Equal(t, "replica_b", getMarker())
+		require.Error(t, replicaA.Ping())
+
+		sqlConfigs := client.EffectiveConfig().SQL
+		require.Len(t, sqlConfigs, 2)
+		assert.False(t, sqlConfigs[0].Replica)
+		assert.True(t, sqlConfigs[1].Replica)
+
+		// Without replicas the reads use the source
+		require.NoError(t, client.UpdateReplicas(ctx, nil))
+		assert.Equal(t, "replica_source", getMarker())
+		require.Error(t, replicaB.Ping())
+	})
+
+	t.Run("the given configurations are not changed", func(t *testing.T) {
+		ctx := context.Background()
+		client, err := NewClient(ctx, WithSQLConnection(PostgreSQL, testReplicaDB(t, "replica_config_source"), ""))
+		require.NoError(t, err)
+		defer func() {
+			_ = client.Close(ctx)
+		}()
+
+		config := &SQLConfig{ExistingConnection: testReplicaDB(t, "replica_config")}
+		require.NoError(t, client.UpdateReplicas(ctx, []*SQLConfig{config}))
+		assert.Empty(t, config.Driver)
+		assert.False(t, config.Replica)
+
+		sqlConfigs := client.EffectiveConfig().SQL
+		require.Len(t, sqlConfigs, 2)
+		assert.Equal(t, PostgreSQL.String(), sqlConfigs[1].Driver)
+		assert.True(t, sqlConfigs[1].Replica)
+	})
+
+	t.Run("old replicas are closed after their readers", func(t *testing.T) {
+		ctx := context.Background()
+		client, err := NewClient(ctx, WithSQLConnection(PostgreSQL, testReplicaDB(t, "replica_reader_source"), ""))
+		require.NoError(t, err)
+		defer func() {
+			_ = client.Close(ctx)
+		}()
+
+		replica := testReplicaDB(t, "replica_reader")
+		require.NoError(t, client.UpdateReplicas(ctx, []*SQLConfig{{ExistingConnection: replica}}))
+
+		// A reader picked the replica before the switch
+		db, done := client.(*Client).options.replicas.get()
+		require.Equal(t, replica, db)
+
+		updated := make(chan error, 1)
+		go func() {
+			updated <- client.UpdateReplicas(ctx, nil)
+		}()
+		assert.Eventually(t, func() bool {
+			return len(client.(*Client).options.replicas.readReplicas()) == 0
+		}, time.Second, time.Millisecond)
+
+		// The replica is not closed while the reader has not started its query
+		time.Sleep(20 * time.Millisecond)
+		assert.Empty(t, updated)
+		var name string
+		require.NoError(t, db.QueryRow("SELECT name FROM marker").Scan(&name))
+		assert.Equal(t, "replica_reader", name)
+
+		done()
+		require.NoError(t, <-updated)
+		require.Error(t, replica.Ping())
+	})
+}
@@ -0,0 +1,133 @@
+// Package repository is a typed layer on the datastore client (one repository per model type)
+//
+// The repository uses the client methods, the models are pointers to T (IE: Repository[Record] uses *Record):
+//
+//	records := repository.New[Record](client)
+//	record, err := records.First(ctx, map[string]interface{}{"name": "alice"})
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+)
+
+// defaultTimeout is the default timeout of the queries
+const defaultTimeout = 60 * time.Second
+
+// Page is a page of models with the total count of matching records (see: Repository.Paginate)
+type Page[T any] struct {
+	Items      []*T  `json:"items"`       // Models of the page (empty after the last page)
+	Page       int   `json:"page"`        // Current page (starting at 1)
+	PageSize   int   `json:"page_size"`   // Number of results per page
+	Total      int64 `json:"total"`       // Total number of matching records
+	TotalPages int   `json:"total_pages"` // Total number of pages
+}
+
+// Repository is the typed repository of the models of type T
+type Repository[T any] struct {
+	client  datastore.ClientInterface
+	timeout time.Duration
+}
+
+// New will return a new repository of the models of type T using the client
+func New[T any](client datastore.ClientInterface) *Repository[T] {
+	return &Repository[T]{client: client, timeout: defaultTimeout}
+}
+
+// WithTimeout will return a copy of the repository using the timeout for the queries (default is 60 seconds)
+func (r *Repository[T]) WithTimeout(timeout time.Duration) *Repository[T] {
+	repository := *r
+	if timeout > 0 {
+		repository.timeout = timeout
+	}
+	return &repository
+}
+
+// Client will return the client of the repository (IE: for the methods without a typed version)
+func (r *Repository[T]) Client() datastore.ClientInterface {
+	return r.client
+}
+
+// Find will return the models matching the conditions (an empty slice if there are no results)
+func (r *Repository[T]) Find(ctx context.Context, conditions map[string]interface{},
+	queryParams *datastore.QueryParams,
+) ([]*T, error) {
+	models := make([]*T, 0)
+	if err := r.client.GetModels(
+		ctx, &models, conditions, queryParams, nil, r.timeout,
+	); err != nil && !errors.Is(err, datastore.ErrNoResults) {
+		return nil, err
+	}
+	return models, nil
+}
+
+// First will return the first model matching the conditions (datastore.ErrNoResults if there are no results)
+func (r *Repository[T]) First(ctx context.Context, conditions map[string]interface{}) (*T, error) {
+	model := new(T)
+	if err := r.client.GetModel(ctx, model, conditions, r.timeout, false); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// Create will create the model (in its own transaction)
+func (r *Repository[T]) Create(ctx context.Context, model *T) error {
+	return r.client.SaveModelAuto(ctx, model, true)
+}
+
+// Save will update the existing model, primary key based (in its own transaction)
+func (r *Repository[T]) Save(ctx context.Context, model *T) error {
+	return r.client.SaveModelAuto(ctx, model, false)
+}
+
+// Delete will delete the model, primary key based (in its own transaction)
+func (r *Repository[T]) Delete(ctx context.Context, model *T) error {
+	return r.client.NewTx(ctx, func(tx *datastore.Transaction) error {
+		return r.client.DeleteModel(ctx, model, tx, true)
+	})
+}
+
+// Count will return the number of models matching the conditions
+func (r *Repository[T]) Count(ctx context.Context, conditions map[string]interface{}) (int64, error) {
+	return r.client.GetModelCount(ctx, new(T), conditions, r.timeout)
+}
+
+// Paginate will return a page of the models matching the conditions and the total count (see: GetModelsPaged)
+//
+// A page after the last page has no items (the total is still counted)
+func (r *Repository[T]) Paginate(ctx context.Context, conditions map[string]interface{},
+	queryParams *datastore.QueryParams,
+) (*Page[T], error) {
+	models := make([]*T, 0)
+	result, err := r.client.GetModelsPaged(ctx, &models, conditions, queryParams, r.timeout)
+	if err == nil {
+		return &Page[T]{
+			Items:      models,
+			Page:       result.Page,
+			PageSize:   result.PageSize,
+			Total:      result.Total,
+			TotalPages: result.TotalPages,
+		}, nil
+	} else if !errors.Is(err, datastore.ErrNoResults) {
+		return nil, err
+	}
+
+	// No results on the page
+	page := &Page[T]{Items: models, Page: 1}
+	if queryParams != nil {
+		page.Page = max(queryParams.Page, 1)
+		page.PageSize = queryParams.PageSize
+	}
+	if page.Page > 1 {
+		if page.Total, err = r.Count(ctx, conditions); err != nil {
+			return nil, err
+		}
+	}
+	if page.PageSize > 0 {
+		page.TotalPages = int((page.Total + int64(page.PageSize) - 1) / int64(page.PageSize))
+	}
+	return page, nil
+}
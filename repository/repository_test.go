@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRecord is the model of the tests
+type testRecord struct {
+	ID     string `json:"id" toml:"id" yaml:"id" gorm:"<-:create;type:char(64);primaryKey" bson:"_id"`
+	Name   string `json:"name" toml:"name" yaml:"name" gorm:"type:varchar(64)" bson:"name"`
+	Amount int64  `json:"amount" toml:"amount" yaml:"amount" bson:"amount"`
+}
+
+// GetModelName will return the model name
+func (r *testRecord) GetModelName() string {
+	return "test_record"
+}
+
+// GetModelTableName will return the table name
+func (r *testRecord) GetModelTableName() string {
+	return "test_records"
+}
+
+// newTestRepository will return a new repository of the test records (the client is closed by the cleanup)
+func newTestRepository(t *testing.T, opts ...datastore.ClientOps) *Repository[testRecord] {
+	ctx := context.Background()
+	client, err := datastore.NewClient(ctx, append(opts, datastore.WithAutoMigrate(&testRecord{}))...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = client.Close(ctx)
+	})
+
+	repository := New[testRecord](client)
+	for _, record := range []*testRecord{
+		{ID: "record-1", Name: "alice", Amount: 10},
+		{ID: "record-2", Name: "bob", Amount: 20},
+		{ID: "record-3", Name: "carol", Amount: 30},
+	} {
+		require.NoError(t, repository.Create(ctx, record))
+	}
+	return repository
+}
+
+// TestRepository will test the methods of the Repository
+func TestRepository(t *testing.T) {
+	engines := map[string]func(t *testing.T) datastore.ClientOps{
+		"sqlite": func(t *testing.T) datastore.ClientOps {
+			return datastore.WithSQLite(datastore.NewEphemeralSQLiteConfig(t, t.Name()))
+		},
+		"memory": func(*testing.T) datastore.ClientOps {
+			return datastore.WithMemory(&datastore.MemoryConfig{})
+		},
+	}
+	for name, engine := range engines {
+		t.Run(name+" find and first", func(t *testing.T) {
+			ctx := context.Background()
+			repository := newTestRepository(t, engine(t))
+
+			records, err := repository.Find(ctx, map[string]interface{}{
+				"amount": map[string]interface{}{"$gte": 20},
+			}, &datastore.QueryParams{OrderByField: "amount", SortDirection: datastore.SortAsc})
+			require.NoError(t, err)
+			require.Len(t, records, 2)
+			assert.Equal(t, "bob", records[0].Name)
+			assert.Equal(t, "carol", records[1].Name)
+
+			records, err = repository.Find(ctx, map[string]interface{}{"name": "nobody"}, nil)
+			require.NoError(t, err)
+			assert.Empty(t, records)
+
+			record, err := repository.First(ctx, map[string]interface{}{"name": "alice"})
+			require.NoError(t, err)
+			assert.Equal(t, "record-1", record.ID)
+
+			_, err = repository.First(ctx, map[string]interface{}{"name": "nobody"})
+			require.ErrorIs(t, err, datastore.ErrNoResults)
+		})
+
+		t.Run(name+" save, count and delete", func(t *testing.T) {
+			ctx := context.Background()
+			repository := newTestRepository(t, engine(t))
+
+			record, err := repository.First(ctx, map[string]interface{}{"id": "record-2"})
+			require.NoError(t, err)
+			record.Amount = 25
+			require.NoError(t, repository.Save(ctx, record))
+
+			count, err := repository.Count(ctx, map[string]interface{}{"amount": 25})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+
+			require.NoError(t, repository.Delete(ctx, record))
+			count, err = repository.Count(ctx, nil)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), count)
+		})
+
+		t.Run(name+" paginate", func(t *testing.T) {
+			ctx := context.Background()
+			repository := newTestRepository(t, engine(t))
+
+			page, err := repository.Paginate(ctx, nil, &datastore.QueryParams{
+				OrderByField: "amount", SortDirection: datastore.SortDesc, Page: 1, PageSize: 2,
+			})
+			require.NoError(t, err)
+			require.Len(t, page.Items, 2)
+			assert.Equal(t, "carol", page.Items[0].Name)
+			assert.Equal(t, int64(3), page.Total)
+			assert.Equal(t, 2, page.TotalPages)
+
+			page, err = repository.Paginate(ctx, nil, &datastore.QueryParams{
+				OrderByField: "amount", SortDirection: datastore.SortDesc, Page: 3, PageSize: 2,
+			})
+			require.NoError(t, err)
+			assert.Empty(t, page.Items)
+			assert.Equal(t, 3, page.Page)
+			assert.Equal(t, int64(3), page.Total)
+			assert.Equal(t, 2, page.TotalPages)
+		})
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		repository := New[testRecord](nil)
+		assert.Equal(t, defaultTimeout, repository.timeout)
+		assert.Equal(t, time.Second, repository.WithTimeout(time.Second).timeout)
+		assert.Equal(t, defaultTimeout, repository.WithTimeout(0).timeout)
+		assert.Equal(t, defaultTimeout, repository.timeout)
+	})
+}
@@ -0,0 +1,286 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+)
+
+// Retention settings
+const (
+	defaultRetentionArchiveSuffix = "_archive" // Default archive table (collection) suffix
+	defaultRetentionBatchSize     = 1000       // Default rows per batch
+	defaultRetentionInterval      = time.Hour  // Default time between scheduled runs
+)
+
+// RetentionMode is what happens to the expired rows
+type RetentionMode string
+
+// Retention modes
+const (
+	RetentionArchive RetentionMode = "archive" // Move the expired rows to the archive table (collection)
+	RetentionDelete  RetentionMode = "delete"  // Delete the expired rows
+)
+
+// ErrInvalidRetentionPolicy is when the retention policy is missing a field, TTL or valid mode
+var ErrInvalidRetentionPolicy = errors.New("invalid retention policy")
+
+// RetentionPolicy is a declarative retention policy for a model (see: RegisterRetention)
+type RetentionPolicy struct {
+	ArchiveTable string        // Destination for the archive mode (default: table name + "_archive")
+	BatchSize    int           // Rows per batch (default: 1000)
+	Field        string        // Time column compared to the TTL (IE: created_at)
+	Mode         RetentionMode // Delete or archive the expired rows
+	TTL          time.Duration // Rows older than the TTL are expired
+}
+
+// RetentionMetricsRecorder can be implemented by the MetricsRecorder to report the retention runs
+type RetentionMetricsRecorder interface {
+	RecordRetention(ctx context.Context, tableName string, mode RetentionMode, rows int64)
+}
+
+// retentionPolicies are the registered retention policies (by table name) and the scheduler
+type retentionPolicies struct {
+	cancel   context.CancelFunc          // Stops the scheduler (nil if not running)
+	mu       sync.Mutex                  // Lock for the policies and scheduler
+	policies map[string]*retentionPolicy // Registered policies (by table name)
+}
+
+// retentionPolicy is a registered retention policy
+type retentionPolicy struct {
+	RetentionPolicy
+	primaryKey string // Primary key column (SQL)
+	tableName  string // Full table (collection) name
+}
+
+// RegisterRetention will register a retention policy for the model (replacing any existing policy)
+//
+// The archive table is created using the model for SQL engines (composite primary keys are not supported)
+// Policies are enforced by EnforceRetention() or the scheduler (see: StartRetention)
+func (c *Client) RegisterRetention(model interface{}, policy RetentionPolicy) error {
+	if !indexNamePattern.MatchString(policy.Field) || policy.TTL <= 0 ||
+		(policy.Mode != RetentionArchive && policy.Mode != RetentionDelete) ||
+		(policy.ArchiveTable != "" && !indexNamePattern.MatchString(policy.ArchiveTable)) {
+		return ErrInvalidRetentionPolicy
+	}
+
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return err
+	}
+	registered := &retentionPolicy{RetentionPolicy: policy, tableName: tableName}
+	if registered.BatchSize <= 0 {
+		registered.BatchSize = defaultRetentionBatchSize
+	}
+	if registered.Mode == RetentionArchive && registered.ArchiveTable == "" {
+		registered.ArchiveTable = tableName + defaultRetentionArchiveSuffix
+	}
+
+	// SQL needs the primary key (batches) and the archive table
+	if IsSQLEngine(c.Engine()) {
+		if registered.primaryKey, err = c.getSinglePrimaryKeyColumn(model); err != nil {
+			return err
+		}
+
+		if registered.Mode == RetentionArchive {
+			if err = c.options.db.Table(registered.ArchiveTable).AutoMigrate(model); err != nil {
+				return err
+			}
+		}
+	}
+
+	retention := c.options.retention
+	retention.mu.Lock()
+	defer retention.mu.Unlock()
+	if retention.policies == nil {
+		retention.policies = make(map[string]*retentionPolicy)
+	}
+	retention.policies[tableName] = registered
+	return nil
+}
+
+// EnforceRetention will enforce all the registered retention policies (in batches)
+//
+// Returns the number of expired rows deleted or archived (by table name)
+func (c *Client) EnforceRetention(ctx context.Context) (map[string]int64, error) {
+	retention := c.options.retention
+	retention.mu.Lock()
+	policies := make([]*retentionPolicy, 0, len(retention.policies))
+	for _, policy := range retention.policies {
+		policies = append(policies, policy)
+	}
+	retention.mu.Unlock()
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].tableName < policies[j].tableName
+	})
+
+	results := make(map[string]int64, len(policies))
+	var errs []error
+	for _, policy := range policies {
+		rows, err := c.enforceRetentionPolicy(ctx, policy)
+		results[policy.tableName] = rows
+		if recorder, ok := c.options.metrics.(RetentionMetricsRecorder); ok {
+			recorder.RecordRetention(ctx, policy.tableName, policy.Mode, rows)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("retention for %s: %w", policy.tableName, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// StartRetention will start the scheduler that enforces the retention policies every interval
+//
+// The scheduler stops when the context is canceled or the client is closed
+func (c *Client) StartRetention(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+
+	retention := c.options.retention
+	retention.mu.Lock()
+	defer retention.mu.Unlock()
+	if retention.cancel != nil {
+		retention.cancel()
+	}
+	ctx, retention.cancel = context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := c.EnforceRetention(ctx); err != nil {
+					c.warnLog(ctx, "failed to enforce retention: "+err.Error())
+				}
+			}
+		}
+	}()
+}
+
+// stopRetention will stop the retention scheduler (if running)
+func (c *Client) stopRetention() {
+	if c.options.retention == nil {
+		return
+	}
+	c.options.retention.mu.Lock()
+	defer c.options.retention.mu.Unlock()
+	if c.options.retention.cancel != nil {
+		c.options.retention.cancel()
+		c.options.retention.cancel = nil
+	}
+}
+
+// enforceRetentionPolicy will delete or archive the expired rows in batches until none are left
+func (c *Client) enforceRetentionPolicy(ctx context.Context, policy *retentionPolicy) (total int64, err error) {
+	defer func() {
+		if total > 0 {
+			c.invalidateCacheTable(ctx, policy.tableName)
+		}
+	}()
+
+	cutoff := time.Now().UTC().Add(-policy.TTL)
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var rows int64
+		if c.Engine() == MongoDB {
+			rows, err = c.enforceRetentionMongo(ctx, policy, cutoff)
+		} else if IsSQLEngine(c.Engine()) {
+			rows, err = c.enforceRetentionSQL(ctx, policy, cutoff)
+		} else {
+			return total, ErrUnsupportedEngine
+		}
+		total += rows
+		if err != nil || rows < int64(policy.BatchSize) {
+			return
+		}
+	}
+}
+
+// enforceRetentionSQL will delete or archive one batch of expired rows (in a transaction)
+func (c *Client) enforceRetentionSQL(ctx context.Context, policy *retentionPolicy, cutoff time.Time) (int64, error) {
+	db := c.options.db.WithContext(ctx)
+	engine := c.Engine()
+	tableName := quoteIdentifier(engine, policy.tableName)
+	primaryKey := quoteIdentifier(engine, policy.primaryKey)
+
+	var ids []interface{}
+	if err := db.Table(policy.tableName).Where(quoteIdentifier(engine, policy.Field)+" < ?", cutoff).
+		Order(primaryKey).Limit(policy.BatchSize).Pluck(policy.primaryKey, &ids).Error; err != nil {
+		return 0, err
+	} else if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var rows int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if policy.Mode == RetentionArchive {
+			if err := tx.Exec(
+				"INSERT INTO "+quoteIdentifier(engine, policy.ArchiveTable)+" SELECT * FROM "+tableName+
+					" WHERE "+primaryKey+" IN ?", ids,
+			).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Exec("DELETE FROM "+tableName+" WHERE "+primaryKey+" IN ?", ids)
+		rows = result.RowsAffected
+		return result.Error
+	})
+	return rows, err
+}
+
+// enforceRetentionMongo will delete or archive one batch of expired documents
+func (c *Client) enforceRetentionMongo(ctx context.Context, policy *retentionPolicy, cutoff time.Time) (int64, error) {
+	collection := c.getMongoWriteCollection(ctx, policy.tableName)
+
+	findOptions := options.Find().SetLimit(int64(policy.BatchSize)).SetSort(bson.D{{Key: mongoIDField, Value: 1}})
+	if policy.Mode == RetentionDelete {
+		findOptions.SetProjection(bson.M{mongoIDField: 1})
+	}
+	cursor, err := collection.Find(ctx, bson.M{policy.Field: bson.M{conditionLessThan: cutoff}}, findOptions)
+	if err != nil {
+		return 0, err
+	}
+	var documents []bson.M
+	if err = cursor.All(ctx, &documents); err != nil {
+		return 0, err
+	} else if len(documents) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]interface{}, 0, len(documents))
+	archive := make([]interface{}, 0, len(documents))
+	for _, document := range documents {
+		ids = append(ids, document[mongoIDField])
+		archive = append(archive, document)
+	}
+
+	// Archived documents from a previous (failed) batch are skipped
+	if policy.Mode == RetentionArchive {
+		if _, err = c.getMongoWriteCollection(ctx, policy.ArchiveTable).InsertMany(
+			ctx, archive, options.InsertMany().SetOrdered(false),
+		); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return 0, err
+		}
+	}
+
+	result, err := collection.DeleteMany(ctx, bson.M{mongoIDField: bson.M{conditionIn: ids}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,163 @@
+package datastore
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRetentionRecorder records all retention runs
+type testRetentionRecorder struct {
+	testMetricsRecorder
+	mu   sync.Mutex
+	rows map[string]int64
+}
+
+// RecordRetention will record the retention run
+func (r *testRetentionRecorder) RecordRetention(_ context.Context, tableName string, _ RetentionMode, rows int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.rows == nil {
+		r.rows = make(map[string]int64)
+	}
+	r.rows[tableName] += rows
+}
+
+// testSaveRetentionModels will save two expired and one current model
+func testSaveRetentionModels(ctx context.Context, t *testing.T, client ClientInterface) {
+	expired := time.Now().UTC().Add(-48 * time.Hour)
+	testSaveModels(ctx, t, client,
+		&testSQLModel{ID: "retention-1", Name: "expired", CreatedAt: expired},
+		&testSQLModel{ID: "retention-2", Name: "expired", CreatedAt: expired},
+		&testSQLModel{ID: "retention-3", Name: "current", CreatedAt: time.Now().UTC()},
+	)
+}
+
+// TestClient_RegisterRetention will test the method RegisterRetention()
+func TestClient_RegisterRetention(t *testing.T) {
+	t.Run("invalid policies", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		for _, policy := range []RetentionPolicy{
+			{Field: "", Mode: RetentionDelete, TTL: time.Hour},
+			{Field: "created_at;", Mode: RetentionDelete, TTL: time.Hour},
+			{Field: "created_at", Mode: RetentionDelete},
+			{Field: "created_at", Mode: "truncate", TTL: time.Hour},
+			{ArchiveTable: "bad table", Field: "created_at", Mode: RetentionArchive, TTL: time.Hour},
+		} {
+			require.ErrorIs(t, client.RegisterRetention(&testSQLModel{}, policy), ErrInvalidRetentionPolicy)
+		}
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		err := client.RegisterRetention(&testSQLModel{}, RetentionPolicy{
+			Field: "created_at", Mode: RetentionDelete, TTL: time.Hour,
+		})
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// TestClient_EnforceRetention will test the method EnforceRetention()
+func TestClient_EnforceRetention(t *testing.T) {
+	t.Run("delete in batches", func(t *testing.T) {
+		ctx := context.Background()
+		recorder := &testRetentionRecorder{}
+		client, deferFunc := testSQLiteClient(ctx, t, WithMetrics(recorder))
+		defer deferFunc()
+		testSaveRetentionModels(ctx, t, client)
+
+		require.NoError(t, client.RegisterRetention(&testSQLModel{}, RetentionPolicy{
+			BatchSize: 1, Field: "created_at", Mode: RetentionDelete, TTL: 24 * time.Hour,
+		}))
+
+		results, err := client.EnforceRetention(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{testSQLTableName: 2}, results)
+		assert.Equal(t, int64(2), recorder.rows[testSQLTableName])
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("invalidates the cached reads", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithCache(NewLRUCache(0), time.Hour))
+		defer deferFunc()
+		testSaveRetentionModels(ctx, t, client)
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+
+		require.NoError(t, client.RegisterRetention(&testSQLModel{}, RetentionPolicy{
+			Field: "created_at", Mode: RetentionDelete, TTL: 24 * time.Hour,
+		}))
+		_, err = client.EnforceRetention(ctx)
+		require.NoError(t, err)
+
+		count, err = client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveRetentionModels(ctx, t, client)
+
+		require.NoError(t, client.RegisterRetention(&testSQLModel{}, RetentionPolicy{
+			Field: "created_at", Mode: RetentionArchive, TTL: 24 * time.Hour,
+		}))
+
+		results, err := client.EnforceRetention(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), results[testSQLTableName])
+
+		var archived int64
+		require.NoError(t, client.Raw("SELECT COUNT(*) FROM "+testSQLTableName+defaultRetentionArchiveSuffix).
+			Scan(&archived).Error)
+		assert.Equal(t, int64(2), archived)
+	})
+
+	t.Run("no policies", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		results, err := client.EnforceRetention(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+// TestClient_StartRetention will test the method StartRetention()
+func TestClient_StartRetention(t *testing.T) {
+	ctx := context.Background()
+	recorder := &testRetentionRecorder{}
+	client, deferFunc := testSQLiteClient(ctx, t, WithMetrics(recorder))
+	defer deferFunc()
+	testSaveRetentionModels(ctx, t, client)
+
+	require.NoError(t, client.RegisterRetention(&testSQLModel{}, RetentionPolicy{
+		Field: "created_at", Mode: RetentionDelete, TTL: 24 * time.Hour,
+	}))
+	client.StartRetention(ctx, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		return recorder.rows[testSQLTableName] == 2
+	}, time.Second, 10*time.Millisecond)
+
+	client.(*Client).stopRetention()
+	assert.Nil(t, client.(*Client).options.retention.cancel)
+}
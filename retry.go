@@ -0,0 +1,116 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transaction retry settings
+const (
+	defaultRetryBaseDelay   = 50 * time.Millisecond       // Default delay before the first retry
+	defaultRetryMaxAttempts = 3                           // Default attempts (including the first run)
+	defaultRetryMaxDelay    = 2 * time.Second             // Default max delay between the attempts
+	mysqlDeadlockError      = 1213                        // MySQL: ER_LOCK_DEADLOCK
+	mongoTransientLabel     = "TransientTransactionError" // MongoDB: transient transaction error label
+	postgresDeadlockError   = "40P01"                     // PostgreSQL: deadlock_detected
+	postgresSerializeError  = "40001"                     // PostgreSQL: serialization_failure
+)
+
+// RetryPolicy is the retry policy for NewTxWithRetry (zero fields use the defaults)
+type RetryPolicy struct {
+	BaseDelay   time.Duration // Delay before the first retry, doubled for each attempt (default: 50ms)
+	MaxAttempts int           // Max attempts, including the first run (default: 3)
+	MaxDelay    time.Duration // Max delay between the attempts (default: 2s)
+}
+
+// NewTxWithRetry will run fn in a new transaction (see: NewTx), re-running it in a new transaction on a
+// deadlock or serialization failure (MySQL 1213, PostgreSQL 40001 / 40P01, MongoDB TransientTransactionError)
+//
+// The attempts are delayed using an exponential backoff (with jitter), fn must be safe to run again
+func (c *Client) NewTxWithRetry(ctx context.Context, fn func(*Transaction) error, policy RetryPolicy,
+	txOptions ...*TxOptions,
+) error {
+	policy = getRetryPolicy(policy)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+
+		// Each attempt gets a share of the remaining timeout budget (see: WithTimeoutBudget)
+		attemptCtx, cancel, budgetErr := newAttemptCtx(ctx, policy.MaxAttempts-attempt+1)
+		if budgetErr != nil {
+			cancel()
+			if err != nil {
+				return err
+			}
+			return budgetErr
+		}
+		err = c.newTx(attemptCtx, fn, txOptions...)
+		cancel()
+		if err == nil || attempt >= policy.MaxAttempts || !IsRetryableTxError(err) {
+			return err
+		}
+		c.DebugLog(ctx, "retrying the transaction after: "+err.Error())
+
+		timer := time.NewTimer(getRetryDelay(policy, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// IsRetryableTxError will return true if the transaction can be retried (deadlock or serialization failure)
+func IsRetryableTxError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlockError
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgresDeadlockError || pgErr.Code == postgresSerializeError
+	}
+	var mongoErr mongo.ServerError
+	if errors.As(err, &mongoErr) && mongoErr.HasErrorLabel(mongoTransientLabel) {
+		return true
+	}
+	return IsDeadlockError(err)
+}
+
+// getRetryPolicy will return the policy with the defaults for the zero fields
+func getRetryPolicy(policy RetryPolicy) RetryPolicy {
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = defaultRetryBaseDelay
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = defaultRetryMaxDelay
+	}
+	return policy
+}
+
+// getRetryDelay will return the delay after the attempt (exponential backoff, between half and the full delay)
+func getRetryDelay(policy RetryPolicy, attempt int) time.Duration {
+	delay := policy.BaseDelay
+	for i := 1; i < attempt && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1) //nolint:gosec // jitter does not need a secure random number
+}
@@ -0,0 +1,165 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// errTestRetry is a non-retryable error for the tests
+var errTestRetry = errors.New("not retryable")
+
+// TestIsRetryableTxError will test the method IsRetryableTxError()
+func TestIsRetryableTxError(t *testing.T) {
+	for _, err := range []error{
+		&mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"},
+		fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "40001"}),
+		&pgconn.PgError{Code: "40P01"},
+		mongo.CommandError{Code: 251, Labels: []string{"TransientTransactionError"}},
+		errors.New("write conflict during plan execution"),
+	} {
+		assert.True(t, IsRetryableTxError(err), err.Error())
+	}
+
+	for _, err := range []error{
+		nil,
+		errTestRetry,
+		&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"},
+		&pgconn.PgError{Code: "23505"},
+		mongo.CommandError{Code: 11000, Labels: []string{"RetryableWriteError"}},
+	} {
+		assert.False(t, IsRetryableTxError(err))
+	}
+}
+
+// TestGetRetryDelay will test the method getRetryDelay()
+func TestGetRetryDelay(t *testing.T) {
+	policy := getRetryPolicy(RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond})
+	assert.Equal(t, defaultRetryMaxAttempts, policy.MaxAttempts)
+
+	for attempt, maxDelay := range map[int]time.Duration{
+		1: 10 * time.Millisecond,
+		2: 20 * time.Millisecond,
+		3: 40 * time.Millisecond,
+		4: 50 * time.Millisecond,
+		9: 50 * time.Millisecond,
+	} {
+		delay := getRetryDelay(policy, attempt)
+		assert.GreaterOrEqual(t, delay, maxDelay/2)
+		assert.LessOrEqual(t, delay, maxDelay)
+	}
+}
+
+// TestClient_NewTxWithRetry will test the method NewTxWithRetry()
+func TestClient_NewTxWithRetry(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Millisecond, MaxAttempts: 3, MaxDelay: 2 * time.Millisecond}
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+
+	t.Run("retried until committed", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var attempts int
+		require.NoError(t, client.NewTxWithRetry(ctx, func(tx *Transaction) error {
+			attempts++
+			if err := client.SaveModel(ctx, &testSQLModel{ID: "retry-1", Name: "saved"}, tx, true, false); err != nil {
+				return err
+			}
+			if attempts < 3 {
+				return deadlock
+			}
+			return nil
+		}, policy))
+		assert.Equal(t, 3, attempts)
+
+		// The failed attempts were rolled back
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var attempts int
+		err := client.NewTxWithRetry(ctx, func(*Transaction) error {
+			attempts++
+			return deadlock
+		}, policy)
+		require.ErrorIs(t, err, deadlock)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var attempts int
+		require.ErrorIs(t, client.NewTxWithRetry(ctx, func(*Transaction) error {
+			attempts++
+			return errTestRetry
+		}, policy), errTestRetry)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var attempts int
+		require.ErrorIs(t, client.NewTxWithRetry(ctx, func(*Transaction) error {
+			attempts++
+			cancel()
+			return deadlock
+		}, RetryPolicy{BaseDelay: time.Minute, MaxAttempts: 3}), deadlock)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("timeout budget exhausted", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		budget, cancel := WithTimeoutBudget(ctx, time.Nanosecond)
+		defer cancel()
+		time.Sleep(time.Millisecond)
+
+		var attempts int
+		require.ErrorIs(t, client.NewTxWithRetry(budget, func(*Transaction) error {
+			attempts++
+			return nil
+		}, policy), ErrTimeoutBudgetExhausted)
+		assert.Equal(t, 0, attempts)
+	})
+
+	t.Run("the attempt timeout limits the transaction", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		budget, cancel := WithTimeoutBudget(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		require.Error(t, client.NewTxWithRetry(budget, func(tx *Transaction) error {
+			time.Sleep(100 * time.Millisecond)
+			return client.SaveModel(ctx, &testSQLModel{ID: "budget-1"}, tx, true, false)
+		}, RetryPolicy{MaxAttempts: 1}))
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+}
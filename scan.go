@@ -0,0 +1,165 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+)
+
+// Scan settings
+const (
+	defaultScanBatchSize = 1000 // Rows per batch when scanning a table
+	maxScanReportRows    = 1000 // Max invalid rows kept in a scan report (all are counted)
+)
+
+// RowValidator will return an error if the row (column: value) violates a business invariant
+type RowValidator func(row map[string]interface{}) error
+
+// InvalidRow is a row that failed one or more validators
+type InvalidRow struct {
+	Errors []error                // Errors returned by the validators
+	Key    interface{}            // Primary key value (_id for MongoDB)
+	Row    map[string]interface{} // Row values (column: value)
+}
+
+// ScanReport is the result of scanning a table for invalid rows
+type ScanReport struct {
+	InvalidCount int64         // Number of invalid rows
+	InvalidRows  []*InvalidRow // Invalid rows (at most the first 1000)
+	Scanned      int64         // Number of rows scanned
+}
+
+// ScanForInvalidRows will stream through the model's table in batches and report the rows failing the validators
+//
+// Useful before tightening constraints in a migration (SQL engines require a single column primary key)
+func (c *Client) ScanForInvalidRows(ctx context.Context, model interface{},
+	validators ...RowValidator,
+) (*ScanReport, error) {
+	report := &ScanReport{}
+	err := c.forEachBatch(ctx, model, nil, nil, defaultScanBatchSize,
+		func(_, keyColumn string, rows []map[string]interface{}) error {
+			for _, row := range rows {
+				report.Scanned++
+				var errs []error
+				for _, validator := range validators {
+					if err := validator(row); err != nil {
+						errs = append(errs, err)
+					}
+				}
+				if len(errs) == 0 {
+					continue
+				}
+				report.InvalidCount++
+				if len(report.InvalidRows) < maxScanReportRows {
+					report.InvalidRows = append(report.InvalidRows, &InvalidRow{
+						Errors: errs, Key: row[keyColumn], Row: row,
+					})
+				}
+			}
+			return nil
+		},
+	)
+	return report, err
+}
+
+// forEachBatch will read the rows matching the conditions in batches (keyset pagination on the primary key)
+//
+// Only the key column and the given fields are read (all columns if no fields are given)
+func (c *Client) forEachBatch(ctx context.Context, model interface{}, conditions map[string]interface{},
+	fields []string, batchSize int, fn func(tableName, keyColumn string, rows []map[string]interface{}) error,
+) error {
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return err
+	}
+
+	if c.Engine() == MongoDB {
+		return c.forEachBatchMongo(ctx, model, tableName, conditions, fields, batchSize, fn)
+	}
+
+	var keyColumn string
+	if keyColumn, err = c.getSinglePrimaryKeyColumn(model); err != nil {
+		return err
+	}
+	columns := []string{"*"}
+	if len(fields) > 0 {
+		columns = append([]string{keyColumn}, fields...)
+	}
+	db := c.options.db.WithContext(ctx)
+
+	var lastKey interface{}
+	for {
+		tx := db.Table(tableName).Select(columns).Order(keyColumn).Limit(batchSize)
+		if lastKey != nil {
+			tx = tx.Where(keyColumn+" > ?", lastKey)
+		}
+		if len(conditions) > 0 {
+			tx = c.CustomWhere(&gormWhere{tx: tx}, conditions, c.Engine()).(*gorm.DB)
+		}
+		var rows []map[string]interface{}
+		if err = tx.Find(&rows).Error; err != nil {
+			return err
+		} else if len(rows) == 0 {
+			return nil
+		}
+		for _, row := range rows {
+			for column, value := range row {
+				row[column] = getScannedValue(value)
+			}
+		}
+
+		if err = fn(tableName, keyColumn, rows); err != nil {
+			return err
+		} else if len(rows) < batchSize {
+			return nil
+		}
+		lastKey = rows[len(rows)-1][keyColumn]
+	}
+}
+
+// forEachBatchMongo will read the documents matching the conditions in batches (keyset pagination on the _id)
+func (c *Client) forEachBatchMongo(ctx context.Context, model interface{}, collectionName string,
+	conditions map[string]interface{}, fields []string, batchSize int,
+	fn func(tableName, keyColumn string, rows []map[string]interface{}) error,
+) error {
+	collection := c.getMongoReadCollection(ctx, collectionName)
+	filter := getMongoQueryConditions(model, conditions, c.options.fields.customMongoConditionProcessor)
+
+	findOptions := options.Find().SetLimit(int64(batchSize)).SetSort(bson.D{{Key: mongoIDField, Value: 1}})
+	if len(fields) > 0 {
+		projection := bson.M{mongoIDField: 1}
+		for _, field := range fields {
+			projection[field] = 1
+		}
+		findOptions.SetProjection(projection)
+	}
+
+	var lastKey interface{}
+	for {
+		batchFilter := bson.M(filter)
+		if lastKey != nil {
+			batchFilter = bson.M{conditionAnd: []interface{}{
+				filter, bson.M{mongoIDField: bson.M{conditionGreaterThan: lastKey}},
+			}}
+		}
+		cursor, err := collection.Find(ctx, batchFilter, findOptions)
+		if err != nil {
+			return err
+		}
+		var documents []map[string]interface{}
+		if err = cursor.All(ctx, &documents); err != nil {
+			return err
+		} else if len(documents) == 0 {
+			return nil
+		}
+
+		if err = fn(collectionName, mongoIDField, documents); err != nil {
+			return err
+		} else if len(documents) < batchSize {
+			return nil
+		}
+		lastKey = documents[len(documents)-1][mongoIDField]
+	}
+}
@@ -0,0 +1,67 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_ScanForInvalidRows will test the method ScanForInvalidRows()
+func TestClient_ScanForInvalidRows(t *testing.T) {
+	t.Run("report the invalid rows", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "scan-1", Name: "alice", Amount: 10},
+			&testSQLModel{ID: "scan-2", Name: "", Amount: -1},
+			&testSQLModel{ID: "scan-3", Name: "carol", Amount: -5},
+		)
+
+		errNegative := errors.New("amount is negative")
+		errMissingName := errors.New("name is missing")
+		report, err := client.ScanForInvalidRows(ctx, &testSQLModel{},
+			func(row map[string]interface{}) error {
+				if amount, _ := row["amount"].(int64); amount < 0 {
+					return errNegative
+				}
+				return nil
+			},
+			func(row map[string]interface{}) error {
+				if row["name"] == "" {
+					return errMissingName
+				}
+				return nil
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), report.Scanned)
+		assert.Equal(t, int64(2), report.InvalidCount)
+		require.Len(t, report.InvalidRows, 2)
+		assert.Equal(t, "scan-2", report.InvalidRows[0].Key)
+		assert.Equal(t, []error{errNegative, errMissingName}, report.InvalidRows[0].Errors)
+		assert.Equal(t, "scan-3", report.InvalidRows[1].Key)
+		assert.Equal(t, "carol", report.InvalidRows[1].Row["name"])
+	})
+
+	t.Run("no validators", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "scan-1", Name: "alice"})
+
+		report, err := client.ScanForInvalidRows(ctx, &testSQLModel{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), report.Scanned)
+		assert.Empty(t, report.InvalidRows)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		_, err := client.ScanForInvalidRows(context.Background(), &testSQLModel{})
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
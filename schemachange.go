@@ -0,0 +1,98 @@
+package datastore
+
+import (
+	"context"
+	"regexp"
+)
+
+// alterTablePattern matches the ALTER TABLE statements planned by the migrations (table name and the alter clause)
+var alterTablePattern = regexp.MustCompile("(?is)^\\s*ALTER\\s+TABLE\\s+[`\"]?([A-Za-z0-9_]+)[`\"]?\\s+(.+?)\\s*;?\\s*$")
+
+// SchemaChange is a schema change (ALTER TABLE) planned by the migrations
+type SchemaChange struct {
+	Alter     string // Alter clause (IE: "ADD `nickname` longtext" for gh-ost --alter)
+	Statement string // Full statement that would have been executed
+	Table     string // Table name
+}
+
+// SchemaChangeExecutor runs schema changes without locking the table (IE: gh-ost or pt-online-schema-change)
+type SchemaChangeExecutor interface {
+	ExecuteSchemaChange(ctx context.Context, change *SchemaChange) error
+}
+
+// SchemaChangeExecutorFunc is a function that implements the SchemaChangeExecutor interface
+type SchemaChangeExecutorFunc func(ctx context.Context, change *SchemaChange) error
+
+// ExecuteSchemaChange will run the schema change
+func (f SchemaChangeExecutorFunc) ExecuteSchemaChange(ctx context.Context, change *SchemaChange) error {
+	return f(ctx, change)
+}
+
+// schemaChangeConfig is the configuration for delegating the schema changes of large tables
+type schemaChangeConfig struct {
+	executor SchemaChangeExecutor // Runs the delegated schema changes
+	minRows  int64                // Only tables with at least this many rows are delegated (zero is all tables)
+}
+
+// migrateWithSchemaChanges will migrate the models, delegating the ALTER TABLE statements of large tables
+//
+// All other statements (IE: CREATE TABLE, CREATE INDEX) are executed directly (in order)
+func (c *Client) migrateWithSchemaChanges(ctx context.Context, models ...interface{}) error {
+	statements, err := c.planMigration(ctx, models...)
+	if err != nil {
+		return err
+	}
+
+	db := c.options.db.WithContext(ctx)
+	for _, statement := range statements {
+		change := parseSchemaChange(statement)
+		if change != nil {
+			var large bool
+			if large, err = c.isLargeTable(ctx, change.Table); err != nil {
+				return err
+			} else if large {
+				c.DebugLog(ctx, "delegating schema change: "+statement)
+				if err = c.options.schemaChanges.executor.ExecuteSchemaChange(ctx, change); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err = db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isLargeTable will return true if the table has at least the minimum rows for delegating schema changes
+//
+// MySQL uses the (estimated) table_rows from information_schema
+func (c *Client) isLargeTable(ctx context.Context, tableName string) (bool, error) {
+	if c.options.schemaChanges.minRows <= 0 {
+		return true, nil
+	}
+
+	var rows int64
+	db := c.options.db.WithContext(ctx)
+	if c.Engine() == MySQL {
+		if err := db.Raw(
+			"SELECT table_rows FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+			tableName,
+		).Scan(&rows).Error; err != nil {
+			return false, err
+		}
+	} else if err := db.Table(tableName).Count(&rows).Error; err != nil {
+		return false, err
+	}
+	return rows >= c.options.schemaChanges.minRows, nil
+}
+
+// parseSchemaChange will return the schema change for an ALTER TABLE statement (nil for all other statements)
+func parseSchemaChange(statement string) *SchemaChange {
+	matches := alterTablePattern.FindStringSubmatch(statement)
+	if len(matches) != 3 {
+		return nil
+	}
+	return &SchemaChange{Alter: matches[2], Statement: statement, Table: matches[1]}
+}
@@ -0,0 +1,74 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSQLModelNickname is testSQLModel with an additional column (same table)
+type testSQLModelNickname struct {
+	testSQLModel
+	Nickname string `json:"nickname" toml:"nickname" yaml:"nickname" bson:"nickname"`
+}
+
+// TableName will return the (GORM) table name
+func (m *testSQLModelNickname) TableName() string {
+	return testSQLTableName
+}
+
+// Test_parseSchemaChange will test the method parseSchemaChange()
+func Test_parseSchemaChange(t *testing.T) {
+	change := parseSchemaChange("ALTER TABLE `users` ADD `nickname` longtext")
+	require.NotNil(t, change)
+	assert.Equal(t, "users", change.Table)
+	assert.Equal(t, "ADD `nickname` longtext", change.Alter)
+	assert.Equal(t, "ALTER TABLE `users` ADD `nickname` longtext", change.Statement)
+
+	change = parseSchemaChange(`alter table "users" DROP COLUMN "nickname";`)
+	require.NotNil(t, change)
+	assert.Equal(t, "users", change.Table)
+	assert.Equal(t, `DROP COLUMN "nickname"`, change.Alter)
+
+	assert.Nil(t, parseSchemaChange("CREATE TABLE `users` (`id` bigint)"))
+	assert.Nil(t, parseSchemaChange("CREATE INDEX `idx_name` ON `users`(`name`)"))
+}
+
+// TestClient_migrateWithSchemaChanges will test the method migrateWithSchemaChanges()
+func TestClient_migrateWithSchemaChanges(t *testing.T) {
+	t.Run("delegate the alter table", func(t *testing.T) {
+		ctx := context.Background()
+		var changes []*SchemaChange
+		client, deferFunc := testSQLiteClient(ctx, t, WithOnlineSchemaChanges(
+			SchemaChangeExecutorFunc(func(_ context.Context, change *SchemaChange) error {
+				changes = append(changes, change)
+				return nil
+			}), 0,
+		))
+		defer deferFunc()
+
+		require.NoError(t, client.(*Client).migrateWithSchemaChanges(ctx, &testSQLModelNickname{}))
+		require.Len(t, changes, 1)
+		assert.Equal(t, testSQLTableName, changes[0].Table)
+		assert.Contains(t, changes[0].Alter, "`nickname`")
+
+		// The executor is responsible for the change
+		assert.False(t, client.(*Client).options.db.Migrator().HasColumn(&testSQLModelNickname{}, "nickname"))
+	})
+
+	t.Run("small tables are altered directly", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithOnlineSchemaChanges(
+			SchemaChangeExecutorFunc(func(context.Context, *SchemaChange) error {
+				t.Fatal("schema change should not be delegated")
+				return nil
+			}), 1000,
+		))
+		defer deferFunc()
+
+		require.NoError(t, client.(*Client).migrateWithSchemaChanges(ctx, &testSQLModelNickname{}))
+		assert.True(t, client.(*Client).options.db.Migrator().HasColumn(&testSQLModelNickname{}, "nickname"))
+	})
+}
@@ -0,0 +1,405 @@
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Search sync defaults
+const (
+	defaultSearchSyncBatchSize = 500         // Default documents per bulk request
+	defaultSearchSyncInterval  = time.Second // Default max time a document waits for a batch
+)
+
+// ErrSearchIndexFailed is when the search index rejected the bulk request (or some of its documents)
+var ErrSearchIndexFailed = errors.New("search index request failed")
+
+// SearchAction is the action for a document in the search index
+type SearchAction string
+
+// Search index actions
+const (
+	SearchActionDelete SearchAction = "delete" // Remove the document (model deleted)
+	SearchActionIndex  SearchAction = "index"  // Create or replace the document (model created or updated)
+)
+
+// SearchDocument is a model mirrored to the search index
+type SearchDocument struct {
+	Action SearchAction           // Index or delete
+	ID     string                 // Document ID (primary key, composite keys are joined using "_")
+	Index  string                 // Index name
+	Source map[string]interface{} // Document fields (JSON names, empty for deletes)
+}
+
+// SearchIndexer writes the documents to a search index in a single (bulk) request
+type SearchIndexer interface {
+	IndexDocuments(ctx context.Context, documents []*SearchDocument) error
+}
+
+// SearchIndexerFunc is a function that implements the SearchIndexer interface
+type SearchIndexerFunc func(ctx context.Context, documents []*SearchDocument) error
+
+// IndexDocuments will write the documents
+func (f SearchIndexerFunc) IndexDocuments(ctx context.Context, documents []*SearchDocument) error {
+	return f(ctx, documents)
+}
+
+// SearchIndex is a model mirrored to a search index (see: WithSearchSync)
+type SearchIndex struct {
+	Fields []string    // Fields to mirror (JSON names, empty is all the fields)
+	Index  string      // Index name (empty is the model table name)
+	Model  interface{} // Model to mirror
+}
+
+// SearchSyncConfig is the configuration for mirroring models to a search index (see: WithSearchSync)
+type SearchSyncConfig struct {
+	BatchSize     int            // Documents per bulk request (default: 500)
+	FlushInterval time.Duration  // Max time a document waits for a batch (default: 1s)
+	Indexes       []*SearchIndex // Models to mirror
+	Retry         RetryPolicy    // Backoff for the failed bulk requests (zero fields use the defaults)
+}
+
+// searchSync mirrors the committed model events to the search indexer
+type searchSync struct {
+	batchSize     int                     // Documents per bulk request
+	done          chan struct{}           // Closed when the background flush stopped
+	flush         chan struct{}           // Signals a full batch
+	flushInterval time.Duration           // Max time a document waits for a batch
+	flushMu       sync.Mutex              // One flush at a time (keeps the order of the documents)
+	indexer       SearchIndexer           // Writes the batches
+	indexes       map[string]*SearchIndex // Mirrored models (by model name)
+	mu            sync.Mutex              // Lock for the pending documents
+	pending       []*SearchDocument       // Documents waiting for a batch
+	retry         RetryPolicy             // Backoff for the failed bulk requests
+	stop          chan struct{}           // Closed to stop the background flush (after the flush in progress)
+}
+
+// newSearchSync will create the search sync from the configuration (nil if there is nothing to mirror)
+func newSearchSync(indexer SearchIndexer, config *SearchSyncConfig) *searchSync {
+	if indexer == nil || config == nil {
+		return nil
+	}
+	indexes := make(map[string]*SearchIndex, len(config.Indexes))
+	for _, index := range config.Indexes {
+		if index == nil {
+			continue
+		}
+		modelName := GetModelName(index.Model)
+		if modelName == nil {
+			continue
+		}
+		mirrored := *index
+		if len(mirrored.Index) == 0 {
+			if tableName := GetModelTableName(index.Model); tableName != nil {
+				mirrored.Index = *tableName
+			}
+		}
+		indexes[*modelName] = &mirrored
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	syncer := &searchSync{
+		batchSize:     config.BatchSize,
+		flushInterval: config.FlushInterval,
+		indexer:       indexer,
+		indexes:       indexes,
+		retry:         getRetryPolicy(config.Retry),
+	}
+	if syncer.batchSize <= 0 {
+		syncer.batchSize = defaultSearchSyncBatchSize
+	}
+	if syncer.flushInterval <= 0 {
+		syncer.flushInterval = defaultSearchSyncInterval
+	}
+	return syncer
+}
+
+// startSearchSync will subscribe to the events of the mirrored models and start the background flush
+func (c *Client) startSearchSync() error {
+	syncer := c.options.searchSync
+	if syncer == nil {
+		return nil
+	}
+	for _, index := range syncer.indexes {
+		for _, event := range []ModelEvent{EventCreated, EventDeleted, EventUpdated} {
+			if err := c.SubscribeModelEvents(index.Model, event, c.syncSearchDocument); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The flush in progress is not canceled by a stop (its batch was already taken from the pending documents)
+	ctx := context.Background()
+	syncer.done = make(chan struct{})
+	syncer.flush = make(chan struct{}, 1)
+	syncer.stop = make(chan struct{})
+	go func() {
+		defer close(syncer.done)
+		ticker := time.NewTicker(syncer.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-syncer.stop:
+				return
+			case <-ticker.C:
+			case <-syncer.flush:
+			}
+			if err := c.FlushSearchSync(ctx); err != nil {
+				c.warnLog(ctx, "failed to sync the search index: "+err.Error())
+			}
+		}
+	}()
+	return nil
+}
+
+// stopSearchSync will stop the background flush (waiting for the flush in progress) and flush the pending documents
+func (c *Client) stopSearchSync(ctx context.Context) error {
+	syncer := c.options.searchSync
+	if syncer == nil || syncer.stop == nil {
+		return nil
+	}
+	close(syncer.stop)
+	<-syncer.done
+	syncer.stop = nil
+	return c.FlushSearchSync(ctx)
+}
+
+// FlushSearchSync will write the pending documents to the search index (see: WithSearchSync)
+//
+// Documents are written in batches (in order), a batch that still fails after the retries is dropped and the
+// error is returned (the background flush logs it), use a full reindex to repair the search index
+// A batch interrupted by the context (canceled or timed out) is kept for the next flush
+func (c *Client) FlushSearchSync(ctx context.Context) error {
+	syncer := c.options.searchSync
+	if syncer == nil {
+		return nil
+	}
+	syncer.flushMu.Lock()
+	defer syncer.flushMu.Unlock()
+
+	for {
+		documents := syncer.take()
+		if len(documents) == 0 {
+			return nil
+		}
+		if err := syncer.write(ctx, documents); err != nil {
+			if ctx.Err() != nil {
+				syncer.requeue(documents)
+			}
+			return err
+		}
+	}
+}
+
+// syncSearchDocument will queue the search document of a committed model event
+func (c *Client) syncSearchDocument(ctx context.Context, event ModelEvent, model interface{}) {
+	syncer := c.options.searchSync
+	document, err := c.getSearchDocument(event, model)
+	if err != nil {
+		c.DebugLog(ctx, "failed to build the search document: "+err.Error())
+		return
+	} else if document == nil {
+		return
+	}
+
+	syncer.mu.Lock()
+	syncer.pending = append(syncer.pending, document)
+	full := len(syncer.pending) >= syncer.batchSize
+	syncer.mu.Unlock()
+
+	if full {
+		select {
+		case syncer.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// getSearchDocument will return the search document of the model event (nil if the model is not mirrored)
+func (c *Client) getSearchDocument(event ModelEvent, model interface{}) (*SearchDocument, error) {
+	modelName := GetModelName(model)
+	if modelName == nil {
+		return nil, nil
+	}
+	index := c.options.searchSync.indexes[*modelName]
+	if index == nil {
+		return nil, nil
+	}
+
+	primaryKey, err := c.getModelPrimaryKey(model)
+	if err != nil {
+		return nil, err
+	}
+	document := &SearchDocument{
+		Action: SearchActionIndex,
+		ID:     getSearchDocumentID(primaryKey),
+		Index:  index.Index,
+	}
+	if event == EventDeleted {
+		document.Action = SearchActionDelete
+		return document, nil
+	}
+
+	// Use the JSON fields of the model
+	var raw []byte
+	if raw, err = json.Marshal(model); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(raw, &document.Source); err != nil {
+		return nil, err
+	}
+	if len(index.Fields) > 0 {
+		for field := range document.Source {
+			if !StringInSlice(field, index.Fields) {
+				delete(document.Source, field)
+			}
+		}
+	}
+	return document, nil
+}
+
+// getSearchDocumentID will return the document ID of the primary key (composite keys are joined in column order)
+func getSearchDocumentID(primaryKey map[string]interface{}) string {
+	columns := make([]string, 0, len(primaryKey))
+	for column := range primaryKey {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	values := make([]string, 0, len(columns))
+	for _, column := range columns {
+		values = append(values, fmt.Sprint(primaryKey[column]))
+	}
+	return strings.Join(values, "_")
+}
+
+// take will remove and return the next batch of pending documents
+func (s *searchSync) take() []*SearchDocument {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	size := len(s.pending)
+	if size > s.batchSize {
+		size = s.batchSize
+	}
+	documents := s.pending[:size:size]
+	s.pending = s.pending[size:]
+	return documents
+}
+
+// requeue will put the batch back in front of the pending documents (keeping the order)
+func (s *searchSync) requeue(documents []*SearchDocument) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(documents, s.pending...)
+}
+
+// write will write the batch, retrying with an exponential backoff
+func (s *searchSync) write(ctx context.Context, documents []*SearchDocument) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = s.indexer.IndexDocuments(ctx, documents); err == nil || attempt >= s.retry.MaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(getRetryDelay(s.retry, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// bulkSearchIndexer writes the documents using the _bulk API (Elasticsearch and OpenSearch)
+type bulkSearchIndexer struct {
+	client   *http.Client // HTTP client (IE: with the authentication transport)
+	endpoint string       // Base URL of the cluster (IE: http://localhost:9200)
+}
+
+// NewBulkSearchIndexer will return an indexer using the _bulk API of an Elasticsearch or OpenSearch cluster
+//
+// The HTTP client can add the authentication (nil uses http.DefaultClient)
+func NewBulkSearchIndexer(endpoint string, client *http.Client) SearchIndexer {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &bulkSearchIndexer{client: client, endpoint: strings.TrimSuffix(endpoint, "/")}
+}
+
+// IndexDocuments will write the documents in a single _bulk request (NDJSON)
+func (b *bulkSearchIndexer) IndexDocuments(ctx context.Context, documents []*SearchDocument) error {
+	body, err := getBulkSearchBody(documents)
+	if err != nil {
+		return err
+	}
+
+	var request *http.Request
+	if request, err = http.NewRequestWithContext(
+		ctx, http.MethodPost, b.endpoint+"/_bulk", bytes.NewReader(body),
+	); err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-ndjson")
+
+	var response *http.Response
+	if response, err = b.client.Do(request); err != nil {
+		return err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: status %d", ErrSearchIndexFailed, response.StatusCode)
+	}
+
+	// Partial failures are reported per item
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Error interface{} `json:"error"`
+		} `json:"items"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return err
+	} else if !result.Errors {
+		return nil
+	}
+	for _, item := range result.Items {
+		for action, status := range item {
+			if status.Error != nil {
+				return fmt.Errorf("%w: %s: %v", ErrSearchIndexFailed, action, status.Error)
+			}
+		}
+	}
+	return ErrSearchIndexFailed
+}
+
+// getBulkSearchBody will return the _bulk request body (an action line, followed by the source for index)
+func getBulkSearchBody(documents []*SearchDocument) ([]byte, error) {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, document := range documents {
+		if err := encoder.Encode(map[string]interface{}{
+			string(document.Action): map[string]string{"_id": document.ID, "_index": document.Index},
+		}); err != nil {
+			return nil, err
+		}
+		if document.Action == SearchActionIndex {
+			if err := encoder.Encode(document.Source); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return body.Bytes(), nil
+}
@@ -0,0 +1,242 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSearchIndexer records the documents written to the search index
+type testSearchIndexer struct {
+	batches [][]*SearchDocument
+	fail    int // Number of requests to fail
+	mu      sync.Mutex
+}
+
+// IndexDocuments will record the documents (or fail)
+func (i *testSearchIndexer) IndexDocuments(_ context.Context, documents []*SearchDocument) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.fail > 0 {
+		i.fail--
+		return ErrSearchIndexFailed
+	}
+	i.batches = append(i.batches, documents)
+	return nil
+}
+
+// getBatches will return the recorded batches
+func (i *testSearchIndexer) getBatches() [][]*SearchDocument {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([][]*SearchDocument(nil), i.batches...)
+}
+
+// TestWithSearchSync will test the method WithSearchSync()
+func TestWithSearchSync(t *testing.T) {
+	t.Run("nothing to mirror", func(t *testing.T) {
+		options := &clientOptions{}
+		WithSearchSync(nil, &SearchSyncConfig{Indexes: []*SearchIndex{{Model: &testSQLModel{}}}})(options)
+		assert.Nil(t, options.searchSync)
+		WithSearchSync(&testSearchIndexer{}, &SearchSyncConfig{Indexes: []*SearchIndex{nil, {}}})(options)
+		assert.Nil(t, options.searchSync)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		options := &clientOptions{}
+		WithSearchSync(&testSearchIndexer{}, &SearchSyncConfig{Indexes: []*SearchIndex{{Model: &testSQLModel{}}}})(options)
+		require.NotNil(t, options.searchSync)
+		assert.Equal(t, defaultSearchSyncBatchSize, options.searchSync.batchSize)
+		assert.Equal(t, defaultSearchSyncInterval, options.searchSync.flushInterval)
+		assert.Equal(t, defaultRetryMaxAttempts, options.searchSync.retry.MaxAttempts)
+		assert.Equal(t, "test_sql_models", options.searchSync.indexes["test_sql_model"].Index)
+	})
+}
+
+// TestClient_FlushSearchSync will test the mirroring of the model events (see: WithSearchSync)
+func TestClient_FlushSearchSync(t *testing.T) {
+	t.Run("[sqlite] index and delete", func(t *testing.T) {
+		ctx := context.Background()
+		indexer := &testSearchIndexer{}
+		client, deferFunc := testSQLiteClient(ctx, t, WithSearchSync(indexer, &SearchSyncConfig{
+			FlushInterval: time.Hour,
+			Indexes:       []*SearchIndex{{Fields: []string{"id", "name"}, Index: "models", Model: &testSQLModel{}}},
+		}))
+		defer deferFunc()
+
+		model := &testSQLModel{ID: "search-1", Name: "a", Amount: 10}
+		require.NoError(t, client.SaveModelAuto(ctx, model, true))
+		model.Name = "b"
+		require.NoError(t, client.SaveModelAuto(ctx, model, false))
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.DeleteModel(ctx, model, tx, false)
+		}))
+
+		// Rolled back writes are not mirrored
+		require.Error(t, client.NewTx(ctx, func(tx *Transaction) error {
+			require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "search-2"}, tx, true, false))
+			return errors.New("failed")
+		}))
+
+		assert.Empty(t, indexer.getBatches())
+		require.NoError(t, client.FlushSearchSync(ctx))
+		assert.Equal(t, [][]*SearchDocument{{
+			{Action: SearchActionIndex, ID: "search-1", Index: "models", Source: map[string]interface{}{"id": "search-1", "name": "a"}},
+			{Action: SearchActionIndex, ID: "search-1", Index: "models", Source: map[string]interface{}{"id": "search-1", "name": "b"}},
+			{Action: SearchActionDelete, ID: "search-1", Index: "models"},
+		}}, indexer.getBatches())
+	})
+
+	t.Run("[sqlite] full batch is flushed in the background", func(t *testing.T) {
+		ctx := context.Background()
+		indexer := &testSearchIndexer{}
+		client, deferFunc := testSQLiteClient(ctx, t, WithSearchSync(indexer, &SearchSyncConfig{
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+			Indexes:       []*SearchIndex{{Model: &testSQLModel{}}},
+		}))
+		defer deferFunc()
+
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "search-1"}, &testSQLModel{ID: "search-2"})
+		assert.Eventually(t, func() bool {
+			return len(indexer.getBatches()) == 1
+		}, time.Second, 5*time.Millisecond)
+		assert.Len(t, indexer.getBatches()[0], 2)
+	})
+
+	t.Run("[sqlite] failed batches are retried", func(t *testing.T) {
+		ctx := context.Background()
+		indexer := &testSearchIndexer{fail: 1}
+		client, deferFunc := testSQLiteClient(ctx, t, WithSearchSync(indexer, &SearchSyncConfig{
+			FlushInterval: time.Hour,
+			Indexes:       []*SearchIndex{{Model: &testSQLModel{}}},
+			Retry:         RetryPolicy{BaseDelay: time.Millisecond, MaxAttempts: 2},
+		}))
+		defer deferFunc()
+
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "search-1"})
+		require.NoError(t, client.FlushSearchSync(ctx))
+		assert.Len(t, indexer.getBatches(), 1)
+
+		// Dropped after the retries
+		indexer.fail = 2
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "search-2"})
+		require.ErrorIs(t, client.FlushSearchSync(ctx), ErrSearchIndexFailed)
+		require.NoError(t, client.FlushSearchSync(ctx))
+		assert.Len(t, indexer.getBatches(), 1)
+	})
+
+	t.Run("[sqlite] pending documents are flushed on close", func(t *testing.T) {
+		ctx := context.Background()
+		indexer := &testSearchIndexer{}
+		client, _ := testSQLiteClient(ctx, t, WithSearchSync(indexer, &SearchSyncConfig{
+			FlushInterval: time.Hour,
+			Indexes:       []*SearchIndex{{Model: &testSQLModel{}}},
+		}))
+
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "search-1"})
+		require.NoError(t, client.Close(ctx))
+		assert.Len(t, indexer.getBatches(), 1)
+	})
+
+	t.Run("[sqlite] batch in progress is not lost on close", func(t *testing.T) {
+		ctx := context.Background()
+		indexer := &testSearchIndexer{fail: 1}
+		client, _ := testSQLiteClient(ctx, t, WithSearchSync(indexer, &SearchSyncConfig{
+			BatchSize:     1,
+			FlushInterval: time.Hour,
+			Indexes:       []*SearchIndex{{Model: &testSQLModel{}}},
+			Retry:         RetryPolicy{BaseDelay: 20 * time.Millisecond, MaxAttempts: 2},
+		}))
+
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "search-1"})
+		require.NoError(t, client.Close(ctx))
+		require.Len(t, indexer.getBatches(), 1)
+		assert.Equal(t, "search-1", indexer.getBatches()[0][0].ID)
+	})
+
+	t.Run("[sqlite] interrupted batches are kept", func(t *testing.T) {
+		ctx := context.Background()
+		indexer := &testSearchIndexer{fail: 1}
+		client, deferFunc := testSQLiteClient(ctx, t, WithSearchSync(indexer, &SearchSyncConfig{
+			FlushInterval: time.Hour,
+			Indexes:       []*SearchIndex{{Model: &testSQLModel{}}},
+			Retry:         RetryPolicy{BaseDelay: time.Hour, MaxAttempts: 2},
+		}))
+		defer deferFunc()
+
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "search-1"})
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		require.ErrorIs(t, client.FlushSearchSync(canceled), ErrSearchIndexFailed)
+		assert.Empty(t, indexer.getBatches())
+
+		require.NoError(t, client.FlushSearchSync(ctx))
+		assert.Len(t, indexer.getBatches(), 1)
+	})
+
+	t.Run("no search sync", func(t *testing.T) {
+		client := &Client{options: &clientOptions{}}
+		require.NoError(t, client.FlushSearchSync(context.Background()))
+	})
+}
+
+// TestNewBulkSearchIndexer will test the method NewBulkSearchIndexer()
+func TestNewBulkSearchIndexer(t *testing.T) {
+	documents := []*SearchDocument{
+		{Action: SearchActionIndex, ID: "1", Index: "models", Source: map[string]interface{}{"name": "a"}},
+		{Action: SearchActionDelete, ID: "2", Index: "models"},
+	}
+
+	t.Run("bulk request", func(t *testing.T) {
+		var body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/_bulk", r.URL.Path)
+			assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+			raw, _ := io.ReadAll(r.Body)
+			body = string(raw)
+			_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+		}))
+		defer server.Close()
+
+		require.NoError(t, NewBulkSearchIndexer(server.URL+"/", nil).IndexDocuments(context.Background(), documents))
+		assert.Equal(t, `{"index":{"_id":"1","_index":"models"}}`+"\n"+`{"name":"a"}`+"\n"+
+			`{"delete":{"_id":"2","_index":"models"}}`+"\n", body)
+	})
+
+	t.Run("item errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":201}},` +
+				`{"delete":{"status":400,"error":{"type":"mapper_parsing_exception"}}}]}`))
+		}))
+		defer server.Close()
+
+		err := NewBulkSearchIndexer(server.URL, nil).IndexDocuments(context.Background(), documents)
+		require.ErrorIs(t, err, ErrSearchIndexFailed)
+		assert.Contains(t, err.Error(), "mapper_parsing_exception")
+	})
+
+	t.Run("request error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		require.ErrorIs(t, NewBulkSearchIndexer(server.URL, nil).IndexDocuments(context.Background(), documents),
+			ErrSearchIndexFailed)
+	})
+}
+
+// Test_getSearchDocumentID will test the method getSearchDocumentID()
+func Test_getSearchDocumentID(t *testing.T) {
+	assert.Equal(t, "1", getSearchDocumentID(map[string]interface{}{"id": 1}))
+	assert.Equal(t, "a_2", getSearchDocumentID(map[string]interface{}{"tenant_id": 2, "id": "a"}))
+}
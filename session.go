@@ -0,0 +1,48 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// causalSessionKey is the context key for flagging a causally consistent session
+type causalSessionKey struct{}
+
+// NewCausalSession will run fn in a causally consistent (read-your-writes) session
+//
+// All datastore calls inside fn must use the context given to fn
+// MongoDB: uses a causal consistency session, so reads observe the session's writes (even on secondaries)
+// MySQL and PostgreSQL: all reads use the write (source) database instead of a replica
+func (c *Client) NewCausalSession(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx = context.WithValue(ctx, causalSessionKey{}, true)
+
+	// For MongoDB
+	if c.Engine() == MongoDB {
+		return c.options.mongoDB.Client().UseSessionWithOptions(
+			ctx, options.Session().SetCausalConsistency(true),
+			func(sessionContext mongo.SessionContext) error {
+				return fn(sessionContext)
+			},
+		)
+	}
+
+	return fn(ctx)
+}
+
+// isCausalSession will return true if the context is inside a causally consistent session
+func isCausalSession(ctx context.Context) bool {
+	causal, _ := ctx.Value(causalSessionKey{}).(bool)
+	return causal
+}
+
+// useWriteDBInSession will use the "write" database for reads inside a causal session (Only MySQL and Postgres)
+func (c *Client) useWriteDBInSession(ctx context.Context, tx *gorm.DB) *gorm.DB {
+	if isCausalSession(ctx) && (c.Engine() == MySQL || c.Engine() == PostgreSQL) {
+		return tx.Clauses(dbresolver.Write)
+	}
+	return tx
+}
@@ -0,0 +1,48 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_NewCausalSession will test the method NewCausalSession()
+func TestClient_NewCausalSession(t *testing.T) {
+	t.Run("reads observe the session writes", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		assert.False(t, isCausalSession(ctx))
+		err := client.NewCausalSession(ctx, func(ctx context.Context) error {
+			assert.True(t, isCausalSession(ctx))
+			testSaveModels(ctx, t, client, &testSQLModel{ID: "causal-1", Name: "alice"})
+
+			model := &testSQLModel{}
+			if err := client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "causal-1"},
+				defaultDatabaseMaxTimeout, false); err != nil {
+				return err
+			}
+			assert.Equal(t, "alice", model.Name)
+
+			count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+			assert.Equal(t, int64(1), count)
+			return err
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("error is returned", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		testErr := errors.New("session error")
+		require.ErrorIs(t, client.NewCausalSession(ctx, func(context.Context) error {
+			return testErr
+		}), testErr)
+	})
+}
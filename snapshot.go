@@ -0,0 +1,51 @@
+package datastore
+
+import (
+	"context"
+	"database/sql"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+)
+
+// readTxKey is the context key for the transaction used by the reads (SQL, see: NewSnapshotTx and ReadContext)
+type readTxKey struct{}
+
+// NewSnapshotTx will run fn in a read-only snapshot, so all the reads observe a consistent point-in-time view
+//
+// All datastore reads inside fn must use the context given to fn (IE: several GetModels calls for a report)
+// MongoDB: uses a snapshot session (snapshot read concern)
+// MySQL and PostgreSQL: uses a read-only REPEATABLE READ transaction, SQLite: a read-only (serializable) transaction
+func (c *Client) NewSnapshotTx(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	// For MongoDB
+	if c.Engine() == MongoDB {
+		return c.options.mongoDB.Client().UseSessionWithOptions(
+			ctx, options.Session().SetSnapshot(true),
+			func(sessionContext mongo.SessionContext) error {
+				return fn(sessionContext)
+			},
+		)
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	isolation := sql.LevelRepeatableRead
+	if c.Engine() == SQLite {
+		isolation = sql.LevelSerializable
+	}
+	tx := c.options.db.WithContext(ctx).Begin(&sql.TxOptions{Isolation: isolation, ReadOnly: true})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer tx.Rollback() // Read-only, nothing to commit
+
+	return fn(context.WithValue(ctx, readTxKey{}, tx))
+}
+
+// getReadTx will return the transaction for the reads from the context (nil if not set)
+func getReadTx(ctx context.Context) *gorm.DB {
+	tx, _ := ctx.Value(readTxKey{}).(*gorm.DB)
+	return tx
+}
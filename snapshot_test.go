@@ -0,0 +1,58 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_NewSnapshotTx will test the method NewSnapshotTx()
+func TestClient_NewSnapshotTx(t *testing.T) {
+	t.Run("reads use the snapshot", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "snapshot-1", Name: "a"},
+			&testSQLModel{ID: "snapshot-2", Name: "b"},
+		)
+
+		require.NoError(t, client.NewSnapshotTx(ctx, func(ctx context.Context) error {
+			require.NotNil(t, getReadTx(ctx))
+
+			var models []*testSQLModel
+			require.NoError(t, client.GetModels(ctx, &models, nil, nil, nil, defaultDatabaseMaxTimeout))
+			assert.Len(t, models, 2)
+
+			count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), count)
+
+			model := &testSQLModel{}
+			return client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "snapshot-1"},
+				defaultDatabaseMaxTimeout, false)
+		}))
+		assert.Nil(t, getReadTx(ctx))
+	})
+
+	t.Run("error from fn", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		err := client.NewSnapshotTx(ctx, func(context.Context) error {
+			return ErrNoResults
+		})
+		require.ErrorIs(t, err, ErrNoResults)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		err := client.NewSnapshotTx(context.Background(), func(context.Context) error {
+			return nil
+		})
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
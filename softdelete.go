@@ -0,0 +1,216 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mrz1836/go-datastore/nrgorm"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// softDeleteField is the column (field) that marks a model as soft-deleted
+const softDeleteField = "deleted_at"
+
+// includeDeletedKey is the context key for including soft-deleted records
+type includeDeletedKey struct{}
+
+// IncludeDeleted will flag the context to include soft-deleted records (see: WithSoftDeletes)
+//
+// By default, GetModel, GetModels, GetModelsByCursor, GetModelCount and GetModelsAggregate exclude them
+func IncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// isIncludeDeleted will return true if the context is flagged to include soft-deleted records
+func isIncludeDeleted(ctx context.Context) bool {
+	flagged, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return flagged
+}
+
+// DeleteModel will delete the model (primary key based)
+//
+// Models registered using WithSoftDeletes() are marked as deleted (deleted_at) instead of being removed
+// A tombstone is saved in the transaction for the deleted records (see: WithTombstones)
+func (c *Client) DeleteModel(
+	ctx context.Context,
+	model interface{},
+	tx *Transaction,
+	commitTx bool,
+) error {
+	softDelete := c.isSoftDeleteModel(model)
+
+	// MongoDB (does not support transactions at this time)
+	if c.Engine() == MongoDB {
+		sessionContext := ctx //nolint:contextcheck // we need to overwrite the ctx for transaction support
+		if tx.mongoTx != nil {
+			// set the context to the session context -> mongo transaction
+			sessionContext = *tx.mongoTx
+		}
+		start := time.Now()
+		rows, err := c.deleteWithMongo(sessionContext, model, softDelete)
+		if err == nil && rows > 0 && c.options.tombstones {
+			err = c.saveTombstoneWithMongo(sessionContext, model, softDelete)
+		}
+		if err != nil {
+			return newMongoQueryError("delete", model, nil, start, err)
+		}
+		tx.addRowsAffected(rows)
+		c.queueModelEvent(ctx, tx, EventDeleted, model)
+		return nil
+	} else if c.Engine() == Memory {
+		rows, err := c.deleteWithMemory(ctx, model, softDelete)
+		if err != nil {
+			return err
+		}
+		tx.addRowsAffected(rows)
+		c.queueModelEvent(ctx, tx, EventDeleted, model)
+		return nil
+	} else if !IsSQLEngine(c.Engine()) {
+		return ErrUnsupportedEngine
+	}
+
+	// Set the NewRelic txn
+	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+	// Capture any panics
+	defer func() {
+		if r := recover(); r != nil {
+			c.DebugLog(context.Background(), fmt.Sprintf("panic recovered: %v", r))
+			_ = tx.Rollback()
+		}
+	}()
+	if err := tx.sqlTx.Error; err != nil {
+		return err
+	}
+	end, err := tx.watchdog.begin()
+	if err != nil {
+		return err
+	}
+	defer end()
+
+	// Get the primary key of the model
+	primaryKey, err := c.getModelPrimaryKey(model)
+	if err != nil {
+		return err
+	}
+
+	// Mark as deleted vs delete
+	var result *gorm.DB
+	if softDelete {
+		result = c.setQueryComment(ctx, tx.sqlTx).Model(model).Where(primaryKey).Update(softDeleteField, time.Now().UTC())
+	} else {
+		result = c.setQueryComment(ctx, tx.sqlTx).Omit(clause.Associations).Where(primaryKey).Delete(model)
+	}
+	if err = result.Error; err != nil {
+		_ = tx.rollbackFailed()
+		return err
+	}
+	tx.addRowsAffected(result.RowsAffected)
+
+	// Save the tombstone (same transaction)
+	if result.RowsAffected > 0 && c.options.tombstones {
+		var tombstone *Tombstone
+		if tombstone, err = newTombstone(model, primaryKey, softDelete); err == nil {
+			err = c.setQueryComment(ctx, tx.sqlTx).Create(tombstone).Error
+		}
+		if err != nil {
+			_ = tx.rollbackFailed()
+			return err
+		}
+	}
+	c.queueModelEvent(ctx, tx, EventDeleted, model)
+
+	// Commit & check for errors
+	if commitTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteWithMongo will delete (or mark as deleted) a given struct in MongoDB (returns the number of documents
+// deleted or marked)
+func (c *Client) deleteWithMongo(
+	ctx context.Context,
+	model interface{},
+	softDelete bool,
+) (rows int64, err error) {
+	collectionName := GetModelTableName(model)
+	if collectionName == nil {
+		return 0, ErrUnknownCollection
+	}
+
+	// Set the collection
+	collection := c.getMongoWriteCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	)
+
+	var primaryKey map[string]interface{}
+	if primaryKey, err = c.getModelPrimaryKey(model); err != nil {
+		return 0, err
+	}
+
+	c.DebugLog(ctx, fmt.Sprintf(logLine, "delete", *collectionName, model))
+
+	if softDelete {
+		var result *mongo.UpdateResult
+		if result, err = collection.UpdateOne(
+			ctx, primaryKey, bson.M{conditionSet: bson.M{softDeleteField: time.Now().UTC()}},
+			options.Update().SetComment(c.getMongoComment(ctx)),
+		); err == nil {
+			rows = result.ModifiedCount
+		}
+	} else {
+		var result *mongo.DeleteResult
+		if result, err = collection.DeleteOne(
+			ctx, primaryKey, options.Delete().SetComment(c.getMongoComment(ctx)),
+		); err == nil {
+			rows = result.DeletedCount
+		}
+	}
+
+	if err != nil {
+		c.DebugLog(ctx, fmt.Sprintf(logErrorLine, "error", *collectionName, err, model))
+	}
+
+	return
+}
+
+// isSoftDeleteModel will return true if the model (or slice of models) was registered using WithSoftDeletes()
+func (c *Client) isSoftDeleteModel(model interface{}) bool {
+	if len(c.options.softDeletes) == 0 {
+		return false
+	}
+	modelName := GetModelName(model)
+	return modelName != nil && c.options.softDeletes[*modelName]
+}
+
+// getSoftDeleteConditions will return the conditions excluding the soft-deleted records
+//
+// The conditions are not changed if the model does not use soft deletes, the context includes the deleted
+// records (see: IncludeDeleted) or the conditions already filter on deleted_at
+func (c *Client) getSoftDeleteConditions(ctx context.Context, model interface{},
+	conditions map[string]interface{},
+) map[string]interface{} {
+	if !c.isSoftDeleteModel(model) || isIncludeDeleted(ctx) {
+		return conditions
+	} else if _, ok := conditions[softDeleteField]; ok {
+		return conditions
+	}
+
+	// Copy the conditions (the caller's map is not modified)
+	softConditions := make(map[string]interface{}, len(conditions)+1)
+	for key, value := range conditions {
+		softConditions[key] = value
+	}
+	softConditions[softDeleteField] = nil
+	return softConditions
+}
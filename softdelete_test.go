@@ -0,0 +1,140 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSoftDeleteModel is a model used for testing soft deletes
+type testSoftDeleteModel struct {
+	DeletedAt *time.Time `json:"deleted_at" toml:"deleted_at" yaml:"deleted_at" bson:"deleted_at"`
+	ID        string     `json:"id" toml:"id" yaml:"id" gorm:"<-:create;type:char(64);primaryKey" bson:"_id"`
+	Name      string     `json:"name" toml:"name" yaml:"name" gorm:"type:varchar(64)" bson:"name"`
+}
+
+// GetModelName will return a model name
+func (m *testSoftDeleteModel) GetModelName() string {
+	return "test_soft_delete_model"
+}
+
+// GetModelTableName will return a table name
+func (m *testSoftDeleteModel) GetModelTableName() string {
+	return "test_soft_delete_models"
+}
+
+// testDeleteModel will delete the model in a new transaction
+func testDeleteModel(ctx context.Context, t *testing.T, client ClientInterface, model interface{}) {
+	require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+		return client.DeleteModel(ctx, model, tx, true)
+	}))
+}
+
+// TestIncludeDeleted will test the method IncludeDeleted()
+func TestIncludeDeleted(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, isIncludeDeleted(ctx))
+	assert.True(t, isIncludeDeleted(IncludeDeleted(ctx)))
+}
+
+// TestClient_DeleteModel will test the method DeleteModel()
+func TestClient_DeleteModel(t *testing.T) {
+	t.Run("hard delete", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "delete-1", Name: "alice"})
+
+		testDeleteModel(ctx, t, client, &testSQLModel{ID: "delete-1"})
+
+		count, err := client.GetModelCount(IncludeDeleted(ctx), &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t,
+			WithAutoMigrate(&testSoftDeleteModel{}),
+			WithSoftDeletes(&testSoftDeleteModel{}),
+		)
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testSoftDeleteModel{ID: "soft-1", Name: "alice"},
+			&testSoftDeleteModel{ID: "soft-2", Name: "bob"},
+		)
+
+		testDeleteModel(ctx, t, client, &testSoftDeleteModel{ID: "soft-1"})
+
+		var models []*testSoftDeleteModel
+		require.NoError(t, client.GetModels(ctx, &models, nil, nil, nil, defaultDatabaseMaxTimeout))
+		require.Len(t, models, 1)
+		assert.Equal(t, "soft-2", models[0].ID)
+
+		err := client.GetModel(ctx, &testSoftDeleteModel{}, map[string]interface{}{
+			sqlIDField: "soft-1",
+		}, defaultDatabaseMaxTimeout, false)
+		require.ErrorIs(t, err, ErrNoResults)
+
+		count, err := client.GetModelCount(ctx, &testSoftDeleteModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		// Include the soft-deleted records
+		deleted := &testSoftDeleteModel{}
+		require.NoError(t, client.GetModel(IncludeDeleted(ctx), deleted, map[string]interface{}{
+			sqlIDField: "soft-1",
+		}, defaultDatabaseMaxTimeout, false))
+		assert.NotNil(t, deleted.DeletedAt)
+
+		count, err = client.GetModelCount(IncludeDeleted(ctx), &testSoftDeleteModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("missing primary key", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		err := client.NewTx(ctx, func(tx *Transaction) error {
+			return client.DeleteModel(ctx, &testSQLModel{}, tx, true)
+		})
+		require.ErrorIs(t, err, ErrMissingPrimaryKey)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		err := client.DeleteModel(context.Background(), &testSQLModel{ID: "delete-1"}, nil, true)
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
+
+// TestClient_getSoftDeleteConditions will test the method getSoftDeleteConditions()
+func TestClient_getSoftDeleteConditions(t *testing.T) {
+	client := &Client{options: &clientOptions{}}
+	WithSoftDeletes(&testSoftDeleteModel{})(client.options)
+	ctx := context.Background()
+
+	t.Run("excludes the deleted records", func(t *testing.T) {
+		conditions := map[string]interface{}{"name": "alice"}
+		assert.Equal(t, map[string]interface{}{"name": "alice", softDeleteField: nil},
+			client.getSoftDeleteConditions(ctx, &testSoftDeleteModel{}, conditions))
+		assert.Equal(t, map[string]interface{}{"name": "alice"}, conditions)
+
+		var models []*testSoftDeleteModel
+		assert.Equal(t, map[string]interface{}{softDeleteField: nil},
+			client.getSoftDeleteConditions(ctx, &models, nil))
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		assert.Nil(t, client.getSoftDeleteConditions(ctx, &testSQLModel{}, nil))
+		assert.Nil(t, client.getSoftDeleteConditions(IncludeDeleted(ctx), &testSoftDeleteModel{}, nil))
+
+		conditions := map[string]interface{}{softDeleteField: map[string]interface{}{conditionExists: true}}
+		assert.Equal(t, conditions, client.getSoftDeleteConditions(ctx, &testSoftDeleteModel{}, conditions))
+	})
+}
@@ -1,9 +1,16 @@
 package datastore
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mrz1836/go-datastore/nrgorm"
@@ -24,28 +31,58 @@ import (
 */
 
 // SQL related default settings
-// todo: make this configurable for the end-user?
+//
+// The MySQL defaults are used when the server version is unknown (see: SQLConfig.ServerVersion)
 const (
 	defaultDatetimePrecision            = true            // disable datetime precision, which not supported before MySQL 5.6
-	defaultDontSupportRenameColumn      = true            // `change` when rename column, rename column not supported before MySQL 8, MariaDB
-	defaultDontSupportRenameIndex       = true            // drop & create when rename index, rename index not supported before MySQL 5.7, MariaDB
+	defaultDontSupportRenameColumn      = true            // `change` when rename column, rename column not supported before MySQL 8, MariaDB 10.5.2
+	defaultDontSupportRenameIndex       = true            // drop & create when rename index, rename index not supported before MySQL 5.7, MariaDB 10.5.2
 	defaultFieldStringSize         uint = 256             // default size for string fields
 	dsnDefault                          = "file::memory:" // DSN for connection (file or memory, default is memory)
 	defaultPreparedStatements           = false           // Flag for prepared statements for SQL
 )
 
+// ErrInvalidSessionVariable is when a session variable name is not a valid variable name
+var ErrInvalidSessionVariable = errors.New("invalid session variable name")
+
+// mySQLVersionPattern is the version number of a MySQL or MariaDB server version (IE: 10.11.6-MariaDB-log)
+var mySQLVersionPattern = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)`)
+
+// sessionVariablePattern is the allowed session variable names (IE: sql_mode, search_path)
+var sessionVariablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
 // openSQLDatabase will open a new SQL database
-func openSQLDatabase(optionalLogger glogger.Interface, configs ...*SQLConfig) (db *gorm.DB, err error) {
+//
+// The replicas are served by the replica pool (see: UpdateReplicas)
+func openSQLDatabase(optionalLogger glogger.Interface, configs ...*SQLConfig) (db *gorm.DB,
+	replicas *replicaPool, err error,
+) {
+
+	// Check the session variables
+	for _, config := range configs {
+		for name := range config.SessionVariables {
+			if !sessionVariablePattern.MatchString(name) {
+				return nil, nil, ErrInvalidSessionVariable
+			}
+		}
+	}
+
+	// Register the TLS configurations (MySQL)
+	for _, config := range configs {
+		if err = registerMySQLTLSConfig(config); err != nil {
+			return nil, nil, err
+		}
+	}
 
 	// Try to find a source
 	var sourceConfig *SQLConfig
 	if sourceConfig, configs = getSourceDatabase(configs); sourceConfig == nil {
-		return nil, ErrNoSourceFound
+		return nil, nil, ErrNoSourceFound
 	}
 
 	// Not a valid driver?
 	if sourceConfig.Driver != MySQL.String() && sourceConfig.Driver != PostgreSQL.String() {
-		return nil, ErrUnsupportedDriver
+		return nil, nil, ErrUnsupportedDriver
 	}
 
 	// Switch on driver
@@ -56,44 +93,36 @@ func openSQLDatabase(optionalLogger glogger.Interface, configs ...*SQLConfig) (d
 	if db, err = gorm.Open(
 		sourceDialector, getGormConfig(
 			sourceConfig.TablePrefix, defaultPreparedStatements,
-			sourceConfig.Debug, optionalLogger,
+			sourceConfig.Debug, sourceConfig.EnableForeignKeys, optionalLogger,
 		),
 	); err != nil {
 		return
 	}
 
-	// Start the resolver (default is source and replica are the same)
+	// Start the replica pool (default is the source without replicas)
+	replicas = newReplicaPool(nil)
+	if replicas.source, err = db.DB(); err != nil {
+		return
+	}
 	resolverConfig := dbresolver.Config{
 		Policy:   dbresolver.RandomPolicy{},
-		Replicas: []gorm.Dialector{sourceDialector},
+		Replicas: []gorm.Dialector{getReplicaPoolDialector(Engine(sourceConfig.Driver), replicas)},
 		Sources:  []gorm.Dialector{sourceDialector},
 	}
 
-	// Do we have additional
-	if len(configs) > 0 {
-
-		// Clear the existing replica
-		resolverConfig.Replicas = nil
-
-		// Loop configs
-		for _, config := range configs {
-
-			// Get the dialector
-			dialector := getDialector(config)
-
-			// Set based on replica
-			if config.Replica {
-				resolverConfig.Replicas = append(resolverConfig.Replicas, dialector)
-			} else {
-				resolverConfig.Sources = append(resolverConfig.Sources, dialector)
-			}
-		}
-
-		// No replica?
-		if len(resolverConfig.Replicas) == 0 {
-			resolverConfig.Replicas = append(resolverConfig.Replicas, sourceDialector)
+	// Loop the additional configs (based on replica)
+	var replicaConfigs []*SQLConfig
+	for _, config := range configs {
+		if config.Replica {
+			replicaConfigs = append(replicaConfigs, config)
+		} else {
+			resolverConfig.Sources = append(resolverConfig.Sources, getDialector(config))
 		}
 	}
+	if replicas.replicas, err = openReplicaDatabases(optionalLogger, replicaConfigs); err != nil {
+		_ = closeSQLDatabase(db)
+		return
+	}
 
 	// Create the register and set the configuration
 	//
@@ -116,12 +145,21 @@ func openSQLDatabase(optionalLogger glogger.Interface, configs ...*SQLConfig) (d
 
 	// Use the register
 	if err = db.Use(register); err != nil {
+		_ = replicas.close()
 		return
 	}
 
+	// Enable the features of the detected MySQL / MariaDB version (after the resolver opened the sources)
+	if dialector, ok := db.Dialector.(*mysql.Dialector); ok && len(dialector.ServerVersion) > 0 {
+		setMySQLVersionFeatures(dialector.Config, dialector.ServerVersion)
+	}
+
 	// Register the callbacks with NewRelic
 	nrgorm.AddGormCallbacks(db)
 
+	// Wrap query errors with the query details
+	addQueryErrorCallbacks(db, Engine(sourceConfig.Driver))
+
 	// Return the connection
 	return
 }
@@ -134,7 +172,7 @@ func openSQLiteDatabase(optionalLogger glogger.Interface, config *SQLiteConfig)
 	if config.ExistingConnection != nil {
 		dialector = sqlite.Dialector{Conn: config.ExistingConnection}
 	} else {
-		dialector = sqlite.Open(getDNS(config.DatabasePath, config.Shared))
+		dialector = sqlite.Dialector{DriverName: getSQLiteRegexpDriver(), DSN: getDNS(config.DatabasePath, config.Shared)}
 	}
 
 	/*
@@ -149,7 +187,7 @@ func openSQLiteDatabase(optionalLogger glogger.Interface, config *SQLiteConfig)
 	if db, err = gorm.Open(
 		dialector, getGormConfig(
 			config.TablePrefix, defaultPreparedStatements,
-			config.Debug, optionalLogger,
+			config.Debug, config.EnableForeignKeys, optionalLogger,
 		),
 	); err != nil {
 		return
@@ -167,6 +205,9 @@ func openSQLiteDatabase(optionalLogger glogger.Interface, config *SQLiteConfig)
 	// Register the callbacks with NewRelic
 	nrgorm.AddGormCallbacks(db)
 
+	// Wrap query errors with the query details
+	addQueryErrorCallbacks(db, SQLite)
+
 	// Return the connection
 	return
 }
@@ -199,20 +240,41 @@ func getDialector(config *SQLConfig) gorm.Dialector {
 // mySQLDialector will return a gorm.Dialector
 func mySQLDialector(config *SQLConfig) gorm.Dialector {
 
+	// Connect using TCP or the unix socket
+	address := "tcp(" + config.Host + ":" + config.Port + ")"
+	if len(config.Socket) > 0 {
+		address = "unix(" + config.Socket + ")"
+	}
+
 	// Create the default MySQL configuration
 	cfg := mysql.Config{
 		// DriverName: "nrmysql",
 		// todo: make all params customizable via config
 		DSN: config.User + ":" + config.Password +
-			"@tcp(" + config.Host + ":" + config.Port + ")/" +
-			config.Name + "?charset=utf8&parseTime=True&loc=Local", // data source name (connection string)
+			"@" + address + "/" +
+			config.Name + "?charset=utf8&parseTime=True&loc=Local" + // data source name (connection string)
+			getMySQLSessionVariables(config.SessionVariables) + getMySQLTLSParam(config),
 		DefaultStringSize:         defaultFieldStringSize,           // default size for string fields
-		DisableDatetimePrecision:  defaultDatetimePrecision,         // disable datetime precision, which not supported before MySQL 5.6
-		DontSupportRenameIndex:    defaultDontSupportRenameIndex,    // drop & create when rename index, rename index not supported before MySQL 5.7, MariaDB
-		DontSupportRenameColumn:   defaultDontSupportRenameColumn,   // `change` when rename column, rename column not supported before MySQL 8, MariaDB
 		SkipInitializeWithVersion: config.SkipInitializeWithVersion, // autoconfigure based on currently MySQL version
 	}
 
+	// Use the features of the given server version, the lowest common denominator if the version is not
+	// detected (otherwise the detected version is used, see: openSQLDatabase)
+	if len(config.ServerVersion) > 0 {
+		cfg.ServerVersion = config.ServerVersion
+		cfg.SkipInitializeWithVersion = true
+		setMySQLVersionFeatures(&cfg, config.ServerVersion)
+	} else if config.SkipInitializeWithVersion {
+		cfg.DisableDatetimePrecision = defaultDatetimePrecision // disable datetime precision, which not supported before MySQL 5.6
+		cfg.DontSupportRenameIndex = defaultDontSupportRenameIndex
+		cfg.DontSupportRenameColumn = defaultDontSupportRenameColumn
+	}
+
+	// Use the raw DSN (IE: tls=custom or multiStatements=true)
+	if len(config.DSN) > 0 {
+		cfg.DSN = config.DSN
+	}
+
 	// Do we have an existing connection
 	if config.ExistingConnection != nil {
 		cfg.DSN = ""
@@ -222,27 +284,114 @@ func mySQLDialector(config *SQLConfig) gorm.Dialector {
 	return mysql.New(cfg)
 }
 
+// getMySQLTLSParam will return the DSN param of the registered TLS configuration (see: registerMySQLTLSConfig)
+func getMySQLTLSParam(config *SQLConfig) string {
+	if !hasMySQLTLS(config) {
+		return ""
+	}
+	return "&tls=" + getMySQLTLSConfigName(config)
+}
+
+// setMySQLVersionFeatures will set the datetime precision and the rename support of the server version
+//
+// MariaDB supports renaming columns and indexes since 10.5.2, MySQL supports the datetime precision since 5.6,
+// renaming indexes since 5.7 and renaming columns since 8.0 (unknown versions use the default settings)
+func setMySQLVersionFeatures(cfg *mysql.Config, serverVersion string) {
+	version := serverVersion
+	mariaDB := strings.Contains(serverVersion, "MariaDB")
+	if mariaDB {
+		version = strings.TrimPrefix(version, "5.5.5-") // Replication compatibility prefix
+	}
+	matches := mySQLVersionPattern.FindStringSubmatch(version)
+	if len(matches) == 0 {
+		cfg.DisableDatetimePrecision = defaultDatetimePrecision
+		cfg.DontSupportRenameIndex = defaultDontSupportRenameIndex
+		cfg.DontSupportRenameColumn = defaultDontSupportRenameColumn
+		return
+	}
+	number := make([]int, 0, 3)
+	for _, match := range matches[1:] {
+		part, _ := strconv.Atoi(match)
+		number = append(number, part)
+	}
+	atLeast := func(minimum ...int) bool {
+		return slices.Compare(number, minimum) >= 0
+	}
+
+	if mariaDB {
+		cfg.DisableDatetimePrecision = false // Since MariaDB 5.3
+		cfg.DontSupportRenameIndex = !atLeast(10, 5, 2)
+		cfg.DontSupportRenameColumn = !atLeast(10, 5, 2)
+		return
+	}
+	cfg.DisableDatetimePrecision = !atLeast(5, 6, 0)
+	cfg.DontSupportRenameIndex = !atLeast(5, 7, 0)
+	cfg.DontSupportRenameColumn = !atLeast(8, 0, 0)
+}
+
 // postgreSQLDialector will return a gorm.Dialector
 func postgreSQLDialector(config *SQLConfig) gorm.Dialector {
 
 	// Create the default PostgreSQL configuration
 	cfg := postgres.Config{
 		// DriverName: "nrpgx",
-		PreferSimpleProtocol: true, // turn to TRUE to disable implicit prepared statement usage
+		PreferSimpleProtocol: !config.ExtendedProtocol, // turn to TRUE to disable implicit prepared statement usage
 		WithoutReturning:     false,
 	}
 
 	// Do we have an existing connection
 	if config.ExistingConnection != nil {
 		cfg.Conn = config.ExistingConnection
+	} else if len(config.DSN) > 0 {
+		cfg.DSN = config.DSN // Raw DSN (IE: sslrootcert=/path/to/ca.pem)
 	} else {
+		host := config.Host
+		if len(config.Socket) > 0 {
+			host = config.Socket // The directory of the unix socket (.s.PGSQL.<port>)
+		}
 		cfg.DSN = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
-			config.Host, config.User, config.Password, config.Name, config.Port, config.SslMode, config.TimeZone)
+			host, config.User, config.Password, config.Name, config.Port, config.SslMode, config.TimeZone) +
+			getPostgreSQLSessionVariables(config.SessionVariables)
 	}
 
 	return postgres.New(cfg)
 }
 
+// getMySQLSessionVariables will return the DSN params for the session variables (SET <name> = <value> on connect)
+//
+// Values are quoted as strings (numbers are not quoted)
+func getMySQLSessionVariables(variables map[string]string) string {
+	var params string
+	for _, name := range getSortedKeys(variables) {
+		value := variables[name]
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			value = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+		params += "&" + name + "=" + url.QueryEscape(value)
+	}
+	return params
+}
+
+// getPostgreSQLSessionVariables will return the DSN runtime params for the session variables (sent on connect)
+func getPostgreSQLSessionVariables(variables map[string]string) string {
+	var params string
+	for _, name := range getSortedKeys(variables) {
+		value := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(variables[name])
+		params += " " + name + "='" + value + "'"
+	}
+	return params
+}
+
+// getSortedKeys will return the sorted keys of the map
+func getSortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // getSourceDatabase will loop all configs and get the first source
 //
 // todo: this will grab ANY source (create a better way to seed the source database)
@@ -307,7 +456,8 @@ func getGormSessionConfig(preparedStatement, debug bool, optionalLogger glogger.
 // getGormConfig will return a valid gorm.Config
 //
 // See: https://gorm.io/docs/gorm_config.html
-func getGormConfig(tablePrefix string, preparedStatement, debug bool, optionalLogger glogger.Interface) *gorm.Config {
+func getGormConfig(tablePrefix string, preparedStatement, debug, foreignKeys bool,
+	optionalLogger glogger.Interface) *gorm.Config {
 
 	// Set the prefix
 	if len(tablePrefix) > 0 {
@@ -322,7 +472,7 @@ func getGormConfig(tablePrefix string, preparedStatement, debug bool, optionalLo
 		CreateBatchSize:                          0,
 		Dialector:                                nil,
 		DisableAutomaticPing:                     false,
-		DisableForeignKeyConstraintWhenMigrating: true,
+		DisableForeignKeyConstraintWhenMigrating: !foreignKeys,
 		DisableNestedTransaction:                 false,
 		DryRun:                                   false, // toggle for extreme debugging
 		FullSaveAssociations:                     false,
@@ -6,6 +6,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm/schema"
 )
 
 // TestClient_getSourceDatabase will test the method getSourceDatabase()
@@ -124,3 +127,155 @@ func TestClient_getSourceDatabase(t *testing.T) {
 		assert.Equal(t, "host-read.domain.com", configs[0].Host)
 	})
 }
+
+// Test_getGormConfig will test the method getGormConfig()
+func Test_getGormConfig(t *testing.T) {
+	t.Run("foreign keys are disabled by default", func(t *testing.T) {
+		config := getGormConfig("", false, false, false, nil)
+		assert.True(t, config.DisableForeignKeyConstraintWhenMigrating)
+		assert.NotNil(t, config.Logger)
+	})
+
+	t.Run("enable foreign keys", func(t *testing.T) {
+		config := getGormConfig("test", false, false, true, nil)
+		assert.False(t, config.DisableForeignKeyConstraintWhenMigrating)
+		assert.Equal(t, "test_", config.NamingStrategy.(schema.NamingStrategy).TablePrefix)
+	})
+}
+
+// Test_getSessionVariables will test the methods getMySQLSessionVariables() and getPostgreSQLSessionVariables()
+func Test_getSessionVariables(t *testing.T) {
+	t.Run("no variables", func(t *testing.T) {
+		assert.Empty(t, getMySQLSessionVariables(nil))
+		assert.Empty(t, getPostgreSQLSessionVariables(nil))
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		params := getMySQLSessionVariables(map[string]string{
+			"sql_mode":     "STRICT_ALL_TABLES,NO_ZERO_DATE",
+			"wait_timeout": "600",
+		})
+		assert.Equal(t, "&sql_mode=%27STRICT_ALL_TABLES%2CNO_ZERO_DATE%27&wait_timeout=600", params)
+	})
+
+	t.Run("postgresql", func(t *testing.T) {
+		params := getPostgreSQLSessionVariables(map[string]string{
+			"application_name": "it's",
+			"search_path":      "app,public",
+		})
+		assert.Equal(t, ` application_name='it\'s' search_path='app,public'`, params)
+	})
+
+	t.Run("dialector dsn", func(t *testing.T) {
+		dialector := getDialector(&SQLConfig{
+			Driver:           PostgreSQL.String(),
+			Host:             "localhost",
+			SessionVariables: map[string]string{"search_path": "app"},
+		})
+		assert.Contains(t, dialector.(*postgres.Dialector).Config.DSN, " search_path='app'")
+
+		dialector = getDialector(&SQLConfig{
+			Driver:           MySQL.String(),
+			Host:             "localhost",
+			SessionVariables: map[string]string{"sql_mode": "ANSI"},
+		})
+		assert.Contains(t, dialector.(*mysql.Dialector).Config.DSN, "&sql_mode=%27ANSI%27")
+	})
+
+	t.Run("raw dsn", func(t *testing.T) {
+		dsn := "user:password@tcp(localhost:3306)/db?tls=custom&multiStatements=true"
+		dialector := getDialector(&SQLConfig{
+			Driver:           MySQL.String(),
+			DSN:              dsn,
+			Host:             "other",
+			SessionVariables: map[string]string{"sql_mode": "ANSI"},
+		})
+		assert.Equal(t, dsn, dialector.(*mysql.Dialector).Config.DSN)
+
+		dsn = "host=localhost user=user dbname=db sslmode=verify-full sslrootcert=/etc/ssl/ca.pem"
+		dialector = getDialector(&SQLConfig{Driver: PostgreSQL.String(), DSN: dsn, Host: "other"})
+		assert.Equal(t, dsn, dialector.(*postgres.Dialector).Config.DSN)
+	})
+
+	t.Run("unix socket", func(t *testing.T) {
+		dialector := getDialector(&SQLConfig{
+			Driver: MySQL.String(),
+			Host:   "localhost",
+			Name:   "db",
+			Socket: "/var/run/mysqld/mysqld.sock",
+			User:   "user",
+		})
+		assert.Contains(t, dialector.(*mysql.Dialector).Config.DSN, "user:@unix(/var/run/mysqld/mysqld.sock)/db?")
+
+		dialector = getDialector(&SQLConfig{
+			Driver: PostgreSQL.String(),
+			Host:   "localhost",
+			Socket: "/cloudsql/project:region:instance",
+		})
+		assert.Contains(t, dialector.(*postgres.Dialector).Config.DSN, "host=/cloudsql/project:region:instance ")
+	})
+
+	t.Run("extended protocol", func(t *testing.T) {
+		dialector := getDialector(&SQLConfig{Driver: PostgreSQL.String(), Host: "localhost"})
+		assert.True(t, dialector.(*postgres.Dialector).Config.PreferSimpleProtocol)
+
+		dialector = getDialector(&SQLConfig{Driver: PostgreSQL.String(), Host: "localhost", ExtendedProtocol: true})
+		assert.False(t, dialector.(*postgres.Dialector).Config.PreferSimpleProtocol)
+	})
+}
+
+// Test_setMySQLVersionFeatures will test the method setMySQLVersionFeatures()
+func Test_setMySQLVersionFeatures(t *testing.T) {
+	tests := []struct {
+		serverVersion            string
+		disableDatetimePrecision bool
+		dontSupportRenameIndex   bool
+		dontSupportRenameColumn  bool
+	}{
+		{"8.0.36", false, false, false},
+		{"8.4.0-log", false, false, false},
+		{"5.7.44", false, false, true},
+		{"5.6.51", false, true, true},
+		{"5.5.62", true, true, true},
+		{"10.11.6-MariaDB-1:10.11.6+maria~ubu2204", false, false, false},
+		{"5.5.5-10.6.16-MariaDB", false, false, false},
+		{"10.4.32-MariaDB", false, true, true},
+		{"unknown", true, true, true},
+	}
+	for _, test := range tests {
+		t.Run(test.serverVersion, func(t *testing.T) {
+			cfg := &mysql.Config{}
+			setMySQLVersionFeatures(cfg, test.serverVersion)
+			assert.Equal(t, test.disableDatetimePrecision, cfg.DisableDatetimePrecision)
+			assert.Equal(t, test.dontSupportRenameIndex, cfg.DontSupportRenameIndex)
+			assert.Equal(t, test.dontSupportRenameColumn, cfg.DontSupportRenameColumn)
+		})
+	}
+
+	t.Run("dialector", func(t *testing.T) {
+		dialector := getDialector(&SQLConfig{Driver: MySQL.String(), ServerVersion: "8.0.36"})
+		cfg := dialector.(*mysql.Dialector).Config
+		assert.True(t, cfg.SkipInitializeWithVersion)
+		assert.Equal(t, "8.0.36", cfg.ServerVersion)
+		assert.False(t, cfg.DontSupportRenameColumn)
+
+		dialector = getDialector(&SQLConfig{Driver: MySQL.String(), SkipInitializeWithVersion: true})
+		cfg = dialector.(*mysql.Dialector).Config
+		assert.True(t, cfg.DisableDatetimePrecision)
+		assert.True(t, cfg.DontSupportRenameColumn)
+
+		dialector = getDialector(&SQLConfig{Driver: MySQL.String()})
+		cfg = dialector.(*mysql.Dialector).Config
+		assert.False(t, cfg.SkipInitializeWithVersion)
+		assert.False(t, cfg.DontSupportRenameColumn) // Set when connecting (detected version)
+	})
+}
+
+// Test_openSQLDatabase_sessionVariables will test validating the session variable names
+func Test_openSQLDatabase_sessionVariables(t *testing.T) {
+	_, _, err := openSQLDatabase(nil, &SQLConfig{
+		Driver:           MySQL.String(),
+		SessionVariables: map[string]string{"sql_mode=''; DROP TABLE users": "ANSI"},
+	})
+	require.ErrorIs(t, err, ErrInvalidSessionVariable)
+}
@@ -0,0 +1,39 @@
+package datastore
+
+import (
+	"database/sql"
+)
+
+// Driver names (database/sql) of the pools managed by the client (IE: for sqlx.NewDb)
+const (
+	sqlDriverMySQL      = "mysql"   // go-sql-driver/mysql
+	sqlDriverPostgreSQL = "pgx"     // jackc/pgx (stdlib)
+	sqlDriverSQLite     = "sqlite3" // mattn/go-sqlite3
+)
+
+// SQLDB will return the underlying connection pool (source/write) and its driver name
+//
+// Lets existing sqlx code share the pool managed by the client:
+//
+//	db, driverName, err := client.SQLDB()
+//	sqlxDB := sqlx.NewDb(db, driverName)
+//
+// Do not close the pool, use client.Close() instead
+func (c *Client) SQLDB() (*sql.DB, string, error) {
+	var driverName string
+	if c.Engine() == MySQL {
+		driverName = sqlDriverMySQL
+	} else if c.Engine() == PostgreSQL {
+		driverName = sqlDriverPostgreSQL
+	} else if c.Engine() == SQLite {
+		driverName = sqlDriverSQLite
+	} else {
+		return nil, "", ErrUnsupportedEngine
+	}
+
+	db, err := c.options.db.DB()
+	if err != nil {
+		return nil, "", err
+	}
+	return db, driverName, nil
+}
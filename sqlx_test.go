@@ -0,0 +1,36 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_SQLDB will test the method SQLDB()
+func TestClient_SQLDB(t *testing.T) {
+	t.Run("sqlite pool is shared", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "sqlx-1", Name: "shared", Amount: 5})
+
+		db, driverName, err := client.SQLDB()
+		require.NoError(t, err)
+		require.NotNil(t, db)
+		assert.Equal(t, sqlDriverSQLite, driverName)
+
+		var name string
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT name FROM "+testSQLTableName+" WHERE id = ?", "sqlx-1").Scan(&name))
+		assert.Equal(t, "shared", name)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: MongoDB}}
+		db, driverName, err := client.SQLDB()
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+		assert.Nil(t, db)
+		assert.Empty(t, driverName)
+	})
+}
@@ -0,0 +1,79 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Startup retry settings (see: WithStartupRetry)
+const (
+	defaultStartupRetryBaseDelay   = 500 * time.Millisecond // Default delay before the second attempt
+	defaultStartupRetryMaxAttempts = 10                     // Default attempts (including the first connection)
+	defaultStartupRetryMaxDelay    = 10 * time.Second       // Default max delay between the attempts
+)
+
+// connectWithRetry will run connect again (see: WithStartupRetry) until the connection succeeds, the attempts
+// are exhausted, the error is not retryable (IE: an invalid configuration) or the context is done
+//
+// Without a startup retry policy the connection is attempted once
+func (c *Client) connectWithRetry(ctx context.Context, connect func(ctx context.Context) error) error {
+	if c.options.startupRetry == nil {
+		return connect(ctx)
+	}
+	policy := getStartupRetryPolicy(*c.options.startupRetry)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+
+		// Each attempt gets a share of the remaining timeout budget (see: WithTimeoutBudget)
+		attemptCtx, cancel, budgetErr := newAttemptCtx(ctx, policy.MaxAttempts-attempt+1)
+		if budgetErr != nil {
+			cancel()
+			if err != nil {
+				return err
+			}
+			return budgetErr
+		}
+		err = connect(attemptCtx)
+		cancel()
+		if err == nil || attempt >= policy.MaxAttempts || !isRetryableConnectError(err) {
+			return err
+		}
+		delay := getRetryDelay(policy, attempt)
+		c.warnLog(ctx, fmt.Sprintf(
+			"failed to connect to the %s datastore (attempt %d of %d), retrying in %s: %s",
+			c.Engine().String(), attempt, policy.MaxAttempts, delay, err.Error(),
+		))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryableConnectError will return false for the errors of an invalid configuration (the same error on
+// every attempt)
+func isRetryableConnectError(err error) bool {
+	return !errors.Is(err, ErrInvalidSessionVariable) && !errors.Is(err, ErrInvalidTLSConfig) &&
+		!errors.Is(err, ErrNoSourceFound) && !errors.Is(err, ErrUnsupportedDriver)
+}
+
+// getStartupRetryPolicy will return the policy with the startup defaults for the zero fields
+func getStartupRetryPolicy(policy RetryPolicy) RetryPolicy {
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = defaultStartupRetryBaseDelay
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaultStartupRetryMaxAttempts
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = defaultStartupRetryMaxDelay
+	}
+	return policy
+}
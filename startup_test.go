@@ -0,0 +1,122 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errTestUnreachable is a connection error of the tests
+var errTestUnreachable = errors.New("connection refused")
+
+// TestWithStartupRetry will test the method WithStartupRetry()
+func TestWithStartupRetry(t *testing.T) {
+	t.Run("check type", func(t *testing.T) {
+		opt := WithStartupRetry(RetryPolicy{})
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying", func(t *testing.T) {
+		options := &clientOptions{}
+		WithStartupRetry(RetryPolicy{MaxAttempts: 5})(options)
+		require.NotNil(t, options.startupRetry)
+		assert.Equal(t, 5, options.startupRetry.MaxAttempts)
+	})
+}
+
+// TestClient_connectWithRetry will test the method connectWithRetry()
+func TestClient_connectWithRetry(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: time.Millisecond, MaxAttempts: 3, MaxDelay: 2 * time.Millisecond}
+
+	t.Run("without a policy", func(t *testing.T) {
+		client := &Client{options: &clientOptions{}}
+		attempts := 0
+		err := client.connectWithRetry(context.Background(), func(context.Context) error {
+			attempts++
+			return errTestUnreachable
+		})
+		require.ErrorIs(t, err, errTestUnreachable)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("connected after a retry", func(t *testing.T) {
+		client := &Client{options: &clientOptions{startupRetry: policy}}
+		attempts := 0
+		err := client.connectWithRetry(context.Background(), func(context.Context) error {
+			if attempts++; attempts < 2 {
+				return errTestUnreachable
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		client := &Client{options: &clientOptions{startupRetry: policy}}
+		attempts := 0
+		err := client.connectWithRetry(context.Background(), func(context.Context) error {
+			attempts++
+			return errTestUnreachable
+		})
+		require.ErrorIs(t, err, errTestUnreachable)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		client := &Client{options: &clientOptions{startupRetry: policy}}
+		attempts := 0
+		err := client.connectWithRetry(context.Background(), func(context.Context) error {
+			attempts++
+			return ErrInvalidSessionVariable
+		})
+		require.ErrorIs(t, err, ErrInvalidSessionVariable)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		client := &Client{options: &clientOptions{startupRetry: &RetryPolicy{BaseDelay: time.Hour, MaxDelay: time.Hour}}}
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+		err := client.connectWithRetry(ctx, func(context.Context) error {
+			attempts++
+			cancel()
+			return errTestUnreachable
+		})
+		require.ErrorIs(t, err, errTestUnreachable)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("timeout budget", func(t *testing.T) {
+		client := &Client{options: &clientOptions{startupRetry: policy}}
+		budget, cancel := WithTimeoutBudget(context.Background(), time.Minute)
+		defer cancel()
+		budgetDeadline, _ := budget.Deadline()
+
+		var deadlines []time.Time
+		err := client.connectWithRetry(budget, func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			deadlines = append(deadlines, deadline)
+			return errTestUnreachable
+		})
+		require.ErrorIs(t, err, errTestUnreachable)
+		require.Len(t, deadlines, 3)
+		assert.True(t, deadlines[0].Before(budgetDeadline))
+	})
+}
+
+// Test_getStartupRetryPolicy will test the method getStartupRetryPolicy()
+func Test_getStartupRetryPolicy(t *testing.T) {
+	policy := getStartupRetryPolicy(RetryPolicy{})
+	assert.Equal(t, defaultStartupRetryBaseDelay, policy.BaseDelay)
+	assert.Equal(t, defaultStartupRetryMaxAttempts, policy.MaxAttempts)
+	assert.Equal(t, defaultStartupRetryMaxDelay, policy.MaxDelay)
+
+	policy = getStartupRetryPolicy(RetryPolicy{MaxAttempts: 2})
+	assert.Equal(t, 2, policy.MaxAttempts)
+}
@@ -0,0 +1,82 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TableStats are the statistics of a table (or collection) for capacity dashboards
+//
+// Row counts are estimates for MySQL and PostgreSQL (from the engine statistics)
+type TableStats struct {
+	BloatRatio float64 `json:"bloat_ratio"` // Estimated share of the table that is dead or free space (0-1)
+	DeadRows   int64   `json:"dead_rows"`   // Dead tuples waiting for a vacuum (PostgreSQL)
+	FreeBytes  int64   `json:"free_bytes"`  // Allocated but unused space (MySQL: data_free, MongoDB: storage - data size)
+	IndexBytes int64   `json:"index_bytes"` // Size of all indexes
+	Rows       int64   `json:"rows"`        // Number of rows (documents)
+	TableBytes int64   `json:"table_bytes"` // Size of the table data
+	TableName  string  `json:"table_name"`  // Full table (collection) name
+}
+
+// TableStats will return the row count, table and index sizes and bloat estimates for the model's table
+//
+// PostgreSQL: pg_stat_user_tables, MySQL: information_schema.tables, MongoDB: collStats
+// SQLite: only the row count
+func (c *Client) TableStats(ctx context.Context, model interface{}) (*TableStats, error) {
+	tableName, err := c.getModelTableName(model)
+	if err != nil {
+		return nil, err
+	}
+	stats := &TableStats{TableName: tableName}
+
+	if c.Engine() == MongoDB {
+		var result struct {
+			Count          int64 `bson:"count"`
+			Size           int64 `bson:"size"`
+			StorageSize    int64 `bson:"storageSize"`
+			TotalIndexSize int64 `bson:"totalIndexSize"`
+		}
+		if err = c.options.mongoDB.RunCommand(
+			ctx, bson.D{{Key: "collStats", Value: tableName}},
+		).Decode(&result); err != nil {
+			return nil, err
+		}
+		stats.Rows = result.Count
+		stats.TableBytes = result.Size
+		stats.IndexBytes = result.TotalIndexSize
+		if result.StorageSize > result.Size {
+			stats.FreeBytes = result.StorageSize - result.Size
+		}
+	} else if c.Engine() == PostgreSQL {
+		if err = c.options.db.WithContext(ctx).Raw(
+			`SELECT n_live_tup AS rows, n_dead_tup AS dead_rows,
+				pg_table_size(relid) AS table_bytes, pg_indexes_size(relid) AS index_bytes
+			FROM pg_stat_user_tables WHERE relname = ?`, tableName,
+		).Scan(stats).Error; err != nil {
+			return nil, err
+		}
+	} else if c.Engine() == MySQL {
+		if err = c.options.db.WithContext(ctx).Raw(
+			"SELECT table_rows AS `rows`, data_length AS table_bytes, index_length AS index_bytes, "+
+				"data_free AS free_bytes FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+			tableName,
+		).Scan(stats).Error; err != nil {
+			return nil, err
+		}
+	} else if c.Engine() == SQLite {
+		if err = c.options.db.WithContext(ctx).Table(tableName).Count(&stats.Rows).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, ErrUnsupportedEngine
+	}
+
+	// Estimate the bloat
+	if stats.DeadRows > 0 {
+		stats.BloatRatio = float64(stats.DeadRows) / float64(stats.Rows+stats.DeadRows)
+	} else if stats.FreeBytes > 0 {
+		stats.BloatRatio = float64(stats.FreeBytes) / float64(stats.TableBytes+stats.FreeBytes)
+	}
+	return stats, nil
+}
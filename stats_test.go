@@ -0,0 +1,34 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_TableStats will test the method TableStats()
+func TestClient_TableStats(t *testing.T) {
+	t.Run("sqlite row count", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "stats-1", Name: "alice"},
+			&testSQLModel{ID: "stats-2", Name: "bob"},
+		)
+
+		stats, err := client.TableStats(ctx, &testSQLModel{})
+		require.NoError(t, err)
+		assert.Equal(t, testSQLTableName, stats.TableName)
+		assert.Equal(t, int64(2), stats.Rows)
+		assert.Zero(t, stats.BloatRatio)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		_, err := client.TableStats(context.Background(), &testSQLModel{})
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
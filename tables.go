@@ -0,0 +1,52 @@
+package datastore
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ListTables will return the (sorted) tables or collections owned by this client
+//
+// Only the names using the client's table prefix are returned (all tables if there is no prefix)
+// SQL: information_schema (sqlite_master for SQLite), MongoDB: ListCollectionNames
+func (c *Client) ListTables(ctx context.Context) ([]string, error) {
+	prefix := ""
+	if c.options.tablePrefix != "" {
+		prefix = c.options.tablePrefix + "_"
+	}
+
+	var tables []string
+	if c.Engine() == MongoDB {
+		filter := bson.M{}
+		if prefix != "" {
+			filter["name"] = bson.M{conditionRegex: "^" + regexp.QuoteMeta(prefix)}
+		}
+		names, err := c.options.mongoDB.ListCollectionNames(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		tables = names
+	} else if IsSQLEngine(c.Engine()) {
+		names, err := c.options.db.WithContext(ctx).Migrator().GetTables()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if c.Engine() == SQLite && strings.HasPrefix(name, "sqlite_") { // internal tables
+				continue
+			}
+			if strings.HasPrefix(name, prefix) {
+				tables = append(tables, name)
+			}
+		}
+	} else {
+		return nil, ErrUnsupportedEngine
+	}
+
+	sort.Strings(tables)
+	return tables, nil
+}
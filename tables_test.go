@@ -0,0 +1,41 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_ListTables will test the method ListTables()
+func TestClient_ListTables(t *testing.T) {
+	t.Run("sqlite tables", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		tables, err := client.ListTables(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, tables, testSQLTableName)
+	})
+
+	t.Run("filtered by table prefix", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		require.NoError(t, client.Execute("CREATE TABLE other_table (id TEXT)").Error)
+		require.NoError(t, client.Execute("CREATE TABLE app_table (id TEXT)").Error)
+
+		client.(*Client).options.tablePrefix = "app"
+		tables, err := client.ListTables(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app_table"}, tables)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		_, err := client.ListTables(context.Background())
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
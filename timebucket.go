@@ -0,0 +1,300 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mrz1836/go-datastore/nrgorm"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+// BucketGranularity is the size of the time buckets (see: TimeSeriesCount)
+type BucketGranularity string
+
+// Bucket granularities
+const (
+	BucketDay    BucketGranularity = "day"
+	BucketHour   BucketGranularity = "hour"
+	BucketMinute BucketGranularity = "minute"
+	BucketMonth  BucketGranularity = "month"
+	BucketWeek   BucketGranularity = "week" // Weeks start on Monday
+)
+
+// Pre-aggregation steps (SQL), buckets are grouped in UTC then merged into the time zone buckets
+const (
+	bucketStepHour    = 3600 // One hour (UTC)
+	bucketStepMinute  = 60   // One minute
+	bucketStepQuarter = 900  // 15 minutes (all the time zone offsets are multiples of 15 minutes)
+)
+
+// ErrInvalidBucketGranularity is when the granularity (or the date field) for the time buckets is invalid
+var ErrInvalidBucketGranularity = errors.New("invalid time bucket granularity or date field")
+
+// Bucket is the count of the records in a time bucket
+type Bucket struct {
+	Count int64     // Number of records in the bucket
+	Start time.Time // Start of the bucket (in the time zone)
+}
+
+// TimeSeriesCount will count the records matching the conditions per time bucket of the date field
+//
+// Buckets are computed in the time zone (nil is UTC) and returned in order, the gaps between the first and
+// last bucket are filled with zero counts. Records without a date are not counted
+// SQL engines pre-aggregate by UTC hour (or 15 minutes for other time zones), MongoDB uses $dateTrunc (5.0+)
+func (c *Client) TimeSeriesCount(
+	ctx context.Context,
+	model interface{},
+	conditions map[string]interface{},
+	dateField string,
+	granularity BucketGranularity,
+	tz *time.Location,
+) ([]Bucket, error) {
+	if !indexNamePattern.MatchString(dateField) || !isValidBucketGranularity(granularity) {
+		return nil, ErrInvalidBucketGranularity
+	}
+	if tz == nil {
+		tz = time.UTC
+	}
+
+	// Exclude the soft-deleted records
+	conditions = c.getSoftDeleteConditions(ctx, model, conditions)
+
+	// Normalize the conditions (see: WithConditionNormalization)
+	var err error
+	if conditions, err = c.normalizeQueryConditions(conditions); err != nil {
+		if errors.Is(err, ErrEmptyResultGuaranteed) {
+			return []Bucket{}, nil
+		}
+		return nil, err
+	}
+
+	var counts map[time.Time]int64
+	if c.Engine() == MongoDB {
+		start := time.Now()
+		counts, err = c.timeBucketsWithMongo(ctx, model, conditions, dateField, granularity, tz)
+		err = newMongoQueryError("aggregate", model, conditions, start, err)
+	} else if IsSQLEngine(c.Engine()) {
+		counts, err = c.timeBuckets(ctx, model, conditions, dateField, granularity, tz)
+	} else {
+		return nil, ErrUnsupportedEngine
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return fillTimeBuckets(counts, granularity, tz), nil
+}
+
+// timeBuckets will return the counts per UTC step (SQL)
+func (c *Client) timeBuckets(ctx context.Context, model interface{}, conditions map[string]interface{},
+	dateField string, granularity BucketGranularity, tz *time.Location,
+) (map[time.Time]int64, error) {
+
+	// Set the NewRelic txn
+	c.options.db = nrgorm.SetTxnToGorm(newrelic.FromContext(ctx), c.options.db)
+
+	// Create a new context, and new db tx
+	ctxDB, cancel, err := createCtx(ctx, c.options.db, defaultDatabaseMaxTimeout, c.IsDebug(), c.options.loggerDB)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	tx := c.useWriteDBInSession(ctx, ctxDB.Model(model))
+	if len(conditions) > 0 {
+		gtx := gormWhere{tx: tx}
+		tx = c.CustomWhere(&gtx, conditions, c.Engine()).(*gorm.DB)
+	}
+
+	bucket := getTimeBucketExpression(c.Engine(), dateField, getTimeBucketStep(granularity, tz))
+	var rows []map[string]interface{}
+	if err := tx.Select(bucket + " AS bucket, COUNT(*) AS count").Where(dateField + " IS NOT NULL").
+		Group(bucket).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[time.Time]int64, len(rows))
+	for _, row := range rows {
+		seconds, err := getBucketNumber(row["bucket"])
+		if err != nil {
+			return nil, err
+		}
+		var count int64
+		if count, err = getBucketNumber(row[accumulationCountField]); err != nil {
+			return nil, err
+		}
+		counts[time.Unix(seconds, 0)] += count
+	}
+	return counts, nil
+}
+
+// getTimeBucketStep will return the pre-aggregation step in seconds (SQL)
+func getTimeBucketStep(granularity BucketGranularity, tz *time.Location) int {
+	if granularity == BucketMinute {
+		return bucketStepMinute
+	} else if tz == time.UTC {
+		return bucketStepHour
+	}
+	return bucketStepQuarter
+}
+
+// getTimeBucketExpression will return the SQL expression of the bucket (unix seconds, rounded down to the step)
+func getTimeBucketExpression(engine Engine, dateField string, step int) string {
+	steps := strconv.Itoa(step)
+	switch engine {
+	case PostgreSQL:
+		return "CAST(FLOOR(EXTRACT(EPOCH FROM " + dateField + ") / " + steps + ") * " + steps + " AS BIGINT)"
+	case MySQL:
+		return "CAST(FLOOR(UNIX_TIMESTAMP(" + dateField + ") / " + steps + ") * " + steps + " AS SIGNED)"
+	default: // SQLite (integer division)
+		return "(CAST(strftime('%s', " + dateField + ") AS INTEGER) / " + steps + " * " + steps + ")"
+	}
+}
+
+// getBucketNumber will return the scanned number (drivers can return []byte or strings)
+func getBucketNumber(value interface{}) (int64, error) {
+	number, err := convertToInt64(getScannedValue(value))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", err, value)
+	}
+	return number, nil
+}
+
+// timeBucketsWithMongo will return the counts per bucket using $dateTrunc (in the time zone)
+func (c *Client) timeBucketsWithMongo(ctx context.Context, model interface{}, conditions map[string]interface{},
+	dateField string, granularity BucketGranularity, tz *time.Location,
+) (map[time.Time]int64, error) {
+	queryConditions := getMongoQueryConditions(model, conditions, c.GetMongoConditionProcessor())
+	collectionName := GetModelTableName(model)
+	if collectionName == nil {
+		return nil, ErrUnknownCollection
+	}
+
+	// Set the collection
+	collection := c.getMongoReadCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, *collectionName),
+	)
+
+	c.DebugLog(ctx, fmt.Sprintf(logLine, accumulationCountField, *collectionName, queryConditions))
+
+	match := bson.M{}
+	for key, value := range queryConditions {
+		match[key] = value
+	}
+	if _, ok := match[dateField]; !ok {
+		match[dateField] = bson.M{conditionNotEquals: nil}
+	} else {
+		match = bson.M{conditionAnd: []bson.M{match, {dateField: bson.M{conditionNotEquals: nil}}}}
+	}
+
+	truncate := bson.D{
+		{Key: "date", Value: "$" + dateField},
+		{Key: "unit", Value: string(granularity)},
+		{Key: "timezone", Value: tz.String()},
+	}
+	if granularity == BucketWeek {
+		truncate = append(truncate, bson.E{Key: "startOfWeek", Value: "monday"})
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: conditionMatch, Value: match}},
+		{{Key: conditionGroup, Value: bson.D{
+			{Key: mongoIDField, Value: bson.D{{Key: "$dateTrunc", Value: truncate}}},
+			{Key: accumulationCountField, Value: bson.D{{Key: conditionSum, Value: 1}}},
+		}}},
+	}
+
+	aggregateCtx, cancel := context.WithTimeout(ctx, defaultDatabaseMaxTimeout)
+	defer cancel()
+	cursor, err := collection.Aggregate(aggregateCtx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		ID    time.Time `bson:"_id"`
+		Count int64     `bson:"count"`
+	}
+	if err = cursor.All(aggregateCtx, &results); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[time.Time]int64, len(results))
+	for _, result := range results {
+		counts[result.ID] += result.Count
+	}
+	return counts, nil
+}
+
+// fillTimeBuckets will merge the counts into the time zone buckets, ordered and with the gaps filled (zero)
+func fillTimeBuckets(counts map[time.Time]int64, granularity BucketGranularity, tz *time.Location) []Bucket {
+	merged := make(map[time.Time]int64, len(counts))
+	for start, count := range counts {
+		merged[truncateTimeBucket(start, granularity, tz)] += count
+	}
+	if len(merged) == 0 {
+		return []Bucket{}
+	}
+
+	starts := make([]time.Time, 0, len(merged))
+	for start := range merged {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	buckets := make([]Bucket, 0, len(starts))
+	last := starts[len(starts)-1]
+	for start := starts[0]; !start.After(last); start = nextTimeBucket(start, granularity, tz) {
+		buckets = append(buckets, Bucket{Count: merged[start], Start: start})
+	}
+	return buckets
+}
+
+// truncateTimeBucket will return the start of the bucket containing the time (in the time zone)
+func truncateTimeBucket(t time.Time, granularity BucketGranularity, tz *time.Location) time.Time {
+	t = t.In(tz)
+	year, month, day := t.Date()
+	switch granularity {
+	case BucketMinute:
+		return t.Truncate(time.Minute)
+	case BucketHour: // Local hours (IE: +05:30), using the offset keeps the two hours when the clocks go back
+		_, offset := t.Zone()
+		shift := time.Duration(offset) * time.Second
+		return t.Add(shift).Truncate(time.Hour).Add(-shift)
+	case BucketWeek:
+		return time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, tz)
+	case BucketMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, tz)
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, tz)
+}
+
+// nextTimeBucket will return the start of the next bucket (in the time zone)
+func nextTimeBucket(start time.Time, granularity BucketGranularity, tz *time.Location) time.Time {
+	year, month, day := start.Date()
+	switch granularity {
+	case BucketMinute:
+		return start.Add(time.Minute)
+	case BucketHour:
+		return start.Add(time.Hour)
+	case BucketWeek:
+		return time.Date(year, month, day+7, 0, 0, 0, 0, tz)
+	case BucketMonth:
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, tz)
+	}
+	return time.Date(year, month, day+1, 0, 0, 0, 0, tz)
+}
+
+// isValidBucketGranularity will return true if the granularity is known
+func isValidBucketGranularity(granularity BucketGranularity) bool {
+	switch granularity {
+	case BucketDay, BucketHour, BucketMinute, BucketMonth, BucketWeek:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_TimeSeriesCount will test the method TimeSeriesCount()
+func TestClient_TimeSeriesCount(t *testing.T) {
+	t.Run("invalid granularity or date field", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: SQLite}}
+		_, err := client.TimeSeriesCount(context.Background(), &testSQLModel{}, nil, "created_at", "year", nil)
+		require.ErrorIs(t, err, ErrInvalidBucketGranularity)
+		_, err = client.TimeSeriesCount(context.Background(), &testSQLModel{}, nil, "created_at;", BucketDay, nil)
+		require.ErrorIs(t, err, ErrInvalidBucketGranularity)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		_, err := client.TimeSeriesCount(context.Background(), &testSQLModel{}, nil, "created_at", BucketDay, nil)
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+
+	t.Run("[sqlite] daily buckets with gaps", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "bucket-1", Name: "a", CreatedAt: day.Add(time.Hour)},
+			&testSQLModel{ID: "bucket-2", Name: "a", CreatedAt: day.Add(23 * time.Hour)},
+			&testSQLModel{ID: "bucket-3", Name: "a", CreatedAt: day.AddDate(0, 0, 3).Add(time.Minute)},
+			&testSQLModel{ID: "bucket-4", Name: "b", CreatedAt: day.AddDate(0, 0, 3)},
+		)
+
+		buckets, err := client.TimeSeriesCount(ctx, &testSQLModel{}, nil, "created_at", BucketDay, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []Bucket{
+			{Count: 2, Start: day},
+			{Count: 0, Start: day.AddDate(0, 0, 1)},
+			{Count: 0, Start: day.AddDate(0, 0, 2)},
+			{Count: 2, Start: day.AddDate(0, 0, 3)},
+		}, buckets)
+
+		// Using the conditions
+		buckets, err = client.TimeSeriesCount(ctx, &testSQLModel{}, map[string]interface{}{"name": "b"},
+			"created_at", BucketDay, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []Bucket{{Count: 1, Start: day.AddDate(0, 0, 3)}}, buckets)
+
+		// No records
+		buckets, err = client.TimeSeriesCount(ctx, &testSQLModel{}, map[string]interface{}{"name": "c"},
+			"created_at", BucketDay, nil)
+		require.NoError(t, err)
+		assert.Empty(t, buckets)
+	})
+
+	t.Run("[sqlite] buckets in a time zone", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		kolkata, err := time.LoadLocation("Asia/Kolkata") // +05:30
+		require.NoError(t, err)
+
+		// 2024-03-01 23:00 UTC is 2024-03-02 04:30 in Kolkata
+		testSaveModels(ctx, t, client,
+			&testSQLModel{ID: "bucket-5", Name: "a", CreatedAt: time.Date(2024, 3, 1, 17, 0, 0, 0, time.UTC)},
+			&testSQLModel{ID: "bucket-6", Name: "a", CreatedAt: time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)},
+		)
+
+		buckets, err := client.TimeSeriesCount(ctx, &testSQLModel{}, nil, "created_at", BucketDay, kolkata)
+		require.NoError(t, err)
+		require.Len(t, buckets, 2)
+		assert.True(t, time.Date(2024, 3, 1, 0, 0, 0, 0, kolkata).Equal(buckets[0].Start))
+		assert.True(t, time.Date(2024, 3, 2, 0, 0, 0, 0, kolkata).Equal(buckets[1].Start))
+		assert.Equal(t, int64(1), buckets[0].Count)
+		assert.Equal(t, int64(1), buckets[1].Count)
+
+		buckets, err = client.TimeSeriesCount(ctx, &testSQLModel{}, nil, "created_at", BucketHour, kolkata)
+		require.NoError(t, err)
+		require.Len(t, buckets, 7)
+		assert.True(t, time.Date(2024, 3, 1, 22, 0, 0, 0, kolkata).Equal(buckets[0].Start))
+		assert.Equal(t, int64(1), buckets[6].Count)
+	})
+}
+
+// TestFillTimeBuckets will test the method fillTimeBuckets()
+func TestFillTimeBuckets(t *testing.T) {
+	t.Run("weeks start on monday", func(t *testing.T) {
+		buckets := fillTimeBuckets(map[time.Time]int64{
+			time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC):  1, // Wednesday
+			time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC): 2, // Sunday
+			time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC): 3, // Monday (two weeks later)
+		}, BucketWeek, time.UTC)
+		assert.Equal(t, []Bucket{
+			{Count: 3, Start: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+			{Count: 0, Start: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+			{Count: 3, Start: time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)},
+		}, buckets)
+	})
+
+	t.Run("months", func(t *testing.T) {
+		buckets := fillTimeBuckets(map[time.Time]int64{
+			time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC): 1,
+			time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC):  1,
+		}, BucketMonth, time.UTC)
+		require.Len(t, buckets, 3)
+		assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), buckets[1].Start)
+		assert.Zero(t, buckets[1].Count)
+	})
+
+	t.Run("hours when the clocks go back", func(t *testing.T) {
+		newYork, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+
+		// 2024-11-03 01:00 happens twice (05:00 and 06:00 UTC)
+		buckets := fillTimeBuckets(map[time.Time]int64{
+			time.Date(2024, 11, 3, 5, 0, 0, 0, time.UTC): 1,
+			time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC): 2,
+		}, BucketHour, newYork)
+		require.Len(t, buckets, 2)
+		assert.Equal(t, int64(1), buckets[0].Count)
+		assert.Equal(t, int64(2), buckets[1].Count)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Empty(t, fillTimeBuckets(nil, BucketDay, time.UTC))
+	})
+}
+
+// TestGetTimeBucketExpression will test the method getTimeBucketExpression()
+func TestGetTimeBucketExpression(t *testing.T) {
+	assert.Equal(t, "CAST(FLOOR(EXTRACT(EPOCH FROM created_at) / 3600) * 3600 AS BIGINT)",
+		getTimeBucketExpression(PostgreSQL, "created_at", getTimeBucketStep(BucketDay, time.UTC)))
+	assert.Equal(t, "CAST(FLOOR(UNIX_TIMESTAMP(created_at) / 900) * 900 AS SIGNED)",
+		getTimeBucketExpression(MySQL, "created_at", getTimeBucketStep(BucketDay, time.Local)))
+	assert.Equal(t, "(CAST(strftime('%s', created_at) AS INTEGER) / 60 * 60)",
+		getTimeBucketExpression(SQLite, "created_at", getTimeBucketStep(BucketMinute, time.UTC)))
+}
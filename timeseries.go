@@ -0,0 +1,128 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongoOptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TimeSeriesGranularity is the expected interval between measurements (MongoDB)
+type TimeSeriesGranularity string
+
+// Time-series granularities
+const (
+	TimeSeriesHours   TimeSeriesGranularity = "hours"
+	TimeSeriesMinutes TimeSeriesGranularity = "minutes"
+	TimeSeriesSeconds TimeSeriesGranularity = "seconds"
+)
+
+// timeSeriesDefaultPartition is the suffix of the default partition (PostgreSQL)
+const timeSeriesDefaultPartition = "_default"
+
+// TimeSeriesConfig is the storage configuration for an event or metric model
+//
+// MongoDB: a time-series collection (timeField, metaField, granularity)
+// PostgreSQL: a table partitioned by range on the time field (with a default partition)
+// MySQL: a table partitioned by range columns on the time field (with a catch-all partition)
+// SQLite: a regular table
+// SQL partitioned tables require the time field to be part of the primary key
+type TimeSeriesConfig struct {
+	Granularity TimeSeriesGranularity // Expected interval between measurements (MongoDB)
+	MetaField   string                // Field that identifies the series (MongoDB)
+	TimeField   string                // Field (column) that contains the date of each measurement
+}
+
+// getTimeSeriesConfig will return the time-series config registered for the model (if found)
+func (c *Client) getTimeSeriesConfig(model interface{}) *TimeSeriesConfig {
+	if len(c.options.timeSeries) == 0 {
+		return nil
+	}
+	modelName := GetModelName(model)
+	if modelName == nil {
+		return nil
+	}
+	return c.options.timeSeries[*modelName]
+}
+
+// createTimeSeriesTables will create the partitioned tables for the time-series models (SQL)
+//
+// Only new tables are created, existing tables are left as-is (and migrated normally)
+// CockroachDB does not support the PostgreSQL partitions (the tables are migrated normally)
+func (c *Client) createTimeSeriesTables(ctx context.Context, models ...interface{}) error {
+	if (c.Engine() != MySQL && c.Engine() != PostgreSQL) || c.isCockroachDB() {
+		return nil
+	}
+
+	db := c.options.db.WithContext(ctx)
+	for _, model := range models {
+		config := c.getTimeSeriesConfig(model)
+		if config == nil || db.Migrator().HasTable(model) {
+			continue
+		}
+
+		tableName, err := c.getModelTableName(model)
+		if err != nil {
+			return err
+		}
+
+		if c.Engine() == PostgreSQL {
+			if err = db.Set(
+				"gorm:table_options", " PARTITION BY RANGE ("+config.TimeField+")",
+			).Migrator().CreateTable(model); err != nil {
+				return err
+			}
+			if err = db.Exec(
+				"CREATE TABLE IF NOT EXISTS " + tableName + timeSeriesDefaultPartition +
+					" PARTITION OF " + tableName + " DEFAULT",
+			).Error; err != nil {
+				return err
+			}
+		} else if err = db.Set(
+			"gorm:table_options", "ENGINE=InnoDB PARTITION BY RANGE COLUMNS("+config.TimeField+
+				") (PARTITION p_max VALUES LESS THAN (MAXVALUE))",
+		).Migrator().CreateTable(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createTimeSeriesCollections will create the time-series collections for the time-series models (MongoDB)
+func createTimeSeriesCollections(ctx context.Context, options *clientOptions, models ...interface{}) error {
+	for _, model := range models {
+		modelName := GetModelName(model)
+		if modelName == nil || options.timeSeries[*modelName] == nil {
+			continue
+		}
+		config := options.timeSeries[*modelName]
+
+		collectionName := GetModelTableName(model)
+		if collectionName == nil {
+			return ErrUnknownCollection
+		}
+		name := setPrefix(options.mongoDBConfig.TablePrefix, *collectionName)
+
+		// Skip existing collections
+		names, err := options.mongoDB.ListCollectionNames(ctx, bson.M{"name": name})
+		if err != nil {
+			return err
+		} else if len(names) > 0 {
+			continue
+		}
+
+		timeSeries := mongoOptions.TimeSeries().SetTimeField(config.TimeField)
+		if config.MetaField != "" {
+			timeSeries.SetMetaField(config.MetaField)
+		}
+		if config.Granularity != "" {
+			timeSeries.SetGranularity(string(config.Granularity))
+		}
+		if err = options.mongoDB.CreateCollection(
+			ctx, name, mongoOptions.CreateCollection().SetTimeSeriesOptions(timeSeries),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_getTimeSeriesConfig will test the method getTimeSeriesConfig()
+func TestClient_getTimeSeriesConfig(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no config", func(t *testing.T) {
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		assert.Nil(t, client.(*Client).getTimeSeriesConfig(&testSQLModel{}))
+	})
+
+	t.Run("registered model", func(t *testing.T) {
+		config := TimeSeriesConfig{TimeField: "created_at"}
+		client, deferFunc := testSQLiteClient(ctx, t, WithTimeSeries(&testSQLModel{}, config))
+		defer deferFunc()
+		assert.Equal(t, &config, client.(*Client).getTimeSeriesConfig(&testSQLModel{}))
+		assert.Nil(t, client.(*Client).getTimeSeriesConfig(nil))
+	})
+}
+
+// TestClient_createTimeSeriesTables will test the method createTimeSeriesTables()
+func TestClient_createTimeSeriesTables(t *testing.T) {
+	t.Run("sqlite uses a regular table", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithTimeSeries(&testSQLModel{}, TimeSeriesConfig{TimeField: "created_at"}))
+		defer deferFunc()
+		require.NoError(t, client.(*Client).createTimeSeriesTables(ctx, &testSQLModel{}))
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "event-1", Name: "event", Amount: 1})
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "event-1"}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "event", model.Name)
+	})
+}
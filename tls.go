@@ -0,0 +1,90 @@
+package datastore
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mySQLTLSConfigPrefix is the prefix of the TLS configurations registered with the MySQL driver (tls=<name>)
+const mySQLTLSConfigPrefix = "datastore-"
+
+// ErrInvalidTLSConfig is when the TLS configuration (CA bundle, client certificate) can not be loaded
+var ErrInvalidTLSConfig = errors.New("invalid tls configuration")
+
+// hasMySQLTLS will return true if the configuration requires a custom MySQL TLS configuration
+//
+// The raw DSN and the existing connections are used as-is (tls=<name> can be set in the raw DSN)
+func hasMySQLTLS(config *SQLConfig) bool {
+	if config.Driver != MySQL.String() || len(config.DSN) > 0 || config.ExistingConnection != nil {
+		return false
+	}
+	return config.TLSConfig != nil || len(config.TLSCAFile) > 0 || len(config.TLSCertFile) > 0 ||
+		len(config.TLSKeyFile) > 0 || len(config.TLSServerName) > 0
+}
+
+// getMySQLTLSConfigName will return the name of the TLS configuration registered for the configuration
+//
+// The name is derived from the TLS settings, reconnecting with the same settings replaces the registration
+func getMySQLTLSConfigName(config *SQLConfig) string {
+	hash := fnv.New64a()
+	_, _ = fmt.Fprintf(hash, "%s\x00%s\x00%s\x00%s\x00%p",
+		config.TLSCAFile, config.TLSCertFile, config.TLSKeyFile, config.TLSServerName, config.TLSConfig)
+	return mySQLTLSConfigPrefix + strconv.FormatUint(hash.Sum64(), 16)
+}
+
+// registerMySQLTLSConfig will register the TLS configuration of the MySQL configuration with the driver
+// (see: mySQLDialector), configurations without TLS settings are skipped
+func registerMySQLTLSConfig(config *SQLConfig) error {
+	if !hasMySQLTLS(config) {
+		return nil
+	}
+	tlsConfig, err := getTLSConfig(config)
+	if err != nil {
+		return err
+	}
+	return mysql.RegisterTLSConfig(getMySQLTLSConfigName(config), tlsConfig)
+}
+
+// getTLSConfig will return the TLS configuration (a copy of SQLConfig.TLSConfig) with the CA bundle,
+// the client certificate and the server name of the configuration
+func getTLSConfig(config *SQLConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.TLSConfig != nil {
+		tlsConfig = config.TLSConfig.Clone()
+	}
+
+	// Verify the server using the CA bundle (instead of the system roots)
+	if len(config.TLSCAFile) > 0 {
+		pem, err := os.ReadFile(config.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTLSConfig, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificates found in %s", ErrInvalidTLSConfig, config.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// Client certificate (both files are required)
+	if len(config.TLSCertFile) > 0 || len(config.TLSKeyFile) > 0 {
+		certificate, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTLSConfig, err)
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, certificate)
+	}
+
+	// Server name (the driver uses the host if NOT set)
+	if len(config.TLSServerName) > 0 {
+		tlsConfig.ServerName = config.TLSServerName
+	}
+	return tlsConfig, nil
+}
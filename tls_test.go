@@ -0,0 +1,121 @@
+package datastore
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+)
+
+// writeTestCertificate will write a self-signed certificate and its key (PEM) in the directory
+func writeTestCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "datastore-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+// Test_getTLSConfig will test the method getTLSConfig()
+func Test_getTLSConfig(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+
+	t.Run("ca bundle, client certificate and server name", func(t *testing.T) {
+		tlsConfig, err := getTLSConfig(&SQLConfig{
+			TLSCAFile:     certFile,
+			TLSCertFile:   certFile,
+			TLSKeyFile:    keyFile,
+			TLSServerName: "db.example.com",
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, tlsConfig.RootCAs)
+		assert.Len(t, tlsConfig.Certificates, 1)
+		assert.Equal(t, "db.example.com", tlsConfig.ServerName)
+		assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	})
+
+	t.Run("custom configuration is copied", func(t *testing.T) {
+		custom := &tls.Config{MinVersion: tls.VersionTLS13, ServerName: "custom"}
+		tlsConfig, err := getTLSConfig(&SQLConfig{TLSConfig: custom, TLSCAFile: certFile})
+		require.NoError(t, err)
+		assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+		assert.Equal(t, "custom", tlsConfig.ServerName)
+		assert.NotNil(t, tlsConfig.RootCAs)
+		assert.Nil(t, custom.RootCAs)
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		_, err := getTLSConfig(&SQLConfig{TLSCAFile: filepath.Join(t.TempDir(), "missing.pem")})
+		require.ErrorIs(t, err, ErrInvalidTLSConfig)
+
+		_, err = getTLSConfig(&SQLConfig{TLSCAFile: keyFile})
+		require.ErrorIs(t, err, ErrInvalidTLSConfig)
+
+		_, err = getTLSConfig(&SQLConfig{TLSCertFile: certFile})
+		require.ErrorIs(t, err, ErrInvalidTLSConfig)
+	})
+}
+
+// Test_registerMySQLTLSConfig will test the method registerMySQLTLSConfig()
+func Test_registerMySQLTLSConfig(t *testing.T) {
+	certFile, _ := writeTestCertificate(t, t.TempDir())
+
+	t.Run("tls param", func(t *testing.T) {
+		config := &SQLConfig{Driver: MySQL.String(), Host: "localhost", Name: "db", TLSCAFile: certFile}
+		require.NoError(t, registerMySQLTLSConfig(config))
+
+		name := getMySQLTLSConfigName(config)
+		assert.True(t, strings.HasPrefix(name, mySQLTLSConfigPrefix))
+		assert.Equal(t, name, getMySQLTLSConfigName(&SQLConfig{TLSCAFile: certFile}))
+		assert.NotEqual(t, name, getMySQLTLSConfigName(&SQLConfig{TLSCAFile: certFile, TLSServerName: "other"}))
+
+		dialector := getDialector(config)
+		assert.Contains(t, dialector.(*mysql.Dialector).Config.DSN, "&tls="+name)
+	})
+
+	t.Run("skipped", func(t *testing.T) {
+		configs := []*SQLConfig{
+			{Driver: MySQL.String(), Host: "localhost"},
+			{Driver: MySQL.String(), DSN: "user@tcp(localhost:3306)/db", TLSCAFile: "missing.pem"},
+			{Driver: PostgreSQL.String(), TLSCAFile: "missing.pem"},
+		}
+		for _, config := range configs {
+			require.NoError(t, registerMySQLTLSConfig(config))
+			assert.Empty(t, getMySQLTLSParam(config))
+		}
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		_, _, err := openSQLDatabase(nil, &SQLConfig{Driver: MySQL.String(), TLSCAFile: "missing.pem"})
+		require.ErrorIs(t, err, ErrInvalidTLSConfig)
+	})
+}
@@ -0,0 +1,113 @@
+package datastore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// tombstoneCollectionName is the collection for the tombstones (MongoDB)
+const tombstoneCollectionName = "tombstones"
+
+// Tombstone is the record of a deleted model (see: WithTombstones)
+type Tombstone struct {
+	ID         string    `json:"id" gorm:"type:char(32);primaryKey" bson:"_id"`
+	DeletedAt  time.Time `json:"deleted_at" gorm:"index" bson:"deleted_at"`
+	Model      string    `json:"model" gorm:"type:varchar(255);index" bson:"model"`
+	PrimaryKey string    `json:"primary_key" gorm:"type:text" bson:"primary_key"` // JSON of the primary key (by column)
+	SoftDelete bool      `json:"soft_delete" bson:"soft_delete"`                  // Marked as deleted (see: WithSoftDeletes)
+}
+
+// GetTombstones will get the tombstones of the models deleted after the time (oldest first, zero is no limit)
+//
+// IE: a differential sync consumer keeps the time of the last tombstone and polls for the next ones
+func (c *Client) GetTombstones(ctx context.Context, since time.Time, limit int) ([]*Tombstone, error) {
+	tombstones := make([]*Tombstone, 0)
+	if c.Engine() == MongoDB {
+		collection := c.getMongoReadCollection(
+			ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, tombstoneCollectionName),
+		)
+		findOptions := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: 1}, {Key: mongoIDField, Value: 1}})
+		if limit > 0 {
+			findOptions.SetLimit(int64(limit))
+		}
+		start := time.Now()
+		cursor, err := collection.Find(ctx, bson.M{"deleted_at": bson.M{conditionGreaterThan: since}}, findOptions)
+		if err == nil {
+			err = cursor.All(ctx, &tombstones)
+		}
+		return tombstones, newMongoQueryError("find", &Tombstone{}, nil, start, err)
+	} else if !IsSQLEngine(c.Engine()) {
+		return nil, ErrUnsupportedEngine
+	}
+
+	db := c.options.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&Tombstone{}) {
+		return tombstones, nil
+	}
+	query := db.Where("deleted_at > ?", since).Order("deleted_at ASC").Order("id ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	return tombstones, query.Find(&tombstones).Error
+}
+
+// migrateTombstones will create the tombstone table (if needed, SQL only)
+func (c *Client) migrateTombstones(ctx context.Context) error {
+	if !c.options.tombstones || !IsSQLEngine(c.Engine()) {
+		return nil
+	}
+	db := c.options.db.WithContext(ctx)
+	if db.Migrator().HasTable(&Tombstone{}) {
+		return nil
+	}
+	return db.AutoMigrate(&Tombstone{})
+}
+
+// newTombstone will return the tombstone of the deleted model
+func newTombstone(model interface{}, primaryKey map[string]interface{}, softDelete bool) (*Tombstone, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	key, err := json.Marshal(primaryKey)
+	if err != nil {
+		return nil, err
+	}
+	modelName := fmt.Sprintf("%T", model)
+	if name := GetModelName(model); name != nil {
+		modelName = *name
+	}
+	return &Tombstone{
+		DeletedAt:  time.Now().UTC(),
+		ID:         hex.EncodeToString(id),
+		Model:      modelName,
+		PrimaryKey: string(key),
+		SoftDelete: softDelete,
+	}, nil
+}
+
+// saveTombstoneWithMongo will save the tombstone of the deleted model (in the session, if any)
+func (c *Client) saveTombstoneWithMongo(ctx context.Context, model interface{}, softDelete bool) error {
+	primaryKey, err := c.getModelPrimaryKey(model)
+	if err != nil {
+		return err
+	}
+	var tombstone *Tombstone
+	if tombstone, err = newTombstone(model, primaryKey, softDelete); err != nil {
+		return err
+	}
+
+	collection := c.getMongoWriteCollection(
+		ctx, setPrefix(c.options.mongoDBConfig.TablePrefix, tombstoneCollectionName),
+	)
+	c.DebugLog(ctx, fmt.Sprintf(logLine, "insert", tombstoneCollectionName, tombstone))
+	_, err = collection.InsertOne(ctx, tombstone)
+	return err
+}
@@ -0,0 +1,79 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_GetTombstones will test the option WithTombstones() and the method GetTombstones()
+func TestClient_GetTombstones(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "tombstone-1"})
+		testDeleteModel(ctx, t, client, &testSQLModel{ID: "tombstone-1"})
+
+		tombstones, err := client.GetTombstones(ctx, time.Time{}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, tombstones)
+	})
+
+	t.Run("[sqlite] deleted and soft-deleted records", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithTombstones(),
+			WithAutoMigrate(&testSoftDeleteModel{}), WithSoftDeletes(&testSoftDeleteModel{}))
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "tombstone-1"})
+		testSaveModels(ctx, t, client, &testSoftDeleteModel{ID: "tombstone-2"})
+
+		start := time.Now().UTC().Add(-time.Second)
+		testDeleteModel(ctx, t, client, &testSQLModel{ID: "tombstone-1"})
+		testDeleteModel(ctx, t, client, &testSoftDeleteModel{ID: "tombstone-2"})
+
+		// Nothing was deleted
+		testDeleteModel(ctx, t, client, &testSQLModel{ID: "missing"})
+
+		tombstones, err := client.GetTombstones(ctx, start, 0)
+		require.NoError(t, err)
+		require.Len(t, tombstones, 2)
+		assert.Equal(t, "test_sql_model", tombstones[0].Model)
+		assert.JSONEq(t, `{"id":"tombstone-1"}`, tombstones[0].PrimaryKey)
+		assert.False(t, tombstones[0].SoftDelete)
+		assert.Len(t, tombstones[0].ID, 32)
+		assert.Equal(t, "test_soft_delete_model", tombstones[1].Model)
+		assert.True(t, tombstones[1].SoftDelete)
+
+		// Polling from the last tombstone
+		tombstones, err = client.GetTombstones(ctx, tombstones[0].DeletedAt, 1)
+		require.NoError(t, err)
+		require.Len(t, tombstones, 1)
+		assert.Equal(t, "test_soft_delete_model", tombstones[0].Model)
+	})
+
+	t.Run("[sqlite] rolled back deletes", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithTombstones())
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "tombstone-1"})
+
+		require.Error(t, client.NewTx(ctx, func(tx *Transaction) error {
+			require.NoError(t, client.DeleteModel(ctx, &testSQLModel{ID: "tombstone-1"}, tx, false))
+			return errors.New("failed")
+		}))
+		tombstones, err := client.GetTombstones(ctx, time.Time{}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, tombstones)
+	})
+
+	t.Run("unsupported engine", func(t *testing.T) {
+		client := &Client{options: &clientOptions{engine: Empty}}
+		_, err := client.GetTombstones(context.Background(), time.Time{}, 0)
+		require.ErrorIs(t, err, ErrUnsupportedEngine)
+	})
+}
@@ -2,53 +2,102 @@ package datastore
 
 import (
 	"context"
+	"database/sql"
+	"strconv"
+	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
-// NewTx will start a new datastore transaction
-func (c *Client) NewTx(ctx context.Context, fn func(*Transaction) error) error {
+// TxOptions are the options for a new transaction (see: NewTx and NewRawTx)
+//
+// SQL engines use the isolation level and read-only flag as-is (sql.TxOptions), MongoDB maps the isolation
+// level to a read concern (local, majority or snapshot) with a majority write concern
+type TxOptions struct {
+	IdleTimeout time.Duration      // Roll back a raw transaction idle for this long (SQL only, overrides WithTransactionWatchdog)
+	Isolation   sql.IsolationLevel // Isolation level (zero is the database default)
+	ReadOnly    bool               // Read-only transaction using a replica if found (SQL only, see: ReadContext)
+}
+
+// NewTx will start a new datastore transaction and run fn
+//
+// The transaction is committed if fn returns nil (unless already committed by fn), and rolled back
+// if fn returns an error or panics (the panic is re-raised), matching gorm.Transaction
+// Use the transaction options for the isolation level (IE: SERIALIZABLE) or a read-only transaction
+// CockroachDB: serialization failures are retried using the default RetryPolicy (see: WithCockroachDB)
+func (c *Client) NewTx(ctx context.Context, fn func(*Transaction) error, txOptions ...*TxOptions) error {
+	if c.isCockroachDB() {
+		return c.NewTxWithRetry(ctx, fn, RetryPolicy{}, txOptions...)
+	}
+	return c.newTx(ctx, fn, txOptions...)
+}
+
+// newTx will start a new datastore transaction and run fn (see: NewTx)
+func (c *Client) newTx(ctx context.Context, fn func(*Transaction) error, txOptions ...*TxOptions) error {
 
 	// All GORM databases
 	if c.options.db != nil {
-		sessionDb := c.options.db.Session(getGormSessionConfig(c.options.db.PrepareStmt, c.IsDebug(), c.options.loggerDB))
-		return fn(&Transaction{
-			sqlTx: sessionDb.Begin(),
-		})
+		return runTx(&Transaction{
+			sqlTx: c.beginSQLTx(ctx, txOptions),
+		}, fn)
 	}
 
 	// For MongoDB
-	if c.options.mongoDBConfig.Transactions {
+	if c.options.mongoDBConfig != nil && c.options.mongoDBConfig.Transactions {
 		return c.options.mongoDB.Client().UseSession(ctx, func(sessionContext mongo.SessionContext) error {
-			if err := sessionContext.StartTransaction(); err != nil {
+			if err := sessionContext.StartTransaction(getMongoTxOptions(txOptions)...); err != nil {
 				return err
 			}
-			return fn(&Transaction{
+			return runTx(&Transaction{
 				sqlTx:   nil,
 				mongoTx: &sessionContext,
-			})
+			}, fn)
 		})
 	}
 
 	// Empty transaction
-	return fn(&Transaction{})
+	return runTx(&Transaction{}, fn)
+}
+
+// runTx will run fn, then commit the transaction (fn returned nil) or roll it back (error or panic)
+func runTx(tx *Transaction, fn func(*Transaction) error) (err error) {
+	panicked := true
+	defer func() {
+		if (panicked || err != nil) && !tx.committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = fn(tx); err == nil {
+		err = tx.Commit()
+	}
+	panicked = false
+	return
 }
 
 // NewRawTx will start a new datastore transaction
-func (c *Client) NewRawTx() (*Transaction, error) {
+//
+// The transaction is rolled back if it exceeds the limits of the watchdog (see: WithTransactionWatchdog), or
+// is idle beyond the idle timeout of the transaction options
+func (c *Client) NewRawTx(txOptions ...*TxOptions) (*Transaction, error) {
 
 	// All GORM databases
 	if c.options.db != nil {
-		sessionDb := c.options.db.Session(getGormSessionConfig(c.options.db.PrepareStmt, c.IsDebug(), c.options.loggerDB))
-		return &Transaction{
-			sqlTx: sessionDb.Begin(),
-		}, nil
+		tx := &Transaction{
+			sqlTx: c.beginSQLTx(context.Background(), txOptions),
+		}
+		c.startTxWatchdog(tx, txOptions)
+		return tx, nil
 	}
 
 	// For MongoDB
 	// todo: implement - but the issue is Mongo uses a callback
-	if c.options.mongoDBConfig.Transactions {
+	if c.options.mongoDBConfig != nil && c.options.mongoDBConfig.Transactions {
 		return nil, ErrNotImplemented
 	}
 
@@ -56,12 +105,74 @@ func (c *Client) NewRawTx() (*Transaction, error) {
 	return &Transaction{}, nil
 }
 
+// beginSQLTx will begin the SQL transaction, read-only transactions are pinned to a replica (dbresolver)
+//
+// The transaction is rolled back by the driver if the context is done (IE: the attempt of NewTxWithRetry)
+func (c *Client) beginSQLTx(ctx context.Context, txOptions []*TxOptions) *gorm.DB {
+	sessionDb := c.options.db.WithContext(ctx).Session(getGormSessionConfig(c.options.db.PrepareStmt, c.IsDebug(), c.options.loggerDB))
+	if len(txOptions) > 0 && txOptions[0] != nil && txOptions[0].ReadOnly {
+		sessionDb = sessionDb.Clauses(dbresolver.Read)
+	}
+	return sessionDb.Begin(getSQLTxOptions(txOptions)...)
+}
+
+// getSQLTxOptions will return the sql.TxOptions for the (first) transaction options (none if not set)
+func getSQLTxOptions(txOptions []*TxOptions) []*sql.TxOptions {
+	if len(txOptions) == 0 || txOptions[0] == nil {
+		return nil
+	}
+	return []*sql.TxOptions{{Isolation: txOptions[0].Isolation, ReadOnly: txOptions[0].ReadOnly}}
+}
+
+// getMongoTxOptions will return the MongoDB transaction options for the (first) transaction options
+// (none if not set or using the default isolation level)
+func getMongoTxOptions(txOptions []*TxOptions) []*options.TransactionOptions {
+	if len(txOptions) == 0 || txOptions[0] == nil {
+		return nil
+	}
+
+	var concern *readconcern.ReadConcern
+	switch txOptions[0].Isolation {
+	case sql.LevelDefault:
+		return nil
+	case sql.LevelReadUncommitted:
+		concern = readconcern.Local()
+	case sql.LevelReadCommitted, sql.LevelWriteCommitted, sql.LevelRepeatableRead:
+		concern = readconcern.Majority()
+	default: // Snapshot, Serializable and Linearizable
+		concern = readconcern.Snapshot()
+	}
+	return []*options.TransactionOptions{
+		options.Transaction().SetReadConcern(concern).SetWriteConcern(writeconcern.Majority()),
+	}
+}
+
 // Transaction is the internal datastore transaction
 type Transaction struct {
+	closed       bool // Committed or rolled back (see: Closed)
 	committed    bool
 	mongoTx      *mongo.SessionContext
+	onCommit     []func() // Run after the commit (see: OnCommit)
 	rowsAffected int64
+	savePoint    string // Current savepoint (see: RunIsolated)
+	savePoints   int    // Number of savepoints created (for unique names)
 	sqlTx        *gorm.DB
+	watchdog     *txWatchdog // Rolls back a leaked (raw) transaction (see: WithTransactionWatchdog)
+}
+
+// ReadContext will return a context for running the reads (IE: GetModel, GetModels) in the transaction
+//
+// Useful with a read-only transaction (see: TxOptions) for multi-statement consistent reads off the primary
+func (tx *Transaction) ReadContext(ctx context.Context) context.Context {
+	if tx.mongoTx != nil {
+		return *tx.mongoTx
+	} else if tx.sqlTx == nil {
+		return ctx
+	}
+	if tx.watchdog != nil {
+		ctx = context.WithValue(ctx, txWatchdogKey{}, tx.watchdog)
+	}
+	return context.WithValue(ctx, readTxKey{}, tx.sqlTx)
 }
 
 // CanCommit will return true if it can commit
@@ -71,6 +182,12 @@ func (tx *Transaction) CanCommit() bool {
 
 // Rollback the transaction
 func (tx *Transaction) Rollback() error {
+	if tx.watchdog.stop() {
+		return nil
+	}
+
+	tx.onCommit, tx.rowsAffected = nil, 0
+	tx.closed = true
 	if tx.sqlTx != nil {
 		tx.sqlTx.Rollback()
 	}
@@ -82,6 +199,61 @@ func (tx *Transaction) Rollback() error {
 	return nil
 }
 
+// RunIsolated will run fn inside a savepoint, if fn fails only the savepoint is rolled back
+// and the outer transaction can continue (IE: skip a failed row in a batch of writes)
+//
+// Use commitTx = false for writes inside fn, the outer transaction is committed by the caller
+// MongoDB transactions do not support savepoints (fn is run as-is)
+func (tx *Transaction) RunIsolated(fn func() error) error {
+	if tx.sqlTx == nil {
+		return fn()
+	}
+
+	// Create the savepoint
+	end, err := tx.watchdog.begin()
+	if err != nil {
+		return err
+	}
+	tx.savePoints++
+	savePoint := "sp_isolated_" + strconv.Itoa(tx.savePoints)
+	err = tx.sqlTx.SavePoint(savePoint).Error
+	end()
+	if err != nil {
+		return err
+	}
+
+	// Run the statements (savepoints can be nested)
+	parentSavePoint := tx.savePoint
+	tx.savePoint = savePoint
+	onCommit, rowsAffected := len(tx.onCommit), tx.rowsAffected
+	err = fn()
+	tx.savePoint = parentSavePoint
+
+	// Roll back only this savepoint (and discard its commit callbacks and rows affected)
+	if err != nil {
+		tx.onCommit, tx.rowsAffected = tx.onCommit[:onCommit], rowsAffected
+		endRollback, abortErr := tx.watchdog.begin()
+		if abortErr != nil {
+			return abortErr
+		}
+		defer endRollback()
+		if rollbackErr := tx.sqlTx.RollbackTo(savePoint).Error; rollbackErr != nil {
+			return rollbackErr
+		}
+	}
+	return err
+}
+
+// rollbackFailed will roll back the transaction after a failed statement
+//
+// Inside RunIsolated, only the savepoint is rolled back (by RunIsolated)
+func (tx *Transaction) rollbackFailed() error {
+	if tx.savePoint != "" {
+		return nil
+	}
+	return tx.Rollback()
+}
+
 // Commit will commit the transaction
 func (tx *Transaction) Commit() error {
 
@@ -91,6 +263,8 @@ func (tx *Transaction) Commit() error {
 	} else if tx.sqlTx == nil &&
 		tx.mongoTx == nil {
 		return nil
+	} else if tx.watchdog.stop() {
+		return ErrTransactionAborted
 	}
 
 	// Finally commit
@@ -98,19 +272,63 @@ func (tx *Transaction) Commit() error {
 		result := tx.sqlTx.Commit()
 		if result.Error != nil {
 			_ = result.Rollback()
+			tx.closed = true
 			return result.Error
 		}
-		tx.committed = true
-		tx.rowsAffected = result.RowsAffected
+		tx.closed, tx.committed = true, true
 	}
 
 	if tx.mongoTx != nil {
 		if err := (*tx.mongoTx).CommitTransaction(*tx.mongoTx); err != nil {
 			return err
 		}
-		tx.committed = true
-		tx.rowsAffected = 1 // todo: can we get all rows affected ?
+		tx.closed, tx.committed = true, true
 	}
 
+	tx.runOnCommit()
 	return nil
 }
+
+// Closed will return true if the transaction was committed, rolled back or aborted by the watchdog
+// (see: TxOptions.IdleTimeout), a closed transaction can not run any more statements
+func (tx *Transaction) Closed() bool {
+	return tx.closed || tx.watchdog.isAborted()
+}
+
+// Committed will return true if the transaction was committed
+func (tx *Transaction) Committed() bool {
+	return tx.committed
+}
+
+// RowsAffected will return the number of rows (documents) affected by the writes in the transaction
+//
+// Counted by SaveModel, SaveModels, UpdateModelFields and DeleteModel as reported by the driver (IE: MySQL
+// counts changed rows, MongoDB modified documents), rolled back writes (and savepoints) are not counted
+func (tx *Transaction) RowsAffected() int64 {
+	return tx.rowsAffected
+}
+
+// addRowsAffected will add the rows affected by a write in the transaction
+func (tx *Transaction) addRowsAffected(rows int64) {
+	tx.rowsAffected += rows
+}
+
+// OnCommit will run fn after the transaction is committed (in the order added), fn is discarded on a rollback
+//
+// Without a transaction to commit (IE: MongoDB without transactions) or once committed, fn is run right away
+func (tx *Transaction) OnCommit(fn func()) {
+	if !tx.CanCommit() {
+		fn()
+		return
+	}
+	tx.onCommit = append(tx.onCommit, fn)
+}
+
+// runOnCommit will run the commit callbacks (once)
+func (tx *Transaction) runOnCommit() {
+	callbacks := tx.onCommit
+	tx.onCommit = nil
+	for _, fn := range callbacks {
+		fn()
+	}
+}
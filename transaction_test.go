@@ -1 +1,364 @@
 package datastore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransaction_RunIsolated will test the method RunIsolated()
+func TestTransaction_RunIsolated(t *testing.T) {
+	t.Run("failed statement does not poison the transaction", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "isolated-1", Name: "existing"})
+
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			save := func(model *testSQLModel) func() error {
+				return func() error {
+					return client.SaveModel(ctx, model, tx, true, false)
+				}
+			}
+			require.NoError(t, tx.RunIsolated(save(&testSQLModel{ID: "isolated-2", Name: "first"})))
+			require.Error(t, tx.RunIsolated(save(&testSQLModel{ID: "isolated-1", Name: "duplicate"})))
+			require.NoError(t, tx.RunIsolated(save(&testSQLModel{ID: "isolated-3", Name: "last"})))
+			return tx.Commit()
+		}))
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "isolated-1"}, defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "existing", model.Name)
+	})
+
+	t.Run("savepoint is rolled back", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		errFailed := errors.New("failed")
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "outer-1", Name: "outer"}, tx, true, false))
+			require.ErrorIs(t, tx.RunIsolated(func() error {
+				require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "inner-1", Name: "inner"}, tx, true, false))
+				return errFailed
+			}), errFailed)
+			assert.Empty(t, tx.savePoint)
+			return tx.Commit()
+		}))
+
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(ctx, model, map[string]interface{}{sqlIDField: "outer-1"}, defaultDatabaseMaxTimeout, false))
+		require.ErrorIs(t, client.GetModel(ctx, &testSQLModel{}, map[string]interface{}{sqlIDField: "inner-1"}, defaultDatabaseMaxTimeout, false), ErrNoResults)
+	})
+
+	t.Run("empty transaction runs fn", func(t *testing.T) {
+		tx := &Transaction{}
+		called := false
+		require.NoError(t, tx.RunIsolated(func() error {
+			called = true
+			return nil
+		}))
+		assert.True(t, called)
+	})
+}
+
+// TestClient_NewTx will test the method NewTx()
+func TestClient_NewTx(t *testing.T) {
+	t.Run("commit when fn returns nil", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var transaction *Transaction
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			transaction = tx
+			return client.SaveModel(ctx, &testSQLModel{ID: "tx-1", Name: "a"}, tx, true, false)
+		}))
+		assert.True(t, transaction.committed)
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("rollback when fn returns an error", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		testErr := errors.New("test error")
+		err := client.NewTx(ctx, func(tx *Transaction) error {
+			require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "tx-2", Name: "a"}, tx, true, false))
+			return testErr
+		})
+		require.ErrorIs(t, err, testErr)
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("rollback when fn panics", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		assert.Panics(t, func() {
+			_ = client.NewTx(ctx, func(tx *Transaction) error {
+				require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "tx-3", Name: "a"}, tx, true, false))
+				panic("test panic")
+			})
+		})
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("already committed by fn", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.SaveModel(ctx, &testSQLModel{ID: "tx-4", Name: "a"}, tx, true, true)
+		}))
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("closed when the commit fails", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		txCtx, cancel := context.WithCancel(ctx)
+		var transaction *Transaction
+		require.Error(t, client.NewTx(txCtx, func(tx *Transaction) error {
+			transaction = tx
+			require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "tx-5", Name: "a"}, tx, true, false))
+			cancel() // The driver rolls back the transaction
+			return nil
+		}))
+		assert.True(t, transaction.Closed())
+		assert.False(t, transaction.Committed())
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
+
+// TestClient_NewTxOptions will test the transaction options of NewTx() and NewRawTx()
+func TestClient_NewTxOptions(t *testing.T) {
+	t.Run("[sqlite] serializable transaction", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			return client.SaveModel(ctx, &testSQLModel{ID: "tx-options-1", Name: "a"}, tx, true, false)
+		}, &TxOptions{Isolation: sql.LevelSerializable}))
+
+		tx, err := client.NewRawTx(&TxOptions{Isolation: sql.LevelSerializable})
+		require.NoError(t, err)
+		require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "tx-options-2", Name: "b"}, tx, true, true))
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("sql options", func(t *testing.T) {
+		assert.Nil(t, getSQLTxOptions(nil))
+		assert.Nil(t, getSQLTxOptions([]*TxOptions{nil}))
+		assert.Equal(t, []*sql.TxOptions{{Isolation: sql.LevelReadCommitted, ReadOnly: true}},
+			getSQLTxOptions([]*TxOptions{{Isolation: sql.LevelReadCommitted, ReadOnly: true}}))
+	})
+
+	t.Run("mongo options", func(t *testing.T) {
+		assert.Nil(t, getMongoTxOptions(nil))
+		assert.Nil(t, getMongoTxOptions([]*TxOptions{{ReadOnly: true}}))
+
+		for isolation, level := range map[sql.IsolationLevel]string{
+			sql.LevelReadUncommitted: "local",
+			sql.LevelReadCommitted:   "majority",
+			sql.LevelRepeatableRead:  "majority",
+			sql.LevelSnapshot:        "snapshot",
+			sql.LevelSerializable:    "snapshot",
+		} {
+			mongoOptions := getMongoTxOptions([]*TxOptions{{Isolation: isolation}})
+			require.Len(t, mongoOptions, 1)
+			assert.Equal(t, level, mongoOptions[0].ReadConcern.Level, isolation.String())
+			assert.NotNil(t, mongoOptions[0].WriteConcern)
+		}
+	})
+}
+
+// TestTransaction_ReadContext will test the method ReadContext()
+func TestTransaction_ReadContext(t *testing.T) {
+	t.Run("[sqlite] read-only transaction", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "read-only-1", Name: "a"})
+
+		tx, err := client.NewRawTx(&TxOptions{ReadOnly: true})
+		require.NoError(t, err)
+		defer func() {
+			_ = tx.Rollback()
+		}()
+
+		// Pinned to the replicas (dbresolver)
+		_, pinned := tx.sqlTx.Statement.Settings.Load("gorm:db_resolver:read")
+		assert.True(t, pinned)
+
+		readCtx := tx.ReadContext(ctx)
+		assert.Equal(t, tx.sqlTx, getReadTx(readCtx))
+		model := &testSQLModel{}
+		require.NoError(t, client.GetModel(readCtx, model, map[string]interface{}{sqlIDField: "read-only-1"},
+			defaultDatabaseMaxTimeout, false))
+		assert.Equal(t, "a", model.Name)
+		count, err := client.GetModelCount(readCtx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("read-write transaction is not pinned", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		tx, err := client.NewRawTx()
+		require.NoError(t, err)
+		defer func() {
+			_ = tx.Rollback()
+		}()
+		_, pinned := tx.sqlTx.Statement.Settings.Load("gorm:db_resolver:read")
+		assert.False(t, pinned)
+	})
+
+	t.Run("empty transaction", func(t *testing.T) {
+		ctx := context.Background()
+		tx := &Transaction{}
+		assert.Equal(t, ctx, tx.ReadContext(ctx))
+	})
+}
+
+// TestTransaction_OnCommit will test the method OnCommit()
+func TestTransaction_OnCommit(t *testing.T) {
+	t.Run("run after the commit", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		tx, err := client.NewRawTx()
+		require.NoError(t, err)
+		var calls []int
+		tx.OnCommit(func() { calls = append(calls, 1) })
+		tx.OnCommit(func() { calls = append(calls, 2) })
+		assert.Empty(t, calls)
+
+		require.NoError(t, tx.Commit())
+		assert.Equal(t, []int{1, 2}, calls)
+
+		// Already committed (run right away, once)
+		require.NoError(t, tx.Commit())
+		tx.OnCommit(func() { calls = append(calls, 3) })
+		assert.Equal(t, []int{1, 2, 3}, calls)
+	})
+
+	t.Run("discarded on rollback", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		called := false
+		require.Error(t, client.NewTx(ctx, func(tx *Transaction) error {
+			tx.OnCommit(func() { called = true })
+			return errors.New("failed")
+		}))
+		assert.False(t, called)
+	})
+
+	t.Run("discarded with a rolled back savepoint", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		var calls []string
+		require.NoError(t, client.NewTx(ctx, func(tx *Transaction) error {
+			tx.OnCommit(func() { calls = append(calls, "outer") })
+			_ = tx.RunIsolated(func() error {
+				tx.OnCommit(func() { calls = append(calls, "inner") })
+				return errors.New("failed")
+			})
+			return nil
+		}))
+		assert.Equal(t, []string{"outer"}, calls)
+	})
+
+	t.Run("empty transaction runs fn", func(t *testing.T) {
+		called := false
+		(&Transaction{}).OnCommit(func() { called = true })
+		assert.True(t, called)
+	})
+}
+
+// TestTransaction_RowsAffected will test the methods RowsAffected() and Committed()
+func TestTransaction_RowsAffected(t *testing.T) {
+	t.Run("counts the writes", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+		testSaveModels(ctx, t, client, &testSQLModel{ID: "rows-1", Name: "a"})
+
+		tx, err := client.NewRawTx()
+		require.NoError(t, err)
+		assert.False(t, tx.Committed())
+		require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "rows-2"}, tx, true, false))
+		require.Nil(t, client.SaveModels(ctx, []interface{}{
+			&testSQLModel{ID: "rows-3"}, &testSQLModel{ID: "rows-4"},
+		}, tx, true))
+		require.NoError(t, client.UpdateModelFields(ctx, &testSQLModel{ID: "rows-1"},
+			map[string]interface{}{"name": "b"}, tx, false))
+		require.NoError(t, client.UpdateModelFields(ctx, &testSQLModel{ID: "missing"},
+			map[string]interface{}{"name": "b"}, tx, false))
+		require.NoError(t, client.DeleteModel(ctx, &testSQLModel{ID: "rows-2"}, tx, false))
+		assert.Equal(t, int64(5), tx.RowsAffected())
+
+		require.NoError(t, tx.Commit())
+		assert.True(t, tx.Committed())
+		assert.Equal(t, int64(5), tx.RowsAffected())
+	})
+
+	t.Run("rolled back writes are not counted", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		tx, err := client.NewRawTx()
+		require.NoError(t, err)
+		require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "rows-1"}, tx, true, false))
+		require.Error(t, tx.RunIsolated(func() error {
+			require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "rows-2"}, tx, true, false))
+			return errors.New("failed")
+		}))
+		assert.Equal(t, int64(1), tx.RowsAffected())
+
+		require.NoError(t, tx.Rollback())
+		assert.False(t, tx.Committed())
+		assert.Zero(t, tx.RowsAffected())
+	})
+}
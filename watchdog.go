@@ -0,0 +1,181 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// minWatchdogInterval is the minimum time between the watchdog checks
+const minWatchdogInterval = 10 * time.Millisecond
+
+// ErrTransactionAborted is when the transaction was rolled back by the watchdog (idle or open too long)
+var ErrTransactionAborted = errors.New("transaction was aborted by the watchdog")
+
+// txWatchdogConfig is the configuration for the transaction watchdog (see: WithTransactionWatchdog)
+type txWatchdogConfig struct {
+	maxIdle time.Duration // Max time without a statement (zero is no limit)
+	maxOpen time.Duration // Max time the transaction is open (zero is no limit)
+}
+
+// txWatchdog rolls back a (raw) transaction that is idle or open beyond the limits
+//
+// The watchdog rolls back the underlying transaction (IE: *sql.Tx) and not the gorm handle, which is not safe
+// for concurrent use with the caller
+type txWatchdog struct {
+	aborted  bool              // The transaction was rolled back by the watchdog
+	config   *txWatchdogConfig // Limits
+	done     chan struct{}     // Closed when the watchdog is stopped
+	inFlight int               // Number of statements running (the transaction is not idle)
+	lastUsed time.Time         // Last time a statement was run (started or finished)
+	mu       sync.Mutex        // Lock for the state
+	stack    string            // Stack trace of the caller that started the transaction
+	started  time.Time         // Time the transaction was started
+	stopped  bool              // The watchdog was stopped (committed, rolled back or aborted)
+	sqlTx    gorm.TxCommitter  // Underlying transaction of the connection pool (IE: *sql.Tx)
+}
+
+// txWatchdogKey is the context key for the watchdog of the transaction used by the reads (see: ReadContext)
+type txWatchdogKey struct{}
+
+// startTxWatchdog will start the watchdog for the transaction (if enabled)
+//
+// The idle timeout of the transaction options replaces the max idle time of the client
+func (c *Client) startTxWatchdog(tx *Transaction, txOptions []*TxOptions) {
+	config := c.options.txWatchdog
+	if len(txOptions) > 0 && txOptions[0] != nil && txOptions[0].IdleTimeout > 0 {
+		config = &txWatchdogConfig{maxIdle: txOptions[0].IdleTimeout}
+		if c.options.txWatchdog != nil {
+			config.maxOpen = c.options.txWatchdog.maxOpen
+		}
+	}
+	if config == nil || tx.sqlTx == nil {
+		return
+	}
+	sqlTx, ok := tx.sqlTx.Statement.ConnPool.(gorm.TxCommitter)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	tx.watchdog = &txWatchdog{
+		config:   config,
+		done:     make(chan struct{}),
+		lastUsed: now,
+		stack:    string(debug.Stack()),
+		started:  now,
+		sqlTx:    sqlTx,
+	}
+
+	// Check at least twice per limit
+	interval := config.maxOpen
+	if config.maxIdle > 0 && (interval <= 0 || config.maxIdle < interval) {
+		interval = config.maxIdle
+	}
+	interval /= 2
+	if interval < minWatchdogInterval {
+		interval = minWatchdogInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-tx.watchdog.done:
+				return
+			case <-ticker.C:
+				if reason := tx.watchdog.abort(); reason != "" {
+					c.warnLog(context.Background(), fmt.Sprintf(
+						"transaction rolled back by the watchdog (%s), started at:\n%s", reason, tx.watchdog.stack,
+					))
+					return
+				}
+			}
+		}
+	}()
+}
+
+// abort will roll back the transaction if it exceeded a limit (returns the reason, empty if not aborted)
+//
+// The transaction is not idle while a statement is running
+func (w *txWatchdog) abort() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return ""
+	}
+
+	now := time.Now()
+	reason := ""
+	if w.config.maxOpen > 0 && now.Sub(w.started) > w.config.maxOpen {
+		reason = "open for " + now.Sub(w.started).String()
+	} else if w.config.maxIdle > 0 && w.inFlight == 0 && now.Sub(w.lastUsed) > w.config.maxIdle {
+		reason = "idle for " + now.Sub(w.lastUsed).String()
+	}
+	if reason == "" {
+		return ""
+	}
+
+	_ = w.sqlTx.Rollback()
+	w.aborted = true
+	w.stopped = true
+	close(w.done)
+	return reason
+}
+
+// stop will stop the watchdog and return true if the transaction was already aborted
+func (w *txWatchdog) stop() bool {
+	if w == nil {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.stopped {
+		w.stopped = true
+		close(w.done)
+	}
+	return w.aborted
+}
+
+// begin will mark the start of a statement (the transaction is not idle until the returned func is called),
+// returns ErrTransactionAborted if the transaction was already rolled back by the watchdog
+func (w *txWatchdog) begin() (func(), error) {
+	if w == nil {
+		return func() {}, nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.aborted {
+		return nil, ErrTransactionAborted
+	}
+	w.inFlight++
+	w.lastUsed = time.Now()
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.inFlight--
+		w.lastUsed = time.Now()
+	}, nil
+}
+
+// getReadWatchdog will return the watchdog of the transaction used by the reads (nil if not set)
+func getReadWatchdog(ctx context.Context) *txWatchdog {
+	w, _ := ctx.Value(txWatchdogKey{}).(*txWatchdog)
+	return w
+}
+
+// isAborted will return true if the transaction was rolled back by the watchdog
+func (w *txWatchdog) isAborted() bool {
+	if w == nil {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.aborted
+}
@@ -0,0 +1,136 @@
+package datastore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_TransactionWatchdog will test the option WithTransactionWatchdog()
+func TestClient_TransactionWatchdog(t *testing.T) {
+	t.Run("idle transaction is rolled back", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithTransactionWatchdog(20*time.Millisecond, 0))
+		defer deferFunc()
+
+		tx, err := client.NewRawTx()
+		require.NoError(t, err)
+		require.NotNil(t, tx.watchdog)
+		assert.Contains(t, tx.watchdog.stack, "TestClient_TransactionWatchdog")
+		require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "watchdog-1", Name: "leaked"}, tx, true, false))
+
+		assert.Eventually(t, func() bool {
+			tx.watchdog.mu.Lock()
+			defer tx.watchdog.mu.Unlock()
+			return tx.watchdog.aborted
+		}, time.Second, 10*time.Millisecond)
+
+		require.ErrorIs(t, tx.Commit(), ErrTransactionAborted)
+		require.NoError(t, tx.Rollback())
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("commit before the limit", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithTransactionWatchdog(time.Hour, time.Hour))
+		defer deferFunc()
+
+		tx, err := client.NewRawTx()
+		require.NoError(t, err)
+		require.NoError(t, client.SaveModel(ctx, &testSQLModel{ID: "watchdog-2", Name: "committed"}, tx, true, true))
+		assert.True(t, tx.watchdog.stopped)
+		assert.False(t, tx.watchdog.aborted)
+
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("idle timeout of the transaction", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		tx, err := client.NewRawTx(&TxOptions{IdleTimeout: 20 * time.Millisecond})
+		require.NoError(t, err)
+		require.NotNil(t, tx.watchdog)
+		assert.Equal(t, 20*time.Millisecond, tx.watchdog.config.maxIdle)
+		assert.False(t, tx.Closed())
+
+		assert.Eventually(t, tx.Closed, time.Second, 10*time.Millisecond)
+		require.ErrorIs(t, client.SaveModel(ctx, &testSQLModel{ID: "watchdog-3"}, tx, true, false), ErrTransactionAborted)
+		require.ErrorIs(t, tx.RunIsolated(func() error { return nil }), ErrTransactionAborted)
+		require.ErrorIs(t, tx.Commit(), ErrTransactionAborted)
+		assert.False(t, tx.Committed())
+	})
+
+	t.Run("not idle while a statement is running", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithTransactionWatchdog(20*time.Millisecond, 0))
+		defer deferFunc()
+
+		tx, err := client.NewRawTx()
+		require.NoError(t, err)
+
+		// A long running statement
+		end, err := tx.watchdog.begin()
+		require.NoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, tx.Closed())
+
+		end()
+		assert.Eventually(t, tx.Closed, time.Second, 10*time.Millisecond)
+		assert.Equal(t, 0, tx.watchdog.inFlight)
+	})
+
+	t.Run("reads of the transaction are statements", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithTransactionWatchdog(time.Hour, 0))
+		defer deferFunc()
+
+		tx, err := client.NewRawTx()
+		require.NoError(t, err)
+		readCtx := tx.ReadContext(ctx)
+		require.Equal(t, tx.watchdog, getReadWatchdog(readCtx))
+
+		_, cancel, err := createCtx(readCtx, client.(*Client).options.db, time.Minute, false, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, tx.watchdog.inFlight)
+		cancel()
+		assert.Equal(t, 0, tx.watchdog.inFlight)
+
+		_, err = client.GetModelCount(readCtx, &testSQLModel{}, nil, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+	})
+
+	t.Run("idle timeout keeps the client max open time", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t, WithTransactionWatchdog(time.Hour, 2*time.Hour))
+		defer deferFunc()
+
+		tx, err := client.NewRawTx(&TxOptions{IdleTimeout: time.Minute})
+		require.NoError(t, err)
+		assert.Equal(t, &txWatchdogConfig{maxIdle: time.Minute, maxOpen: 2 * time.Hour}, tx.watchdog.config)
+		require.NoError(t, tx.Commit())
+		assert.True(t, tx.Closed())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ctx := context.Background()
+		client, deferFunc := testSQLiteClient(ctx, t)
+		defer deferFunc()
+
+		tx, err := client.NewRawTx()
+		require.NoError(t, err)
+		assert.Nil(t, tx.watchdog)
+		require.NoError(t, tx.Rollback())
+		assert.True(t, tx.Closed())
+	})
+}
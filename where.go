@@ -2,7 +2,10 @@ package datastore
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -25,6 +28,9 @@ func (c *Client) CustomWhere(tx CustomWhereInterface, conditions map[string]inte
 	// Process the conditions
 	processConditions(c, tx, conditions, engine, &varNum, nil)
 
+	// Suggest the missing JSON indexes (debug only)
+	c.suggestJSONIndexes(tx.getGormTx(), conditions, engine)
+
 	// Return the GORM tx
 	return tx.getGormTx()
 }
@@ -83,6 +89,62 @@ func processConditions(client ClientInterface, tx CustomWhereInterface, conditio
 			varName := "var" + strconv.Itoa(*varNum)
 			tx.Where(*parentKey+" != @"+varName, map[string]interface{}{varName: formatCondition(condition, engine)})
 			*varNum++
+		} else if key == conditionLike || key == conditionILike {
+			varName := "var" + strconv.Itoa(*varNum)
+			tx.Where(whereLike(engine, *parentKey, "@"+varName, key == conditionILike), map[string]interface{}{varName: condition})
+			*varNum++
+		} else if key == conditionStartsWith || key == conditionEndsWith {
+			varName := "var" + strconv.Itoa(*varNum)
+			pattern := escapeLikePattern(fmt.Sprint(condition))
+			if key == conditionStartsWith {
+				pattern += "%"
+			} else {
+				pattern = "%" + pattern
+			}
+			query := whereLike(engine, *parentKey, "@"+varName, false)
+			if engine == SQLite { // SQLite does not have a default escape character
+				query += ` ESCAPE '\'`
+			}
+			tx.Where(query, map[string]interface{}{varName: pattern})
+			*varNum++
+		} else if key == conditionRegex {
+			varName := "var" + strconv.Itoa(*varNum)
+			pattern, caseInsensitive := getRegexCondition(engine, condition, conditions[conditionRegexOptions])
+			tx.Where(whereRegex(engine, *parentKey, "@"+varName, caseInsensitive), map[string]interface{}{varName: pattern})
+			*varNum++
+		} else if key == conditionRegexOptions {
+			continue // Used by the $regex condition
+		} else if key == conditionBetween {
+			bounds, ok := getBetweenBounds(condition)
+			if !ok {
+				tx.Where("1 = 0") // Invalid ranges do not match any records
+				continue
+			}
+			fromName := "var" + strconv.Itoa(*varNum)
+			toName := "var" + strconv.Itoa(*varNum+1)
+			tx.Where(*parentKey+" BETWEEN @"+fromName+" AND @"+toName, map[string]interface{}{
+				fromName: formatCondition(bounds[0], engine),
+				toName:   formatCondition(bounds[1], engine),
+			})
+			*varNum += 2
+		} else if key == conditionBitsAllSet || key == conditionBitsAnySet {
+			mask, ok := getBitMask(condition)
+			if !ok {
+				tx.Where("1 = 0") // Invalid masks do not match any records
+				continue
+			}
+			varName := "var" + strconv.Itoa(*varNum)
+			query := "(" + *parentKey + " & @" + varName + ") = @" + varName
+			if key == conditionBitsAnySet {
+				query = "(" + *parentKey + " & @" + varName + ") <> 0"
+			}
+			tx.Where(query, map[string]interface{}{varName: mask})
+			*varNum++
+		} else if key == conditionEqOrNull {
+			varName := "var" + strconv.Itoa(*varNum)
+			tx.Where("("+*parentKey+" = @"+varName+" OR "+*parentKey+" IS NULL)",
+				map[string]interface{}{varName: formatCondition(condition, engine)})
+			*varNum++
 		} else if key == conditionExists {
 			if condition.(bool) {
 				tx.Where(*parentKey + " IS NOT NULL")
@@ -90,27 +152,33 @@ func processConditions(client ClientInterface, tx CustomWhereInterface, conditio
 				tx.Where(*parentKey + " IS NULL")
 			}
 		} else if StringInSlice(key, client.GetArrayFields()) {
-			tx.Where(whereSlice(engine, key, formatCondition(condition, engine)))
+			tx.Where(whereSlice(engine, quoteIdentifier(engine, key), formatCondition(condition, engine)))
 		} else if StringInSlice(key, client.GetObjectFields()) {
-			tx.Where(whereObject(engine, key, formatCondition(condition, engine)))
+			query, vars := whereObject(engine, quoteIdentifier(engine, key), formatCondition(condition, engine), varNum)
+			if len(vars) > 0 {
+				tx.Where(query, vars)
+			} else {
+				tx.Where(query)
+			}
 		} else {
+			column := quoteIdentifier(engine, key) // Reserved words and other characters are quoted
 			if condition == nil {
-				tx.Where(key + " IS NULL")
+				tx.Where(column + " IS NULL")
 			} else {
 				v := reflect.ValueOf(condition)
 				switch v.Kind() { //nolint:exhaustive // not all cases are needed
 				case reflect.Map:
 					if _, ok := condition.(map[string]interface{}); ok {
-						processConditions(client, tx, condition.(map[string]interface{}), engine, varNum, &key) //nolint:scopelint // ignore for now
+						processConditions(client, tx, condition.(map[string]interface{}), engine, varNum, &column)
 					} else {
 						c, _ := json.Marshal(condition) //nolint:errchkjson // this check might break the current code
 						var cc map[string]interface{}
 						_ = json.Unmarshal(c, &cc)
-						processConditions(client, tx, cc, engine, varNum, &key) //nolint:scopelint // ignore for now
+						processConditions(client, tx, cc, engine, varNum, &column)
 					}
 				default:
 					varName := "var" + strconv.Itoa(*varNum)
-					tx.Where(key+" = @"+varName, map[string]interface{}{varName: formatCondition(condition, engine)})
+					tx.Where(column+" = @"+varName, map[string]interface{}{varName: formatCondition(condition, engine)})
 					*varNum++
 				}
 			}
@@ -181,15 +249,80 @@ func processWhereOr(client ClientInterface, tx CustomWhereInterface, condition i
 	}
 }
 
+// getBitMask will return the bit mask of a $bitsAllSet or $bitsAnySet condition (a non-negative number,
+// or a slice of bit positions like MongoDB)
+func getBitMask(condition interface{}) (int64, bool) {
+	v := reflect.ValueOf(condition)
+	switch v.Kind() { //nolint:exhaustive // only numbers and slices are masks
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), v.Int() >= 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), v.Uint() <= math.MaxInt64 //nolint:gosec // checked
+	case reflect.Slice, reflect.Array:
+		var mask int64
+		for i := 0; i < v.Len(); i++ {
+			position, ok := getConditionNumber(v.Index(i).Interface())
+			if !ok || position < 0 || position > 62 || position != math.Trunc(position) {
+				return 0, false
+			}
+			mask |= 1 << int(position)
+		}
+		return mask, v.Len() > 0
+	}
+	return 0, false
+}
+
+// getBetweenBounds will return the lower and upper bounds of a $between condition (a slice of two values)
+func getBetweenBounds(condition interface{}) ([]interface{}, bool) {
+	if condition == nil {
+		return nil, false
+	}
+	v := reflect.ValueOf(condition)
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Len() != 2 {
+		return nil, false
+	}
+	return []interface{}{v.Index(0).Interface(), v.Index(1).Interface()}, true
+}
+
+// escapeLikePattern will escape the LIKE wildcards (%, _) and the escape character (\) in the value
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// whereLike generates the LIKE statement (ILIKE for case-insensitive matching on PostgreSQL)
+func whereLike(engine Engine, k, placeholder string, caseInsensitive bool) string {
+	if !caseInsensitive {
+		return k + " LIKE " + placeholder
+	} else if engine == PostgreSQL {
+		return k + " ILIKE " + placeholder
+	}
+	return "LOWER(" + k + ") LIKE LOWER(" + placeholder + ")"
+}
+
 // escapeDBString will escape the database string
 func escapeDBString(s string) string {
 	rs := strings.Replace(s, "'", "\\'", -1)
 	return strings.Replace(rs, "\"", "\\\"", -1)
 }
 
-// whereObject generates the where object
-func whereObject(engine Engine, k string, v interface{}) string {
+// whereObjectVars are the bound operands of the object conditions (named like processConditions: @var0...)
+type whereObjectVars struct {
+	varNum *int                   // Shared variable counter (see: processConditions)
+	vars   map[string]interface{} // Bound operands (by variable name)
+}
+
+// bind will bind the operand as a new variable and return its placeholder
+func (w *whereObjectVars) bind(value interface{}) string {
+	varName := "var" + strconv.Itoa(*w.varNum)
+	*w.varNum++
+	w.vars[varName] = value
+	return "@" + varName
+}
+
+// whereObject generates the where object, the operands are bound as variables (see: whereObjectVars)
+func whereObject(engine Engine, k string, v interface{}, varNum *int) (string, map[string]interface{}) {
 	queryParts := make([]string, 0)
+	w := &whereObjectVars{varNum: varNum, vars: make(map[string]interface{})}
 
 	// we don't know the type, we handle the rangeValue as a map[string]interface{}
 	vJSON, _ := json.Marshal(v) //nolint:errchkjson // this check might break the current code
@@ -197,45 +330,188 @@ func whereObject(engine Engine, k string, v interface{}) string {
 	var rangeV map[string]interface{}
 	_ = json.Unmarshal(vJSON, &rangeV)
 
-	for rangeKey, rangeValue := range rangeV {
-		if engine == MySQL || engine == SQLite {
+	// Sorted keys (stable variable names)
+	rangeKeys := make([]string, 0, len(rangeV))
+	for rangeKey := range rangeV {
+		rangeKeys = append(rangeKeys, rangeKey)
+	}
+	sort.Strings(rangeKeys)
+
+	for _, rangeKey := range rangeKeys {
+		rangeValue := rangeV[rangeKey]
+		if !isValidObjectKey(rangeKey) {
+			queryParts = append(queryParts, "1 = 0") // Invalid keys do not match any records
+		} else if operators, ok := getObjectOperators(rangeValue); ok {
+			queryParts = append(queryParts, w.whereObjectOperators(engine, k, rangeKey, operators))
+		} else if engine == MySQL || engine == SQLite {
 			switch vv := rangeValue.(type) {
 			case string:
-				rangeValue = "\"" + escapeDBString(rangeValue.(string)) + "\""
-				queryParts = append(queryParts, "JSON_EXTRACT("+k+", '$."+rangeKey+"') = "+rangeValue.(string))
-			default:
-				metadataJSON, _ := json.Marshal(vv) //nolint:errchkjson // this check might break the current code
-				var metadata map[string]interface{}
-				_ = json.Unmarshal(metadataJSON, &metadata)
-				for kk, vvv := range metadata {
-					mJSON, _ := json.Marshal(vvv) //nolint:errchkjson // this check might break the current code
-					vvv = string(mJSON)
-					queryParts = append(queryParts, "JSON_EXTRACT("+k+", '$."+rangeKey+"."+kk+"') = "+vvv.(string))
+				queryParts = append(queryParts, "JSON_EXTRACT("+k+", "+getJSONPath(rangeKey)+") = "+w.bind(vv))
+			case map[string]interface{}:
+				keys := make([]string, 0, len(vv))
+				for kk := range vv {
+					keys = append(keys, kk)
+				}
+				sort.Strings(keys)
+				for _, kk := range keys {
+					if !isValidObjectKey(kk) {
+						queryParts = append(queryParts, "1 = 0")
+						continue
+					}
+					queryParts = append(queryParts, "JSON_EXTRACT("+k+", "+getJSONPath(rangeKey, kk)+") = "+
+						w.whereObjectLiteral(engine, vv[kk]))
 				}
-			}
-		} else if engine == PostgreSQL {
-			switch vv := rangeValue.(type) {
-			case string:
-				rangeValue = "\"" + escapeDBString(rangeValue.(string)) + "\""
 			default:
-				metadataJSON, _ := json.Marshal(vv) //nolint:errchkjson // this check might break the current code
-				rangeValue = string(metadataJSON)
+				queryParts = append(queryParts, w.whereObjectComparison(engine, k, rangeKey, conditionEquals, vv))
 			}
-			queryParts = append(queryParts, k+"::jsonb @> '{\""+rangeKey+"\":"+rangeValue.(string)+"}'::jsonb")
+		} else if engine == PostgreSQL {
+			queryParts = append(queryParts, w.whereObjectContains(k, rangeKey, rangeValue))
 		} else {
-			queryParts = append(queryParts, "JSON_EXTRACT("+k+", '$."+rangeKey+"') = '"+escapeDBString(rangeValue.(string))+"'")
+			queryParts = append(queryParts, "JSON_EXTRACT("+k+", "+getJSONPath(rangeKey)+") = "+
+				w.bind(fmt.Sprint(rangeValue)))
 		}
 	}
 
 	if len(queryParts) == 0 {
-		return ""
+		return "", w.vars
 	}
 	query := queryParts[0]
 	if len(queryParts) > 1 {
 		query = "(" + strings.Join(queryParts, " AND ") + ")"
 	}
 
-	return query
+	return query, w.vars
+}
+
+// whereObjectComparisons are the SQL comparisons of the object key operators
+var whereObjectComparisons = map[string]string{
+	conditionEquals:             "=",
+	conditionNotEquals:          "!=",
+	conditionGreaterThan:        ">",
+	conditionGreaterThanOrEqual: ">=",
+	conditionLessThan:           "<",
+	conditionLessThanOrEqual:    "<=",
+}
+
+// whereObjectOperators generates the where statement of the object key operators
+// (IE: {"amount": {"$gt": 5, "$lte": 10}}), unknown operators do not match any records
+func (w *whereObjectVars) whereObjectOperators(engine Engine, k, objectKey string,
+	operators map[string]interface{},
+) string {
+	names := make([]string, 0, len(operators))
+	for operator := range operators {
+		names = append(names, operator)
+	}
+	sort.Strings(names)
+
+	queryParts := make([]string, 0, len(names))
+	for _, operator := range names {
+		if operator == conditionExists || operator == conditionIn {
+			queryParts = append(queryParts, w.whereObjectOperator(engine, k, objectKey, operator, operators[operator]))
+		} else if _, ok := whereObjectComparisons[operator]; ok {
+			queryParts = append(queryParts, w.whereObjectComparison(engine, k, objectKey, operator, operators[operator]))
+		} else {
+			queryParts = append(queryParts, "1 = 0")
+		}
+	}
+	if len(queryParts) == 1 {
+		return queryParts[0]
+	}
+	return "(" + strings.Join(queryParts, " AND ") + ")"
+}
+
+// whereObjectComparison generates the comparison of an object key and a scalar value
+//
+// PostgreSQL casts the extracted text (numeric or boolean), MySQL and SQLite compare the JSON values
+func (w *whereObjectVars) whereObjectComparison(engine Engine, k, objectKey, operator string,
+	operand interface{},
+) string {
+	comparison := whereObjectComparisons[operator]
+	if operand == nil {
+		query := "JSON_EXTRACT(" + k + ", " + getJSONPath(objectKey) + ")"
+		if engine == PostgreSQL {
+			query = "(" + k + "::jsonb -> '" + objectKey + "')"
+		}
+		if operator == conditionEquals {
+			return query + " IS NULL"
+		} else if operator == conditionNotEquals {
+			return query + " IS NOT NULL"
+		}
+		return "1 = 0" // NULL values can not be compared
+	}
+
+	if engine != PostgreSQL {
+		return "JSON_EXTRACT(" + k + ", " + getJSONPath(objectKey) + ") " + comparison + " " +
+			w.whereObjectLiteral(engine, operand)
+	}
+
+	text := k + "::jsonb ->> '" + objectKey + "'"
+	switch operand.(type) {
+	case string:
+		return text + " " + comparison + " " + w.bind(operand)
+	case bool:
+		return "(" + text + ")::boolean " + comparison + " " + w.bind(operand)
+	default:
+		return "(" + text + ")::numeric " + comparison + " " + w.bind(operand)
+	}
+}
+
+// whereObjectLiteral will bind the value compared with a JSON value (MySQL and SQLite)
+//
+// MySQL compares JSON booleans, objects and arrays with JSON values only (SQL booleans are integers),
+// SQLite extracts them as integers (booleans) and JSON text
+func (w *whereObjectVars) whereObjectLiteral(engine Engine, value interface{}) string {
+	switch value.(type) {
+	case bool, map[string]interface{}, []interface{}:
+		valueJSON, _ := json.Marshal(value) //nolint:errchkjson // decoded from JSON (see: whereObject)
+		if engine == MySQL {
+			return "CAST(" + w.bind(string(valueJSON)) + " AS JSON)"
+		} else if _, ok := value.(bool); !ok {
+			return w.bind(string(valueJSON))
+		}
+	}
+	return w.bind(value)
+}
+
+// whereObjectContains generates the containment of the object key and value (PostgreSQL)
+func (w *whereObjectVars) whereObjectContains(k, objectKey string, value interface{}) string {
+	valueJSON, _ := json.Marshal(map[string]interface{}{objectKey: value}) //nolint:errchkjson // decoded from JSON
+	return k + "::jsonb @> " + w.bind(string(valueJSON)) + "::jsonb"
+}
+
+// whereObjectOperator generates the where statement of an object key operator ($exists or $in)
+func (w *whereObjectVars) whereObjectOperator(engine Engine, k, objectKey, operator string,
+	operand interface{},
+) string {
+	if operator == conditionExists {
+		query := "JSON_EXTRACT(" + k + ", " + getJSONPath(objectKey) + ")"
+		if engine == PostgreSQL {
+			query = "(" + k + "::jsonb -> '" + objectKey + "')"
+		}
+		if exists, _ := operand.(bool); exists {
+			return query + " IS NOT NULL"
+		}
+		return query + " IS NULL"
+	}
+
+	values, _ := operand.([]interface{})
+	if len(values) == 0 {
+		return "1 = 0" // Empty lists do not match any records
+	}
+	queryParts := make([]string, 0, len(values))
+	for _, value := range values {
+		if engine == PostgreSQL {
+			queryParts = append(queryParts, w.whereObjectContains(k, objectKey, value))
+		} else {
+			queryParts = append(queryParts, w.whereObjectLiteral(engine, value))
+		}
+	}
+	if engine != PostgreSQL {
+		return "JSON_EXTRACT(" + k + ", " + getJSONPath(objectKey) + ") IN (" + strings.Join(queryParts, ", ") + ")"
+	} else if len(queryParts) == 1 {
+		return queryParts[0]
+	}
+	return "(" + strings.Join(queryParts, " OR ") + ")"
 }
 
 // whereSlice generates the where slice
@@ -9,6 +9,7 @@ import (
 	customtypes "github.com/mrz1836/go-datastore/custom_types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
@@ -103,6 +104,12 @@ func Test_processConditions(t *testing.T) {
 	})
 }
 
+// testWhereObject will return the where object statement and its variables (numbered from @var0)
+func testWhereObject(engine Engine, k string, v interface{}) (string, map[string]interface{}) {
+	varNum := 0
+	return whereObject(engine, k, v, &varNum)
+}
+
 // Test_whereObject test the SQL where selector
 func Test_whereObject(t *testing.T) {
 	t.Parallel()
@@ -111,30 +118,25 @@ func Test_whereObject(t *testing.T) {
 		metadata := map[string]interface{}{
 			"test_key": "test-value",
 		}
-		query := whereObject(MySQL, metadataField, metadata)
-		expected := "JSON_EXTRACT(" + metadataField + ", '$.test_key') = \"test-value\""
-		assert.Equal(t, expected, query)
+		query, vars := testWhereObject(MySQL, metadataField, metadata)
+		assert.Equal(t, "JSON_EXTRACT("+metadataField+", '$.test_key') = @var0", query)
+		assert.Equal(t, map[string]interface{}{"var0": "test-value"}, vars)
 
 		metadata = map[string]interface{}{
 			"test_key": "test-'value'",
 		}
-		query = whereObject(MySQL, metadataField, metadata)
-		expected = "JSON_EXTRACT(" + metadataField + ", '$.test_key') = \"test-\\'value\\'\""
-		assert.Equal(t, expected, query)
+		query, vars = testWhereObject(MySQL, metadataField, metadata)
+		assert.Equal(t, "JSON_EXTRACT("+metadataField+", '$.test_key') = @var0", query)
+		assert.Equal(t, map[string]interface{}{"var0": "test-'value'"}, vars)
 
 		metadata = map[string]interface{}{
 			"test_key1": "test-value",
 			"test_key2": "test-value2",
 		}
-		query = whereObject(MySQL, metadataField, metadata)
-
-		assert.Contains(t, []string{
-			"(JSON_EXTRACT(" + metadataField + ", '$.test_key1') = \"test-value\" AND JSON_EXTRACT(" + metadataField + ", '$.test_key2') = \"test-value2\")",
-			"(JSON_EXTRACT(" + metadataField + ", '$.test_key2') = \"test-value2\" AND JSON_EXTRACT(" + metadataField + ", '$.test_key1') = \"test-value\")",
-		}, query)
-
-		// NOTE: the order of the items can change, hence the query order can change
-		// assert.Equal(t, expected, query)
+		query, vars = testWhereObject(MySQL, metadataField, metadata)
+		assert.Equal(t, "(JSON_EXTRACT("+metadataField+", '$.test_key1') = @var0 AND JSON_EXTRACT("+
+			metadataField+", '$.test_key2') = @var1)", query)
+		assert.Equal(t, map[string]interface{}{"var0": "test-value", "var1": "test-value2"}, vars)
 
 		objectMetadata := map[string]interface{}{
 			"testId": map[string]interface{}{
@@ -142,45 +144,36 @@ func Test_whereObject(t *testing.T) {
 				"test_key2": "test-value2",
 			},
 		}
-		query = whereObject(MySQL, "object_metadata", objectMetadata)
-
-		assert.Contains(t, []string{
-			"(JSON_EXTRACT(object_metadata, '$.testId.test_key1') = \"test-value\" AND JSON_EXTRACT(object_metadata, '$.testId.test_key2') = \"test-value2\")",
-			"(JSON_EXTRACT(object_metadata, '$.testId.test_key2') = \"test-value2\" AND JSON_EXTRACT(object_metadata, '$.testId.test_key1') = \"test-value\")",
-		}, query)
-
-		// NOTE: the order of the items can change, hence the query order can change
-		// assert.Equal(t, expected, query)
+		query, vars = testWhereObject(MySQL, "object_metadata", objectMetadata)
+		assert.Equal(t, "(JSON_EXTRACT(object_metadata, '$.testId.test_key1') = @var0 AND "+
+			"JSON_EXTRACT(object_metadata, '$.testId.test_key2') = @var1)", query)
+		assert.Equal(t, map[string]interface{}{"var0": "test-value", "var1": "test-value2"}, vars)
 	})
 
 	t.Run("Postgres", func(t *testing.T) {
 		metadata := map[string]interface{}{
 			"test_key": "test-value",
 		}
-		query := whereObject(PostgreSQL, metadataField, metadata)
-		expected := metadataField + "::jsonb @> '{\"test_key\":\"test-value\"}'::jsonb"
-		assert.Equal(t, expected, query)
+		query, vars := testWhereObject(PostgreSQL, metadataField, metadata)
+		assert.Equal(t, metadataField+"::jsonb @> @var0::jsonb", query)
+		assert.Equal(t, map[string]interface{}{"var0": `{"test_key":"test-value"}`}, vars)
 
 		metadata = map[string]interface{}{
 			"test_key": "test-'value'",
 		}
-		query = whereObject(PostgreSQL, metadataField, metadata)
-		expected = metadataField + "::jsonb @> '{\"test_key\":\"test-\\'value\\'\"}'::jsonb"
-		assert.Equal(t, expected, query)
+		query, vars = testWhereObject(PostgreSQL, metadataField, metadata)
+		assert.Equal(t, metadataField+"::jsonb @> @var0::jsonb", query)
+		assert.Equal(t, map[string]interface{}{"var0": `{"test_key":"test-'value'"}`}, vars)
 
 		metadata = map[string]interface{}{
 			"test_key1": "test-value",
 			"test_key2": "test-value2",
 		}
-		query = whereObject(PostgreSQL, metadataField, metadata)
-
-		assert.Contains(t, []string{
-			"(" + metadataField + "::jsonb @> '{\"test_key1\":\"test-value\"}'::jsonb AND " + metadataField + "::jsonb @> '{\"test_key2\":\"test-value2\"}'::jsonb)",
-			"(" + metadataField + "::jsonb @> '{\"test_key2\":\"test-value2\"}'::jsonb AND " + metadataField + "::jsonb @> '{\"test_key1\":\"test-value\"}'::jsonb)",
-		}, query)
-
-		// NOTE: the order of the items can change, hence the query order can change
-		// assert.Equal(t, expected, query)
+		query, vars = testWhereObject(PostgreSQL, metadataField, metadata)
+		assert.Equal(t, "("+metadataField+"::jsonb @> @var0::jsonb AND "+metadataField+"::jsonb @> @var1::jsonb)", query)
+		assert.Equal(t, map[string]interface{}{
+			"var0": `{"test_key1":"test-value"}`, "var1": `{"test_key2":"test-value2"}`,
+		}, vars)
 
 		objectMetadata := map[string]interface{}{
 			"testId": map[string]interface{}{
@@ -188,57 +181,110 @@ func Test_whereObject(t *testing.T) {
 				"test_key2": "test-value2",
 			},
 		}
-		query = whereObject(PostgreSQL, "object_metadata", objectMetadata)
-		assert.Contains(t, []string{
-			"object_metadata::jsonb @> '{\"testId\":{\"test_key1\":\"test-value\",\"test_key2\":\"test-value2\"}}'::jsonb",
-			"object_metadata::jsonb @> '{\"testId\":{\"test_key2\":\"test-value2\",\"test_key1\":\"test-value\"}}'::jsonb",
-		}, query)
-
-		// NOTE: the order of the items can change, hence the query order can change
-		// assert.Equal(t, expected, query)
+		query, vars = testWhereObject(PostgreSQL, "object_metadata", objectMetadata)
+		assert.Equal(t, "object_metadata::jsonb @> @var0::jsonb", query)
+		assert.Equal(t, map[string]interface{}{
+			"var0": `{"testId":{"test_key1":"test-value","test_key2":"test-value2"}}`,
+		}, vars)
 	})
 
 	t.Run("SQLite", func(t *testing.T) {
 		metadata := map[string]interface{}{
 			"test_key": "test-value",
 		}
-		query := whereObject(SQLite, metadataField, metadata)
-		expected := "JSON_EXTRACT(" + metadataField + ", '$.test_key') = \"test-value\""
-		assert.Equal(t, expected, query)
+		query, vars := testWhereObject(SQLite, metadataField, metadata)
+		assert.Equal(t, "JSON_EXTRACT("+metadataField+", '$.test_key') = @var0", query)
+		assert.Equal(t, map[string]interface{}{"var0": "test-value"}, vars)
 
 		metadata = map[string]interface{}{
 			"test_key": "test-'value'",
 		}
-		query = whereObject(SQLite, metadataField, metadata)
-		expected = "JSON_EXTRACT(" + metadataField + ", '$.test_key') = \"test-\\'value\\'\""
-		assert.Equal(t, expected, query)
-
-		metadata = map[string]interface{}{
-			"test_key1": "test-value",
-			"test_key2": "test-value2",
-		}
-		query = whereObject(SQLite, metadataField, metadata)
-		assert.Contains(t, []string{
-			"(JSON_EXTRACT(" + metadataField + ", '$.test_key1') = \"test-value\" AND JSON_EXTRACT(" + metadataField + ", '$.test_key2') = \"test-value2\")",
-			"(JSON_EXTRACT(" + metadataField + ", '$.test_key2') = \"test-value2\" AND JSON_EXTRACT(" + metadataField + ", '$.test_key1') = \"test-value\")",
-		}, query)
-
-		// NOTE: the order of the items can change, hence the query order can change
-		// assert.Equal(t, expected, query)
+		query, vars = testWhereObject(SQLite, metadataField, metadata)
+		assert.Equal(t, "JSON_EXTRACT("+metadataField+", '$.test_key') = @var0", query)
+		assert.Equal(t, map[string]interface{}{"var0": "test-'value'"}, vars)
 
 		objectMetadata := map[string]interface{}{
 			"testId": map[string]interface{}{
 				"test_key1": "test-value",
-				"test_key2": "test-value2",
+				"test_key2": 2,
 			},
 		}
-		query = whereObject(SQLite, "object_metadata", objectMetadata)
-		assert.Contains(t, []string{
-			"(JSON_EXTRACT(object_metadata, '$.testId.test_key1') = \"test-value\" AND JSON_EXTRACT(object_metadata, '$.testId.test_key2') = \"test-value2\")",
-			"(JSON_EXTRACT(object_metadata, '$.testId.test_key2') = \"test-value2\" AND JSON_EXTRACT(object_metadata, '$.testId.test_key1') = \"test-value\")",
-		}, query)
-		// NOTE: the order of the items can change, hence the query order can change
-		// assert.Equal(t, expected, query)
+		query, vars = testWhereObject(SQLite, "object_metadata", objectMetadata)
+		assert.Equal(t, "(JSON_EXTRACT(object_metadata, '$.testId.test_key1') = @var0 AND "+
+			"JSON_EXTRACT(object_metadata, '$.testId.test_key2') = @var1)", query)
+		assert.Equal(t, map[string]interface{}{"var0": "test-value", "var1": float64(2)}, vars)
+	})
+
+	t.Run("variables continue the numbering", func(t *testing.T) {
+		varNum := 3
+		query, vars := whereObject(SQLite, metadataField, map[string]interface{}{"plan": "pro"}, &varNum)
+		assert.Equal(t, "JSON_EXTRACT("+metadataField+", '$.plan') = @var3", query)
+		assert.Equal(t, map[string]interface{}{"var3": "pro"}, vars)
+		assert.Equal(t, 4, varNum)
+	})
+
+	t.Run("scalar values and nested operators", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			engine   Engine
+			metadata map[string]interface{}
+			expected string
+			vars     map[string]interface{}
+		}{
+			{"MySQL number", MySQL, map[string]interface{}{"amount": 5},
+				"JSON_EXTRACT(metadata, '$.amount') = @var0", map[string]interface{}{"var0": float64(5)}},
+			{"MySQL bool", MySQL, map[string]interface{}{"active": true},
+				"JSON_EXTRACT(metadata, '$.active') = CAST(@var0 AS JSON)", map[string]interface{}{"var0": "true"}},
+			{"MySQL null", MySQL, map[string]interface{}{"deleted": nil},
+				"JSON_EXTRACT(metadata, '$.deleted') IS NULL", map[string]interface{}{}},
+			{"MySQL range", MySQL, map[string]interface{}{"amount": map[string]interface{}{
+				conditionGreaterThan: 5, conditionLessThanOrEqual: 10.5,
+			}}, "(JSON_EXTRACT(metadata, '$.amount') > @var0 AND JSON_EXTRACT(metadata, '$.amount') <= @var1)",
+				map[string]interface{}{"var0": float64(5), "var1": 10.5}},
+			{"MySQL not equal text", MySQL, map[string]interface{}{"plan": map[string]interface{}{
+				conditionNotEquals: "pro",
+			}}, "JSON_EXTRACT(metadata, '$.plan') != @var0", map[string]interface{}{"var0": "pro"}},
+			{"MySQL in", MySQL, map[string]interface{}{"plan": map[string]interface{}{
+				conditionIn: []interface{}{"it's", true},
+			}}, "JSON_EXTRACT(metadata, '$.plan') IN (@var0, CAST(@var1 AS JSON))",
+				map[string]interface{}{"var0": "it's", "var1": "true"}},
+			{"SQLite bool", SQLite, map[string]interface{}{"active": false},
+				"JSON_EXTRACT(metadata, '$.active') = @var0", map[string]interface{}{"var0": false}},
+			{"SQLite greater than", SQLite, map[string]interface{}{"amount": map[string]interface{}{
+				conditionGreaterThan: 5,
+			}}, "JSON_EXTRACT(metadata, '$.amount') > @var0", map[string]interface{}{"var0": float64(5)}},
+			{"Postgres number", PostgreSQL, map[string]interface{}{"amount": 5},
+				"metadata::jsonb @> @var0::jsonb", map[string]interface{}{"var0": `{"amount":5}`}},
+			{"Postgres greater than", PostgreSQL, map[string]interface{}{"amount": map[string]interface{}{
+				conditionGreaterThan: 5,
+			}}, "(metadata::jsonb ->> 'amount')::numeric > @var0", map[string]interface{}{"var0": float64(5)}},
+			{"Postgres bool", PostgreSQL, map[string]interface{}{"active": map[string]interface{}{
+				conditionNotEquals: true,
+			}}, "(metadata::jsonb ->> 'active')::boolean != @var0", map[string]interface{}{"var0": true}},
+			{"Postgres text", PostgreSQL, map[string]interface{}{"plan": map[string]interface{}{
+				conditionLessThan: "it's",
+			}}, "metadata::jsonb ->> 'plan' < @var0", map[string]interface{}{"var0": "it's"}},
+			{"Postgres in", PostgreSQL, map[string]interface{}{"plan": map[string]interface{}{
+				conditionIn: []interface{}{"it's", 2},
+			}}, "(metadata::jsonb @> @var0::jsonb OR metadata::jsonb @> @var1::jsonb)",
+				map[string]interface{}{"var0": `{"plan":"it's"}`, "var1": `{"plan":2}`}},
+			{"unknown operator", MySQL, map[string]interface{}{"plan": map[string]interface{}{
+				"$regex": "pro",
+			}}, "1 = 0", map[string]interface{}{}},
+			{"other engine", Engine("other"), map[string]interface{}{"amount": 5},
+				"JSON_EXTRACT(metadata, '$.amount') = @var0", map[string]interface{}{"var0": "5"}},
+			{"quoted key", SQLite, map[string]interface{}{"test-key": "value"},
+				"JSON_EXTRACT(metadata, '$.\"test-key\"') = @var0", map[string]interface{}{"var0": "value"}},
+			{"invalid key", MySQL, map[string]interface{}{"key') = 1 OR ('": "value"}, "1 = 0",
+				map[string]interface{}{}},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				query, vars := testWhereObject(test.engine, metadataField, test.metadata)
+				assert.Equal(t, test.expected, query)
+				assert.Equal(t, test.vars, vars)
+			})
+		}
 	})
 }
 
@@ -377,7 +423,8 @@ func TestCustomWhere(t *testing.T) {
 		}
 		_ = client.CustomWhere(&tx, conditions, SQLite)
 		assert.Len(t, tx.WhereClauses, 1)
-		assert.Equal(t, "JSON_EXTRACT("+metadataField+", '$.field_name') = \"field_value\"", tx.WhereClauses[0])
+		assert.Equal(t, "JSON_EXTRACT("+metadataField+", '$.field_name') = @var0", tx.WhereClauses[0])
+		assert.Equal(t, "field_value", tx.Vars["var0"])
 	})
 
 	t.Run("MySQL "+metadataField, func(t *testing.T) {
@@ -394,7 +441,8 @@ func TestCustomWhere(t *testing.T) {
 		}
 		_ = client.CustomWhere(&tx, conditions, MySQL)
 		assert.Len(t, tx.WhereClauses, 1)
-		assert.Equal(t, "JSON_EXTRACT("+metadataField+", '$.field_name') = \"field_value\"", tx.WhereClauses[0])
+		assert.Equal(t, "JSON_EXTRACT("+metadataField+", '$.field_name') = @var0", tx.WhereClauses[0])
+		assert.Equal(t, "field_value", tx.Vars["var0"])
 	})
 
 	t.Run("PostgreSQL "+metadataField, func(t *testing.T) {
@@ -411,7 +459,8 @@ func TestCustomWhere(t *testing.T) {
 		}
 		_ = client.CustomWhere(&tx, conditions, PostgreSQL)
 		assert.Len(t, tx.WhereClauses, 1)
-		assert.Equal(t, metadataField+"::jsonb @> '{\"field_name\":\"field_value\"}'::jsonb", tx.WhereClauses[0])
+		assert.Equal(t, metadataField+"::jsonb @> @var0::jsonb", tx.WhereClauses[0])
+		assert.Equal(t, `{"field_name":"field_value"}`, tx.Vars["var0"])
 	})
 
 	t.Run("SQLite "+conditionAnd, func(t *testing.T) {
@@ -741,6 +790,76 @@ func TestProcessConditions(t *testing.T) {
 			},
 			expected: "field != @var0",
 		},
+		{
+			name: "Equals Or Null Condition",
+			conditions: map[string]interface{}{
+				"$eqOrNull": 10,
+			},
+			expected: "(field = @var0 OR field IS NULL)",
+		},
+		{
+			name: "Between Condition",
+			conditions: map[string]interface{}{
+				"$between": []int{100, 200},
+			},
+			expected: "field BETWEEN @var0 AND @var1",
+		},
+		{
+			name: "Bits All Set Condition",
+			conditions: map[string]interface{}{
+				"$bitsAllSet": 6,
+			},
+			expected: "(field & @var0) = @var0",
+		},
+		{
+			name: "Bits Any Set Condition",
+			conditions: map[string]interface{}{
+				"$bitsAnySet": []int{1, 2},
+			},
+			expected: "(field & @var0) <> 0",
+		},
+		{
+			name: "Bits All Set Condition - Invalid",
+			conditions: map[string]interface{}{
+				"$bitsAllSet": -1,
+			},
+			expected: "1 = 0",
+		},
+		{
+			name: "Between Condition - Invalid",
+			conditions: map[string]interface{}{
+				"$between": []int{100},
+			},
+			expected: "1 = 0",
+		},
+		{
+			name: "Starts With Condition",
+			conditions: map[string]interface{}{
+				"$startsWith": "abc",
+			},
+			expected: "field LIKE @var0 ESCAPE '\\'",
+		},
+		{
+			name: "Ends With Condition",
+			conditions: map[string]interface{}{
+				"$endsWith": "abc",
+			},
+			expected: "field LIKE @var0 ESCAPE '\\'",
+		},
+		{
+			name: "Like Condition",
+			conditions: map[string]interface{}{
+				"$like": "%smith%",
+			},
+			expected: "field LIKE @var0",
+		},
+		{
+			name: "ILike Condition",
+			conditions: map[string]interface{}{
+				"$ilike": "%smith%",
+			},
+			expected: "LOWER(field) LIKE LOWER(@var0)",
+		},
 		{
 			name: "Exists Condition - True",
 			conditions: map[string]interface{}{
@@ -780,3 +899,194 @@ func TestProcessConditions(t *testing.T) {
 		})
 	}
 }
+
+// Test_processConditions_identifiers will test quoting the condition keys (reserved words and injection)
+func Test_processConditions_identifiers(t *testing.T) {
+	client, deferFunc := testClient(context.Background(), t)
+	defer deferFunc()
+
+	tests := []struct {
+		name       string
+		engine     Engine
+		conditions map[string]interface{}
+		expected   string
+	}{
+		{"plain", MySQL, map[string]interface{}{"amount": 1}, "amount = @var0"},
+		{"MySQL reserved word", MySQL, map[string]interface{}{"order": 1}, "`order` = @var0"},
+		{"Postgres reserved word", PostgreSQL, map[string]interface{}{"order": nil}, `"order" IS NULL`},
+		{"SQLite operator", SQLite, map[string]interface{}{"group": map[string]interface{}{conditionGreaterThan: 1}},
+			`"group" > @var0`},
+		{"qualified name", PostgreSQL, map[string]interface{}{"users.user": 1}, `users."user" = @var0`},
+		{"injection", MySQL, map[string]interface{}{"id = 1 OR `1`": 1}, "`id = 1 OR ``1``` = @var0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tx := &txAccumulator{WhereClauses: make([]string, 0), Vars: make(map[string]interface{})}
+			varNum := 0
+			processConditions(client, tx, test.conditions, test.engine, &varNum, nil)
+			require.Len(t, tx.WhereClauses, 1)
+			assert.Equal(t, test.expected, tx.WhereClauses[0])
+		})
+	}
+}
+
+// Test_whereLike will test the method whereLike()
+func Test_whereLike(t *testing.T) {
+	assert.Equal(t, "name LIKE @var0", whereLike(MySQL, "name", "@var0", false))
+	assert.Equal(t, "name LIKE @var0", whereLike(PostgreSQL, "name", "@var0", false))
+	assert.Equal(t, "name ILIKE @var0", whereLike(PostgreSQL, "name", "@var0", true))
+	assert.Equal(t, "LOWER(name) LIKE LOWER(@var0)", whereLike(MySQL, "name", "@var0", true))
+	assert.Equal(t, "LOWER(name) LIKE LOWER(@var0)", whereLike(SQLite, "name", "@var0", true))
+}
+
+// TestClient_GetModels_like will test the $like and $ilike conditions using SQLite
+func TestClient_GetModels_like(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+	testSaveModels(ctx, t, client,
+		&testSQLModel{ID: "like-1", Name: "John Smith"},
+		&testSQLModel{ID: "like-2", Name: "Jane Smithers"},
+		&testSQLModel{ID: "like-3", Name: "Bob Jones"},
+	)
+
+	var models []*testSQLModel
+	require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{
+		"name": map[string]interface{}{conditionLike: "%Smith"},
+	}, nil, nil, defaultDatabaseMaxTimeout))
+	require.Len(t, models, 1)
+	assert.Equal(t, "like-1", models[0].ID)
+
+	models = nil
+	require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{
+		"name": map[string]interface{}{conditionILike: "%SMITH%"},
+	}, nil, nil, defaultDatabaseMaxTimeout))
+	assert.Len(t, models, 2)
+}
+
+// TestClient_GetModels_between will test the $between condition using SQLite
+func TestClient_GetModels_between(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+	testSaveModels(ctx, t, client,
+		&testSQLModel{ID: "between-1", Amount: 50},
+		&testSQLModel{ID: "between-2", Amount: 100},
+		&testSQLModel{ID: "between-3", Amount: 200},
+		&testSQLModel{ID: "between-4", Amount: 250},
+	)
+
+	var models []*testSQLModel
+	require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{
+		"amount": map[string]interface{}{conditionBetween: []int64{100, 200}},
+	}, &QueryParams{OrderByField: sqlIDField, SortDirection: SortAsc}, nil, defaultDatabaseMaxTimeout))
+	require.Len(t, models, 2)
+	assert.Equal(t, "between-2", models[0].ID)
+	assert.Equal(t, "between-3", models[1].ID)
+}
+
+// Test_escapeLikePattern will test the method escapeLikePattern()
+func Test_escapeLikePattern(t *testing.T) {
+	assert.Equal(t, "abc", escapeLikePattern("abc"))
+	assert.Equal(t, `100\%`, escapeLikePattern("100%"))
+	assert.Equal(t, `a\_b\\c`, escapeLikePattern(`a_b\c`))
+}
+
+// TestClient_GetModels_startsWith will test the $startsWith and $endsWith conditions using SQLite
+func TestClient_GetModels_startsWith(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+	testSaveModels(ctx, t, client,
+		&testSQLModel{ID: "prefix-1", Name: "abc_def"},
+		&testSQLModel{ID: "prefix-2", Name: "abcXdef"},
+		&testSQLModel{ID: "prefix-3", Name: "xyz 100%"},
+	)
+
+	var models []*testSQLModel
+	require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{
+		"name": map[string]interface{}{conditionStartsWith: "abc_"},
+	}, nil, nil, defaultDatabaseMaxTimeout))
+	require.Len(t, models, 1)
+	assert.Equal(t, "prefix-1", models[0].ID)
+
+	models = nil
+	require.NoError(t, client.GetModels(ctx, &models, map[string]interface{}{
+		"name": map[string]interface{}{conditionEndsWith: "100%"},
+	}, nil, nil, defaultDatabaseMaxTimeout))
+	require.Len(t, models, 1)
+	assert.Equal(t, "prefix-3", models[0].ID)
+}
+
+// TestClient_GetModelCount_eqOrNull will test the $eqOrNull condition using SQLite
+func TestClient_GetModelCount_eqOrNull(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+	testSaveModels(ctx, t, client,
+		&testSQLModel{ID: "null-1", Name: "abc", Amount: 10},
+		&testSQLModel{ID: "null-2", Name: "def", Amount: 20},
+		&testSQLModel{ID: "null-3", Name: "ghi", Amount: 30},
+	)
+	require.NoError(t, client.Execute("UPDATE "+testSQLTableName+" SET name = NULL WHERE id = 'null-2'").Error)
+
+	count, err := client.GetModelCount(ctx, &testSQLModel{}, map[string]interface{}{
+		"name": map[string]interface{}{conditionEqOrNull: "abc"},
+	}, defaultDatabaseMaxTimeout)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = client.GetModelCount(ctx, &testSQLModel{}, map[string]interface{}{
+		"name": map[string]interface{}{conditionEqOrNull: "xyz"},
+	}, defaultDatabaseMaxTimeout)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestClient_GetModelCount_bits will test the $bitsAllSet and $bitsAnySet conditions using SQLite
+func TestClient_GetModelCount_bits(t *testing.T) {
+	ctx := context.Background()
+	client, deferFunc := testSQLiteClient(ctx, t)
+	defer deferFunc()
+	testSaveModels(ctx, t, client,
+		&testSQLModel{ID: "bits-1", Amount: 1}, // 001
+		&testSQLModel{ID: "bits-2", Amount: 3}, // 011
+		&testSQLModel{ID: "bits-3", Amount: 6}, // 110
+	)
+
+	tests := []struct {
+		conditions map[string]interface{}
+		expected   int64
+	}{
+		{map[string]interface{}{conditionBitsAllSet: 3}, 1},
+		{map[string]interface{}{conditionBitsAllSet: []int{1}}, 2},
+		{map[string]interface{}{conditionBitsAnySet: 1}, 2},
+		{map[string]interface{}{conditionBitsAnySet: []int{0, 2}}, 3},
+		{map[string]interface{}{conditionBitsAnySet: 8}, 0},
+	}
+	for _, test := range tests {
+		count, err := client.GetModelCount(ctx, &testSQLModel{}, map[string]interface{}{
+			"amount": test.conditions,
+		}, defaultDatabaseMaxTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, count, test.conditions)
+	}
+}
+
+// TestGetBitMask will test the method getBitMask()
+func TestGetBitMask(t *testing.T) {
+	t.Parallel()
+
+	mask, ok := getBitMask(uint8(5))
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), mask)
+
+	mask, ok = getBitMask([]interface{}{0, 3})
+	assert.True(t, ok)
+	assert.Equal(t, int64(9), mask)
+
+	for _, invalid := range []interface{}{-1, []int{}, []int{63}, []interface{}{"a"}, "5", 1.5} {
+		_, ok = getBitMask(invalid)
+		assert.False(t, ok, invalid)
+	}
+}